@@ -4,11 +4,15 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/joho/godotenv"
 
 	server "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/application"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/cache"
 )
 
 func main() {
@@ -25,7 +29,19 @@ func main() {
 			DBName:    os.Getenv("DB_NAME"),
 			ParseTime: true,
 		},
-		Address: os.Getenv("API_ADDRESS"),
+		Address:         os.Getenv("API_ADDRESS"),
+		Cache:           cacheConfig(),
+		Replica:         replicaConfig(),
+		ReadAfterWrite:  readAfterWrite(),
+		Migrate:         os.Getenv("DB_AUTO_MIGRATE") == "true",
+		SearchLike:      os.Getenv("SEARCH_LIKE") == "true",
+		EventBuffer:     eventBuffer(),
+		Jobs:            jobsConfig(),
+		ReportDir:       os.Getenv("REPORT_DIR"),
+		ReportWorkers:   reportWorkers(),
+		ReportRetention: reportRetention(),
+		ExpiryLeadTime:  expiryLeadTime(),
+		AlertWebhooks:   alertWebhooks(),
 	}
 	app := server.NewSQLConfig(cfg)
 	// - run
@@ -34,3 +50,109 @@ func main() {
 		return
 	}
 }
+
+// cacheConfig reads the repository cache settings, e.g. CACHE_PRODUCTS_ENABLED=false, CACHE_SECTIONS_SIZE=500 or CACHE_SELLERS_TTL=2m
+func cacheConfig() map[string]cache.Config {
+	cfg := make(map[string]cache.Config, len(server.DefaultCache))
+	for name, c := range server.DefaultCache {
+		prefix := "CACHE_" + strings.ToUpper(name) + "_"
+		if v, err := strconv.ParseBool(os.Getenv(prefix + "ENABLED")); err == nil {
+			c.Enabled = v
+		}
+		if v, err := strconv.Atoi(os.Getenv(prefix + "SIZE")); err == nil {
+			c.Size = v
+		}
+		if v, err := time.ParseDuration(os.Getenv(prefix + "TTL")); err == nil {
+			c.TTL = v
+		}
+		cfg[name] = c
+	}
+	return cfg
+}
+
+// jobsConfig reads the job schedules, e.g. JOB_RUNS_CLEANUP_SCHEDULE="0 3 * * *" or JOB_RUNS_CLEANUP_SCHEDULE=off
+func jobsConfig() map[string]string {
+	cfg := make(map[string]string, len(server.DefaultJobs))
+	for name, spec := range server.DefaultJobs {
+		if v := os.Getenv("JOB_" + strings.ToUpper(name) + "_SCHEDULE"); v != "" {
+			spec = v
+		}
+		cfg[name] = spec
+	}
+	return cfg
+}
+
+// replicaConfig reads the optional read replica, it uses the primary credentials unless DB_REPLICA_USER and DB_REPLICA_PASSWORD are set
+func replicaConfig() *mysql.Config {
+	addr := os.Getenv("DB_REPLICA_ADDRESS")
+	if addr == "" {
+		return nil
+	}
+	user, passwd := os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD")
+	if v := os.Getenv("DB_REPLICA_USER"); v != "" {
+		user, passwd = v, os.Getenv("DB_REPLICA_PASSWORD")
+	}
+	return &mysql.Config{
+		User:      user,
+		Passwd:    passwd,
+		Net:       "tcp",
+		Addr:      addr,
+		DBName:    os.Getenv("DB_NAME"),
+		ParseTime: true,
+	}
+}
+
+// readAfterWrite reads how long reads stay on the primary after a write, e.g. DB_READ_AFTER_WRITE=5s
+func readAfterWrite() time.Duration {
+	v, err := time.ParseDuration(os.Getenv("DB_READ_AFTER_WRITE"))
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// eventBuffer reads how many events are kept for the clients resuming the event stream, e.g. EVENT_BUFFER=5000
+func eventBuffer() int {
+	v, err := strconv.Atoi(os.Getenv("EVENT_BUFFER"))
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// reportWorkers reads how many reports are generated at the same time, e.g. REPORT_WORKERS=4
+func reportWorkers() int {
+	v, err := strconv.Atoi(os.Getenv("REPORT_WORKERS"))
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// reportRetention reads how long the finished reports are kept, e.g. REPORT_RETENTION=72h
+func reportRetention() time.Duration {
+	v, err := time.ParseDuration(os.Getenv("REPORT_RETENTION"))
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// expiryLeadTime reads how long before its due date a batch is warned about, e.g. EXPIRY_LEAD_TIME=72h
+func expiryLeadTime() time.Duration {
+	v, err := time.ParseDuration(os.Getenv("EXPIRY_LEAD_TIME"))
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// alertWebhooks reads the comma separated urls the alerts are posted to, e.g. ALERT_WEBHOOKS=https://hooks.example.com/frescos
+func alertWebhooks() (urls []string) {
+	for _, url := range strings.Split(os.Getenv("ALERT_WEBHOOKS"), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
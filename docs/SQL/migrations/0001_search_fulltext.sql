@@ -0,0 +1,6 @@
+-- FULLTEXT indexes used by the unified search endpoint GET /v1/search
+ALTER TABLE `products` ADD FULLTEXT INDEX `ft_products_search` (`product_code`, `description`);
+ALTER TABLE `sellers` ADD FULLTEXT INDEX `ft_sellers_search` (`company_name`, `cid`);
+ALTER TABLE `buyers` ADD FULLTEXT INDEX `ft_buyers_search` (`first_name`, `last_name`, `id_card_number`);
+ALTER TABLE `warehouses` ADD FULLTEXT INDEX `ft_warehouses_search` (`warehouse_code`, `address`);
+ALTER TABLE `carries` ADD FULLTEXT INDEX `ft_carries_search` (`company_name`);
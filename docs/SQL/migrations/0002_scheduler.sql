@@ -0,0 +1,19 @@
+-- Leases and run history of the job scheduler, a lease lets a single instance run each job
+CREATE TABLE IF NOT EXISTS `job_leases` (
+    `name` VARCHAR(100) NOT NULL PRIMARY KEY,
+    `owner` VARCHAR(255) NOT NULL DEFAULT '',
+    `expires_at` DATETIME(3) NOT NULL DEFAULT '1970-01-01 00:00:01',
+    `paused` BOOLEAN NOT NULL DEFAULT FALSE
+);
+CREATE TABLE IF NOT EXISTS `job_runs` (
+    `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `job` VARCHAR(100) NOT NULL,
+    `owner` VARCHAR(255) NOT NULL,
+    `trigger` VARCHAR(20) NOT NULL,
+    `started_at` DATETIME(3) NOT NULL,
+    `finished_at` DATETIME(3) NOT NULL,
+    `duration_ms` BIGINT NOT NULL,
+    `status` VARCHAR(20) NOT NULL,
+    `error` TEXT NULL,
+    INDEX `idx_job_runs_job_started` (`job`, `started_at`)
+);
@@ -0,0 +1,4 @@
+-- Locality of every warehouse, reported by GET /v1/localities/reportWarehouses. Existing warehouses keep a NULL
+-- locality until they are updated
+ALTER TABLE `warehouses` ADD COLUMN `locality_id` INT NULL;
+ALTER TABLE `warehouses` ADD CONSTRAINT `fk_warehouses_locality` FOREIGN KEY (`locality_id`) REFERENCES `localities` (`id`);
@@ -0,0 +1,15 @@
+-- Stock transfers between sections, POST /v1/transfers records every movement with the employee who did it
+CREATE TABLE IF NOT EXISTS `transfers` (
+    `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `product_batch_id` INT NOT NULL,
+    `destination_batch_id` INT NOT NULL,
+    `from_section_id` INT NOT NULL,
+    `to_section_id` INT NOT NULL,
+    `quantity` INT NOT NULL,
+    `employee_id` INT NOT NULL,
+    `created_at` DATETIME(3) NOT NULL,
+    INDEX `idx_transfers_batch` (`product_batch_id`),
+    CONSTRAINT `fk_transfers_batch` FOREIGN KEY (`product_batch_id`) REFERENCES `product_batches` (`id`),
+    CONSTRAINT `fk_transfers_destination_batch` FOREIGN KEY (`destination_batch_id`) REFERENCES `product_batches` (`id`),
+    CONSTRAINT `fk_transfers_employee` FOREIGN KEY (`employee_id`) REFERENCES `employees` (`id`)
+);
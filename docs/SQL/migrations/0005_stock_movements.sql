@@ -0,0 +1,43 @@
+-- Append-only ledger of stock movements, the balance of a batch or a section is the sum of its movements. The
+-- opening corrections of the sections are not tied to a batch, so product_batch_id is nullable
+CREATE TABLE IF NOT EXISTS `stock_movements` (
+    `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `type` VARCHAR(20) NOT NULL,
+    `product_batch_id` INT NULL,
+    `section_id` INT NOT NULL,
+    `quantity` INT NOT NULL,
+    `reference_type` VARCHAR(30) NULL,
+    `reference_id` INT NULL,
+    `employee_id` INT NULL,
+    `reason` VARCHAR(255) NULL,
+    `created_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    INDEX `idx_stock_movements_batch` (`product_batch_id`, `id`),
+    INDEX `idx_stock_movements_section` (`section_id`),
+    CONSTRAINT `fk_stock_movements_batch` FOREIGN KEY (`product_batch_id`) REFERENCES `product_batches` (`id`),
+    CONSTRAINT `fk_stock_movements_employee` FOREIGN KEY (`employee_id`) REFERENCES `employees` (`id`)
+);
+
+-- Counters that disagreed with the ledger in the last reconciliation
+CREATE TABLE IF NOT EXISTS `stock_discrepancies` (
+    `entity` VARCHAR(20) NOT NULL,
+    `entity_id` INT NOT NULL,
+    `stored` INT NOT NULL,
+    `ledger` INT NOT NULL,
+    `detected_at` DATETIME(3) NOT NULL,
+    PRIMARY KEY (`entity`, `entity_id`)
+);
+
+-- The existing stock opens the ledger so the current counters of the batches reconcile
+INSERT INTO `stock_movements` (`type`, `product_batch_id`, `section_id`, `quantity`, `reason`)
+SELECT 'adjustment', `id`, `section_id`, `current_quantity`, 'opening balance'
+FROM `product_batches`
+WHERE `current_quantity` <> 0;
+
+-- The capacity of a section that does not match the stock of its batches, e.g. the seeded sections, is opened with
+-- a correction for the difference so the current counters of the sections reconcile too
+INSERT INTO `stock_movements` (`type`, `section_id`, `quantity`, `reason`)
+SELECT 'adjustment', s.`id`, s.`current_capacity` - COALESCE(SUM(pb.`current_quantity`), 0), 'opening correction'
+FROM `sections` AS s
+LEFT JOIN `product_batches` AS pb ON pb.`section_id` = s.`id`
+GROUP BY s.`id`, s.`current_capacity`
+HAVING s.`current_capacity` <> COALESCE(SUM(pb.`current_quantity`), 0);
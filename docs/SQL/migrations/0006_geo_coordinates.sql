@@ -0,0 +1,8 @@
+-- Coordinates of the localities and the warehouses, GET /v1/warehouses/nearest ranks the warehouses by distance
+ALTER TABLE `localities`
+    ADD COLUMN `latitude` DECIMAL(9,6) NULL,
+    ADD COLUMN `longitude` DECIMAL(9,6) NULL;
+
+ALTER TABLE `warehouses`
+    ADD COLUMN `latitude` DECIMAL(9,6) NULL,
+    ADD COLUMN `longitude` DECIMAL(9,6) NULL;
@@ -0,0 +1,32 @@
+-- Temperature readings sent by the sensors of the sections, with hourly rollups and the alerts they open
+CREATE TABLE IF NOT EXISTS `section_readings` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `section_id` INT NOT NULL,
+    `temperature` DECIMAL(6,2) NOT NULL,
+    `recorded_at` DATETIME(3) NOT NULL,
+    INDEX `idx_section_readings_section_recorded` (`section_id`, `recorded_at`),
+    CONSTRAINT `fk_section_readings_section` FOREIGN KEY (`section_id`) REFERENCES `sections` (`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `section_reading_rollups` (
+    `section_id` INT NOT NULL,
+    `bucket_start` DATETIME NOT NULL,
+    `min_temperature` DECIMAL(6,2) NOT NULL,
+    `max_temperature` DECIMAL(6,2) NOT NULL,
+    `sum_temperature` DECIMAL(12,2) NOT NULL,
+    `readings_count` INT NOT NULL,
+    PRIMARY KEY (`section_id`, `bucket_start`),
+    CONSTRAINT `fk_section_reading_rollups_section` FOREIGN KEY (`section_id`) REFERENCES `sections` (`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `section_alerts` (
+    `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `section_id` INT NOT NULL,
+    `type` VARCHAR(40) NOT NULL,
+    `temperature` DECIMAL(6,2) NOT NULL,
+    `threshold` DECIMAL(6,2) NOT NULL,
+    `opened_at` DATETIME(3) NOT NULL,
+    `closed_at` DATETIME(3) NULL,
+    INDEX `idx_section_alerts_section_open` (`section_id`, `closed_at`),
+    CONSTRAINT `fk_section_alerts_section` FOREIGN KEY (`section_id`) REFERENCES `sections` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,19 @@
+-- Registry of the product types referenced by products and sections, with their storage range and hazard class
+CREATE TABLE IF NOT EXISTS `product_types` (
+    `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `name` VARCHAR(60) NOT NULL,
+    `minimum_temperature` DECIMAL(6,2) NOT NULL,
+    `maximum_temperature` DECIMAL(6,2) NOT NULL,
+    `hazard_class` VARCHAR(20) NOT NULL DEFAULT 'none',
+    UNIQUE KEY `uq_product_types_name` (`name`)
+);
+
+-- The types already in use get a placeholder with an open range so the foreign keys can be added
+INSERT INTO `product_types` (`id`, `name`, `minimum_temperature`, `maximum_temperature`)
+SELECT t.`id`, CONCAT('product type ', t.`id`), -100, 100
+FROM (SELECT `product_type_id` AS `id` FROM `products` UNION SELECT `product_type_id` FROM `sections`) AS t
+WHERE t.`id` NOT IN (SELECT `id` FROM `product_types`);
+
+ALTER TABLE `products` ADD CONSTRAINT `fk_products_product_type` FOREIGN KEY (`product_type_id`) REFERENCES `product_types` (`id`);
+
+ALTER TABLE `sections` ADD CONSTRAINT `fk_sections_product_type` FOREIGN KEY (`product_type_id`) REFERENCES `product_types` (`id`);
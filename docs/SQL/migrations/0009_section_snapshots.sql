@@ -0,0 +1,12 @@
+-- Occupancy and temperature of the sections over time, taken periodically and after every section update
+CREATE TABLE IF NOT EXISTS `section_snapshots` (
+    `id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `section_id` INT NOT NULL,
+    `current_capacity` INT NOT NULL,
+    `maximum_capacity` INT NOT NULL,
+    `current_temperature` DECIMAL(6,2) NOT NULL,
+    `source` VARCHAR(20) NOT NULL,
+    `taken_at` DATETIME(3) NOT NULL,
+    INDEX `idx_section_snapshots_section_taken` (`section_id`, `taken_at`),
+    CONSTRAINT `fk_section_snapshots_section` FOREIGN KEY (`section_id`) REFERENCES `sections` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+-- Archived batches keep the history the orders point to without stock, retired batches are archived batches
+-- deleted through the API that are still referenced and so cannot be removed
+ALTER TABLE `product_batches`
+    ADD COLUMN `archived_at` DATETIME(3) NULL,
+    ADD COLUMN `retired_at` DATETIME(3) NULL,
+    ADD INDEX `idx_product_batches_due_date` (`due_date`);
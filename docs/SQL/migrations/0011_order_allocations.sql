@@ -0,0 +1,14 @@
+-- The batches that served every order detail, picked first-expired-first-out when the purchase order is saved.
+-- backordered_quantity is the part of a detail that had no stock when the order allowed backorders
+ALTER TABLE `order_details`
+    ADD COLUMN `backordered_quantity` INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS `order_detail_allocations` (
+    `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `order_detail_id` INT NOT NULL,
+    `product_batch_id` INT NOT NULL,
+    `quantity` INT NOT NULL,
+    INDEX `idx_order_detail_allocations_batch` (`product_batch_id`),
+    CONSTRAINT `fk_order_detail_allocations_detail` FOREIGN KEY (`order_detail_id`) REFERENCES `order_details` (`id`),
+    CONSTRAINT `fk_order_detail_allocations_batch` FOREIGN KEY (`product_batch_id`) REFERENCES `product_batches` (`id`)
+);
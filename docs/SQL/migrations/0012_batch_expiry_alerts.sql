@@ -0,0 +1,17 @@
+-- The expiry sweep warns once about the batches entering the lead time of their product and moves the due ones to
+-- the expired state, both raise alerts grouped by warehouse and seller
+ALTER TABLE `product_batches`
+    ADD COLUMN `expiry_warned_at` DATETIME(3) NULL,
+    ADD COLUMN `expired_at` DATETIME(3) NULL;
+
+CREATE TABLE IF NOT EXISTS `alerts` (
+    `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `type` VARCHAR(32) NOT NULL,
+    `warehouse_id` INT NOT NULL,
+    `seller_id` INT NOT NULL,
+    `quantity` INT NOT NULL,
+    `product_batch_ids` JSON NOT NULL,
+    `created_at` DATETIME(3) NOT NULL,
+    INDEX `idx_alerts_created_at` (`created_at`),
+    INDEX `idx_alerts_warehouse_seller` (`warehouse_id`, `seller_id`)
+);
@@ -0,0 +1,18 @@
+-- Quality holds: a quarantined or rejected batch keeps its units but cannot be picked, transferred or reported as
+-- stock. Every change of status is recorded with its reason and the employee that made it
+ALTER TABLE `product_batches`
+    ADD COLUMN `status` VARCHAR(16) NOT NULL DEFAULT 'available',
+    ADD INDEX `idx_product_batches_status` (`status`);
+
+CREATE TABLE IF NOT EXISTS `product_batch_status_changes` (
+    `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `product_batch_id` INT NOT NULL,
+    `from_status` VARCHAR(16) NOT NULL,
+    `to_status` VARCHAR(16) NOT NULL,
+    `reason` VARCHAR(255) NOT NULL,
+    `employee_id` INT NOT NULL,
+    `created_at` DATETIME(3) NOT NULL,
+    INDEX `idx_product_batch_status_changes_batch` (`product_batch_id`, `id`),
+    CONSTRAINT `fk_product_batch_status_changes_batch` FOREIGN KEY (`product_batch_id`) REFERENCES `product_batches` (`id`),
+    CONSTRAINT `fk_product_batch_status_changes_employee` FOREIGN KEY (`employee_id`) REFERENCES `employees` (`id`)
+);
@@ -0,0 +1,21 @@
+-- Recalls of a batch and the batches split from it by transfers. The affected batches are rejected, see
+-- 0013_batch_quality_holds.sql, and the buyers to contact are read from order_detail_allocations
+CREATE TABLE IF NOT EXISTS `recalls` (
+    `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `product_batch_id` INT NOT NULL,
+    `reason` VARCHAR(255) NOT NULL,
+    `employee_id` INT NOT NULL,
+    `created_at` DATETIME(3) NOT NULL,
+    CONSTRAINT `fk_recalls_batch` FOREIGN KEY (`product_batch_id`) REFERENCES `product_batches` (`id`),
+    CONSTRAINT `fk_recalls_employee` FOREIGN KEY (`employee_id`) REFERENCES `employees` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `recall_batches` (
+    `recall_id` INT NOT NULL,
+    `product_batch_id` INT NOT NULL,
+    PRIMARY KEY (`recall_id`, `product_batch_id`),
+    INDEX `idx_recall_batches_batch` (`product_batch_id`),
+    CONSTRAINT `fk_recall_batches_recall` FOREIGN KEY (`recall_id`) REFERENCES `recalls` (`id`),
+    CONSTRAINT `fk_recall_batches_batch` FOREIGN KEY (`product_batch_id`) REFERENCES `product_batches` (`id`)
+);
+
@@ -0,0 +1,2 @@
+-- Last scheduled activation taken by each job, an instance whose timer fires late cannot run it again
+ALTER TABLE `job_leases` ADD COLUMN `last_activation` DATETIME(3) NOT NULL DEFAULT '1970-01-01 00:00:01' AFTER `expires_at`;
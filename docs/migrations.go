@@ -0,0 +1,18 @@
+package docs
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed SQL/migrations/*.sql
+var migrations embed.FS
+
+// Migrations returns the SQL migrations shipped with the application, named NNNN_description.sql
+func Migrations() fs.FS {
+	sub, err := fs.Sub(migrations, "SQL/migrations")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
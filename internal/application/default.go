@@ -1,36 +1,296 @@
 package server
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-sql-driver/mysql"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/docs"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/internal/database"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/internal/events"
 	hand "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/handler"
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
 	repo "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/repository"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/internal/scheduler"
 	serv "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/service"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/cache"
 )
 
 type SQLConfig struct {
 	Database mysql.Config
 	Address  string
+	// Cache holds the repository cache settings by entity: products, sellers, sections and warehouses
+	Cache map[string]cache.Config
+	// Replica is the optional read replica, reads fall back to the primary when it is nil or unhealthy
+	Replica *mysql.Config
+	// ReadAfterWrite is how long reads stay on the primary after a write
+	ReadAfterWrite time.Duration
+	// Migrate applies the pending migrations of docs/SQL/migrations on start
+	Migrate bool
+	// SearchLike disables the FULLTEXT search, for databases without its migration
+	SearchLike bool
+	// EventBuffer is how many events are kept for the clients resuming the event stream
+	EventBuffer int
+	// Jobs holds the cron schedule of every scheduled job by name, JobOff disables a job
+	Jobs map[string]string
+	// ReportDir is where the asynchronous reports are written
+	ReportDir string
+	// ReportWorkers is how many reports are generated at the same time
+	ReportWorkers int
+	// ReportRetention is how long the finished reports are kept
+	ReportRetention time.Duration
+	// ExpiryLeadTime is how long before its due date a batch is warned about, scaled by the expiration rate of its product
+	ExpiryLeadTime time.Duration
+	// AlertWebhooks are the urls the alerts are posted to, none disables the webhooks
+	AlertWebhooks []string
+}
+
+// DefaultReadAfterWrite covers the usual replication lag of the replica
+const DefaultReadAfterWrite = 2 * time.Second
+
+// replicaCheckInterval is how often the replica health is checked
+const replicaCheckInterval = 5 * time.Second
+
+// DefaultEventBuffer keeps a few minutes of events on a busy floor
+const DefaultEventBuffer = 1000
+
+// eventHeartbeat is how often an idle event stream sends a keep-alive
+const eventHeartbeat = 15 * time.Second
+
+// JobOff is the schedule of a disabled job
+const JobOff = "off"
+
+// DefaultJobs are the schedules used when no job configuration is given
+var DefaultJobs = map[string]string{
+	"runs_cleanup":      "@daily",
+	"reports_cleanup":   "@hourly",
+	"stock_reconcile":   "@hourly",
+	"section_snapshots": "@hourly",
+	"batch_expiry":      "@hourly",
+}
+
+// jobRunsRetention is how long the job run history is kept
+const jobRunsRetention = 30 * 24 * time.Hour
+
+// DefaultReportWorkers keeps the report queries from taking over the database
+const DefaultReportWorkers = 2
+
+// DefaultReportRetention is how long a finished report can be downloaded
+const DefaultReportRetention = 24 * time.Hour
+
+// DefaultExpiryLeadTime warns a week before the due date of a product with an expiration rate of 1
+const DefaultExpiryLeadTime = 7 * 24 * time.Hour
+
+// DefaultReportDir is the default directory of the asynchronous reports
+var DefaultReportDir = filepath.Join(os.TempDir(), "frescos-reports")
+
+// shutdownTimeout is how long the running requests and jobs have to finish when the server stops
+const shutdownTimeout = 30 * time.Second
+
+// DefaultCache is used when no cache configuration is given
+var DefaultCache = map[string]cache.Config{
+	"products":   {Enabled: true, Size: 1000, TTL: time.Minute},
+	"sellers":    {Enabled: true, Size: 1000, TTL: time.Minute},
+	"sections":   {Enabled: true, Size: 1000, TTL: 30 * time.Second},
+	"warehouses": {Enabled: true, Size: 1000, TTL: time.Minute},
 }
 
 func NewSQLConfig(cfg *SQLConfig) *SQLConfig {
 	cfgDefault := &SQLConfig{
-		Address: ":8080",
+		Address:         ":8080",
+		Cache:           DefaultCache,
+		ReadAfterWrite:  DefaultReadAfterWrite,
+		EventBuffer:     DefaultEventBuffer,
+		Jobs:            DefaultJobs,
+		ReportDir:       DefaultReportDir,
+		ReportWorkers:   DefaultReportWorkers,
+		ReportRetention: DefaultReportRetention,
+		ExpiryLeadTime:  DefaultExpiryLeadTime,
 	}
 	if cfg != nil {
 		cfgDefault.Database = cfg.Database
 		if cfg.Address != "" {
 			cfgDefault.Address = cfg.Address
 		}
+		if cfg.Cache != nil {
+			cfgDefault.Cache = cfg.Cache
+		}
+		cfgDefault.Replica = cfg.Replica
+		if cfg.ReadAfterWrite > 0 {
+			cfgDefault.ReadAfterWrite = cfg.ReadAfterWrite
+		}
+		cfgDefault.Migrate = cfg.Migrate
+		cfgDefault.SearchLike = cfg.SearchLike
+		if cfg.EventBuffer > 0 {
+			cfgDefault.EventBuffer = cfg.EventBuffer
+		}
+		if cfg.Jobs != nil {
+			cfgDefault.Jobs = cfg.Jobs
+		}
+		if cfg.ReportDir != "" {
+			cfgDefault.ReportDir = cfg.ReportDir
+		}
+		if cfg.ReportWorkers > 0 {
+			cfgDefault.ReportWorkers = cfg.ReportWorkers
+		}
+		if cfg.ReportRetention > 0 {
+			cfgDefault.ReportRetention = cfg.ReportRetention
+		}
+		if cfg.ExpiryLeadTime > 0 {
+			cfgDefault.ExpiryLeadTime = cfg.ExpiryLeadTime
+		}
+		cfgDefault.AlertWebhooks = cfg.AlertWebhooks
 	}
 	return &SQLConfig{
-		Database: cfgDefault.Database,
-		Address:  cfgDefault.Address,
+		Database:        cfgDefault.Database,
+		Address:         cfgDefault.Address,
+		Cache:           cfgDefault.Cache,
+		Replica:         cfgDefault.Replica,
+		ReadAfterWrite:  cfgDefault.ReadAfterWrite,
+		Migrate:         cfgDefault.Migrate,
+		SearchLike:      cfgDefault.SearchLike,
+		EventBuffer:     cfgDefault.EventBuffer,
+		Jobs:            cfgDefault.Jobs,
+		ReportDir:       cfgDefault.ReportDir,
+		ReportWorkers:   cfgDefault.ReportWorkers,
+		ReportRetention: cfgDefault.ReportRetention,
+		ExpiryLeadTime:  cfgDefault.ExpiryLeadTime,
+		AlertWebhooks:   cfgDefault.AlertWebhooks,
+	}
+}
+
+// newCache creates and registers the cache of the entity, it returns nil when the entity is not cached
+func newCache[V any](reg *cache.Registry, cfg map[string]cache.Config, name string) *cache.LRU[int, V] {
+	c, ok := cfg[name]
+	if !ok || !c.Enabled {
+		return nil
+	}
+	lru := cache.New[int, V](c.Size, c.TTL)
+	reg.Register(name, lru)
+	return lru
+}
+
+// openReplica opens the replica connection, it returns nil when there is no replica or it can not be reached
+// so the application keeps working against the primary
+func (d *SQLConfig) openReplica() *sql.DB {
+	if d.Replica == nil {
+		return nil
+	}
+	db, err := sql.Open("mysql", d.Replica.FormatDSN())
+	if err != nil {
+		log.Println("replica disabled:", err)
+		return nil
+	}
+	if err = db.Ping(); err != nil {
+		log.Println("replica disabled:", err)
+		db.Close()
+		return nil
+	}
+	return db
+}
+
+// registerJobs adds to the scheduler the jobs that are not disabled in the configuration
+func (d *SQLConfig) registerJobs(sch *scheduler.Scheduler, jobs map[string]scheduler.Func) error {
+	for name, fn := range jobs {
+		spec, ok := d.Jobs[name]
+		if !ok {
+			spec = DefaultJobs[name]
+		}
+		if spec == "" || spec == JobOff {
+			continue
+		}
+		if err := sch.Register(name, spec, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportsCleanup returns a job that deletes the reports finished longer than retention ago
+func reportsCleanup(sv internal.ReportService, retention time.Duration) scheduler.Func {
+	return func(ctx context.Context) error {
+		deleted, err := sv.Cleanup(time.Now().Add(-retention))
+		if deleted > 0 {
+			log.Printf("reports: %d expired reports deleted", deleted)
+		}
+		return err
+	}
+}
+
+// stockReconcile returns a job that flags the batches and sections whose counters disagree with the stock ledger
+func stockReconcile(sv internal.StockMovementService) scheduler.Func {
+	return func(ctx context.Context) error {
+		found, err := sv.Reconcile()
+		if len(found) > 0 {
+			log.Printf("stock: %d counters disagree with the ledger", len(found))
+		}
+		return err
+	}
+}
+
+// sectionSnapshots returns a job that snapshots the occupancy and temperature of every section
+func sectionSnapshots(sv internal.SectionSnapshotService) scheduler.Func {
+	return func(ctx context.Context) error {
+		_, err := sv.Snapshot()
+		return err
+	}
+}
+
+// batchExpiry returns a job that expires the due batches and warns about the ones entering their lead time
+func batchExpiry(sv internal.BatchExpiryService) scheduler.Func {
+	return func(ctx context.Context) error {
+		alerts, err := sv.Sweep()
+		if len(alerts) > 0 {
+			log.Printf("expiry: %d alerts raised", len(alerts))
+		}
+		return err
+	}
+}
+
+// serve runs the server until it fails or the process is interrupted, then it gives the running requests and
+// the background workers shutdownTimeout to finish
+func serve(srv *http.Server, workers ...func(ctx context.Context) error) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// long lived requests like the event streams end when the server shuts down
+	base, cancelBase := context.WithCancel(context.Background())
+	defer cancelBase()
+	srv.BaseContext = func(net.Listener) context.Context { return base }
+	srv.RegisterOnShutdown(cancelBase)
+
+	failed := make(chan error, 1)
+	go func() { failed <- srv.ListenAndServe() }()
+	select {
+	case err := <-failed:
+		stopCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		for _, stop := range workers {
+			stop(stopCtx)
+		}
+		return err
+	case <-ctx.Done():
 	}
+	log.Println("shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := srv.Shutdown(shutdownCtx)
+	for _, stop := range workers {
+		err = errors.Join(err, stop(shutdownCtx))
+	}
+	return err
 }
 
 func (d *SQLConfig) Run() (err error) {
@@ -45,20 +305,112 @@ func (d *SQLConfig) Run() (err error) {
 	if err != nil {
 		return
 	}
+	//apply pending migrations
+	if d.Migrate {
+		applied, err := database.Migrate(db, docs.Migrations())
+		if err != nil {
+			return err
+		}
+		for _, version := range applied {
+			log.Println("migration applied:", version)
+		}
+	}
+	//open the read replica, when there is none every query goes to the primary
+	replica := d.openReplica()
+	if replica != nil {
+		defer replica.Close()
+	}
+	dbRt := database.NewRouter(db, replica, d.ReadAfterWrite)
+	dbRt.Start(replicaCheckInterval)
+	defer dbRt.Close()
+
 	// instancing repository layer
 
-	buyRepo := repo.NewBuyerRepo(db)
-	purRepo := repo.NewPurchaseOrderRepo(db)
-	empRepo := repo.NewEmployeeRepo(db)
-	inbRepo := repo.NewInboundRepo(db)
-	secRepo := repo.NewSectionRepo(db)
-	pbRepo := repo.NewProductBatchRepo(db)
-	prdRepo := repo.NewProductRepo(db)
-	prdRcRepo := repo.NewProductRecordRepo(db)
-	selRepo := repo.NewSellerRepo(db)
-	locRepo := repo.NewLocalityRepo(db)
-	wrhRepo := repo.NewWarehouseRepository(db)
-	carrRepo := repo.NewCarryRepository(db)
+	var buyRepo internal.BuyerRepository = repo.NewBuyerRepo(db)
+	var purRepo internal.PurchaseOrderRepository = repo.NewPurchaseOrderRepo(db)
+	var empRepo internal.EmployeeRepository = repo.NewEmployeeRepo(db)
+	var inbRepo internal.InboundRepository = repo.NewInboundRepo(db)
+	var secRepo internal.SectionRepository = repo.NewSectionRepo(db)
+	var pbRepo internal.ProductBatchRepository = repo.NewProductBatchRepo(db)
+	var prdRepo internal.ProductRepository = repo.NewProductRepo(db)
+	var prdRcRepo internal.ProductRecordRepository = repo.NewProductRecordRepo(db)
+	var selRepo internal.SellerRepository = repo.NewSellerRepo(db)
+	var locRepo internal.LocalityRepository = repo.NewLocalityRepo(db)
+	var wrhRepo internal.WarehouseRepository = repo.NewWarehouseRepository(db)
+	var carrRepo internal.CarryRepository = repo.NewCarryRepository(db)
+	var srchRepo internal.SearchRepository = repo.NewSearchRepo(db, !d.SearchLike)
+	var incRepo internal.IncludeRepository = repo.NewIncludeRepo(db)
+	var trfRepo internal.TransferRepository = repo.NewTransferRepo(db)
+	var stkRepo internal.StockMovementRepository = repo.NewStockMovementRepo(db)
+	var rdgRepo internal.SectionReadingRepository = repo.NewSectionReadingRepo(db)
+	var ptRepo internal.ProductTypeRepository = repo.NewProductTypeRepo(db)
+	var snpRepo internal.SectionSnapshotRepository = repo.NewSectionSnapshotRepo(db)
+	var expRepo internal.BatchExpiryRepository = repo.NewBatchExpiryRepo(db)
+	var trcRepo internal.TraceRepository = repo.NewTraceRepo(db)
+
+	// splitting reads and writes between the replica and the primary
+	if replica != nil {
+		buyRepo = repo.NewRoutedBuyerRepo(buyRepo, repo.NewBuyerRepo(replica), dbRt)
+		purRepo = repo.NewRoutedPurchaseOrderRepo(purRepo, repo.NewPurchaseOrderRepo(replica), dbRt)
+		empRepo = repo.NewRoutedEmployeeRepo(empRepo, repo.NewEmployeeRepo(replica), dbRt)
+		inbRepo = repo.NewRoutedInboundRepo(inbRepo, repo.NewInboundRepo(replica), dbRt)
+		secRepo = repo.NewRoutedSectionRepo(secRepo, repo.NewSectionRepo(replica), dbRt)
+		pbRepo = repo.NewRoutedProductBatchRepo(pbRepo, repo.NewProductBatchRepo(replica), dbRt)
+		prdRepo = repo.NewRoutedProductRepo(prdRepo, repo.NewProductRepo(replica), dbRt)
+		prdRcRepo = repo.NewRoutedProductRecordRepo(prdRcRepo, repo.NewProductRecordRepo(replica), dbRt)
+		selRepo = repo.NewRoutedSellerRepo(selRepo, repo.NewSellerRepo(replica), dbRt)
+		locRepo = repo.NewRoutedLocalityRepo(locRepo, repo.NewLocalityRepo(replica), dbRt)
+		wrhRepo = repo.NewRoutedWarehouseRepo(wrhRepo, repo.NewWarehouseRepository(replica), dbRt)
+		carrRepo = repo.NewRoutedCarryRepo(carrRepo, repo.NewCarryRepository(replica), dbRt)
+		srchRepo = repo.NewRoutedSearchRepo(srchRepo, repo.NewSearchRepo(replica, !d.SearchLike), dbRt)
+		incRepo = repo.NewRoutedIncludeRepo(incRepo, repo.NewIncludeRepo(replica), dbRt)
+		trfRepo = repo.NewRoutedTransferRepo(trfRepo, repo.NewTransferRepo(replica), dbRt)
+		stkRepo = repo.NewRoutedStockMovementRepo(stkRepo, repo.NewStockMovementRepo(replica), dbRt)
+		rdgRepo = repo.NewRoutedSectionReadingRepo(rdgRepo, repo.NewSectionReadingRepo(replica), dbRt)
+		ptRepo = repo.NewRoutedProductTypeRepo(ptRepo, repo.NewProductTypeRepo(replica), dbRt)
+		snpRepo = repo.NewRoutedSectionSnapshotRepo(snpRepo, repo.NewSectionSnapshotRepo(replica), dbRt)
+		expRepo = repo.NewRoutedBatchExpiryRepo(expRepo, repo.NewBatchExpiryRepo(replica), dbRt)
+		trcRepo = repo.NewRoutedTraceRepo(trcRepo, repo.NewTraceRepo(replica), dbRt)
+	}
+
+	// wrapping the most read repositories with read-through caches
+	caches := cache.NewRegistry()
+	if c := newCache[mod.Product](caches, d.Cache, "products"); c != nil {
+		prdRepo = repo.NewCachedProductRepo(prdRepo, c)
+	}
+	if c := newCache[mod.Seller](caches, d.Cache, "sellers"); c != nil {
+		selRepo = repo.NewCachedSellerRepo(selRepo, c)
+	}
+	if c := newCache[mod.Section](caches, d.Cache, "sections"); c != nil {
+		secRepo = repo.NewCachedSectionRepo(secRepo, c)
+		trfRepo = repo.NewCachedTransferRepo(trfRepo, c)
+		purRepo = repo.NewCachedPurchaseOrderRepo(purRepo, c)
+		stkRepo = repo.NewCachedStockMovementRepo(stkRepo, c)
+		rdgRepo = repo.NewCachedSectionReadingRepo(rdgRepo, c)
+		pbRepo = repo.NewCachedProductBatchRepo(pbRepo, c)
+	}
+	if c := newCache[mod.Warehouse](caches, d.Cache, "warehouses"); c != nil {
+		wrhRepo = repo.NewCachedWarehouseRepo(wrhRepo, c)
+	}
+
+	// publishing the committed writes to the event stream
+	broker := events.NewBroker(d.EventBuffer)
+	// the sections changed around the section repository are read back from the primary, past the cache
+	primarySections := repo.NewSectionRepo(db)
+	secRepo = repo.NewPublishedSectionRepo(secRepo, broker)
+	pbRepo = repo.NewPublishedProductBatchRepo(pbRepo, primarySections, broker)
+	stkRepo = repo.NewPublishedStockMovementRepo(stkRepo, primarySections, broker)
+	inbRepo = repo.NewPublishedInboundRepo(inbRepo, broker)
+	purRepo = repo.NewPublishedPurchaseOrderRepo(purRepo, primarySections, broker)
+	trfRepo = repo.NewPublishedTransferRepo(trfRepo, primarySections, broker)
+	rdgRepo = repo.NewPublishedSectionReadingRepo(rdgRepo, primarySections, broker)
+	expRepo = repo.NewPublishedBatchExpiryRepo(expRepo, broker)
+	trcRepo = repo.NewPublishedTraceRepo(trcRepo, broker)
+	webhooks := events.NewWebhooks(broker, d.AlertWebhooks, events.TopicAlerts)
+
+	// scheduling the recurring jobs, the leases let a single instance run each job
+	jobRepo := repo.NewJobRepo(db)
+	sch := scheduler.New(jobRepo, "")
 
 	//instancing service layer
 	buyServ := serv.NewBuyerService(buyRepo)
@@ -71,8 +423,33 @@ func (d *SQLConfig) Run() (err error) {
 	prdRcServ := serv.NewProductRecordService(prdRcRepo, prdRepo)
 	selServ := serv.NewSellerService(selRepo)
 	locServ := serv.NewLocalityService(locRepo)
-	wrhServ := serv.NewWarehouseService(wrhRepo)
+	wrhServ := serv.NewWarehouseService(wrhRepo, locRepo)
 	carrServ := serv.NewCarryService(carrRepo)
+	srchServ := serv.NewSearchService(srchRepo)
+	incServ := serv.NewIncludeService(incRepo)
+	trfServ := serv.NewTransferService(trfRepo)
+	stkServ := serv.NewStockMovementService(stkRepo)
+	rdgServ := serv.NewSectionReadingService(rdgRepo, secRepo)
+	ptServ := serv.NewProductTypeService(ptRepo)
+	snpServ := serv.NewSectionSnapshotService(snpRepo, secRepo)
+	expServ := serv.NewBatchExpiryService(expRepo, d.ExpiryLeadTime)
+	trcServ := serv.NewTraceService(trcRepo)
+	repServ, err := serv.NewReportService(d.ReportDir, d.ReportWorkers,
+		serv.NewReportGenerators(buyServ, carrServ, locServ, wrhServ, secServ, inbServ, prdRcServ))
+	if err != nil {
+		return err
+	}
+
+	err = d.registerJobs(sch, map[string]scheduler.Func{
+		"runs_cleanup":      scheduler.RunsCleanup(jobRepo, jobRunsRetention),
+		"reports_cleanup":   reportsCleanup(repServ, d.ReportRetention),
+		"stock_reconcile":   stockReconcile(stkServ),
+		"section_snapshots": sectionSnapshots(snpServ),
+		"batch_expiry":      batchExpiry(expServ),
+	})
+	if err != nil {
+		return err
+	}
 
 	//instancing handler layer
 	buyHand := hand.NewBuyerHandler(buyServ)
@@ -87,6 +464,19 @@ func (d *SQLConfig) Run() (err error) {
 	locHand := hand.NewLocalityHandler(locServ)
 	wrhHand := hand.NewWarehouseHandler(wrhServ)
 	carrHand := hand.NewCarryHandler(carrServ)
+	metHand := hand.NewMetricsHandler(caches)
+	srchHand := hand.NewSearchHandler(srchServ)
+	incHand := hand.NewIncludeHandler(incServ)
+	trfHand := hand.NewTransferHandler(trfServ)
+	stkHand := hand.NewStockMovementHandler(stkServ)
+	rdgHand := hand.NewSectionReadingHandler(rdgServ)
+	ptHand := hand.NewProductTypeHandler(ptServ)
+	snpHand := hand.NewSectionSnapshotHandler(snpServ)
+	expHand := hand.NewBatchExpiryHandler(expServ)
+	trcHand := hand.NewTraceHandler(trcServ)
+	repHand := hand.NewReportHandler(repServ)
+	evHand := hand.NewEventHandler(broker, eventHeartbeat)
+	jobHand := hand.NewJobHandler(sch)
 
 	//routing
 
@@ -96,10 +486,16 @@ func (d *SQLConfig) Run() (err error) {
 	rt.Use(middleware.Recoverer)
 
 	//Routing
+	// - metrics
+	rt.Get("/v1/metrics/cache", metHand.GetCacheStats())
+
+	// - search
+	rt.Get("/v1/search", srchHand.Search())
+
 	// - sellers
 	rt.Route("/v1/sellers", func(rt chi.Router) {
-		rt.Get("/", selHand.GetAll())
-		rt.Get("/{id}", selHand.GetByID())
+		rt.Get("/", incHand.Wrap("sellers", selHand.GetAll()))
+		rt.Get("/{id}", incHand.Wrap("sellers", selHand.GetByID()))
 		rt.Post("/", selHand.Create())
 		rt.Patch("/{id}", selHand.Update())
 		rt.Delete("/{id}", selHand.Delete())
@@ -108,12 +504,38 @@ func (d *SQLConfig) Run() (err error) {
 	//// - warehouses
 	rt.Route("/v1/warehouses", func(r chi.Router) {
 		r.Get("/", wrhHand.GetAll())
+		r.Get("/reportCapacity", wrhHand.ReportCapacity())
+		r.Get("/nearest", wrhHand.Nearest())
 		r.Get("/{id}", wrhHand.GetByID())
+		r.Get("/{id}/overview", wrhHand.Overview())
+		r.Get("/{id}/sections", wrhHand.GetSections())
+		r.Get("/{id}/employees", wrhHand.GetEmployees())
 		r.Post("/", wrhHand.Create())
 		r.Put("/{id}", wrhHand.Update())
 		r.Delete("/{id}", wrhHand.Delete())
 	})
 
+	// - events
+	rt.Route("/v1/events", func(rt chi.Router) {
+		rt.Get("/stream", evHand.Stream())
+	})
+
+	// - jobs
+	rt.Route("/v1/jobs", func(rt chi.Router) {
+		rt.Get("/", jobHand.GetAll())
+		rt.Get("/{name}/runs", jobHand.GetRuns())
+		rt.Post("/{name}/trigger", jobHand.Trigger())
+		rt.Post("/{name}/pause", jobHand.Pause())
+		rt.Post("/{name}/resume", jobHand.Resume())
+	})
+
+	// - reports
+	rt.Route("/v1/reports", func(rt chi.Router) {
+		rt.Post("/{type}", repHand.Create())
+		rt.Get("/jobs/{id}", repHand.GetJob())
+		rt.Get("/jobs/{id}/download", repHand.Download())
+	})
+
 	/// - Carries
 	rt.Route("/v1/carries", func(r chi.Router) {
 		r.Post("/", carrHand.Create()) // Crea un nuevo carry
@@ -122,31 +544,74 @@ func (d *SQLConfig) Run() (err error) {
 	// - sections
 
 	rt.Route("/v1/sections", func(rt chi.Router) {
-		rt.Get("/", secHand.GetAll())
-		rt.Get("/{id}", secHand.GetByID())
+		rt.Get("/", incHand.Wrap("sections", secHand.GetAll()))
+		rt.Get("/{id}", incHand.Wrap("sections", secHand.GetByID()))
 		rt.Delete("/{id}", secHand.Delete())
 		rt.Post("/", secHand.Create())
 		rt.Patch("/{id}", secHand.Update())
 		rt.Get("/reportProducts", secHand.ReportProducts())
+		rt.Get("/recommend", secHand.Recommend())
+		rt.Post("/{id}/readings", rdgHand.Create())
+		rt.Get("/{id}/readings", rdgHand.GetReadings())
+		rt.Get("/{id}/readings/rollups", rdgHand.GetRollups())
+		rt.Get("/{id}/alerts", rdgHand.GetAlerts())
+		rt.Get("/{id}/history", snpHand.GetHistory())
+	})
+
+	// - product types
+	rt.Route("/v1/productTypes", func(rt chi.Router) {
+		rt.Get("/", ptHand.GetAll())
+		rt.Get("/{id}", ptHand.GetByID())
+		rt.Post("/", ptHand.Create())
+		rt.Patch("/{id}", ptHand.Update())
+		rt.Delete("/{id}", ptHand.Delete())
 	})
 
 	rt.Route("/v1/productBatches", func(rt chi.Router) {
-		rt.Get("/", pbHand.GetAll())
+		rt.Get("/", incHand.Wrap("batches", pbHand.GetAll()))
+		rt.Get("/expiring", expHand.GetExpiring())
+		rt.Get("/{id}", pbHand.GetByID())
 		rt.Post("/", pbHand.Create())
+		rt.Patch("/{id}", pbHand.Update())
+		rt.Post("/{id}/archive", pbHand.Archive())
+		rt.Post("/{id}/hold", pbHand.Hold())
+		rt.Post("/{id}/release", pbHand.Release())
+		rt.Get("/{id}/statusHistory", pbHand.GetStatusHistory())
+		rt.Delete("/{id}", pbHand.Delete())
+		rt.Get("/{id}/movements", stkHand.GetBatchMovements())
+		rt.Post("/{id}/adjustments", stkHand.Adjust())
+	})
+
+	// - alerts
+	rt.Get("/v1/alerts", expHand.GetAlerts())
+
+	// - lot traceability and recalls
+	rt.Route("/v1/trace", func(rt chi.Router) {
+		rt.Get("/batch/{id}", trcHand.GetBatchTrace())
+		rt.Get("/purchaseOrder/{id}", trcHand.GetPurchaseOrderTrace())
+	})
+	rt.Route("/v1/recalls", func(rt chi.Router) {
+		rt.Post("/", trcHand.CreateRecall())
+		rt.Get("/{id}", trcHand.GetRecall())
 	})
 
+	// - stock ledger
+	rt.Get("/v1/stock/discrepancies", stkHand.GetDiscrepancies())
+
 	// - localities
 	rt.Route("/v1/localities", func(rt chi.Router) {
 		rt.Post("/", locHand.Create())
 		rt.Get("/", locHand.GetAll())
 		rt.Get("/reportSellers", locHand.GetSelByLocID())
 		rt.Get("/reportCarries", carrHand.GetReportByLocality())
+		rt.Get("/reportWarehouses", locHand.GetWhByLocID())
+		rt.Get("/overview", locHand.GetOverview())
 	})
 
 	// - products
 	rt.Route("/v1/products", func(rt chi.Router) {
-		rt.Get("/", prdHand.GetAll())
-		rt.Get("/{id}", prdHand.GetByID())
+		rt.Get("/", incHand.Wrap("products", prdHand.GetAll()))
+		rt.Get("/{id}", incHand.Wrap("products", prdHand.GetByID()))
 		rt.Post("/", prdHand.Create())
 		rt.Patch("/{id}", prdHand.Update())
 		rt.Delete("/{id}", prdHand.Delete())
@@ -160,9 +625,9 @@ func (d *SQLConfig) Run() (err error) {
 
 	// - employees
 	rt.Route("/v1/employees", func(rt chi.Router) {
-		rt.Get("/", empHand.GetAll())
+		rt.Get("/", incHand.Wrap("employees", empHand.GetAll()))
 		rt.Get("/reportInboundOrders", inbHand.GetOrdersByEmployee())
-		rt.Get("/{id}", empHand.GetById())
+		rt.Get("/{id}", incHand.Wrap("employees", empHand.GetById()))
 		rt.Post("/", empHand.Create())
 		rt.Patch("/{id}", empHand.Edit())
 		rt.Delete("/{id}", empHand.Delete())
@@ -171,9 +636,18 @@ func (d *SQLConfig) Run() (err error) {
 	rt.Route("/v1/inboundOrders", func(rt chi.Router) {
 		rt.Post("/", inbHand.Create())
 	})
+
+	// - transfers
+	rt.Route("/v1/transfers", func(rt chi.Router) {
+		rt.Post("/", trfHand.Create())
+		rt.Get("/", trfHand.GetAll())
+		rt.Get("/{id}", trfHand.GetByID())
+	})
 	//
 	//// - buyers
 	rt.Route("/v1/purchaseOrders", func(rt chi.Router) {
+		rt.Get("/", incHand.Wrap("purchaseOrders", purHand.GetAll()))
+		rt.Get("/{id}", incHand.Wrap("purchaseOrders", purHand.GetByID()))
 		rt.Post("/", purHand.Create())
 	})
 
@@ -187,9 +661,12 @@ func (d *SQLConfig) Run() (err error) {
 	})
 
 	//run
-	err = http.ListenAndServe(d.Address, rt)
-	if err != nil {
-		return err
+	sch.Start()
+	repServ.Start()
+	webhooks.Start()
+	err = serve(&http.Server{Addr: d.Address, Handler: rt}, sch.Stop, repServ.Stop, webhooks.Stop)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
 	}
-	return nil
+	return err
 }
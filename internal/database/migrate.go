@@ -0,0 +1,92 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// migrationsTable keeps the versions already applied
+const migrationsTable = "CREATE TABLE IF NOT EXISTS `schema_migrations` (`version` VARCHAR(255) NOT NULL PRIMARY KEY, `applied_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)"
+
+// Migrate applies, in name order, the .sql files of fsys that are not in schema_migrations yet and returns the applied versions.
+// MySQL commits DDL implicitly, so a failing migration is not recorded and must be fixed by hand before running again
+func Migrate(db *sql.DB, fsys fs.FS) (applied []string, err error) {
+	if _, err = db.Exec(migrationsTable); err != nil {
+		return nil, err
+	}
+	done, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		version := strings.TrimSuffix(name, ".sql")
+		if done[version] {
+			continue
+		}
+		script, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return applied, err
+		}
+		for _, stmt := range SplitStatements(string(script)) {
+			if _, err = db.Exec(stmt); err != nil {
+				return applied, errors.Join(fmt.Errorf("migration %s failed", version), err)
+			}
+		}
+		if _, err = db.Exec("INSERT INTO `schema_migrations` (`version`) VALUES (?)", version); err != nil {
+			return applied, err
+		}
+		applied = append(applied, version)
+	}
+	return applied, nil
+}
+
+// appliedVersions returns the versions recorded in schema_migrations
+func appliedVersions(db *sql.DB) (map[string]bool, error) {
+	rows, err := db.Query("SELECT `version` FROM `schema_migrations`")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	done := make(map[string]bool)
+	for rows.Next() {
+		var version string
+		if err = rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		done[version] = true
+	}
+	return done, rows.Err()
+}
+
+// SplitStatements splits a script on the semicolons that end a line, dropping comment lines and empty statements
+func SplitStatements(script string) (stmts []string) {
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			if stmt := strings.TrimSuffix(strings.TrimSpace(current.String()), ";"); stmt != "" {
+				stmts = append(stmts, stmt)
+			}
+			current.Reset()
+		}
+	}
+	if stmt := strings.TrimSpace(current.String()); stmt != "" {
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
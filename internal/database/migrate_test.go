@@ -0,0 +1,57 @@
+package database
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0001_first.sql":  {Data: []byte("-- first\nCREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n")},
+		"0002_second.sql": {Data: []byte("ALTER TABLE a\n  ADD COLUMN name TEXT;\n")},
+		"README.md":       {Data: []byte("not a migration")},
+	}
+
+	t.Run("applies the pending migrations in order", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(migrationsTable)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT `version` FROM `schema_migrations`").
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("0001_first"))
+		mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE a\n  ADD COLUMN name TEXT")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `schema_migrations` (`version`) VALUES (?)")).
+			WithArgs("0002_second").WillReturnResult(sqlmock.NewResult(0, 1))
+
+		applied, err := Migrate(db, fsys)
+		require.NoError(t, err)
+		require.Equal(t, []string{"0002_second"}, applied)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stops on the failing migration", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta(migrationsTable)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT `version` FROM `schema_migrations`").WillReturnRows(sqlmock.NewRows([]string{"version"}))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE a (id INT)")).WillReturnError(errors.New("table exists"))
+
+		applied, err := Migrate(db, fsys)
+		require.ErrorContains(t, err, "migration 0001_first failed")
+		require.Empty(t, applied)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSplitStatements(t *testing.T) {
+	stmts := SplitStatements("-- comment\nCREATE TABLE a (id INT);\n\nINSERT INTO a\nVALUES (1);\nSELECT 1")
+	require.Equal(t, []string{"CREATE TABLE a (id INT)", "INSERT INTO a\nVALUES (1)", "SELECT 1"}, stmts)
+}
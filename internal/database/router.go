@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NewRouter creates a router over the primary connection and an optional replica, a nil replica sends everything to the primary
+func NewRouter(primary, replica *sql.DB, readAfterWrite time.Duration) *Router {
+	rt := &Router{
+		primary:        primary,
+		replica:        replica,
+		readAfterWrite: readAfterWrite,
+		now:            time.Now,
+		stop:           make(chan struct{}),
+	}
+	rt.healthy.Store(replica != nil)
+	return rt
+}
+
+// Router decides which connection serves each query: writes always go to the primary while reads go to the
+// replica as long as it is healthy and no write happened within the read-after-write window. The window is
+// shared by the whole process, so the reports and searches, which tolerate the replication lag, skip it with
+// RouteStale and keep the replica busy under a steady stream of writes
+type Router struct {
+	primary *sql.DB
+	replica *sql.DB
+	// readAfterWrite is how long reads stay on the primary after a write so clients see their own changes
+	readAfterWrite time.Duration
+	healthy        atomic.Bool
+	lastWrite      atomic.Int64
+	now            func() time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// Primary returns the connection used for writes
+func (r *Router) Primary() *sql.DB {
+	return r.primary
+}
+
+// Replica returns the replica connection, it is nil when no replica was configured
+func (r *Router) Replica() *sql.DB {
+	return r.replica
+}
+
+// UseReplica tells if the next read can be served by the replica
+func (r *Router) UseReplica() bool {
+	if r.replica == nil || !r.healthy.Load() {
+		return false
+	}
+	last := r.lastWrite.Load()
+	return last == 0 || r.now().Sub(time.Unix(0, last)) >= r.readAfterWrite
+}
+
+// Reader returns the connection that must serve the next read
+func (r *Router) Reader() *sql.DB {
+	if r.UseReplica() {
+		return r.replica
+	}
+	return r.primary
+}
+
+// MarkWrite records that a write was committed on the primary
+func (r *Router) MarkWrite() {
+	r.lastWrite.Store(r.now().UnixNano())
+}
+
+// Healthy tells if the replica answered the last health check
+func (r *Router) Healthy() bool {
+	return r.healthy.Load()
+}
+
+// Check pings the replica and updates its health
+func (r *Router) Check(timeout time.Duration) bool {
+	if r.replica == nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	ok := r.replica.PingContext(ctx) == nil
+	r.healthy.Store(ok)
+	return ok
+}
+
+// Start checks the replica health every interval until Close is called
+func (r *Router) Start(interval time.Duration) {
+	if r.replica == nil {
+		return
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.Check(interval)
+			}
+		}
+	}()
+}
+
+// Close stops the health checks
+func (r *Router) Close() {
+	r.stopOnce.Do(func() { close(r.stop) })
+	r.wg.Wait()
+}
+
+// Route returns the replica implementation when reads can go to the replica, otherwise the primary one
+func Route[T any](r *Router, primary, replica T) T {
+	if r.UseReplica() {
+		return replica
+	}
+	return primary
+}
+
+// RouteStale returns the replica implementation whenever the replica is healthy, even within the
+// read-after-write window. It is meant for the reads that do not need to see the latest writes
+func RouteStale[T any](r *Router, primary, replica T) T {
+	if r.replica != nil && r.healthy.Load() {
+		return replica
+	}
+	return primary
+}
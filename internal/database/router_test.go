@@ -0,0 +1,62 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_Reader(t *testing.T) {
+	primary, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer primary.Close()
+	replica, replicaMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer replica.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rt := NewRouter(primary, replica, 2*time.Second)
+	rt.now = func() time.Time { return now }
+
+	t.Run("reads go to the replica", func(t *testing.T) {
+		require.Same(t, replica, rt.Reader())
+		require.Same(t, primary, rt.Primary())
+	})
+
+	t.Run("reads stay on the primary right after a write", func(t *testing.T) {
+		rt.MarkWrite()
+		require.Same(t, primary, rt.Reader())
+		require.Equal(t, "replica", RouteStale(rt, "primary", "replica"))
+		now = now.Add(3 * time.Second)
+		require.Same(t, replica, rt.Reader())
+	})
+
+	t.Run("unhealthy replica falls back to the primary", func(t *testing.T) {
+		replicaMock.ExpectPing().WillReturnError(errors.New("connection refused"))
+		require.False(t, rt.Check(time.Second))
+		require.Same(t, primary, rt.Reader())
+
+		replicaMock.ExpectPing()
+		require.True(t, rt.Check(time.Second))
+		require.Same(t, replica, rt.Reader())
+		require.NoError(t, replicaMock.ExpectationsWereMet())
+	})
+}
+
+func TestRouter_WithoutReplica(t *testing.T) {
+	primary, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer primary.Close()
+
+	rt := NewRouter(primary, nil, time.Second)
+	rt.Start(time.Millisecond)
+	defer rt.Close()
+
+	require.False(t, rt.UseReplica())
+	require.Same(t, primary, rt.Reader())
+	require.Equal(t, "primary", Route(rt, "primary", "replica"))
+	require.Equal(t, "primary", RouteStale(rt, "primary", "replica"))
+}
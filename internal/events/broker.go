@@ -0,0 +1,139 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Topics the application publishes to
+const (
+	TopicSections       = "sections"
+	TopicProductBatches = "productBatches"
+	TopicInboundOrders  = "inboundOrders"
+	TopicPurchaseOrders = "purchaseOrders"
+	TopicAlerts         = "alerts"
+)
+
+// Topics lists every topic a client can subscribe to
+var Topics = []string{TopicSections, TopicProductBatches, TopicInboundOrders, TopicPurchaseOrders, TopicAlerts}
+
+// subscriberBuffer is how many events a subscriber can fall behind before it is dropped
+const subscriberBuffer = 64
+
+// Event is a change committed to the database
+type Event struct {
+	ID    int64     `json:"id"`
+	Topic string    `json:"topic"`
+	Type  string    `json:"type"`
+	Time  time.Time `json:"time"`
+	Data  any       `json:"data"`
+}
+
+// NewBroker creates a broker that keeps the last size events so clients can resume from them
+func NewBroker(size int) *Broker {
+	if size <= 0 {
+		size = 1
+	}
+	return &Broker{
+		ring: make([]Event, size),
+		subs: make(map[*Subscription]struct{}),
+		now:  time.Now,
+	}
+}
+
+// Broker fans the published events out to the subscribers and keeps a bounded ring buffer of the latest ones
+type Broker struct {
+	mu     sync.Mutex
+	ring   []Event
+	next   int
+	count  int
+	lastID int64
+	subs   map[*Subscription]struct{}
+	now    func() time.Time
+}
+
+// Subscription receives the events of the topics it subscribed to. Its channel is closed when the
+// subscriber falls too far behind or unsubscribes, a dropped client can resume with its last event id
+type Subscription struct {
+	C      chan Event
+	topics map[string]bool
+}
+
+// accepts tells if the subscription listens to the topic, no topics means every topic
+func (s *Subscription) accepts(topic string) bool {
+	return len(s.topics) == 0 || s.topics[topic]
+}
+
+// Publish stores the event in the buffer and sends it to every subscriber of its topic
+func (b *Broker) Publish(topic, kind string, data any) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastID++
+	ev := Event{
+		ID:    b.lastID,
+		Topic: topic,
+		Type:  kind,
+		Time:  b.now(),
+		Data:  data,
+	}
+	b.ring[b.next] = ev
+	b.next = (b.next + 1) % len(b.ring)
+	if b.count < len(b.ring) {
+		b.count++
+	}
+	for sub := range b.subs {
+		if !sub.accepts(topic) {
+			continue
+		}
+		select {
+		case sub.C <- ev:
+		default:
+			delete(b.subs, sub)
+			close(sub.C)
+		}
+	}
+	return ev
+}
+
+// Subscribe registers a subscriber for the topics and returns the buffered events published after lastID,
+// so nothing is lost between the replay and the live events
+func (b *Broker) Subscribe(topics []string, lastID int64) (*Subscription, []Event) {
+	sub := &Subscription{
+		C:      make(chan Event, subscriberBuffer),
+		topics: make(map[string]bool, len(topics)),
+	}
+	for _, t := range topics {
+		sub.topics[t] = true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var replay []Event
+	if lastID > 0 {
+		start := (b.next - b.count + len(b.ring)) % len(b.ring)
+		for i := 0; i < b.count; i++ {
+			ev := b.ring[(start+i)%len(b.ring)]
+			if ev.ID > lastID && sub.accepts(ev.Topic) {
+				replay = append(replay, ev)
+			}
+		}
+	}
+	b.subs[sub] = struct{}{}
+	return sub, replay
+}
+
+// Unsubscribe removes the subscriber and closes its channel
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.C)
+	}
+}
+
+// Subscribers returns how many clients are listening
+func (b *Broker) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
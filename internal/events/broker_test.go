@@ -0,0 +1,62 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker_Publish(t *testing.T) {
+	t.Run("subscribers receive only their topics", func(t *testing.T) {
+		b := NewBroker(10)
+		sections, _ := b.Subscribe([]string{TopicSections}, 0)
+		all, _ := b.Subscribe(nil, 0)
+
+		b.Publish(TopicProductBatches, "productBatch.created", 1)
+		b.Publish(TopicSections, "section.updated", 2)
+
+		ev := <-sections.C
+		require.Equal(t, int64(2), ev.ID)
+		require.Equal(t, "section.updated", ev.Type)
+		require.Len(t, sections.C, 0)
+		require.Len(t, all.C, 2)
+	})
+
+	t.Run("slow subscribers are dropped", func(t *testing.T) {
+		b := NewBroker(10)
+		sub, _ := b.Subscribe(nil, 0)
+		for i := 0; i <= subscriberBuffer; i++ {
+			b.Publish(TopicSections, "section.updated", i)
+		}
+		require.Equal(t, 0, b.Subscribers())
+		for range sub.C {
+		}
+		b.Unsubscribe(sub)
+	})
+}
+
+func TestBroker_Subscribe(t *testing.T) {
+	b := NewBroker(3)
+	for i := 1; i <= 5; i++ {
+		b.Publish(TopicSections, "section.updated", i)
+	}
+	b.Publish(TopicInboundOrders, "inboundOrder.created", 6)
+
+	t.Run("replays the buffered events after the last id", func(t *testing.T) {
+		_, replay := b.Subscribe([]string{TopicSections}, 3)
+		require.Len(t, replay, 2)
+		require.Equal(t, int64(4), replay[0].ID)
+		require.Equal(t, int64(5), replay[1].ID)
+	})
+
+	t.Run("events older than the buffer are lost", func(t *testing.T) {
+		_, replay := b.Subscribe(nil, 1)
+		require.Len(t, replay, 3)
+		require.Equal(t, int64(4), replay[0].ID)
+	})
+
+	t.Run("no last id means no replay", func(t *testing.T) {
+		_, replay := b.Subscribe(nil, 0)
+		require.Empty(t, replay)
+	})
+}
@@ -0,0 +1,168 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// webhookTimeout is how long a webhook has to answer a delivery
+	webhookTimeout = 5 * time.Second
+	// webhookAttempts is how many times a delivery is tried before it is dropped
+	webhookAttempts = 3
+	// webhookBackoff is the wait before the first retry, it doubles on every retry
+	webhookBackoff = time.Second
+)
+
+// NewWebhooks creates a dispatcher that posts the events of the topics to every url
+func NewWebhooks(broker *Broker, urls []string, topics ...string) *Webhooks {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Webhooks{
+		broker:  broker,
+		urls:    urls,
+		topics:  topics,
+		client:  &http.Client{Timeout: webhookTimeout},
+		backoff: webhookBackoff,
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+}
+
+// Webhooks pushes the events of its topics to external endpoints as JSON, one POST per event and url. A delivery
+// answered with other than 2xx is retried webhookAttempts times and then dropped, the events stay available in
+// the event stream
+type Webhooks struct {
+	broker  *Broker
+	urls    []string
+	topics  []string
+	client  *http.Client
+	backoff time.Duration
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu  sync.Mutex
+	sub *Subscription
+}
+
+// Start subscribes to the broker and delivers the events in the background, it does nothing without urls
+func (w *Webhooks) Start() {
+	if len(w.urls) == 0 {
+		close(w.done)
+		return
+	}
+	w.subscribe(0)
+	go w.run()
+}
+
+// Stop stops the deliveries and waits for the running one to finish or for ctx to be done
+func (w *Webhooks) Stop(ctx context.Context) error {
+	w.cancel()
+	w.mu.Lock()
+	if w.sub != nil {
+		w.broker.Unsubscribe(w.sub)
+	}
+	w.mu.Unlock()
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// subscribe replaces the subscription, the events published after lastID are delivered first
+func (w *Webhooks) subscribe(lastID int64) []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	sub, replay := w.broker.Subscribe(w.topics, lastID)
+	w.sub = sub
+	return replay
+}
+
+// run delivers the events until Stop, a subscription dropped by the broker for falling behind resumes from the
+// last event delivered
+func (w *Webhooks) run() {
+	defer close(w.done)
+	var lastID int64
+	var pending []Event
+	for {
+		w.mu.Lock()
+		sub := w.sub
+		w.mu.Unlock()
+		for _, ev := range pending {
+			w.deliverAll(ev)
+			lastID = ev.ID
+		}
+		for ev := range sub.C {
+			w.deliverAll(ev)
+			lastID = ev.ID
+		}
+		if w.ctx.Err() != nil {
+			return
+		}
+		pending = w.subscribe(lastID)
+	}
+}
+
+// deliverAll posts the event to every url
+func (w *Webhooks) deliverAll(ev Event) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		log.Printf("webhooks: event %d: %v", ev.ID, err)
+		return
+	}
+	for _, url := range w.urls {
+		if err := w.deliver(url, ev, body); err != nil {
+			log.Printf("webhooks: event %d to %s dropped: %v", ev.ID, url, err)
+		}
+	}
+}
+
+// deliver posts the event to the url, retrying with a growing backoff
+func (w *Webhooks) deliver(url string, ev Event, body []byte) (err error) {
+	wait := w.backoff
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		if err = w.post(url, ev, body); err == nil {
+			return nil
+		}
+		if attempt == webhookAttempts {
+			break
+		}
+		select {
+		case <-time.After(wait):
+			wait *= 2
+		case <-w.ctx.Done():
+			return w.ctx.Err()
+		}
+	}
+	return err
+}
+
+// post sends one delivery, the event id and type go in the headers so receivers can drop duplicates
+func (w *Webhooks) post(url string, ev Event, body []byte) error {
+	req, err := http.NewRequestWithContext(w.ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatInt(ev.ID, 10))
+	req.Header.Set("X-Event-Type", ev.Type)
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("status %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks(t *testing.T) {
+	t.Run("posts the events of its topics and retries the failed deliveries", func(t *testing.T) {
+		var calls atomic.Int32
+		received := make(chan Event, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			require.Equal(t, "alert.raised", r.Header.Get("X-Event-Type"))
+			var ev Event
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&ev))
+			received <- ev
+		}))
+		defer srv.Close()
+
+		b := NewBroker(10)
+		wh := NewWebhooks(b, []string{srv.URL}, TopicAlerts)
+		wh.backoff = time.Millisecond
+		wh.Start()
+		b.Publish(TopicSections, "section.updated", 1)
+		b.Publish(TopicAlerts, "alert.raised", map[string]int{"id": 3})
+
+		select {
+		case ev := <-received:
+			require.Equal(t, int64(2), ev.ID)
+			require.Equal(t, TopicAlerts, ev.Topic)
+		case <-time.After(2 * time.Second):
+			t.Fatal("the alert was not delivered")
+		}
+		require.Equal(t, int32(2), calls.Load())
+		require.NoError(t, wh.Stop(context.Background()))
+		require.Equal(t, 0, b.Subscribers())
+	})
+
+	t.Run("without urls it does not subscribe", func(t *testing.T) {
+		b := NewBroker(10)
+		wh := NewWebhooks(b, nil, TopicAlerts)
+		wh.Start()
+		require.Equal(t, 0, b.Subscribers())
+		require.NoError(t, wh.Stop(context.Background()))
+	})
+}
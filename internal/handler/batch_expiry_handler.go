@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// expiringMaxDays bounds the window of the expiring batches
+const expiringMaxDays = 365
+
+// NewBatchExpiryHandler creates a new instance of the batch expiry handler
+func NewBatchExpiryHandler(sv internal.BatchExpiryService) *BatchExpiryHandler {
+	return &BatchExpiryHandler{
+		sv: sv,
+	}
+}
+
+// BatchExpiryHandler serves the batches close to their due date and the alerts of the expiry sweep
+type BatchExpiryHandler struct {
+	// sv is the service used by the handler
+	sv internal.BatchExpiryService
+}
+
+// GetExpiring returns the batches due in the next days, or within the lead time of their product without days,
+// e.g. ?days=3&warehouse_id=2
+func (h *BatchExpiryHandler) GetExpiring() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		days, err := positiveQueryInt(r, "days")
+		if err != nil || days > expiringMaxDays {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrExpiringQueryInvalid.Error())
+			return
+		}
+		warehouseID, err := positiveQueryInt(r, "warehouse_id")
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrExpiringQueryInvalid.Error())
+			return
+		}
+		batches, err := h.sv.FindExpiring(days, warehouseID)
+		if err != nil {
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, batches)
+	}
+}
+
+// GetAlerts returns the alerts filtered by type, warehouse_id, seller_id, from and to, the newest first
+func (h *BatchExpiryHandler) GetAlerts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := alertFilter(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		alerts, err := h.sv.FindAlerts(filter)
+		if err != nil {
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, alerts)
+	}
+}
+
+// positiveQueryInt reads an optional query parameter that must be an integer greater than 0, it is 0 when missing
+func positiveQueryInt(r *http.Request, name string) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		return 0, e.ErrRequestIdMustBeInt
+	}
+	return n, nil
+}
+
+// alertFilter reads the filters of the alerts
+func alertFilter(r *http.Request) (mod.AlertFilter, error) {
+	query := r.URL.Query()
+	f := mod.AlertFilter{Type: query.Get("type")}
+	if f.Type != "" && !slices.Contains(mod.AlertTypes, f.Type) {
+		return mod.AlertFilter{}, e.ErrAlertFilterInvalid
+	}
+	var err error
+	if f.WarehouseID, err = positiveQueryInt(r, "warehouse_id"); err != nil {
+		return mod.AlertFilter{}, e.ErrAlertFilterInvalid
+	}
+	if f.SellerID, err = positiveQueryInt(r, "seller_id"); err != nil {
+		return mod.AlertFilter{}, e.ErrAlertFilterInvalid
+	}
+	for name, dst := range map[string]*time.Time{"from": &f.From, "to": &f.To} {
+		raw := query.Get(name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return mod.AlertFilter{}, e.ErrAlertFilterInvalid
+		}
+		*dst = t.UTC()
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return mod.AlertFilter{}, e.ErrAlertFilterInvalid
+	}
+	return f, nil
+}
@@ -0,0 +1,108 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	hd "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/handler"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	tests2 "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchExpiryHandler_GetExpiring(t *testing.T) {
+	due := time.Date(2024, 7, 5, 17, 0, 0, 0, time.UTC)
+
+	t.Run("#1 Success", func(t *testing.T) {
+		mockService := new(tests2.MockBatchExpiryService)
+		mockService.On("FindExpiring", 3, 2).Return([]mod.ExpiringBatch{{ProductBatchID: 1, BatchNumber: 10, ProductID: 4, SellerID: 5,
+			SectionID: 6, WarehouseID: 2, CurrentQuantity: 20, DueDate: due, HoursLeft: 30.5}}, nil).Once()
+		handler := hd.NewBatchExpiryHandler(mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/productBatches/expiring?days=3&warehouse_id=2", nil)
+		rr := httptest.NewRecorder()
+		handler.GetExpiring().ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"success":true,"message":"handler: data retrieved successfully","data":[{"product_batch_id":1,"batch_number":10,`+
+			`"product_id":4,"seller_id":5,"section_id":6,"warehouse_id":2,"current_quantity":20,"due_date":"2024-07-05T17:00:00Z","hours_left":30.5}]}`,
+			rr.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("#2 Success - Lead time of the products", func(t *testing.T) {
+		mockService := new(tests2.MockBatchExpiryService)
+		mockService.On("FindExpiring", 0, 0).Return([]mod.ExpiringBatch{}, nil).Once()
+		handler := hd.NewBatchExpiryHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.GetExpiring().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/productBatches/expiring", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	for _, query := range []string{"days=0", "days=366", "days=x", "warehouse_id=-1"} {
+		t.Run("#3 Fail - Invalid "+query, func(t *testing.T) {
+			mockService := new(tests2.MockBatchExpiryService)
+			handler := hd.NewBatchExpiryHandler(mockService)
+
+			rr := httptest.NewRecorder()
+			handler.GetExpiring().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/productBatches/expiring?"+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Contains(t, rr.Body.String(), e.ErrExpiringQueryInvalid.Error())
+			mockService.AssertNotCalled(t, "FindExpiring")
+		})
+	}
+
+	t.Run("#4 Fail - Internal error", func(t *testing.T) {
+		mockService := new(tests2.MockBatchExpiryService)
+		mockService.On("FindExpiring", 0, 0).Return([]mod.ExpiringBatch(nil), e.ErrQueryError).Once()
+		handler := hd.NewBatchExpiryHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.GetExpiring().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/productBatches/expiring", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Contains(t, rr.Body.String(), e.ErrRequestInternalServer.Error())
+	})
+}
+
+func TestBatchExpiryHandler_GetAlerts(t *testing.T) {
+	at := time.Date(2024, 7, 5, 10, 0, 0, 0, time.UTC)
+
+	t.Run("#1 Success", func(t *testing.T) {
+		filter := mod.AlertFilter{Type: mod.AlertBatchesExpired, WarehouseID: 2, SellerID: 5, From: at, To: at.Add(time.Hour)}
+		mockService := new(tests2.MockBatchExpiryService)
+		mockService.On("FindAlerts", filter).Return([]mod.Alert{{ID: 3, Type: mod.AlertBatchesExpired, WarehouseID: 2, SellerID: 5,
+			Quantity: 40, ProductBatchIDs: []int{1, 7}, CreatedAt: at}}, nil).Once()
+		handler := hd.NewBatchExpiryHandler(mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/alerts?type=batches_expired&warehouse_id=2&seller_id=5&from=2024-07-05T10:00:00Z&to=2024-07-05T11:00:00Z", nil)
+		rr := httptest.NewRecorder()
+		handler.GetAlerts().ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"success":true,"message":"handler: data retrieved successfully","data":[{"id":3,"type":"batches_expired",`+
+			`"warehouse_id":2,"seller_id":5,"quantity":40,"product_batch_ids":[1,7],"created_at":"2024-07-05T10:00:00Z"}]}`, rr.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	for _, query := range []string{"type=stock", "seller_id=0", "from=yesterday", "from=2024-07-05T11:00:00Z&to=2024-07-05T10:00:00Z"} {
+		t.Run("#2 Fail - Invalid "+query, func(t *testing.T) {
+			mockService := new(tests2.MockBatchExpiryService)
+			handler := hd.NewBatchExpiryHandler(mockService)
+
+			rr := httptest.NewRecorder()
+			handler.GetAlerts().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/alerts?"+query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Contains(t, rr.Body.String(), e.ErrAlertFilterInvalid.Error())
+			mockService.AssertNotCalled(t, "FindAlerts")
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
@@ -109,8 +108,8 @@ func (h *BuyerHandler) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var newBuyer mod.Buyer
 
-		if err := json.NewDecoder(r.Body).Decode(&newBuyer); err != nil {
-			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestFailedBody.Error())
+		if err := utils.DecodeJSON(w, r, &newBuyer); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 
@@ -170,8 +169,8 @@ func (h *BuyerHandler) Update() http.HandlerFunc {
 
 		var buyerPatch mod.BuyerPatch
 
-		if err := json.NewDecoder(r.Body).Decode(&buyerPatch); err != nil {
-			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestFailedBody.Error())
+		if err := utils.DecodeJSON(w, r, &buyerPatch); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 
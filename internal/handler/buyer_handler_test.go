@@ -320,7 +320,7 @@ func (s *THandlerBuyerSuite) TestBuyerHandler_Create() {
 		{
 			name:           "Case 2: Fail - Bad request",
 			mockError:      e.ErrRequestFailedBody,
-			expectedBody:   `{"success":false,"message":"handler: failed to read body","data":null}`,
+			expectedBody:   `{"success":false,"message":"handler: request must have a body","data":null}`,
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
@@ -476,7 +476,7 @@ func (s *THandlerBuyerSuite) TestBuyerHandler_Update() {
 				"last_name": "Perez",
 				`,
 			param:          "1",
-			expectedBody:   `{"success":false,"message":"handler: failed to read body","data":null}`,
+			expectedBody:   `{"success":false,"message":"handler: body does not meet requirements: body must be an object","data":null}`,
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
@@ -1,12 +1,9 @@
 package handler
 
 import (
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"strconv"
 
-	"github.com/go-playground/validator/v10"
 	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
@@ -14,16 +11,12 @@ import (
 )
 
 type carryHandler struct {
-	sv       internal.CarryService
-	validate *validator.Validate
+	sv internal.CarryService
 }
 
 func NewCarryHandler(sv internal.CarryService) *carryHandler {
-	validate := validator.New() // Inicializar primero el validador
-
 	return &carryHandler{
-		sv:       sv,
-		validate: validate,
+		sv: sv,
 	}
 }
 
@@ -32,13 +25,12 @@ func (h *carryHandler) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var carry models.Carry
 
-		if err := json.NewDecoder(r.Body).Decode(&carry); err != nil {
-			fmt.Println(err.Error())
-			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestWrongBody.Error())
+		if err := utils.DecodeJSON(w, r, &carry); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 
-		if err := h.validate.Struct(carry); err != nil {
+		if err := e.Validator().Struct(carry); err != nil {
 			utils.BadResponse(w, http.StatusUnprocessableEntity, "Campos inválidos: "+err.Error())
 			return
 		}
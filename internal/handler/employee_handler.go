@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"encoding/json"
 	"github.com/go-chi/chi/v5"
 	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
 	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
@@ -63,9 +62,9 @@ func (h *EmployeeHandler) GetById() http.HandlerFunc {
 func (h *EmployeeHandler) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var employee mod.Employee
-		err := json.NewDecoder(r.Body).Decode(&employee)
+		err := utils.DecodeJSON(w, r, &employee)
 		if err != nil {
-			utils.BadResponse(w, http.StatusBadRequest, err.Error())
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 		if employee.FirstName == "" || employee.LastName == "" || employee.CardNumberID == "" || employee.WarehouseID == 0 {
@@ -100,9 +99,9 @@ func (h *EmployeeHandler) Edit() http.HandlerFunc {
 			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
 			return
 		}
-		err = json.NewDecoder(r.Body).Decode(&model)
+		err = utils.DecodeJSON(w, r, &model)
 		if err != nil {
-			utils.BadResponse(w, http.StatusUnprocessableEntity, e.ErrRequestWrongBody.Error())
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 		emplo, err := h.sv.FindByID(idNum)
@@ -171,7 +171,7 @@ func TestEmployeeHandler_Create(t *testing.T) {
 			requestBody:    `{"first_name":"Test","last_name":"User",`, // Malformed JSON
 			mockReturnErr:  nil,
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"success":false,"message":"unexpected EOF","data":null}`, // json.NewDecoder error message (often "EOF" for incomplete JSON)
+			expectedBody:   `{"success":false,"message":"handler: body does not meet requirements: malformed JSON","data":null}`,
 		},
 		{
 			name:           "Unprocessable Entity - Missing Required Fields",
@@ -244,7 +244,7 @@ func TestEmployeeHandler_Edit(t *testing.T) {
 		{
 			name:                  "Bad Request - Missing ID in URL",
 			employeeID:            "",
-			requestBody:           `{"FirstName":"UpdatedName"}`,
+			requestBody:           `{"first_name":"UpdatedName"}`,
 			mockFindByIDReturnEmp: nil,
 			mockFindByIDReturnErr: nil,
 			mockUpdateReturnErr:   nil,
@@ -254,7 +254,7 @@ func TestEmployeeHandler_Edit(t *testing.T) {
 		{
 			name:                  "Bad Request - Invalid ID Format in URL",
 			employeeID:            "abc",
-			requestBody:           `{"FirstName":"UpdatedName"}`,
+			requestBody:           `{"first_name":"UpdatedName"}`,
 			mockFindByIDReturnEmp: nil,
 			mockFindByIDReturnErr: nil,
 			mockUpdateReturnErr:   nil,
@@ -262,19 +262,19 @@ func TestEmployeeHandler_Edit(t *testing.T) {
 			expectedBody:          `{"success":false,"message":"` + e.ErrRequestIdMustBeInt.Error() + `","data":null}`,
 		},
 		{
-			name:                  "Unprocessable Entity - Invalid JSON Body",
+			name:                  "Bad Request - Invalid JSON Body",
 			employeeID:            "1",
-			requestBody:           `{"FirstName":"UpdatedName`, // Malformed JSON
-			mockFindByIDReturnEmp: nil,                         // FindByID is not called if JSON decode fails
+			requestBody:           `{"first_name":"UpdatedName`, // Malformed JSON
+			mockFindByIDReturnEmp: nil,                          // FindByID is not called if JSON decode fails
 			mockFindByIDReturnErr: nil,
 			mockUpdateReturnErr:   nil,
-			expectedStatus:        http.StatusUnprocessableEntity,                                                       // Or 400, depending on utils.BadResponse
-			expectedBody:          `{"success":false,"message":"handler: body does not meet requirements","data":null}`, // Common json.NewDecoder error
+			expectedStatus:        http.StatusBadRequest,
+			expectedBody:          `{"success":false,"message":"handler: body does not meet requirements: malformed JSON","data":null}`,
 		},
 		{
 			name:                  "Not Found - Employee Does Not Exist for Update",
 			employeeID:            "99", // Valid ID, but not found
-			requestBody:           `{"FirstName":"UpdatedName"}`,
+			requestBody:           `{"first_name":"UpdatedName"}`,
 			mockFindByIDReturnEmp: nil,
 			mockFindByIDReturnErr: errors.New("employee not found"), // Service returns Not Found
 			mockUpdateReturnErr:   nil,
@@ -284,7 +284,7 @@ func TestEmployeeHandler_Edit(t *testing.T) {
 		{
 			name:                  "Conflict - Service Returns Update Error",
 			employeeID:            "1",
-			requestBody:           `{"FirstName":"UpdatedName"}`,
+			requestBody:           `{"first_name":"UpdatedName"}`,
 			mockFindByIDReturnEmp: &mod.Employee{ID: 1, FirstName: "OriginalName", LastName: "User", CardNumberID: "123", WarehouseID: 10},
 			mockFindByIDReturnErr: nil,
 			mockUpdateReturnErr:   errors.New("update conflict: card number already exists"), // Simulate service conflict
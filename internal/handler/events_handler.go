@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/internal/events"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewEventHandler creates a new instance of the event handler, heartbeat is how often an idle stream
+// sends a comment so proxies keep the connection open
+func NewEventHandler(broker *events.Broker, heartbeat time.Duration) *EventHandler {
+	return &EventHandler{
+		broker:    broker,
+		heartbeat: heartbeat,
+	}
+}
+
+// EventHandler streams the committed changes to the clients
+type EventHandler struct {
+	// broker is where the repositories publish their events
+	broker    *events.Broker
+	heartbeat time.Duration
+}
+
+// Stream pushes the events as Server-Sent Events, e.g. /v1/events/stream?topics=sections,productBatches.
+// A client resumes with the Last-Event-ID header, or the last_event_id parameter for clients that can not set it
+func (h *EventHandler) Stream() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		topics := common.SplitQueryList(r.URL.Query().Get("topics"))
+		for _, topic := range topics {
+			if !slices.Contains(events.Topics, topic) {
+				utils.BadResponse(w, http.StatusBadRequest, e.ErrEventTopicInvalid.Error())
+				return
+			}
+		}
+		lastID, err := lastEventID(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrEventStreamUnsupported.Error())
+			return
+		}
+
+		sub, replay := h.broker.Subscribe(topics, lastID)
+		defer h.broker.Unsubscribe(sub)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		for _, ev := range replay {
+			if writeEvent(w, ev) != nil {
+				return
+			}
+		}
+		flusher.Flush()
+
+		heartbeat := time.NewTicker(h.heartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case ev, open := <-sub.C:
+				if !open {
+					// the client fell behind, it reconnects and resumes from its last event
+					return
+				}
+				if writeEvent(w, ev) != nil {
+					return
+				}
+				flusher.Flush()
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// lastEventID reads the id of the last event the client received
+func lastEventID(r *http.Request) (int64, error) {
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = r.URL.Query().Get("last_event_id")
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id < 0 {
+		return 0, e.ErrEventLastIDInvalid
+	}
+	return id, nil
+}
+
+// writeEvent writes the event in the Server-Sent Events format
+func writeEvent(w http.ResponseWriter, ev events.Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data)
+	return err
+}
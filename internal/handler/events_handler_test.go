@@ -0,0 +1,110 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/internal/events"
+	hd "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/handler"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventHandler_Stream_BadRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		lastEventID string
+		expected    error
+	}{
+		{
+			name:     "#1 Error - Unknown topic",
+			url:      "/v1/events/stream?topics=sections,buyers",
+			expected: e.ErrEventTopicInvalid,
+		},
+		{
+			name:        "#2 Error - Last-Event-ID is not a number",
+			url:         "/v1/events/stream",
+			lastEventID: "abc",
+			expected:    e.ErrEventLastIDInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := hd.NewEventHandler(events.NewBroker(10), time.Minute)
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+			res := httptest.NewRecorder()
+
+			h.Stream()(res, req)
+
+			require.Equal(t, http.StatusBadRequest, res.Code)
+			require.Contains(t, res.Body.String(), tt.expected.Error())
+		})
+	}
+}
+
+func TestEventHandler_Stream(t *testing.T) {
+	broker := events.NewBroker(10)
+	broker.Publish(events.TopicSections, "section.updated", map[string]int{"id": 1})
+	broker.Publish(events.TopicPurchaseOrders, "purchaseOrder.created", map[string]int{"id": 7})
+	broker.Publish(events.TopicSections, "section.updated", map[string]int{"id": 2})
+	srv := httptest.NewServer(hd.NewEventHandler(broker, 20*time.Millisecond).Stream())
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?topics=sections", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "1")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(res.Body)
+	readBlock := func() string {
+		var block strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			if line == "\n" {
+				return block.String()
+			}
+			block.WriteString(line)
+		}
+	}
+
+	// the buffered section event after id 1 is replayed
+	replayed := readBlock()
+	require.Contains(t, replayed, "id: 3\nevent: section.updated\n")
+	require.Contains(t, replayed, `"data":{"id":2}`)
+
+	// live events of other topics are filtered out
+	require.Eventually(t, func() bool { return broker.Subscribers() == 1 }, time.Second, time.Millisecond)
+	broker.Publish(events.TopicProductBatches, "productBatch.created", map[string]int{"id": 4})
+	broker.Publish(events.TopicSections, "section.deleted", map[string]int{"id": 3})
+	for {
+		block := readBlock()
+		if strings.HasPrefix(block, ": keep-alive") {
+			continue
+		}
+		require.Contains(t, block, "id: 5\nevent: section.deleted\n")
+		break
+	}
+
+	// an idle stream keeps sending heartbeats
+	require.Equal(t, ": keep-alive\n", readBlock())
+
+	cancel()
+	require.Eventually(t, func() bool { return broker.Subscribers() == 0 }, time.Second, time.Millisecond)
+}
@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"encoding/json"
 	"errors"
 	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
 	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
@@ -27,8 +26,8 @@ type InboundHandler struct {
 func (h *InboundHandler) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var inboundOrder mod.InboundOrders
-		if err := json.NewDecoder(r.Body).Decode(&inboundOrder); err != nil {
-			utils.BadResponse(w, http.StatusUnprocessableEntity, "Invalid JSON format")
+		if err := utils.DecodeJSON(w, r, &inboundOrder); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 
@@ -36,12 +36,12 @@ func TestInboundHandler_Create(t *testing.T) {
 			requestBody:        `{"order_number": "ORD002", "order_date": "2023-01-02", "employee_id": "invalid"`,
 			mockReturnOrder:    nil,
 			mockReturnErr:      nil,
-			expectedStatusCode: http.StatusUnprocessableEntity,
-			expectedBody:       `{"success":false,"message":"Invalid JSON format","data":null}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"success":false,"message":"handler: body does not meet requirements: malformed JSON","data":null}`,
 		},
 		{
 			name:               "Failure - Invalid Data",
-			requestBody:        `{"order_number":"","order_date":"2023-01-03","employee_id":2,"product_record_id":102,"warehouse_id":1002}`,
+			requestBody:        `{"order_number":"","order_date":"2023-01-03","employee_id":2,"product_batch_id":102,"warehouse_id":1002}`,
 			mockReturnOrder:    &mod.InboundOrders{},
 			mockReturnErr:      e.ErrInboundOrderInvalidData,
 			expectedStatusCode: http.StatusUnprocessableEntity,
@@ -49,7 +49,7 @@ func TestInboundHandler_Create(t *testing.T) {
 		},
 		{
 			name:               "Failure - Order Number Already Exists",
-			requestBody:        `{"order_number":"ORD001","order_date":"2023-01-04","employee_id":3,"product_record_id":103,"warehouse_id":1003}`,
+			requestBody:        `{"order_number":"ORD001","order_date":"2023-01-04","employee_id":3,"product_batch_id":103,"warehouse_id":1003}`,
 			mockReturnOrder:    &mod.InboundOrders{},
 			mockReturnErr:      errors.New("order number already exists"),
 			expectedStatusCode: http.StatusConflict,
@@ -57,7 +57,7 @@ func TestInboundHandler_Create(t *testing.T) {
 		},
 		{
 			name:               "Failure - Employee Not Found",
-			requestBody:        `{"order_number":"ORD005","order_date":"2023-01-05","employee_id":999,"product_record_id":105,"warehouse_id":1005}`,
+			requestBody:        `{"order_number":"ORD005","order_date":"2023-01-05","employee_id":999,"product_batch_id":105,"warehouse_id":1005}`,
 			mockReturnOrder:    &mod.InboundOrders{},
 			mockReturnErr:      errors.New("employee not found"),
 			expectedStatusCode: http.StatusConflict,
@@ -65,7 +65,7 @@ func TestInboundHandler_Create(t *testing.T) {
 		},
 		{
 			name:               "Failure - Internal Server Error",
-			requestBody:        `{"order_number":"ORD006","order_date":"2023-01-06","employee_id":4,"product_record_id":106,"warehouse_id":1006}`,
+			requestBody:        `{"order_number":"ORD006","order_date":"2023-01-06","employee_id":4,"product_batch_id":106,"warehouse_id":1006}`,
 			mockReturnOrder:    &mod.InboundOrders{},
 			mockReturnErr:      errors.New("something went wrong"),
 			expectedStatusCode: http.StatusInternalServerError,
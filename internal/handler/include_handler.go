@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewIncludeHandler creates a new instance of the include handler
+func NewIncludeHandler(sv internal.IncludeService) *IncludeHandler {
+	return &IncludeHandler{
+		sv: sv,
+	}
+}
+
+// IncludeHandler adds ?include= and ?fields= support to the GET handlers of a resource,
+// e.g. /v1/products/1?include=seller.locality,records&fields=id,product_code
+type IncludeHandler struct {
+	// sv is the service used by the handler
+	sv internal.IncludeService
+}
+
+// bufferedResponse keeps the response of the wrapped handler so it can be rewritten
+type bufferedResponse struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header         { return b.header }
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+func (b *bufferedResponse) WriteHeader(code int)        { b.code = code }
+
+// Wrap runs next and embeds the requested relations into its data, requests without include or fields
+// and unsuccessful responses are served by next untouched
+func (h *IncludeHandler) Wrap(resource string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		includes := common.SplitQueryList(r.URL.Query().Get("include"))
+		fields := common.SplitQueryList(r.URL.Query().Get("fields"))
+		if len(includes) == 0 && len(fields) == 0 {
+			next(w, r)
+			return
+		}
+		if err := h.sv.Validate(resource, includes); err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		res := &bufferedResponse{header: make(http.Header), code: http.StatusOK}
+		next(res, r)
+		var body struct {
+			Message string          `json:"message"`
+			Data    json.RawMessage `json:"data"`
+		}
+		if res.code < 200 || res.code > 299 || json.Unmarshal(res.body.Bytes(), &body) != nil {
+			for k, v := range res.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(res.code)
+			w.Write(res.body.Bytes())
+			return
+		}
+
+		data := bytes.TrimSpace(body.Data)
+		single := len(data) > 0 && data[0] == '{'
+		if single {
+			data = append(append([]byte{'['}, data...), ']')
+		}
+		var items []map[string]any
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&items); err != nil && !bytes.Equal(data, []byte("null")) {
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			return
+		}
+
+		if err := h.sv.Expand(resource, items, includes); err != nil {
+			if errors.Is(err, e.ErrIncludeInvalid) {
+				utils.BadResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			utils.BadResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if len(fields) > 0 {
+			keep := h.sv.Keys(resource, includes)
+			for i := range items {
+				items[i] = common.SelectFields(items[i], fields, keep)
+			}
+		}
+
+		switch {
+		case single:
+			utils.GoodResponse(w, res.code, body.Message, items[0])
+		default:
+			utils.GoodResponse(w, res.code, body.Message, items)
+		}
+	}
+}
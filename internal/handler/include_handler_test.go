@@ -0,0 +1,124 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	hd "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/handler"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	tests2 "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIncludeHandler_Wrap(t *testing.T) {
+	product := mod.Product{ID: 1, ProductCode: "P001", Description: "Peas", SellerID: 5}
+	getByID := func(w http.ResponseWriter, r *http.Request) {
+		utils.GoodResponse(w, http.StatusOK, "success", product)
+	}
+	getAll := func(w http.ResponseWriter, r *http.Request) {
+		utils.GoodResponse(w, http.StatusOK, "success", []mod.Product{product})
+	}
+	notFound := func(w http.ResponseWriter, r *http.Request) {
+		utils.BadResponse(w, http.StatusNotFound, e.ErrProductRepositoryNotFound.Error())
+	}
+	addSeller := func(args mock.Arguments) {
+		for _, item := range args.Get(1).([]map[string]any) {
+			item["seller"] = map[string]any{"id": 5, "company_name": "Acme"}
+		}
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		next           http.HandlerFunc
+		setup          func(sv *tests2.MockIncludeService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "#1 Success - Without include nor fields the handler is not changed",
+			url:            "/v1/products/1",
+			next:           getByID,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"message":"success","data":{"id":1,"product_code":"P001","description":"Peas","height":0,"length":0,"width":0,"net_weight":0,"expiration_rate":0,"freezing_rate":0,"recommended_freezing_temperature":0,"product_type_id":0,"seller_id":5}}`,
+		},
+		{
+			name: "#2 Success - Include and fields on a single object",
+			url:  "/v1/products/1?include=seller&fields=id,product_code",
+			next: getByID,
+			setup: func(sv *tests2.MockIncludeService) {
+				sv.On("Validate", "products", []string{"seller"}).Return(nil)
+				sv.On("Expand", "products", mock.Anything, []string{"seller"}).Run(addSeller).Return(nil)
+				sv.On("Keys", "products", []string{"seller"}).Return([]string{"seller"})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"message":"success","data":{"id":1,"product_code":"P001","seller":{"id":5,"company_name":"Acme"}}}`,
+		},
+		{
+			name: "#3 Success - Fields on a list",
+			url:  "/v1/products?fields=id",
+			next: getAll,
+			setup: func(sv *tests2.MockIncludeService) {
+				sv.On("Validate", "products", []string(nil)).Return(nil)
+				sv.On("Expand", "products", mock.Anything, []string(nil)).Return(nil)
+				sv.On("Keys", "products", []string(nil)).Return([]string(nil))
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"message":"success","data":[{"id":1}]}`,
+		},
+		{
+			name: "#4 Error - Unsupported include",
+			url:  "/v1/products/1?include=warehouse",
+			next: getByID,
+			setup: func(sv *tests2.MockIncludeService) {
+				sv.On("Validate", "products", []string{"warehouse"}).Return(e.ErrIncludeInvalid)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"message":"handler: include is not supported","data":null}`,
+		},
+		{
+			name: "#5 Error - Errors of the wrapped handler are kept",
+			url:  "/v1/products/9?include=seller",
+			next: notFound,
+			setup: func(sv *tests2.MockIncludeService) {
+				sv.On("Validate", "products", []string{"seller"}).Return(nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"message":"repository: product not found","data":null}`,
+		},
+		{
+			name: "#6 Error - Loading the relations fails",
+			url:  "/v1/products/1?include=seller",
+			next: getByID,
+			setup: func(sv *tests2.MockIncludeService) {
+				sv.On("Validate", "products", []string{"seller"}).Return(nil)
+				sv.On("Expand", "products", mock.Anything, []string{"seller"}).Return(errors.New("repository: unable to execute query"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"success":false,"message":"repository: unable to execute query","data":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv := new(tests2.MockIncludeService)
+			if tt.setup != nil {
+				tt.setup(sv)
+			}
+			handler := hd.NewIncludeHandler(sv)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rr := httptest.NewRecorder()
+			handler.Wrap("products", tt.next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			sv.AssertExpectations(t)
+		})
+	}
+}
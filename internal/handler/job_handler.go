@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+const (
+	// jobRunsDefaultLimit is the number of runs returned when no limit is given
+	jobRunsDefaultLimit = 20
+	// jobRunsMaxLimit is the greatest limit accepted
+	jobRunsMaxLimit = 100
+)
+
+// NewJobHandler creates a new instance of the job handler
+func NewJobHandler(sv internal.JobService) *JobHandler {
+	return &JobHandler{
+		sv: sv,
+	}
+}
+
+// JobHandler exposes the scheduled jobs
+type JobHandler struct {
+	// sv is the scheduler used by the handler
+	sv internal.JobService
+}
+
+// GetAll returns every registered job with its schedule, next run and latest run
+func (h *JobHandler) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobs, err := h.sv.FindAll()
+		if err != nil {
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, jobs)
+	}
+}
+
+// GetRuns returns the latest runs of the job, e.g. /v1/jobs/runs_cleanup/runs?limit=5
+func (h *JobHandler) GetRuns() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := jobRunsDefaultLimit
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			var err error
+			limit, err = strconv.Atoi(raw)
+			if err != nil || limit < 1 || limit > jobRunsMaxLimit {
+				utils.BadResponse(w, http.StatusBadRequest, e.ErrJobLimitInvalid.Error())
+				return
+			}
+		}
+		runs, err := h.sv.FindRuns(chi.URLParam(r, "name"), limit)
+		if err != nil {
+			utils.BadResponse(w, jobStatus(err), jobMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, runs)
+	}
+}
+
+// Trigger starts a run of the job in the background
+func (h *JobHandler) Trigger() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.sv.Trigger(chi.URLParam(r, "name")); err != nil {
+			utils.BadResponse(w, jobStatus(err), jobMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusAccepted, e.JobTriggered, nil)
+	}
+}
+
+// Pause skips the scheduled runs of the job until it is resumed
+func (h *JobHandler) Pause() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.sv.Pause(chi.URLParam(r, "name")); err != nil {
+			utils.BadResponse(w, jobStatus(err), jobMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.JobPaused, nil)
+	}
+}
+
+// Resume restores the scheduled runs of the job
+func (h *JobHandler) Resume() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.sv.Resume(chi.URLParam(r, "name")); err != nil {
+			utils.BadResponse(w, jobStatus(err), jobMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.JobResumed, nil)
+	}
+}
+
+// jobStatus returns the status code of a scheduler error
+func jobStatus(err error) int {
+	switch {
+	case errors.Is(err, e.ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, e.ErrJobRunning):
+		return http.StatusConflict
+	case errors.Is(err, e.ErrJobSchedulerStopped):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// jobMessage hides the internal errors of the scheduler
+func jobMessage(err error) string {
+	if jobStatus(err) == http.StatusInternalServerError {
+		return e.ErrRequestInternalServer.Error()
+	}
+	return err.Error()
+}
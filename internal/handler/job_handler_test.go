@@ -0,0 +1,149 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	hd "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/handler"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	tests2 "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobHandler_GetAll(t *testing.T) {
+	next := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		mockReturn     []mod.Job
+		mockErr        error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "#1 Success",
+			mockReturn:     []mod.Job{{Name: "runs_cleanup", Schedule: "@daily", NextRun: next}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"message":"handler: data retrieved successfully","data":[{"name":"runs_cleanup","schedule":"@daily","paused":false,"running":false,"next_run":"2024-05-02T00:00:00Z","last_run":null}]}`,
+		},
+		{
+			name:           "#2 Error - Repository failure",
+			mockReturn:     []mod.Job{},
+			mockErr:        e.ErrQueryError,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"success":false,"message":"handler: internal server error","data":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv := new(tests2.MockJobService)
+			sv.On("FindAll").Return(tt.mockReturn, tt.mockErr)
+			h := hd.NewJobHandler(sv)
+			req := httptest.NewRequest(http.MethodGet, "/v1/jobs", nil)
+			res := httptest.NewRecorder()
+
+			h.GetAll()(res, req)
+
+			assert.Equal(t, tt.expectedStatus, res.Code)
+			assert.JSONEq(t, tt.expectedBody, res.Body.String())
+			sv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobHandler_GetRuns(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		mockCall       bool
+		mockLimit      int
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "#1 Success - Default limit", url: "/v1/jobs/runs_cleanup/runs", mockCall: true, mockLimit: 20, expectedStatus: http.StatusOK},
+		{name: "#2 Success - Given limit", url: "/v1/jobs/runs_cleanup/runs?limit=5", mockCall: true, mockLimit: 5, expectedStatus: http.StatusOK},
+		{name: "#3 Error - Invalid limit", url: "/v1/jobs/runs_cleanup/runs?limit=500", expectedStatus: http.StatusBadRequest},
+		{name: "#4 Error - Unknown job", url: "/v1/jobs/runs_cleanup/runs", mockCall: true, mockLimit: 20, mockErr: e.ErrJobNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv := new(tests2.MockJobService)
+			if tt.mockCall {
+				sv.On("FindRuns", "runs_cleanup", tt.mockLimit).Return([]mod.JobRun{}, tt.mockErr)
+			}
+			h := hd.NewJobHandler(sv)
+			req := withURLParam(httptest.NewRequest(http.MethodGet, tt.url, nil), "name", "runs_cleanup")
+			res := httptest.NewRecorder()
+
+			h.GetRuns()(res, req)
+
+			assert.Equal(t, tt.expectedStatus, res.Code)
+			sv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobHandler_Actions(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		mockErr        error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "#1 Success - Trigger",
+			method:         "Trigger",
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `{"success":true,"message":"handler: job run started","data":null}`,
+		},
+		{
+			name:           "#2 Error - Trigger while running",
+			method:         "Trigger",
+			mockErr:        e.ErrJobRunning,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"success":false,"message":"scheduler: job is already running","data":null}`,
+		},
+		{
+			name:           "#3 Success - Pause",
+			method:         "Pause",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"message":"handler: job paused","data":null}`,
+		},
+		{
+			name:           "#4 Error - Pause unknown job",
+			method:         "Pause",
+			mockErr:        e.ErrJobNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"message":"scheduler: job not found","data":null}`,
+		},
+		{
+			name:           "#5 Error - Resume with a database failure",
+			method:         "Resume",
+			mockErr:        e.ErrQueryError,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"success":false,"message":"handler: internal server error","data":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv := new(tests2.MockJobService)
+			sv.On(tt.method, "runs_cleanup").Return(tt.mockErr)
+			h := hd.NewJobHandler(sv)
+			actions := map[string]http.HandlerFunc{"Trigger": h.Trigger(), "Pause": h.Pause(), "Resume": h.Resume()}
+			req := withURLParam(httptest.NewRequest(http.MethodPost, "/v1/jobs/runs_cleanup", nil), "name", "runs_cleanup")
+			res := httptest.NewRecorder()
+
+			actions[tt.method](res, req)
+
+			assert.Equal(t, tt.expectedStatus, res.Code)
+			assert.JSONEq(t, tt.expectedBody, res.Body.String())
+			sv.AssertExpectations(t)
+		})
+	}
+}
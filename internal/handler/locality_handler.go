@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
 	"strconv"
 
@@ -38,22 +37,9 @@ func (h *LocalityHandler) GetAll() http.HandlerFunc {
 
 func (h *LocalityHandler) GetSelByLocID() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var id int
-		var err error
-		req := r.URL.Query().Get("id")
-		switch req {
-		case "":
-			id = -1
-		default:
-			id, err = strconv.Atoi(req)
-			if err != nil {
-				utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
-				return
-			}
-			if id < 0 {
-				utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeGte0.Error())
-				return
-			}
+		id, ok := localityIDQuery(w, r)
+		if !ok {
+			return
 		}
 		result, err := h.sv.FindSellersByLocID(id)
 		if err != nil {
@@ -64,13 +50,64 @@ func (h *LocalityHandler) GetSelByLocID() http.HandlerFunc {
 	}
 }
 
+// GetWhByLocID returns the warehouses count of every locality, or of the locality in the id query param
+func (h *LocalityHandler) GetWhByLocID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := localityIDQuery(w, r)
+		if !ok {
+			return
+		}
+		result, err := h.sv.FindWarehousesByLocID(id)
+		if err != nil {
+			utils.BadResponse(w, 404, err.Error())
+			return
+		}
+		utils.GoodResponse(w, 200, "success", result)
+	}
+}
+
+// GetOverview returns the sellers, carries and warehouses count of every locality, or of the locality in the id query param
+func (h *LocalityHandler) GetOverview() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := localityIDQuery(w, r)
+		if !ok {
+			return
+		}
+		result, err := h.sv.FindOverviewByLocID(id)
+		if err != nil {
+			utils.BadResponse(w, 404, err.Error())
+			return
+		}
+		utils.GoodResponse(w, 200, "success", result)
+	}
+}
+
+// localityIDQuery reads the optional id query param of the locality reports, -1 means every locality. It writes
+// the bad request response and returns false when the id is invalid
+func localityIDQuery(w http.ResponseWriter, r *http.Request) (int, bool) {
+	req := r.URL.Query().Get("id")
+	if req == "" {
+		return -1, true
+	}
+	id, err := strconv.Atoi(req)
+	if err != nil {
+		utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+		return 0, false
+	}
+	if id < 0 {
+		utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeGte0.Error())
+		return 0, false
+	}
+	return id, true
+}
+
 // Create creates a new locality
 func (h *LocalityHandler) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req models.Locality
-		err := json.NewDecoder(r.Body).Decode(&req)
+		err := utils.DecodeJSON(w, r, &req)
 		if err != nil {
-			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestWrongBody.Error())
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 
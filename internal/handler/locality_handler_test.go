@@ -166,6 +166,131 @@ func TestLocalityHandler_GetSelByLocID(t *testing.T) {
 	}
 }
 
+func TestLocalityHandler_GetWhByLocID(t *testing.T) {
+	tests := []struct {
+		name              string
+		localityID        string
+		mockReturnData    []mod.WhByLoc
+		mockReturnErr     error
+		expectServiceCall bool
+		expectedStatus    int
+		expectedBody      string
+	}{
+		{
+			name:              "#1 Success - Warehouses by localities Found",
+			localityID:        "",
+			expectServiceCall: true,
+			mockReturnData: []mod.WhByLoc{
+				{ID: 1, Name: "Brooklyn", Count: 1},
+				{ID: 2, Name: "Santa Monica", Count: 0},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"message":"success","data":[{"locality_id":1,"locality_name":"Brooklyn","warehouses_count":1},{"locality_id":2,"locality_name":"Santa Monica","warehouses_count":0}]}`,
+		},
+		{
+			name:              "#2 Error - Bad Request ID Must be Int",
+			localityID:        "abc",
+			expectServiceCall: false,
+			expectedStatus:    http.StatusBadRequest,
+			expectedBody:      `{"success":false,"message":"handler: id must be an integer","data":null}`,
+		},
+		{
+			name:              "#3 Error - Service Returns Error",
+			localityID:        "99",
+			expectServiceCall: true,
+			mockReturnErr:     e.ErrLocalityRepositoryNotFound,
+			expectedStatus:    http.StatusNotFound,
+			expectedBody:      `{"success":false,"message":"repository: locality not found","data":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(tests2.MockLocalityService)
+			handler := hd.NewLocalityHandler(mockService)
+
+			if tt.expectServiceCall {
+				expectedID := -1
+				if tt.localityID != "" {
+					expectedID, _ = strconv.Atoi(tt.localityID)
+				}
+				mockService.On("FindWarehousesByLocID", expectedID).Return(tt.mockReturnData, tt.mockReturnErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/localities/reportWarehouses?id=%s", tt.localityID), nil)
+			rr := httptest.NewRecorder()
+
+			handler.GetWhByLocID().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, "Expected status code mismatch")
+			assert.JSONEq(t, tt.expectedBody, rr.Body.String(), "Expected response body mismatch")
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLocalityHandler_GetOverview(t *testing.T) {
+	tests := []struct {
+		name              string
+		localityID        string
+		mockReturnData    []mod.LocalityOverview
+		mockReturnErr     error
+		expectServiceCall bool
+		expectedStatus    int
+		expectedBody      string
+	}{
+		{
+			name:              "#1 Success - Overview of one locality",
+			localityID:        "1",
+			expectServiceCall: true,
+			mockReturnData: []mod.LocalityOverview{
+				{ID: 1, Name: "Brooklyn", SellersCount: 2, CarriesCount: 3, WarehousesCount: 1},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"message":"success","data":[{"locality_id":1,"locality_name":"Brooklyn","sellers_count":2,"carries_count":3,"warehouses_count":1}]}`,
+		},
+		{
+			name:              "#2 Error - Bad Request ID Must be Greater than 0",
+			localityID:        "-1",
+			expectServiceCall: false,
+			expectedStatus:    http.StatusBadRequest,
+			expectedBody:      `{"success":false,"message":"handler: id must be greater than 0","data":null}`,
+		},
+		{
+			name:              "#3 Error - Service Returns Error",
+			localityID:        "",
+			expectServiceCall: true,
+			mockReturnErr:     e.ErrLocalityRepositoryNotFound,
+			expectedStatus:    http.StatusNotFound,
+			expectedBody:      `{"success":false,"message":"repository: locality not found","data":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(tests2.MockLocalityService)
+			handler := hd.NewLocalityHandler(mockService)
+
+			if tt.expectServiceCall {
+				expectedID := -1
+				if tt.localityID != "" {
+					expectedID, _ = strconv.Atoi(tt.localityID)
+				}
+				mockService.On("FindOverviewByLocID", expectedID).Return(tt.mockReturnData, tt.mockReturnErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/localities/overview?id=%s", tt.localityID), nil)
+			rr := httptest.NewRecorder()
+
+			handler.GetOverview().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code, "Expected status code mismatch")
+			assert.JSONEq(t, tt.expectedBody, rr.Body.String(), "Expected response body mismatch")
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestLocalityHandler_Create(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -190,7 +315,7 @@ func TestLocalityHandler_Create(t *testing.T) {
 			requestBody:       `{"locality_name":1,"province_name":"Cundinamarca","country_name":"Colombia"}`,
 			expectServiceCall: false,
 			expectedStatus:    http.StatusBadRequest,
-			expectedBody:      `{"success":false,"message":"handler: body does not meet requirements","data":null}`,
+			expectedBody:      `{"success":false,"message":"handler: body has a field with a wrong type: locality_name must be a string, got number","data":null}`,
 		},
 		{
 			name:              "#3 Error - Unprocessable Entity - Missing Required Fields",
@@ -219,6 +344,8 @@ func TestLocalityHandler_Create(t *testing.T) {
 			}
 
 			req := httptest.NewRequest(http.MethodPost, "/localities", bytes.NewBufferString(tt.requestBody))
+
+			req.Header.Set("Content-Type", "application/json")
 			req.Header.Set("Content-Type", "application/json")
 			rr := httptest.NewRecorder()
 
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/cache"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewMetricsHandler creates a new instance of the metrics handler
+func NewMetricsHandler(caches *cache.Registry) *MetricsHandler {
+	return &MetricsHandler{
+		caches: caches,
+	}
+}
+
+// MetricsHandler exposes the internal metrics of the application
+type MetricsHandler struct {
+	// caches is the registry of the repository caches
+	caches *cache.Registry
+}
+
+// GetCacheStats returns the hit and miss counters of every repository cache
+func (h *MetricsHandler) GetCacheStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, h.caches.Stats())
+	}
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/cache"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsHandler_GetCacheStats(t *testing.T) {
+	reg := cache.NewRegistry()
+	c := cache.New[int, string](10, 0)
+	c.Set(1, "one")
+	c.Get(1)
+	c.Get(2)
+	reg.Register("products", c)
+
+	handler := NewMetricsHandler(reg)
+	req := httptest.NewRequest(http.MethodGet, "/v1/metrics/cache", nil)
+	rr := httptest.NewRecorder()
+	handler.GetCacheStats().ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"success":true,"message":"handler: data retrieved successfully","data":{"products":{"hits":1,"misses":1,"evictions":0,"size":1,"hit_ratio":0.5}}}`, rr.Body.String())
+}
@@ -1,12 +1,17 @@
 package handler
 
 import (
-	"encoding/json"
+	"errors"
 	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
-	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type ProductBatchHandler struct {
@@ -19,39 +24,256 @@ func NewProductBatchHandler(sv internal.ProductBatchService) *ProductBatchHandle
 	}
 }
 
+// GetAll returns the batches, filtered by product_id, section_id, warehouse_id, due_from, due_to and archived
 func (h *ProductBatchHandler) GetAll() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		result, err := h.sv.FindAll()
+		filter, err := productBatchFilter(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		result, err := h.sv.FindAll(filter)
 		if err != nil {
 			utils.BadResponse(w, http.StatusNotFound, err.Error())
 			return
 		}
-		utils.GoodResponse(w, http.StatusOK, errors.DataRetrievedSuccess, result)
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, result)
+	}
+}
+
+// GetByID returns the batch of the path
+func (h *ProductBatchHandler) GetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := common.IdRequests(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		batch, err := h.sv.FindByID(id)
+		if err != nil {
+			utils.BadResponse(w, batchStatus(err), batchMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, batch)
 	}
 }
 
 func (h *ProductBatchHandler) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var model models.ProductBatch
-		err := json.NewDecoder(r.Body).Decode(&model)
+		err := utils.DecodeJSON(w, r, &model)
 		if err != nil {
-			utils.BadResponse(w, http.StatusBadRequest, errors.ErrRequestFailedBody.Error())
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
-		validationErrors := errors.ValidateStruct(model)
+		validationErrors := e.ValidateStruct(model)
 		if len(validationErrors) > 0 {
+			fields := make([]string, 0, len(validationErrors))
+			for field := range validationErrors {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
 			str := ""
-			for _, err := range validationErrors {
-				str += err + ", "
+			for _, field := range fields {
+				str += validationErrors[field] + ", "
 			}
 			utils.BadResponse(w, http.StatusUnprocessableEntity, str)
 			return
 		}
 		err = h.sv.Save(&model)
 		if err != nil {
-			utils.BadResponse(w, http.StatusConflict, err.Error())
+			utils.BadResponse(w, batchStatus(err), batchMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusCreated, e.SectionCreated, model)
+	}
+}
+
+// Update changes the current quantity or temperature of the batch of the path
+func (h *ProductBatchHandler) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := common.IdRequests(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		var patch models.ProductBatchPatch
+		if err := utils.DecodeJSON(w, r, &patch); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
+			return
+		}
+		if patch.CurrentQuantity == nil && patch.CurrentTemperature == nil {
+			utils.BadResponse(w, http.StatusUnprocessableEntity, e.ErrProductBatchPatchEmpty.Error())
+			return
+		}
+		batch, err := h.sv.Update(id, patch)
+		if err != nil {
+			utils.BadResponse(w, batchStatus(err), batchMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.ProductBatchUpdated, batch)
+	}
+}
+
+// Archive writes off the units of the batch of the path and archives it
+func (h *ProductBatchHandler) Archive() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := common.IdRequests(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		batch, err := h.sv.Archive(id)
+		if err != nil {
+			utils.BadResponse(w, batchStatus(err), batchMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.ProductBatchArchived, batch)
+	}
+}
+
+// Delete deletes the batch of the path, a batch referenced by orders, transfers or status changes has to be archived first
+func (h *ProductBatchHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := common.IdRequests(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		if err = h.sv.Delete(id); err != nil {
+			utils.BadResponse(w, batchStatus(err), batchMessage(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Hold quarantines the batch of the path, the body gives the reason and the employee that holds it
+func (h *ProductBatchHandler) Hold() http.HandlerFunc {
+	return h.changeStatus(h.sv.Hold, e.ProductBatchHeld)
+}
+
+// Release releases or rejects the quarantined batch of the path, the body gives the reason, the employee and
+// optionally the rejected status
+func (h *ProductBatchHandler) Release() http.HandlerFunc {
+	return h.changeStatus(h.sv.Release, e.ProductBatchReleased)
+}
+
+// changeStatus decodes and validates the hold request of the batch of the path and applies it with change
+func (h *ProductBatchHandler) changeStatus(change func(int, models.BatchHoldRequest) (models.ProductBatch, error), message string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := common.IdRequests(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		var req models.BatchHoldRequest
+		if err := utils.DecodeJSON(w, r, &req); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
+			return
+		}
+		if errs := e.ValidateStruct(req); len(errs) > 0 {
+			msgs := make([]string, 0, len(errs))
+			for _, msg := range errs {
+				msgs = append(msgs, msg)
+			}
+			sort.Strings(msgs)
+			utils.BadResponse(w, http.StatusUnprocessableEntity, strings.Join(msgs, ", "))
+			return
+		}
+		batch, err := change(id, req)
+		if err != nil {
+			utils.BadResponse(w, batchStatus(err), batchMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, message, batch)
+	}
+}
+
+// GetStatusHistory returns the status changes of the batch of the path, oldest first
+func (h *ProductBatchHandler) GetStatusHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := common.IdRequests(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		changes, err := h.sv.FindStatusHistory(id)
+		if err != nil {
+			utils.BadResponse(w, batchStatus(err), batchMessage(err))
 			return
 		}
-		utils.GoodResponse(w, http.StatusCreated, errors.SectionCreated, model)
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, changes)
+	}
+}
+
+// productBatchFilter reads the filters of the batches query, a due_to date without time keeps that whole day
+func productBatchFilter(r *http.Request) (models.ProductBatchFilter, error) {
+	query := r.URL.Query()
+	var filter models.ProductBatchFilter
+	for param, dest := range map[string]*int{"product_id": &filter.ProductID, "section_id": &filter.SectionID, "warehouse_id": &filter.WarehouseID} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return models.ProductBatchFilter{}, e.ErrProductBatchFilter
+		}
+		*dest = n
+	}
+	for param, dest := range map[string]*time.Time{"due_from": &filter.DueFrom, "due_to": &filter.DueTo} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			*dest = t.UTC()
+			continue
+		}
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return models.ProductBatchFilter{}, e.ErrProductBatchFilter
+		}
+		if param == "due_to" {
+			t = t.AddDate(0, 0, 1)
+		}
+		*dest = t
+	}
+	if value := query.Get("archived"); value != "" {
+		archived, err := strconv.ParseBool(value)
+		if err != nil {
+			return models.ProductBatchFilter{}, e.ErrProductBatchFilter
+		}
+		filter.Archived = &archived
+	}
+	return filter, nil
+}
+
+// batchStatus returns the status code of a product batch error
+func batchStatus(err error) int {
+	switch {
+	case errors.Is(err, e.ErrProductBatchNotFound),
+		errors.Is(err, e.ErrEmployeeRepositoryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, e.ErrProductBatchInUse),
+		errors.Is(err, e.ErrProductBatchDuplicated),
+		errors.Is(err, e.ErrProductBatchSectionType),
+		errors.Is(err, e.ErrForeignKeyError),
+		errors.Is(err, e.ErrProductBatchArchived),
+		errors.Is(err, e.ErrProductBatchStatus),
+		errors.Is(err, e.ErrSectionCapacityExceeded):
+		return http.StatusConflict
+	case errors.Is(err, e.ErrProductBatchInvalid):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// batchMessage hides the internal errors of the repository
+func batchMessage(err error) string {
+	if batchStatus(err) == http.StatusInternalServerError {
+		return e.ErrRequestInternalServer.Error()
 	}
+	return err.Error()
 }
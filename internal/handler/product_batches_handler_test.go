@@ -2,13 +2,17 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/v5"
 	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -16,13 +20,14 @@ import (
 func TestProductBatchHandler_GetAll(t *testing.T) {
 	testsSlice := []struct {
 		name            string
-		mockFindAll     func() ([]mod.ProductBatch, error)
+		query           string
+		mockFindAll     func(mod.ProductBatchFilter) ([]mod.ProductBatch, error)
 		expectedStatus  int
 		expectedContent string
 	}{
 		{
 			name: "success",
-			mockFindAll: func() ([]mod.ProductBatch, error) {
+			mockFindAll: func(mod.ProductBatchFilter) ([]mod.ProductBatch, error) {
 				return []mod.ProductBatch{
 					{
 						ID:                 1,
@@ -57,12 +62,37 @@ func TestProductBatchHandler_GetAll(t *testing.T) {
 		},
 		{
 			name: "repo error",
-			mockFindAll: func() ([]mod.ProductBatch, error) {
+			mockFindAll: func(mod.ProductBatchFilter) ([]mod.ProductBatch, error) {
 				return nil, e.ErrEmptyDB
 			},
 			expectedStatus:  http.StatusNotFound,
 			expectedContent: `{"success":false,"message":"repository: empty DB","data":null}`,
 		},
+		{
+			name:            "bad filter",
+			query:           "?product_id=x",
+			expectedStatus:  http.StatusBadRequest,
+			expectedContent: `{"success":false,"message":"` + e.ErrProductBatchFilter.Error() + `","data":null}`,
+		},
+		{
+			name:  "filters",
+			query: "?product_id=1&warehouse_id=2&due_from=2024-07-01&due_to=2024-07-31&archived=false",
+			mockFindAll: func(f mod.ProductBatchFilter) ([]mod.ProductBatch, error) {
+				archived := false
+				if !reflect.DeepEqual(f, mod.ProductBatchFilter{
+					ProductID:   1,
+					WarehouseID: 2,
+					DueFrom:     time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+					DueTo:       time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+					Archived:    &archived,
+				}) {
+					return nil, e.ErrEmptyDB
+				}
+				return []mod.ProductBatch{}, nil
+			},
+			expectedStatus:  http.StatusOK,
+			expectedContent: `{"success":true,"message":"handler: data retrieved successfully","data":[]}`,
+		},
 	}
 	for _, tc := range testsSlice {
 		t.Run(tc.name, func(t *testing.T) {
@@ -71,7 +101,7 @@ func TestProductBatchHandler_GetAll(t *testing.T) {
 			}
 			handler := NewProductBatchHandler(svc)
 
-			req := httptest.NewRequest("GET", "/", nil)
+			req := httptest.NewRequest("GET", "/"+tc.query, nil)
 			rr := httptest.NewRecorder()
 			handler.GetAll().ServeHTTP(rr, req)
 
@@ -136,7 +166,7 @@ func TestProductBatchHandler_Create(t *testing.T) {
 			body:           invalidJSON,
 			mockSave:       func(pb *mod.ProductBatch) error { return nil },
 			expectedStatus: http.StatusBadRequest,
-			expectedText:   `{"success":false,"message":"handler: failed to read body","data":null}`,
+			expectedText:   `{"success":false,"message":"handler: body does not meet requirements: malformed JSON","data":null}`,
 		},
 		{
 			name: "bad section",
@@ -148,11 +178,34 @@ func TestProductBatchHandler_Create(t *testing.T) {
 			expectedText:   `{"success":false,"message":"CurrentQuantity must be greater than or equal to InitialQuantity, CurrentTemperature must be greater than or equal to MinimumTemperature, ","data":null}`,
 		},
 		{
-			name:           "save error",
+			name:           "duplicated batch",
 			body:           toJSON(validBatch),
-			mockSave:       func(pb *mod.ProductBatch) error { return e.ErrSectionRepositoryDuplicated },
+			mockSave:       func(pb *mod.ProductBatch) error { return e.ErrProductBatchDuplicated },
 			expectedStatus: http.StatusConflict,
-			expectedText:   `{"success":false,"message":"repository: section already exists","data":null}`,
+			expectedText:   `{"success":false,"message":"repository: Product Batch already exists","data":null}`,
+		},
+		{
+			name:           "missing section or product",
+			body:           toJSON(validBatch),
+			mockSave:       func(pb *mod.ProductBatch) error { return e.ErrForeignKeyError },
+			expectedStatus: http.StatusConflict,
+			expectedText:   `{"success":false,"message":"repository: unable to execute query due to foreign key error","data":null}`,
+		},
+		{
+			name:           "internal error",
+			body:           toJSON(validBatch),
+			mockSave:       func(pb *mod.ProductBatch) error { return e.ErrQueryError },
+			expectedStatus: http.StatusInternalServerError,
+			expectedText:   `{"success":false,"message":"handler: internal server error","data":null}`,
+		},
+		{
+			name: "section full",
+			body: toJSON(validBatch),
+			mockSave: func(pb *mod.ProductBatch) error {
+				return fmt.Errorf("%w: 40 units left", e.ErrSectionCapacityExceeded)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedText:   `{"success":false,"message":"repository: section does not have capacity for the batch: 40 units left","data":null}`,
 		},
 	}
 	for _, tc := range testsSlice {
@@ -163,6 +216,7 @@ func TestProductBatchHandler_Create(t *testing.T) {
 			handler := NewProductBatchHandler(svc)
 
 			req := httptest.NewRequest("POST", "/", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
 			rr := httptest.NewRecorder()
 			handler.Create().ServeHTTP(rr, req)
 
@@ -171,3 +225,333 @@ func TestProductBatchHandler_Create(t *testing.T) {
 		})
 	}
 }
+
+func TestProductBatchHandler_GetByID(t *testing.T) {
+	testsSlice := []struct {
+		name           string
+		id             string
+		mockFindByID   func(int) (mod.ProductBatch, error)
+		expectedStatus int
+		expectedText   string
+	}{
+		{
+			name:           "success",
+			id:             "1",
+			mockFindByID:   func(id int) (mod.ProductBatch, error) { return mod.ProductBatch{ID: id, BatchNumber: 7}, nil },
+			expectedStatus: http.StatusOK,
+			expectedText:   `"data":{"id":1,"batch_number":7`,
+		},
+		{
+			name:           "not found",
+			id:             "9",
+			mockFindByID:   func(id int) (mod.ProductBatch, error) { return mod.ProductBatch{}, e.ErrProductBatchNotFound },
+			expectedStatus: http.StatusNotFound,
+			expectedText:   `{"success":false,"message":"repository: Product Batch not found","data":null}`,
+		},
+		{
+			name:           "internal error",
+			id:             "1",
+			mockFindByID:   func(id int) (mod.ProductBatch, error) { return mod.ProductBatch{}, e.ErrQueryError },
+			expectedStatus: http.StatusInternalServerError,
+			expectedText:   e.ErrRequestInternalServer.Error(),
+		},
+	}
+	for _, tc := range testsSlice {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewProductBatchHandler(&mock.MockProductBatchService{MockFindByID: tc.mockFindByID})
+
+			req := httptest.NewRequest(http.MethodGet, "/"+tc.id, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tc.id)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rr := httptest.NewRecorder()
+			handler.GetByID().ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			require.Contains(t, rr.Body.String(), tc.expectedText)
+		})
+	}
+}
+
+func TestProductBatchHandler_Update(t *testing.T) {
+	testsSlice := []struct {
+		name           string
+		body           string
+		mockUpdate     func(int, mod.ProductBatchPatch) (mod.ProductBatch, error)
+		expectedStatus int
+		expectedText   string
+	}{
+		{
+			name: "success",
+			body: `{"current_quantity":15}`,
+			mockUpdate: func(id int, patch mod.ProductBatchPatch) (mod.ProductBatch, error) {
+				return mod.ProductBatch{ID: id, CurrentQuantity: *patch.CurrentQuantity}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedText:   `{"success":true,"message":"handler: product batch updated","data":{"id":1,"batch_number":0,"current_quantity":15`,
+		},
+		{
+			name:           "empty patch",
+			body:           `{}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedText:   e.ErrProductBatchPatchEmpty.Error(),
+		},
+		{
+			name: "breaks the rules",
+			body: `{"current_temperature":-10}`,
+			mockUpdate: func(int, mod.ProductBatchPatch) (mod.ProductBatch, error) {
+				return mod.ProductBatch{}, fmt.Errorf("%w: CurrentTemperature must be greater than or equal to MinimumTemperature", e.ErrProductBatchInvalid)
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedText:   "CurrentTemperature must be greater than or equal to MinimumTemperature",
+		},
+		{
+			name: "archived",
+			body: `{"current_quantity":15}`,
+			mockUpdate: func(int, mod.ProductBatchPatch) (mod.ProductBatch, error) {
+				return mod.ProductBatch{}, e.ErrProductBatchArchived
+			},
+			expectedStatus: http.StatusConflict,
+			expectedText:   e.ErrProductBatchArchived.Error(),
+		},
+		{
+			name: "section full",
+			body: `{"current_quantity":15}`,
+			mockUpdate: func(int, mod.ProductBatchPatch) (mod.ProductBatch, error) {
+				return mod.ProductBatch{}, fmt.Errorf("%w: 2 units left", e.ErrSectionCapacityExceeded)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedText:   "2 units left",
+		},
+	}
+	for _, tc := range testsSlice {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewProductBatchHandler(&mock.MockProductBatchService{MockUpdate: tc.mockUpdate})
+
+			req := httptest.NewRequest(http.MethodPatch, "/1", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "1")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rr := httptest.NewRecorder()
+			handler.Update().ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			require.Contains(t, rr.Body.String(), tc.expectedText)
+		})
+	}
+}
+
+func TestProductBatchHandler_Archive(t *testing.T) {
+	at := time.Date(2024, 7, 6, 10, 0, 0, 0, time.UTC)
+	testsSlice := []struct {
+		name           string
+		mockArchive    func(int) (mod.ProductBatch, error)
+		expectedStatus int
+		expectedText   string
+	}{
+		{
+			name:           "success",
+			mockArchive:    func(id int) (mod.ProductBatch, error) { return mod.ProductBatch{ID: id, ArchivedAt: &at}, nil },
+			expectedStatus: http.StatusOK,
+			expectedText:   `"archived_at":"2024-07-06T10:00:00Z"`,
+		},
+		{
+			name:           "already archived",
+			mockArchive:    func(int) (mod.ProductBatch, error) { return mod.ProductBatch{}, e.ErrProductBatchArchived },
+			expectedStatus: http.StatusConflict,
+			expectedText:   e.ErrProductBatchArchived.Error(),
+		},
+		{
+			name:           "not found",
+			mockArchive:    func(int) (mod.ProductBatch, error) { return mod.ProductBatch{}, e.ErrProductBatchNotFound },
+			expectedStatus: http.StatusNotFound,
+			expectedText:   e.ErrProductBatchNotFound.Error(),
+		},
+	}
+	for _, tc := range testsSlice {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewProductBatchHandler(&mock.MockProductBatchService{MockArchive: tc.mockArchive})
+
+			req := httptest.NewRequest(http.MethodPost, "/1/archive", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "1")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rr := httptest.NewRecorder()
+			handler.Archive().ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			require.Contains(t, rr.Body.String(), tc.expectedText)
+		})
+	}
+}
+
+func TestProductBatchHandler_HoldRelease(t *testing.T) {
+	changed := func(status string) func(int, mod.BatchHoldRequest) (mod.ProductBatch, error) {
+		return func(id int, req mod.BatchHoldRequest) (mod.ProductBatch, error) {
+			return mod.ProductBatch{ID: id, Status: status}, nil
+		}
+	}
+	failed := func(err error) func(int, mod.BatchHoldRequest) (mod.ProductBatch, error) {
+		return func(int, mod.BatchHoldRequest) (mod.ProductBatch, error) { return mod.ProductBatch{}, err }
+	}
+	testsSlice := []struct {
+		name           string
+		release        bool
+		body           string
+		mockChange     func(int, mod.BatchHoldRequest) (mod.ProductBatch, error)
+		expectedStatus int
+		expectedText   string
+	}{
+		{
+			name:           "hold",
+			body:           `{"reason":"arrived at 9°C","employee_id":4}`,
+			mockChange:     changed(mod.BatchQuarantined),
+			expectedStatus: http.StatusOK,
+			expectedText:   `"status":"quarantined"`,
+		},
+		{
+			name:           "hold without reason",
+			body:           `{"employee_id":4}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "hold of a rejected batch",
+			body:           `{"reason":"arrived at 9°C","employee_id":4}`,
+			mockChange:     failed(e.ErrProductBatchStatus),
+			expectedStatus: http.StatusConflict,
+			expectedText:   e.ErrProductBatchStatus.Error(),
+		},
+		{
+			name:           "hold by an unknown employee",
+			body:           `{"reason":"arrived at 9°C","employee_id":99}`,
+			mockChange:     failed(e.ErrEmployeeRepositoryNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedText:   e.ErrEmployeeRepositoryNotFound.Error(),
+		},
+		{
+			name:           "release rejects",
+			release:        true,
+			body:           `{"reason":"lab test positive","employee_id":4,"status":"rejected"}`,
+			mockChange:     changed(mod.BatchRejected),
+			expectedStatus: http.StatusOK,
+			expectedText:   `"status":"rejected"`,
+		},
+		{
+			name:           "release to an unknown status",
+			release:        true,
+			body:           `{"reason":"lab test negative","employee_id":4,"status":"available"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tc := range testsSlice {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mock.MockProductBatchService{MockHold: tc.mockChange, MockRelease: tc.mockChange}
+			handler := NewProductBatchHandler(svc)
+			serve, path := handler.Hold(), "/1/hold"
+			if tc.release {
+				serve, path = handler.Release(), "/1/release"
+			}
+
+			req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "1")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rr := httptest.NewRecorder()
+			serve.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			require.Contains(t, rr.Body.String(), tc.expectedText)
+		})
+	}
+}
+
+func TestProductBatchHandler_GetStatusHistory(t *testing.T) {
+	at := time.Date(2024, 7, 6, 10, 0, 0, 0, time.UTC)
+	svc := &mock.MockProductBatchService{MockHistory: func(id int) ([]mod.BatchStatusChange, error) {
+		if id != 1 {
+			return nil, e.ErrProductBatchNotFound
+		}
+		return []mod.BatchStatusChange{{ID: 1, ProductBatchID: 1, FromStatus: mod.BatchAvailable, ToStatus: mod.BatchQuarantined,
+			Reason: "seller reported contamination", EmployeeID: 4, CreatedAt: at}}, nil
+	}}
+	handler := NewProductBatchHandler(svc)
+
+	for id, expected := range map[string]struct {
+		status int
+		text   string
+	}{
+		"1": {http.StatusOK, `"to_status":"quarantined"`},
+		"9": {http.StatusNotFound, e.ErrProductBatchNotFound.Error()},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/"+id+"/statusHistory", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		rr := httptest.NewRecorder()
+		handler.GetStatusHistory().ServeHTTP(rr, req)
+
+		require.Equal(t, expected.status, rr.Code)
+		require.Contains(t, rr.Body.String(), expected.text)
+	}
+}
+
+func TestProductBatchHandler_Delete(t *testing.T) {
+	testsSlice := []struct {
+		name           string
+		id             string
+		mockDelete     func(int) error
+		expectedStatus int
+		expectedText   string
+	}{
+		{
+			name:           "success",
+			id:             "1",
+			mockDelete:     func(id int) error { return nil },
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "bad id",
+			id:             "x",
+			expectedStatus: http.StatusBadRequest,
+			expectedText:   `{"success":false,"message":"handler: id must be an integer","data":null}`,
+		},
+		{
+			name:           "not found",
+			id:             "9",
+			mockDelete:     func(id int) error { return e.ErrProductBatchNotFound },
+			expectedStatus: http.StatusNotFound,
+			expectedText:   `{"success":false,"message":"repository: Product Batch not found","data":null}`,
+		},
+		{
+			name:           "referenced by an order",
+			id:             "1",
+			mockDelete:     func(id int) error { return e.ErrProductBatchInUse },
+			expectedStatus: http.StatusConflict,
+			expectedText:   `{"success":false,"message":"repository: Product Batch is referenced by orders, transfers or status changes, archive it first","data":null}`,
+		},
+	}
+	for _, tc := range testsSlice {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mock.MockProductBatchService{
+				MockDelete: tc.mockDelete,
+			}
+			handler := NewProductBatchHandler(svc)
+
+			req := httptest.NewRequest(http.MethodDelete, "/"+tc.id, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tc.id)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rr := httptest.NewRecorder()
+			handler.Delete().ServeHTTP(rr, req)
+
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			if tc.expectedText == "" {
+				require.Empty(t, rr.Body.String())
+				return
+			}
+			require.Contains(t, rr.Body.String(), tc.expectedText)
+		})
+	}
+}
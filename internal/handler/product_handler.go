@@ -1,13 +1,11 @@
 package handler
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
 	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
@@ -66,14 +64,13 @@ func (h *ProductHandler) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req models.Product
 
-		err := json.NewDecoder(r.Body).Decode(&req)
+		err := utils.DecodeJSON(w, r, &req)
 		if err != nil {
-			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestNoBody.Error())
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 
-		var validate = validator.New(validator.WithRequiredStructEnabled())
-		errValidate := validate.Struct(req)
+		errValidate := e.Validator().Struct(req)
 		if errValidate != nil {
 			utils.BadResponse(w, http.StatusUnprocessableEntity, e.ErrRequestWrongBody.Error()+"\n"+errValidate.Error())
 			return
@@ -84,7 +81,7 @@ func (h *ProductHandler) Create() http.HandlerFunc {
 			utils.BadResponse(w, http.StatusConflict, err.Error())
 			return
 		}
-		if errors.Is(err, e.ErrSellerRepositoryNotFound) {
+		if errors.Is(err, e.ErrSellerRepositoryNotFound) || errors.Is(err, e.ErrProductTypeNotFound) {
 			utils.BadResponse(w, http.StatusNotFound, err.Error())
 			return
 
@@ -118,15 +115,14 @@ func (h *ProductHandler) Update() http.HandlerFunc {
 			return
 		}
 
-		err = json.NewDecoder(r.Body).Decode(&req)
+		err = utils.DecodeJSON(w, r, &req)
 		if err != nil {
-			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestNoBody.Error())
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 
 		common.PatchProduct(&currentProduct, req)
-		var validate = validator.New(validator.WithRequiredStructEnabled())
-		errValidate := validate.Struct(req)
+		errValidate := e.Validator().Struct(req)
 		if errValidate != nil {
 			utils.BadResponse(w, http.StatusUnprocessableEntity, errValidate.Error())
 			return
@@ -137,7 +133,7 @@ func (h *ProductHandler) Update() http.HandlerFunc {
 			utils.BadResponse(w, http.StatusNotFound, err.Error())
 			return
 		}
-		if errors.Is(err, e.ErrSellerRepositoryNotFound) {
+		if errors.Is(err, e.ErrSellerRepositoryNotFound) || errors.Is(err, e.ErrProductTypeNotFound) {
 			utils.BadResponse(w, http.StatusNotFound, err.Error())
 			return
 
@@ -164,6 +164,7 @@ func TestProductHandler_Create_Success(t *testing.T) {
 	}
 	body, _ := json.Marshal(product)
 	req := httptest.NewRequest(http.MethodPost, "/products", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	h.Create()(w, req)
@@ -193,6 +194,7 @@ func TestProductHandler_Create_Duplicated(t *testing.T) {
 	}
 	body, _ := json.Marshal(product)
 	req := httptest.NewRequest(http.MethodPost, "/products", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	h.Create()(w, req)
@@ -206,11 +208,12 @@ func TestProductHandler_Create_BadJSON(t *testing.T) {
 	h := NewProductHandler(mock)
 	body := []byte(`{mal json}`)
 	req := httptest.NewRequest(http.MethodPost, "/products", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	h.Create()(w, req)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), e.ErrRequestNoBody.Error())
+	assert.Contains(t, w.Body.String(), "malformed JSON")
 }
 
 func TestProductHandler_Create_ValidationError(t *testing.T) {
@@ -222,6 +225,7 @@ func TestProductHandler_Create_ValidationError(t *testing.T) {
 	product := models.Product{ID: 1}
 	body, _ := json.Marshal(product)
 	req := httptest.NewRequest(http.MethodPost, "/products", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	h.Create()(w, req)
@@ -250,6 +254,7 @@ func TestProductHandler_Create_SellerNotFound(t *testing.T) {
 	}
 	body, _ := json.Marshal(product)
 	req := httptest.NewRequest(http.MethodPost, "/products", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	h.Create()(w, req)
@@ -278,6 +283,7 @@ func TestProductHandler_Create_InternalError(t *testing.T) {
 	}
 	body, _ := json.Marshal(product)
 	req := httptest.NewRequest(http.MethodPost, "/products", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	h.Create()(w, req)
@@ -318,6 +324,7 @@ func TestProductHandler_Update_Success(t *testing.T) {
 	}
 	body, _ := json.Marshal(patch)
 	req := httptest.NewRequest(http.MethodPatch, "/products/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	ctx := chi.NewRouteContext()
 	ctx.URLParams.Add("id", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
@@ -334,6 +341,7 @@ func TestProductHandler_Update_InvalidID(t *testing.T) {
 	h := NewProductHandler(mock)
 	body := []byte(`{}`)
 	req := httptest.NewRequest(http.MethodPatch, "/products/abc", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	ctx := chi.NewRouteContext()
 	ctx.URLParams.Add("id", "abc")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
@@ -351,6 +359,7 @@ func TestProductHandler_Update_NotFound(t *testing.T) {
 	h := NewProductHandler(mock)
 	body := []byte(`{}`)
 	req := httptest.NewRequest(http.MethodPatch, "/products/99", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	ctx := chi.NewRouteContext()
 	ctx.URLParams.Add("id", "99")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
@@ -368,6 +377,7 @@ func TestProductHandler_Update_FindByIDError(t *testing.T) {
 	h := NewProductHandler(mock)
 	body := []byte(`{}`)
 	req := httptest.NewRequest(http.MethodPatch, "/products/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	ctx := chi.NewRouteContext()
 	ctx.URLParams.Add("id", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
@@ -388,6 +398,7 @@ func TestProductHandler_Update_BadJSON(t *testing.T) {
 	h := NewProductHandler(mock)
 	body := []byte(`{mal json}`)
 	req := httptest.NewRequest(http.MethodPatch, "/products/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	ctx := chi.NewRouteContext()
 	ctx.URLParams.Add("id", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
@@ -395,7 +406,7 @@ func TestProductHandler_Update_BadJSON(t *testing.T) {
 
 	h.Update()(w, req)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), e.ErrRequestNoBody.Error())
+	assert.Contains(t, w.Body.String(), "malformed JSON")
 }
 
 func TestProductHandler_Update_ValidationError(t *testing.T) {
@@ -410,6 +421,7 @@ func TestProductHandler_Update_ValidationError(t *testing.T) {
 	patch := models.ProductPatch{Height: &negativo} // Valor inválido para Height
 	body, _ := json.Marshal(patch)
 	req := httptest.NewRequest(http.MethodPatch, "/products/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	ctx := chi.NewRouteContext()
 	ctx.URLParams.Add("id", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
@@ -430,6 +442,7 @@ func TestProductHandler_Update_UpdateNotFound(t *testing.T) {
 	patch := models.ProductPatch{Description: strPtr("desc")}
 	body, _ := json.Marshal(patch)
 	req := httptest.NewRequest(http.MethodPatch, "/products/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	ctx := chi.NewRouteContext()
 	ctx.URLParams.Add("id", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
@@ -451,6 +464,7 @@ func TestProductHandler_Update_SellerNotFound(t *testing.T) {
 	patch := models.ProductPatch{Description: strPtr("desc")}
 	body, _ := json.Marshal(patch)
 	req := httptest.NewRequest(http.MethodPatch, "/products/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	ctx := chi.NewRouteContext()
 	ctx.URLParams.Add("id", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
@@ -472,6 +486,7 @@ func TestProductHandler_Update_InternalError(t *testing.T) {
 	patch := models.ProductPatch{Description: strPtr("desc")}
 	body, _ := json.Marshal(patch)
 	req := httptest.NewRequest(http.MethodPatch, "/products/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	ctx := chi.NewRouteContext()
 	ctx.URLParams.Add("id", "1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
@@ -1,12 +1,10 @@
 package handler
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
 
-	"github.com/go-playground/validator/v10"
 	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
@@ -76,14 +74,13 @@ func (h *ProductRecordHandler) CreateRecord() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req models.ProductRecord
 
-		err := json.NewDecoder(r.Body).Decode(&req)
+		err := utils.DecodeJSON(w, r, &req)
 		if err != nil {
-			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestNoBody.Error())
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 
-		var validate = validator.New(validator.WithRequiredStructEnabled())
-		errValidate := validate.Struct(req)
+		errValidate := e.Validator().Struct(req)
 		if errValidate != nil {
 			utils.BadResponse(w, http.StatusUnprocessableEntity, e.ErrRequestWrongBody.Error()+"\n"+errValidate.Error())
 			return
@@ -168,6 +168,7 @@ func TestProductRecordHandler_CreateRecord_Success(t *testing.T) {
 	record := models.ProductRecord{ID: 1, ProductID: 1, LastUpdateDate: "2025-07-28", PurchasePrice: 100.0, SalePrice: 120.0}
 	body, _ := json.Marshal(record)
 	req := httptest.NewRequest(http.MethodPost, "/product-records", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	h.CreateRecord()(w, req)
@@ -181,11 +182,12 @@ func TestProductRecordHandler_CreateRecord_BadJSON(t *testing.T) {
 	h := NewProductRecordHandler(mock)
 	body := []byte(`{mal json}`)
 	req := httptest.NewRequest(http.MethodPost, "/product-records", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	h.CreateRecord()(w, req)
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), e.ErrRequestNoBody.Error())
+	assert.Contains(t, w.Body.String(), "malformed JSON")
 }
 
 func TestProductRecordHandler_CreateRecord_ValidationError(t *testing.T) {
@@ -196,6 +198,7 @@ func TestProductRecordHandler_CreateRecord_ValidationError(t *testing.T) {
 	record := models.ProductRecord{ID: 1} // Faltan campos requeridos
 	body, _ := json.Marshal(record)
 	req := httptest.NewRequest(http.MethodPost, "/product-records", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	h.CreateRecord()(w, req)
@@ -211,6 +214,7 @@ func TestProductRecordHandler_CreateRecord_ProductNotFound(t *testing.T) {
 	record := models.ProductRecord{ID: 1, ProductID: 1, LastUpdateDate: "2025-07-28", PurchasePrice: 100.0, SalePrice: 120.0}
 	body, _ := json.Marshal(record)
 	req := httptest.NewRequest(http.MethodPost, "/product-records", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	h.CreateRecord()(w, req)
@@ -226,6 +230,7 @@ func TestProductRecordHandler_CreateRecord_InternalError(t *testing.T) {
 	record := models.ProductRecord{ID: 1, ProductID: 1, LastUpdateDate: "2025-07-28", PurchasePrice: 100.0, SalePrice: 120.0}
 	body, _ := json.Marshal(record)
 	req := httptest.NewRequest(http.MethodPost, "/product-records", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	h.CreateRecord()(w, req)
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewProductTypeHandler creates a new instance of the product type handler
+func NewProductTypeHandler(sv internal.ProductTypeService) *ProductTypeHandler {
+	return &ProductTypeHandler{
+		sv: sv,
+	}
+}
+
+// ProductTypeHandler serves the product type registry
+type ProductTypeHandler struct {
+	// sv is the service used by the handler
+	sv internal.ProductTypeService
+}
+
+// GetAll returns all the product types
+func (h *ProductTypeHandler) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		types, err := h.sv.FindAll()
+		if err != nil {
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, types)
+	}
+}
+
+// GetByID returns the product type of the path
+func (h *ProductTypeHandler) GetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		pt, err := h.sv.FindByID(id)
+		if err != nil {
+			utils.BadResponse(w, productTypeStatus(err), productTypeMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, pt)
+	}
+}
+
+// Create registers a product type
+func (h *ProductTypeHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req mod.ProductType
+		if err := utils.DecodeJSON(w, r, &req); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
+			return
+		}
+		if msg := validationMessage(req); msg != "" {
+			utils.BadResponse(w, http.StatusUnprocessableEntity, msg)
+			return
+		}
+		if err := h.sv.Save(&req); err != nil {
+			utils.BadResponse(w, productTypeStatus(err), productTypeMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusCreated, e.ProductTypeCreated, req)
+	}
+}
+
+// Update applies the fields of the body to the product type of the path
+func (h *ProductTypeHandler) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		var req mod.ProductTypePatch
+		if err := utils.DecodeJSON(w, r, &req); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
+			return
+		}
+		if msg := validationMessage(req); msg != "" {
+			utils.BadResponse(w, http.StatusUnprocessableEntity, msg)
+			return
+		}
+		pt, err := h.sv.Update(id, req)
+		if err != nil {
+			utils.BadResponse(w, productTypeStatus(err), productTypeMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.ProductTypeUpdated, pt)
+	}
+}
+
+// Delete deletes the product type of the path
+func (h *ProductTypeHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		if err = h.sv.Delete(id); err != nil {
+			utils.BadResponse(w, productTypeStatus(err), productTypeMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusNoContent, e.ProductTypeDeleted, nil)
+	}
+}
+
+// validationMessage returns the sorted validation errors of the request, empty when it is valid
+func validationMessage(req any) string {
+	errs := e.ValidateStruct(req)
+	if len(errs) == 0 {
+		return ""
+	}
+	msgs := make([]string, 0, len(errs))
+	for _, msg := range errs {
+		msgs = append(msgs, msg)
+	}
+	sort.Strings(msgs)
+	return strings.Join(msgs, ", ")
+}
+
+// productTypeStatus returns the status code of a product type error
+func productTypeStatus(err error) int {
+	switch {
+	case errors.Is(err, e.ErrProductTypeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, e.ErrProductTypeDuplicated),
+		errors.Is(err, e.ErrProductTypeInUse):
+		return http.StatusConflict
+	case errors.Is(err, e.ErrProductTypeRangeInvalid):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// productTypeMessage hides the internal errors of the repository
+func productTypeMessage(err error) string {
+	if productTypeStatus(err) == http.StatusInternalServerError {
+		return e.ErrRequestInternalServer.Error()
+	}
+	return err.Error()
+}
@@ -0,0 +1,139 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hd "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/handler"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	tests2 "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProductTypeHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		callService    bool
+		mockErr        error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "#1 Success",
+			body:           `{"name":"Chilled","minimum_temperature":0,"maximum_temperature":4,"hazard_class":"none"}`,
+			callService:    true,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"success":true,"message":"handler: product type created","data":{"id":5,"name":"Chilled","minimum_temperature":0,"maximum_temperature":4,"hazard_class":"none"}}`,
+		},
+		{
+			name:           "#2 Error - Missing range and unknown hazard class",
+			body:           `{"name":"Chilled","hazard_class":"radioactive"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"success":false,"message":"HazardClass failed on oneof validation, MaximumTemperature is required, MinimumTemperature is required","data":null}`,
+		},
+		{
+			name:           "#3 Error - Inverted range",
+			body:           `{"name":"Chilled","minimum_temperature":4,"maximum_temperature":0,"hazard_class":"none"}`,
+			callService:    true,
+			mockErr:        e.ErrProductTypeRangeInvalid,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"success":false,"message":"service: minimum_temperature must not be above maximum_temperature","data":null}`,
+		},
+		{
+			name:           "#4 Error - Duplicated name",
+			body:           `{"name":"Chilled","minimum_temperature":0,"maximum_temperature":4,"hazard_class":"none"}`,
+			callService:    true,
+			mockErr:        e.ErrProductTypeDuplicated,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"success":false,"message":"repository: product type already exists","data":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(tests2.MockProductTypeService)
+			if tt.callService {
+				mockService.On("Save", mock.AnythingOfType("*models.ProductType")).Return(tt.mockErr).Run(func(args mock.Arguments) {
+					if tt.mockErr == nil {
+						args.Get(0).(*mod.ProductType).ID = 5
+					}
+				}).Once()
+			}
+			handler := hd.NewProductTypeHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/productTypes", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			handler.Create().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductTypeHandler_Update(t *testing.T) {
+	minimum, maximum := 0.0, 6.0
+
+	t.Run("#1 Success", func(t *testing.T) {
+		mockService := new(tests2.MockProductTypeService)
+		mockService.On("Update", 1, mod.ProductTypePatch{MaximumTemperature: &maximum}).
+			Return(mod.ProductType{ID: 1, Name: "Chilled", MinimumTemperature: &minimum, MaximumTemperature: &maximum, HazardClass: "none"}, nil).Once()
+		handler := hd.NewProductTypeHandler(mockService)
+
+		req := httptest.NewRequest(http.MethodPatch, "/v1/productTypes/1", strings.NewReader(`{"maximum_temperature":6}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		handler.Update().ServeHTTP(rr, withURLParam(req, "id", "1"))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"success":true,"message":"handler: product type updated","data":{"id":1,"name":"Chilled","minimum_temperature":0,"maximum_temperature":6,"hazard_class":"none"}}`, rr.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("#2 Error - Not found", func(t *testing.T) {
+		mockService := new(tests2.MockProductTypeService)
+		mockService.On("Update", 9, mod.ProductTypePatch{MaximumTemperature: &maximum}).Return(mod.ProductType{}, e.ErrProductTypeNotFound).Once()
+		handler := hd.NewProductTypeHandler(mockService)
+
+		req := httptest.NewRequest(http.MethodPatch, "/v1/productTypes/9", strings.NewReader(`{"maximum_temperature":6}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		handler.Update().ServeHTTP(rr, withURLParam(req, "id", "9"))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestProductTypeHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "#1 Success", expectedStatus: http.StatusNoContent},
+		{name: "#2 Error - Not found", mockErr: e.ErrProductTypeNotFound, expectedStatus: http.StatusNotFound},
+		{name: "#3 Error - In use", mockErr: e.ErrProductTypeInUse, expectedStatus: http.StatusConflict},
+		{name: "#4 Error - Internal", mockErr: e.ErrQueryError, expectedStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(tests2.MockProductTypeService)
+			mockService.On("Delete", 1).Return(tt.mockErr).Once()
+			handler := hd.NewProductTypeHandler(mockService)
+
+			rr := httptest.NewRecorder()
+			handler.Delete().ServeHTTP(rr, withURLParam(httptest.NewRequest(http.MethodDelete, "/v1/productTypes/1", nil), "id", "1"))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
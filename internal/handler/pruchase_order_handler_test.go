@@ -74,7 +74,7 @@ func (s *THandlerPurchaseOrderSuite) TestPurchaseOrderHandler_Create() {
 		{
 			name:           "Case 2: Fail - Failed body",
 			mockError:      e.ErrRequestFailedBody,
-			expectedBody:   `{"success":false,"message":"handler: failed to read body","data":null}`,
+			expectedBody:   `{"success":false,"message":"handler: request must have a body","data":null}`,
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
@@ -1,14 +1,15 @@
 package handler
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/go-chi/chi/v5"
 	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
 	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
 	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
@@ -23,13 +24,47 @@ type PurchaseOrderHandler struct {
 	sv internal.PurchaseOrderService
 }
 
+// GetAll returns all purchase orders
+func (h *PurchaseOrderHandler) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		purchaseOrders, err := h.sv.FindAll()
+		if err != nil {
+			utils.BadResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, purchaseOrders)
+	}
+}
+
+// GetByID returns a purchase order
+func (h *PurchaseOrderHandler) GetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+
+		purchaseOrder, err := h.sv.FindByID(id)
+		if err != nil {
+			switch {
+			case errors.Is(err, e.ErrPORepositoryNotFound):
+				utils.BadResponse(w, http.StatusNotFound, err.Error())
+			default:
+				utils.BadResponse(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, purchaseOrder)
+	}
+}
+
 func (h *PurchaseOrderHandler) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var newPurchaseOrder mod.PurchaseOrder
 
-		if err := json.NewDecoder(r.Body).Decode(&newPurchaseOrder); err != nil {
-
-			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestFailedBody.Error())
+		if err := utils.DecodeJSON(w, r, &newPurchaseOrder); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 
@@ -64,7 +99,8 @@ func (h *PurchaseOrderHandler) Create() http.HandlerFunc {
 
 		if err != nil {
 			switch {
-			case errors.Is(err, e.ErrPORepositoryOrderNumberDuplicated) || errors.Is(err, e.ErrForeignKeyError):
+			case errors.Is(err, e.ErrPORepositoryOrderNumberDuplicated) || errors.Is(err, e.ErrForeignKeyError),
+				errors.Is(err, e.ErrStockInsufficient):
 				utils.BadResponse(w, http.StatusConflict, err.Error())
 			case errors.Is(err, e.ErrBuyerRepositoryNotFound):
 				utils.BadResponse(w, http.StatusConflict, err.Error())
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// reportContentTypes are the content types of the report formats
+var reportContentTypes = map[string]string{
+	mod.ReportJSON: "application/json",
+	mod.ReportCSV:  "text/csv",
+}
+
+// NewReportHandler creates a new instance of the report handler
+func NewReportHandler(sv internal.ReportService) *ReportHandler {
+	return &ReportHandler{
+		sv: sv,
+	}
+}
+
+// ReportHandler queues the reports and serves their results
+type ReportHandler struct {
+	// sv is the service used by the handler
+	sv internal.ReportService
+}
+
+// Create queues the report of the type in the path, e.g. POST /v1/reports/purchaseOrders with {"id":1,"format":"csv"}.
+// The body is optional and the response points to the job to poll
+func (h *ReportHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var params mod.ReportParams
+		if r.ContentLength != 0 {
+			if err := utils.DecodeJSON(w, r, &params); err != nil {
+				utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
+				return
+			}
+		}
+		if err := e.Validator().Struct(params); err != nil {
+			utils.BadResponse(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s: %v", e.ErrRequestWrongBody, err))
+			return
+		}
+
+		job, err := h.sv.Enqueue(chi.URLParam(r, "type"), params)
+		if err != nil {
+			switch {
+			case errors.Is(err, e.ErrReportTypeInvalid):
+				utils.BadResponse(w, http.StatusNotFound, err.Error())
+			case errors.Is(err, e.ErrReportQueueFull):
+				utils.BadResponse(w, http.StatusServiceUnavailable, err.Error())
+			default:
+				utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			}
+			return
+		}
+		w.Header().Set("Location", "/v1/reports/jobs/"+job.ID)
+		utils.GoodResponse(w, http.StatusAccepted, e.ReportQueued, job)
+	}
+}
+
+// GetJob returns the status and progress of the report
+func (h *ReportHandler) GetJob() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, err := h.sv.FindByID(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, job)
+	}
+}
+
+// Download serves the file of a finished report
+func (h *ReportHandler) Download() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, file, err := h.sv.Open(chi.URLParam(r, "id"))
+		if err != nil {
+			switch {
+			case errors.Is(err, e.ErrReportNotFound):
+				utils.BadResponse(w, http.StatusNotFound, err.Error())
+			case errors.Is(err, e.ErrReportNotReady):
+				utils.BadResponse(w, http.StatusConflict, fmt.Sprintf("%s: status is %s", err, job.Status))
+			default:
+				utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			}
+			return
+		}
+		defer file.Close()
+
+		name := fmt.Sprintf("%s-%s.%s", job.Type, job.ID, job.Params.Format)
+		w.Header().Set("Content-Type", reportContentTypes[job.Params.Format])
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+		http.ServeContent(w, r, name, *job.FinishedAt, file)
+	}
+}
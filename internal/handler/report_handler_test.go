@@ -0,0 +1,165 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	hd "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/handler"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	tests2 "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// withURLParam adds a chi URL parameter to the request
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	ctx := chi.NewRouteContext()
+	ctx.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+}
+
+// nopReadSeekCloser adds a no-op Close to a strings.Reader
+type nopReadSeekCloser struct {
+	*strings.Reader
+}
+
+func (nopReadSeekCloser) Close() error { return nil }
+
+func TestReportHandler_Create(t *testing.T) {
+	id := 3
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		body           string
+		mockCall       bool
+		mockParams     mod.ReportParams
+		mockErr        error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "#1 Success - Without body",
+			mockCall:       true,
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `{"success":true,"message":"handler: report queued","data":{"id":"abc","type":"purchaseOrders","params":{"format":"json"},"status":"queued","progress":0,"rows":0,"created_at":"2024-05-01T10:00:00Z"}}`,
+		},
+		{
+			name:           "#2 Success - With parameters",
+			body:           `{"id":3,"format":"csv"}`,
+			mockCall:       true,
+			mockParams:     mod.ReportParams{ID: &id, Format: mod.ReportCSV},
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `{"success":true,"message":"handler: report queued","data":{"id":"abc","type":"purchaseOrders","params":{"format":"json"},"status":"queued","progress":0,"rows":0,"created_at":"2024-05-01T10:00:00Z"}}`,
+		},
+		{
+			name:           "#3 Success - With report filters",
+			body:           `{"ids":[1,2],"warehouse_id":4,"product_type_id":2,"windows":[7,15]}`,
+			mockCall:       true,
+			mockParams:     mod.ReportParams{IDs: []int{1, 2}, WarehouseID: 4, ProductTypeID: 2, Windows: []int{7, 15}},
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   `{"success":true,"message":"handler: report queued","data":{"id":"abc","type":"purchaseOrders","params":{"format":"json"},"status":"queued","progress":0,"rows":0,"created_at":"2024-05-01T10:00:00Z"}}`,
+		},
+		{
+			name:           "#4 Error - Unknown sort",
+			body:           `{"sort":"name"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "#5 Error - Unknown format",
+			body:           `{"format":"xml"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "#6 Error - Unknown field",
+			body:           `{"buyer_id":3}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"message":"handler: body has an unknown field: \"buyer_id\"","data":null}`,
+		},
+		{
+			name:           "#7 Error - Unknown type",
+			mockCall:       true,
+			mockErr:        e.ErrReportTypeInvalid,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "#8 Error - Queue full",
+			mockCall:       true,
+			mockErr:        e.ErrReportQueueFull,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv := new(tests2.MockReportService)
+			if tt.mockCall {
+				job := mod.ReportJob{ID: "abc", Type: "purchaseOrders", Params: mod.ReportParams{Format: mod.ReportJSON}, Status: mod.ReportQueued, CreatedAt: created}
+				sv.On("Enqueue", "purchaseOrders", tt.mockParams).Return(job, tt.mockErr)
+			}
+			h := hd.NewReportHandler(sv)
+			req := httptest.NewRequest(http.MethodPost, "/v1/reports/purchaseOrders", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req = withURLParam(req, "type", "purchaseOrders")
+			res := httptest.NewRecorder()
+
+			h.Create()(res, req)
+
+			assert.Equal(t, tt.expectedStatus, res.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, res.Body.String())
+			}
+			if tt.expectedStatus == http.StatusAccepted {
+				assert.Equal(t, "/v1/reports/jobs/abc", res.Header().Get("Location"))
+			}
+			sv.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReportHandler_GetJob(t *testing.T) {
+	sv := new(tests2.MockReportService)
+	sv.On("FindByID", "abc").Return(mod.ReportJob{ID: "abc", Status: mod.ReportRunning, Progress: 10}, nil)
+	sv.On("FindByID", "nope").Return(mod.ReportJob{}, e.ErrReportNotFound)
+	h := hd.NewReportHandler(sv)
+
+	res := httptest.NewRecorder()
+	h.GetJob()(res, withURLParam(httptest.NewRequest(http.MethodGet, "/v1/reports/jobs/abc", nil), "id", "abc"))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), `"status":"running","progress":10`)
+
+	res = httptest.NewRecorder()
+	h.GetJob()(res, withURLParam(httptest.NewRequest(http.MethodGet, "/v1/reports/jobs/nope", nil), "id", "nope"))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
+
+func TestReportHandler_Download(t *testing.T) {
+	finished := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	done := mod.ReportJob{ID: "abc", Type: "carries", Params: mod.ReportParams{Format: mod.ReportCSV}, Status: mod.ReportDone, FinishedAt: &finished}
+	sv := new(tests2.MockReportService)
+	sv.On("Open", "abc").Return(done, nopReadSeekCloser{strings.NewReader("id\n1\n")}, nil)
+	sv.On("Open", "running").Return(mod.ReportJob{Status: mod.ReportRunning}, nil, e.ErrReportNotReady)
+	sv.On("Open", mock.Anything).Return(mod.ReportJob{}, nil, e.ErrReportNotFound)
+	h := hd.NewReportHandler(sv)
+
+	res := httptest.NewRecorder()
+	h.Download()(res, withURLParam(httptest.NewRequest(http.MethodGet, "/v1/reports/jobs/abc/download", nil), "id", "abc"))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "text/csv", res.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="carries-abc.csv"`, res.Header().Get("Content-Disposition"))
+	assert.Equal(t, "id\n1\n", res.Body.String())
+
+	res = httptest.NewRecorder()
+	h.Download()(res, withURLParam(httptest.NewRequest(http.MethodGet, "/v1/reports/jobs/running/download", nil), "id", "running"))
+	assert.Equal(t, http.StatusConflict, res.Code)
+	assert.Contains(t, res.Body.String(), "status is running")
+
+	res = httptest.NewRecorder()
+	h.Download()(res, withURLParam(httptest.NewRequest(http.MethodGet, "/v1/reports/jobs/nope/download", nil), "id", "nope"))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
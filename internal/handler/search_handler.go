@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+const (
+	// searchDefaultLimit is the number of results returned when no limit is given
+	searchDefaultLimit = 20
+	// searchMaxLimit is the greatest limit accepted
+	searchMaxLimit = 100
+)
+
+// NewSearchHandler creates a new instance of the search handler
+func NewSearchHandler(sv internal.SearchService) *SearchHandler {
+	return &SearchHandler{
+		sv: sv,
+	}
+}
+
+// SearchHandler is the default implementation of the search handler
+type SearchHandler struct {
+	// sv is the service used by the handler
+	sv internal.SearchService
+}
+
+// Search returns the entities matching q, e.g. /v1/search?q=frozen&types=products,sellers&limit=10
+func (h *SearchHandler) Search() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		if len(common.SearchTerms(q)) == 0 {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrSearchQueryRequired.Error())
+			return
+		}
+		types, err := common.ParseSearchTypes(r.URL.Query().Get("types"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		limit := searchDefaultLimit
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			limit, err = strconv.Atoi(raw)
+			if err != nil || limit < 1 || limit > searchMaxLimit {
+				utils.BadResponse(w, http.StatusBadRequest, e.ErrSearchLimitInvalid.Error())
+				return
+			}
+		}
+
+		result, err := h.sv.Search(q, types, limit)
+		if err != nil {
+			if errors.Is(err, e.ErrSearchQueryRequired) || errors.Is(err, e.ErrSearchTypeInvalid) {
+				utils.BadResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, result)
+	}
+}
@@ -0,0 +1,99 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	hd "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/handler"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	tests2 "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchHandler_Search(t *testing.T) {
+	found := []mod.SearchResult{
+		{Type: "products", ID: 1, Title: "P001", Score: 2, Fields: map[string]string{"description": "Green peas"}, Highlights: map[string]string{"description": "Green <em>peas</em>"}},
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		mockCall       bool
+		mockTypes      []string
+		mockLimit      int
+		mockReturn     []mod.SearchResult
+		mockErr        error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "#1 Success - Every type with the default limit",
+			url:            "/v1/search?q=peas",
+			mockCall:       true,
+			mockTypes:      mod.SearchTypes,
+			mockLimit:      20,
+			mockReturn:     found,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"message":"handler: data retrieved successfully","data":[{"type":"products","id":1,"title":"P001","score":2,"fields":{"description":"Green peas"},"highlights":{"description":"Green <em>peas</em>"}}]}`,
+		},
+		{
+			name:           "#2 Success - Given types and limit",
+			url:            "/v1/search?q=peas&types=products,sellers&limit=5",
+			mockCall:       true,
+			mockTypes:      []string{"products", "sellers"},
+			mockLimit:      5,
+			mockReturn:     []mod.SearchResult{},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"success":true,"message":"handler: data retrieved successfully","data":[]}`,
+		},
+		{
+			name:           "#3 Error - Missing query",
+			url:            "/v1/search?q=%20",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"message":"handler: q is required","data":null}`,
+		},
+		{
+			name:           "#4 Error - Unknown type",
+			url:            "/v1/search?q=peas&types=orders",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"message":"handler: types must be a list of products, sellers, buyers, warehouses or carries","data":null}`,
+		},
+		{
+			name:           "#5 Error - Limit out of range",
+			url:            "/v1/search?q=peas&limit=500",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"message":"handler: limit must be an integer between 1 and 100","data":null}`,
+		},
+		{
+			name:           "#6 Error - Repository fails",
+			url:            "/v1/search?q=peas&types=carries",
+			mockCall:       true,
+			mockTypes:      []string{"carries"},
+			mockLimit:      20,
+			mockReturn:     []mod.SearchResult(nil),
+			mockErr:        e.ErrQueryError,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"success":false,"message":"handler: internal server error","data":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(tests2.MockSearchService)
+			if tt.mockCall {
+				mockService.On("Search", "peas", tt.mockTypes, tt.mockLimit).Return(tt.mockReturn, tt.mockErr).Once()
+			}
+			handler := hd.NewSearchHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			rr := httptest.NewRecorder()
+			handler.Search().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -1,15 +1,15 @@
 package handler
 
 import (
-	"encoding/json"
 	"errors"
-	"fmt"
 	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
 	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 // NewSectionHandler creates a new instance of the section handler
@@ -59,9 +59,9 @@ func (h *SectionHandler) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var model models.Section
 
-		err := json.NewDecoder(r.Body).Decode(&model)
+		err := utils.DecodeJSON(w, r, &model)
 		if err != nil {
-			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestFailedBody.Error())
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 		validationErrors := e.ValidateStruct(model)
@@ -75,6 +75,10 @@ func (h *SectionHandler) Create() http.HandlerFunc {
 		}
 		err = h.sv.Save(&model)
 		if err != nil {
+			if errors.Is(err, e.ErrProductTypeNotFound) {
+				utils.BadResponse(w, http.StatusNotFound, err.Error())
+				return
+			}
 			utils.BadResponse(w, http.StatusConflict, err.Error())
 			return
 		}
@@ -91,9 +95,9 @@ func (h *SectionHandler) Update() http.HandlerFunc {
 			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
 			return
 		}
-		err = json.NewDecoder(r.Body).Decode(&model)
+		err = utils.DecodeJSON(w, r, &model)
 		if err != nil {
-			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestFailedBody.Error())
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 
@@ -112,11 +116,15 @@ func (h *SectionHandler) Update() http.HandlerFunc {
 
 		result, err := h.sv.Update(id, fields)
 		if err != nil {
-			if errors.Is(err, e.ErrSectionRepositoryNotFound) {
+			switch {
+			case errors.Is(err, e.ErrSectionRepositoryNotFound) || errors.Is(err, e.ErrProductTypeNotFound):
 				utils.BadResponse(w, http.StatusNotFound, err.Error())
-			}
-			if errors.Is(err, e.ErrNoRowsAffected) {
+			case errors.Is(err, e.ErrNoRowsAffected):
 				utils.BadResponse(w, http.StatusUnprocessableEntity, e.ErrNothingToUpdate.Error())
+			case errors.Is(err, e.ErrForeignKeyError):
+				utils.BadResponse(w, http.StatusConflict, err.Error())
+			default:
+				utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
 			}
 			return
 		}
@@ -142,19 +150,88 @@ func (h *SectionHandler) Delete() http.HandlerFunc {
 	}
 }
 
+// ReportProducts returns the stock report of the sections, filtered by ids, warehouse_id and product_type_id
 func (h *SectionHandler) ReportProducts() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		params := r.URL.Query().Get("ids")
-		ids, err := common.ParseIDs(params)
+		filter, err := sectionReportFilter(r)
 		if err != nil {
 			utils.BadResponse(w, http.StatusBadRequest, err.Error())
 			return
 		}
-		res, err := h.sv.ReportProducts(ids)
+		res, err := h.sv.ReportProducts(filter)
 		if err != nil {
-			utils.BadResponse(w, http.StatusNotFound, fmt.Sprintf("db error: %s", err.Error()))
+			if errors.Is(err, e.ErrSectionRepositoryNotFound) {
+				utils.BadResponse(w, http.StatusNotFound, err.Error())
+				return
+			}
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
 			return
 		}
 		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, res)
 	}
 }
+
+// Recommend returns the sections that can take a batch of quantity units of product_id, best first, optionally
+// in warehouse_id
+func (h *SectionHandler) Recommend() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req models.SlottingRequest
+		query := r.URL.Query()
+		for param, dest := range map[string]*int{"product_id": &req.ProductID, "quantity": &req.Quantity, "warehouse_id": &req.WarehouseID} {
+			value := strings.TrimSpace(query.Get(param))
+			if value == "" && param == "warehouse_id" {
+				continue
+			}
+			var err error
+			if *dest, err = strconv.Atoi(value); err != nil || *dest <= 0 {
+				utils.BadResponse(w, http.StatusBadRequest, e.ErrSectionRecommendInvalid.Error())
+				return
+			}
+		}
+		res, err := h.sv.Recommend(req)
+		if err != nil {
+			if errors.Is(err, e.ErrProductRepositoryNotFound) {
+				utils.BadResponse(w, http.StatusNotFound, err.Error())
+				return
+			}
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, res)
+	}
+}
+
+// sectionReportMaxWindows is the number of expiry windows a products report accepts
+const sectionReportMaxWindows = 5
+
+// sectionReportFilter reads the ids, warehouse_id, product_type_id and windows of the products report query
+func sectionReportFilter(r *http.Request) (models.SectionReportFilter, error) {
+	query := r.URL.Query()
+	ids, err := common.ParseIDs(query.Get("ids"))
+	if err != nil {
+		return models.SectionReportFilter{}, err
+	}
+	filter := models.SectionReportFilter{IDs: ids}
+	for param, dest := range map[string]*int{"warehouse_id": &filter.WarehouseID, "product_type_id": &filter.ProductTypeID} {
+		value := strings.TrimSpace(query.Get(param))
+		if value == "" {
+			continue
+		}
+		if *dest, err = strconv.Atoi(value); err != nil || *dest <= 0 {
+			return models.SectionReportFilter{}, e.ErrSectionReportFilterInvalid
+		}
+	}
+	if windows := strings.TrimSpace(query.Get("windows")); windows != "" {
+		for _, value := range strings.Split(windows, ",") {
+			days, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || days < 1 || days > 365 {
+				return models.SectionReportFilter{}, e.ErrSectionReportWindowsInvalid
+			}
+			filter.Windows = append(filter.Windows, days)
+		}
+		if len(filter.Windows) > sectionReportMaxWindows {
+			return models.SectionReportFilter{}, e.ErrSectionReportWindowsInvalid
+		}
+	}
+	return filter, nil
+}
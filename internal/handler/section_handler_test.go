@@ -161,7 +161,7 @@ func TestSectionHandler_Create(t *testing.T) {
 			body:           badJSON,
 			mockSave:       func(s *mod.Section) error { return nil },
 			expectedStatus: http.StatusBadRequest,
-			expectedString: "failed to read body",
+			expectedString: "malformed JSON",
 		},
 		{
 			name:           "repo conflict",
@@ -170,12 +170,20 @@ func TestSectionHandler_Create(t *testing.T) {
 			expectedStatus: http.StatusConflict,
 			expectedString: "section already exists",
 		},
+		{
+			name:           "unknown product type",
+			body:           toJSON(t, valid),
+			mockSave:       func(s *mod.Section) error { return e.ErrProductTypeNotFound },
+			expectedStatus: http.StatusNotFound,
+			expectedString: e.ErrProductTypeNotFound.Error(),
+		},
 	}
 	for _, tc := range testsSlice {
 		t.Run(tc.name, func(t *testing.T) {
 			svc := &mock.MockSectionService{MockSave: tc.mockSave}
 			handler := NewSectionHandler(svc)
 			req := httptest.NewRequest("POST", "/", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
 			rr := httptest.NewRecorder()
 			handler.Create().ServeHTTP(rr, req)
 			require.Equal(t, tc.expectedStatus, rr.Code)
@@ -187,6 +195,9 @@ func TestSectionHandler_Create(t *testing.T) {
 func TestSectionHandler_Update(t *testing.T) {
 	validUpdate := mod.Section{ID: 1, SectionNumber: 2, CurrentTemperature: 2, MinimumTemperature: 1, CurrentCapacity: 5, MinimumCapacity: 1, MaximumCapacity: 10, WarehouseID: 1, ProductTypeID: 1}
 	invalidUpdate := mod.Section{SectionNumber: 1, CurrentTemperature: -1}
+	// the id comes from the url, the body must not carry it
+	updateBody := validUpdate
+	updateBody.ID = 0
 	badUpdate := `{"section_number":`
 
 	testsSlice := []struct {
@@ -200,7 +211,7 @@ func TestSectionHandler_Update(t *testing.T) {
 		{
 			name: "update success",
 			id:   "1",
-			body: toJSON(t, validUpdate),
+			body: toJSON(t, updateBody),
 			mockUpdate: func(id int, fields map[string]interface{}) (*mod.Section, error) {
 				return &validUpdate, nil
 			},
@@ -220,7 +231,7 @@ func TestSectionHandler_Update(t *testing.T) {
 		{
 			name: "invalid update nothing to update",
 			id:   "1",
-			body: toJSON(t, validUpdate),
+			body: toJSON(t, updateBody),
 			mockUpdate: func(i int, m map[string]interface{}) (*mod.Section, error) {
 				return nil, e.ErrNoRowsAffected
 			},
@@ -230,7 +241,7 @@ func TestSectionHandler_Update(t *testing.T) {
 		{
 			name: "bad id",
 			id:   "foo",
-			body: toJSON(t, validUpdate),
+			body: toJSON(t, updateBody),
 			mockUpdate: func(id int, fields map[string]interface{}) (*mod.Section, error) {
 				return nil, e.ErrQueryError
 			},
@@ -250,19 +261,40 @@ func TestSectionHandler_Update(t *testing.T) {
 		{
 			name: "update not found",
 			id:   "100",
-			body: toJSON(t, validUpdate),
+			body: toJSON(t, updateBody),
 			mockUpdate: func(id int, fields map[string]interface{}) (*mod.Section, error) {
 				return nil, e.ErrSectionRepositoryNotFound
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedString: "not found",
 		},
+		{
+			name: "update unknown product type",
+			id:   "1",
+			body: toJSON(t, updateBody),
+			mockUpdate: func(id int, fields map[string]interface{}) (*mod.Section, error) {
+				return nil, e.ErrProductTypeNotFound
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedString: e.ErrProductTypeNotFound.Error(),
+		},
+		{
+			name: "update snapshot fails",
+			id:   "1",
+			body: toJSON(t, updateBody),
+			mockUpdate: func(id int, fields map[string]interface{}) (*mod.Section, error) {
+				return nil, e.ErrInsertError
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedString: "handler: internal server error",
+		},
 	}
 	for _, tc := range testsSlice {
 		t.Run(tc.name, func(t *testing.T) {
 			svc := &mock.MockSectionService{MockUpdate: tc.mockUpdate}
 			handler := NewSectionHandler(svc)
 			req := httptest.NewRequest("PATCH", "/"+tc.id, bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
 			rctx := chi.NewRouteContext()
 			rctx.URLParams.Add("id", tc.id)
 			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -287,14 +319,14 @@ func TestSectionHandler_ReportProducts(t *testing.T) {
 	testsSlice := []struct {
 		name           string
 		ids            string
-		mockReport     func([]int) ([]mod.ReportProductsResponse, error)
+		mockReport     func(mod.SectionReportFilter) ([]mod.ReportProductsResponse, error)
 		expectedStatus int
 		expectedBody   string
 	}{
 		{
 			name: "success-one id",
 			ids:  "1",
-			mockReport: func(ids []int) ([]mod.ReportProductsResponse, error) {
+			mockReport: func(f mod.SectionReportFilter) ([]mod.ReportProductsResponse, error) {
 				return oneRes, nil
 			},
 			expectedStatus: http.StatusOK,
@@ -303,7 +335,7 @@ func TestSectionHandler_ReportProducts(t *testing.T) {
 		{
 			name: "success-multiple-ids",
 			ids:  "1,2",
-			mockReport: func(ints []int) ([]mod.ReportProductsResponse, error) {
+			mockReport: func(f mod.SectionReportFilter) ([]mod.ReportProductsResponse, error) {
 				return multipleRes, nil
 			},
 			expectedStatus: http.StatusOK,
@@ -311,7 +343,7 @@ func TestSectionHandler_ReportProducts(t *testing.T) {
 		},
 		{
 			name: "success-no-args",
-			mockReport: func(ints []int) ([]mod.ReportProductsResponse, error) {
+			mockReport: func(f mod.SectionReportFilter) ([]mod.ReportProductsResponse, error) {
 				return multipleRes, nil
 			},
 			expectedStatus: http.StatusOK,
@@ -320,7 +352,7 @@ func TestSectionHandler_ReportProducts(t *testing.T) {
 		{
 			name: "error in ID parsing",
 			ids:  "test",
-			mockReport: func(ints []int) ([]mod.ReportProductsResponse, error) {
+			mockReport: func(f mod.SectionReportFilter) ([]mod.ReportProductsResponse, error) {
 				return nil, e.ErrRequestIdMustBeInt
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -329,11 +361,54 @@ func TestSectionHandler_ReportProducts(t *testing.T) {
 		{
 			name: "section not found",
 			ids:  "500",
-			mockReport: func(ids []int) ([]mod.ReportProductsResponse, error) {
-				return nil, errors.New("not found")
+			mockReport: func(f mod.SectionReportFilter) ([]mod.ReportProductsResponse, error) {
+				return nil, e.ErrSectionRepositoryNotFound
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   "not found",
+			expectedBody:   "section not found",
+		},
+		{
+			name: "success-filters",
+			ids:  "1&warehouse_id=2&product_type_id=3&windows=1,14",
+			mockReport: func(f mod.SectionReportFilter) ([]mod.ReportProductsResponse, error) {
+				require.Equal(t, mod.SectionReportFilter{IDs: []int{1}, WarehouseID: 2, ProductTypeID: 3, Windows: []int{1, 14}}, f)
+				return oneRes, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   toJSON(t, oneRes),
+		},
+		{
+			name:           "invalid warehouse",
+			ids:            "1&warehouse_id=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   e.ErrSectionReportFilterInvalid.Error(),
+		},
+		{
+			name:           "invalid product type",
+			ids:            "1&product_type_id=abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   e.ErrSectionReportFilterInvalid.Error(),
+		},
+		{
+			name:           "window out of range",
+			ids:            "1&windows=7,400",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   e.ErrSectionReportWindowsInvalid.Error(),
+		},
+		{
+			name:           "too many windows",
+			ids:            "1&windows=1,2,3,4,5,6",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   e.ErrSectionReportWindowsInvalid.Error(),
+		},
+		{
+			name: "internal error",
+			ids:  "1",
+			mockReport: func(f mod.SectionReportFilter) ([]mod.ReportProductsResponse, error) {
+				return nil, e.ErrQueryError
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   e.ErrRequestInternalServer.Error(),
 		},
 	}
 	for _, tc := range testsSlice {
@@ -349,6 +424,79 @@ func TestSectionHandler_ReportProducts(t *testing.T) {
 	}
 }
 
+func TestSectionHandler_Recommend(t *testing.T) {
+	ranked := []mod.SectionRecommendation{{Rank: 1, SectionID: 2, Score: 85, Reasons: []string{"already holds 2 batches of the product"}}}
+	testsSlice := []struct {
+		name           string
+		query          string
+		mockRecommend  func(mod.SlottingRequest) ([]mod.SectionRecommendation, error)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "success",
+			query: "product_id=4&quantity=10&warehouse_id=1",
+			mockRecommend: func(req mod.SlottingRequest) ([]mod.SectionRecommendation, error) {
+				require.Equal(t, mod.SlottingRequest{ProductID: 4, Quantity: 10, WarehouseID: 1}, req)
+				return ranked, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   toJSON(t, ranked),
+		},
+		{
+			name:  "success without warehouse",
+			query: "product_id=4&quantity=10",
+			mockRecommend: func(req mod.SlottingRequest) ([]mod.SectionRecommendation, error) {
+				require.Equal(t, mod.SlottingRequest{ProductID: 4, Quantity: 10}, req)
+				return []mod.SectionRecommendation{}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "[]",
+		},
+		{
+			name:           "missing quantity",
+			query:          "product_id=4",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   e.ErrSectionRecommendInvalid.Error(),
+		},
+		{
+			name:           "invalid warehouse",
+			query:          "product_id=4&quantity=10&warehouse_id=-1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   e.ErrSectionRecommendInvalid.Error(),
+		},
+		{
+			name:  "product not found",
+			query: "product_id=4&quantity=10",
+			mockRecommend: func(req mod.SlottingRequest) ([]mod.SectionRecommendation, error) {
+				return nil, e.ErrProductRepositoryNotFound
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "product not found",
+		},
+		{
+			name:  "internal error",
+			query: "product_id=4&quantity=10",
+			mockRecommend: func(req mod.SlottingRequest) ([]mod.SectionRecommendation, error) {
+				return nil, e.ErrQueryError
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   e.ErrRequestInternalServer.Error(),
+		},
+	}
+	for _, tc := range testsSlice {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &mock.MockSectionService{MockRecommend: tc.mockRecommend}
+			handler := NewSectionHandler(svc)
+			req := httptest.NewRequest("GET", "/?"+tc.query, nil)
+			rr := httptest.NewRecorder()
+			handler.Recommend().ServeHTTP(rr, req)
+			require.Equal(t, tc.expectedStatus, rr.Code)
+			require.Contains(t, rr.Body.String(), tc.expectedBody)
+		})
+	}
+}
+
 func TestSectionHandler_Delete(t *testing.T) {
 	testsSlice := []struct {
 		name           string
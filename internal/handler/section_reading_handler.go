@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// readingsMaxBatch is the largest number of readings accepted in a request
+const readingsMaxBatch = 500
+
+// NewSectionReadingHandler creates a new instance of the section reading handler
+func NewSectionReadingHandler(sv internal.SectionReadingService) *SectionReadingHandler {
+	return &SectionReadingHandler{
+		sv: sv,
+	}
+}
+
+// SectionReadingHandler serves the temperature telemetry of the sections
+type SectionReadingHandler struct {
+	// sv is the service used by the handler
+	sv internal.SectionReadingService
+}
+
+// Create ingests a reading or an array of readings of the section of the path
+func (h *SectionReadingHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		var batch mod.SectionReadingBatch
+		if err := utils.DecodeJSON(w, r, &batch); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
+			return
+		}
+		if len(batch) == 0 || len(batch) > readingsMaxBatch {
+			utils.BadResponse(w, http.StatusUnprocessableEntity, e.ErrSectionReadingsInvalid.Error())
+			return
+		}
+		msgs := []string{}
+		for i, rd := range batch {
+			for _, msg := range e.ValidateStruct(rd) {
+				msgs = append(msgs, fmt.Sprintf("readings[%d]: %s", i, msg))
+			}
+		}
+		if len(msgs) > 0 {
+			sort.Strings(msgs)
+			utils.BadResponse(w, http.StatusUnprocessableEntity, strings.Join(msgs, ", "))
+			return
+		}
+
+		result, err := h.sv.Ingest(id, batch)
+		if err != nil {
+			utils.BadResponse(w, readingStatus(err), readingMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusCreated, e.ReadingsAccepted, result)
+	}
+}
+
+// GetReadings returns the readings of the section of the path between the optional from and to
+func (h *SectionReadingHandler) GetReadings() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		rg, err := readingRange(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		readings, err := h.sv.FindReadings(id, rg)
+		if err != nil {
+			utils.BadResponse(w, readingStatus(err), readingMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, readings)
+	}
+}
+
+// GetRollups returns the hourly rollups of the section of the path between the optional from and to
+func (h *SectionReadingHandler) GetRollups() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		rg, err := readingRange(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		rollups, err := h.sv.FindRollups(id, rg)
+		if err != nil {
+			utils.BadResponse(w, readingStatus(err), readingMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, rollups)
+	}
+}
+
+// GetAlerts returns the alerts of the section of the path, the status parameter keeps the open or the closed ones
+func (h *SectionReadingHandler) GetAlerts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		var open *bool
+		switch r.URL.Query().Get("status") {
+		case "":
+		case "open":
+			open = new(bool)
+			*open = true
+		case "closed":
+			open = new(bool)
+		default:
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrSectionAlertStatusInvalid.Error())
+			return
+		}
+		alerts, err := h.sv.FindAlerts(id, open)
+		if err != nil {
+			utils.BadResponse(w, readingStatus(err), readingMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, alerts)
+	}
+}
+
+// readingRange parses the optional from and to parameters of the request
+func readingRange(r *http.Request) (mod.SectionReadingRange, error) {
+	var rg mod.SectionReadingRange
+	for name, dst := range map[string]*time.Time{"from": &rg.From, "to": &rg.To} {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return mod.SectionReadingRange{}, e.ErrSectionReadingRangeInvalid
+		}
+		*dst = t
+	}
+	if !rg.From.IsZero() && !rg.To.IsZero() && !rg.From.Before(rg.To) {
+		return mod.SectionReadingRange{}, e.ErrSectionReadingRangeInvalid
+	}
+	return rg, nil
+}
+
+// readingStatus returns the status code of a section reading error
+func readingStatus(err error) int {
+	if errors.Is(err, e.ErrSectionRepositoryNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// readingMessage hides the internal errors of the repository
+func readingMessage(err error) string {
+	if readingStatus(err) == http.StatusInternalServerError {
+		return e.ErrRequestInternalServer.Error()
+	}
+	return err.Error()
+}
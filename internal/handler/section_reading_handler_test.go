@@ -0,0 +1,177 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	hd "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/handler"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	tests2 "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSectionReadingHandler_Create(t *testing.T) {
+	opened := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	result := mod.SectionReadingsResult{
+		Accepted:           2,
+		CurrentTemperature: -3,
+		AlertsOpened:       []mod.SectionAlert{{ID: 7, SectionID: 1, Type: mod.AlertBelowMinimum, Temperature: -3, Threshold: 0, OpenedAt: opened}},
+		AlertsClosed:       []int{},
+	}
+	tests := []struct {
+		name           string
+		id             string
+		body           string
+		callService    bool
+		size           int
+		mockErr        error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "#1 Success - Array of readings",
+			id:             "1",
+			body:           `[{"temperature":2,"recorded_at":"2024-05-01T09:00:00Z"},{"temperature":-3,"recorded_at":"2024-05-01T10:00:00Z"}]`,
+			callService:    true,
+			size:           2,
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"success":true,"message":"handler: readings accepted","data":{"accepted":2,"current_temperature":-3,` +
+				`"alerts_opened":[{"id":7,"section_id":1,"type":"below_minimum","temperature":-3,"threshold":0,"opened_at":"2024-05-01T10:00:00Z","closed_at":null}],"alerts_closed":[]}}`,
+		},
+		{
+			name:           "#2 Success - Single reading",
+			id:             "1",
+			body:           `{"temperature":0,"recorded_at":"2024-05-01T09:00:00Z"}`,
+			callService:    true,
+			size:           1,
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"success":true,"message":"handler: readings accepted","data":{"accepted":2,"current_temperature":-3,` +
+				`"alerts_opened":[{"id":7,"section_id":1,"type":"below_minimum","temperature":-3,"threshold":0,"opened_at":"2024-05-01T10:00:00Z","closed_at":null}],"alerts_closed":[]}}`,
+		},
+		{
+			name:           "#3 Error - Missing temperature",
+			id:             "1",
+			body:           `[{"temperature":2,"recorded_at":"2024-05-01T09:00:00Z"},{"recorded_at":"2024-05-01T10:00:00Z"}]`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"success":false,"message":"readings[1]: Temperature is required","data":null}`,
+		},
+		{
+			name:           "#4 Error - Empty array",
+			id:             "1",
+			body:           `[]`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"success":false,"message":"handler: readings must be 1 to 500 readings with a temperature and a recorded_at","data":null}`,
+		},
+		{
+			name:           "#5 Error - Unknown field",
+			id:             "1",
+			body:           `{"temperature":2,"recorded_at":"2024-05-01T09:00:00Z","humidity":40}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"message":"handler: body has an unknown field: \"humidity\"","data":null}`,
+		},
+		{
+			name:           "#6 Error - Section not found",
+			id:             "9",
+			body:           `{"temperature":2,"recorded_at":"2024-05-01T09:00:00Z"}`,
+			callService:    true,
+			size:           1,
+			mockErr:        e.ErrSectionRepositoryNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"message":"repository: section not found","data":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(tests2.MockSectionReadingService)
+			if tt.callService {
+				res := result
+				if tt.mockErr != nil {
+					res = mod.SectionReadingsResult{}
+				}
+				mockService.On("Ingest", mock.Anything, mock.MatchedBy(func(b mod.SectionReadingBatch) bool { return len(b) == tt.size })).
+					Return(res, tt.mockErr).Once()
+			}
+			handler := hd.NewSectionReadingHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/sections/"+tt.id+"/readings", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			handler.Create().ServeHTTP(rr, withURLParam(req, "id", tt.id))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSectionReadingHandler_GetReadings(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("#1 Success", func(t *testing.T) {
+		mockService := new(tests2.MockSectionReadingService)
+		mockService.On("FindReadings", 1, mod.SectionReadingRange{From: from, To: to}).Return([]mod.SectionReading{
+			{ID: 3, SectionID: 1, Temperature: 1.5, RecordedAt: from.Add(time.Hour)},
+		}, nil).Once()
+		handler := hd.NewSectionReadingHandler(mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/sections/1/readings?from=2024-05-01T00:00:00Z&to=2024-05-02T00:00:00Z", nil)
+		rr := httptest.NewRecorder()
+		handler.GetReadings().ServeHTTP(rr, withURLParam(req, "id", "1"))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"success":true,"message":"handler: data retrieved successfully","data":[{"id":3,"section_id":1,"temperature":1.5,"recorded_at":"2024-05-01T01:00:00Z"}]}`, rr.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("#2 Error - From after to", func(t *testing.T) {
+		mockService := new(tests2.MockSectionReadingService)
+		handler := hd.NewSectionReadingHandler(mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/sections/1/readings?from=2024-05-02T00:00:00Z&to=2024-05-01T00:00:00Z", nil)
+		rr := httptest.NewRecorder()
+		handler.GetReadings().ServeHTTP(rr, withURLParam(req, "id", "1"))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{"success":false,"message":"handler: from and to must be RFC3339 times and from must be before to","data":null}`, rr.Body.String())
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestSectionReadingHandler_GetAlerts(t *testing.T) {
+	opened := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("#1 Success - Open alerts", func(t *testing.T) {
+		mockService := new(tests2.MockSectionReadingService)
+		mockService.On("FindAlerts", 1, mock.MatchedBy(func(open *bool) bool { return open != nil && *open })).Return([]mod.SectionAlert{
+			{ID: 7, SectionID: 1, Type: mod.AlertBelowMinimum, Temperature: -3, Threshold: 0, OpenedAt: opened},
+		}, nil).Once()
+		handler := hd.NewSectionReadingHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.GetAlerts().ServeHTTP(rr, withURLParam(httptest.NewRequest(http.MethodGet, "/v1/sections/1/alerts?status=open", nil), "id", "1"))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"success":true,"message":"handler: data retrieved successfully","data":[{"id":7,"section_id":1,"type":"below_minimum","temperature":-3,"threshold":0,"opened_at":"2024-05-01T10:00:00Z","closed_at":null}]}`, rr.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("#2 Error - Invalid status", func(t *testing.T) {
+		mockService := new(tests2.MockSectionReadingService)
+		handler := hd.NewSectionReadingHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.GetAlerts().ServeHTTP(rr, withURLParam(httptest.NewRequest(http.MethodGet, "/v1/sections/1/alerts?status=all", nil), "id", "1"))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{"success":false,"message":"handler: status must be open or closed","data":null}`, rr.Body.String())
+		mockService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// Bounds of the interval of the section history
+const (
+	historyMinInterval = time.Minute
+	historyMaxInterval = 30 * 24 * time.Hour
+)
+
+// NewSectionSnapshotHandler creates a new instance of the section snapshot handler
+func NewSectionSnapshotHandler(sv internal.SectionSnapshotService) *SectionSnapshotHandler {
+	return &SectionSnapshotHandler{
+		sv: sv,
+	}
+}
+
+// SectionSnapshotHandler serves the occupancy and temperature history of the sections
+type SectionSnapshotHandler struct {
+	// sv is the service used by the handler
+	sv internal.SectionSnapshotService
+}
+
+// GetHistory returns the snapshots of the section of the path between the optional from and to, aggregated by
+// interval, e.g. ?interval=1d&format=csv downloads the daily history as CSV
+func (h *SectionSnapshotHandler) GetHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		rg, err := readingRange(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		interval, err := historyInterval(r.URL.Query().Get("interval"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		format := r.URL.Query().Get("format")
+		if format != "" && format != mod.ReportJSON && format != mod.ReportCSV {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrSectionHistoryFormatInvalid.Error())
+			return
+		}
+
+		history, err := h.sv.FindHistory(id, mod.SectionHistoryQuery{From: rg.From, To: rg.To, Interval: interval})
+		if err != nil {
+			utils.BadResponse(w, historyStatus(err), historyMessage(err))
+			return
+		}
+		if format != mod.ReportCSV {
+			utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, history)
+			return
+		}
+
+		rows, err := common.ToMaps(history)
+		if err != nil {
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			return
+		}
+		data, err := common.ReportCSV(rows)
+		if err != nil {
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			return
+		}
+		w.Header().Set("Content-Type", reportContentTypes[mod.ReportCSV])
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"section-%d-history.csv\"", id))
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	}
+}
+
+// historyInterval parses the interval of the history, a Go duration like 15m or 6h or a number of days like 1d
+func historyInterval(raw string) (time.Duration, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	var interval time.Duration
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, e.ErrSectionHistoryInvalid
+		}
+		interval = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if interval, err = time.ParseDuration(raw); err != nil {
+			return 0, e.ErrSectionHistoryInvalid
+		}
+	}
+	if interval < historyMinInterval || interval > historyMaxInterval || interval%time.Second != 0 {
+		return 0, e.ErrSectionHistoryInvalid
+	}
+	return interval, nil
+}
+
+// historyStatus returns the status code of a section history error
+func historyStatus(err error) int {
+	switch {
+	case errors.Is(err, e.ErrSectionRepositoryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, e.ErrSectionHistoryInvalid):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// historyMessage hides the internal errors of the repository
+func historyMessage(err error) string {
+	if historyStatus(err) == http.StatusInternalServerError {
+		return e.ErrRequestInternalServer.Error()
+	}
+	return err.Error()
+}
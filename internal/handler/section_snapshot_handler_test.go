@@ -0,0 +1,120 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	hd "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/handler"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	tests2 "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSectionSnapshotHandler_GetHistory(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)
+	daily := mod.SectionHistoryQuery{From: from, To: to, Interval: 24 * time.Hour}
+	history := []mod.SectionHistoryBucket{{SectionID: 4, BucketStart: from, Snapshots: 24, MaximumCapacity: 100,
+		MinCapacity: 10, AvgCapacity: 25.5, MaxCapacity: 40, MinTemperature: -20, AvgTemperature: -18.25, MaxTemperature: -15}}
+
+	t.Run("#1 Success - JSON", func(t *testing.T) {
+		mockService := new(tests2.MockSectionSnapshotService)
+		mockService.On("FindHistory", 4, daily).Return(history, nil).Once()
+		handler := hd.NewSectionSnapshotHandler(mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/sections/4/history?from=2024-05-01T00:00:00Z&to=2024-05-03T00:00:00Z&interval=1d", nil)
+		rr := httptest.NewRecorder()
+		handler.GetHistory().ServeHTTP(rr, withURLParam(req, "id", "4"))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"success":true,"message":"handler: data retrieved successfully","data":[{"section_id":4,`+
+			`"bucket_start":"2024-05-01T00:00:00Z","snapshots":24,"maximum_capacity":100,"min_capacity":10,"avg_capacity":25.5,`+
+			`"max_capacity":40,"min_temperature":-20,"avg_temperature":-18.25,"max_temperature":-15}]}`, rr.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("#2 Success - CSV", func(t *testing.T) {
+		mockService := new(tests2.MockSectionSnapshotService)
+		mockService.On("FindHistory", 4, daily).Return(history, nil).Once()
+		handler := hd.NewSectionSnapshotHandler(mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/sections/4/history?from=2024-05-01T00:00:00Z&to=2024-05-03T00:00:00Z&interval=24h&format=csv", nil)
+		rr := httptest.NewRecorder()
+		handler.GetHistory().ServeHTTP(rr, withURLParam(req, "id", "4"))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/csv", rr.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="section-4-history.csv"`, rr.Header().Get("Content-Disposition"))
+		assert.Equal(t, "avg_capacity,avg_temperature,bucket_start,max_capacity,max_temperature,maximum_capacity,"+
+			"min_capacity,min_temperature,section_id,snapshots\n25.5,-18.25,2024-05-01T00:00:00Z,40,-15,100,10,-20,4,24\n", rr.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("#3 Success - Defaults", func(t *testing.T) {
+		mockService := new(tests2.MockSectionSnapshotService)
+		mockService.On("FindHistory", 4, mod.SectionHistoryQuery{}).Return([]mod.SectionHistoryBucket{}, nil).Once()
+		handler := hd.NewSectionSnapshotHandler(mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/sections/4/history", nil)
+		rr := httptest.NewRecorder()
+		handler.GetHistory().ServeHTTP(rr, withURLParam(req, "id", "4"))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"success":true,"message":"handler: data retrieved successfully","data":[]}`, rr.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	for _, tc := range []struct {
+		name  string
+		query string
+		body  string
+	}{
+		{"#4 Error - Interval too small", "interval=30s", e.ErrSectionHistoryInvalid.Error()},
+		{"#5 Error - Interval not a duration", "interval=weekly", e.ErrSectionHistoryInvalid.Error()},
+		{"#6 Error - Interval too large", "interval=31d", e.ErrSectionHistoryInvalid.Error()},
+		{"#7 Error - Format", "format=xlsx", e.ErrSectionHistoryFormatInvalid.Error()},
+		{"#8 Error - From after to", "from=2024-05-03T00:00:00Z&to=2024-05-01T00:00:00Z", e.ErrSectionReadingRangeInvalid.Error()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(tests2.MockSectionSnapshotService)
+			handler := hd.NewSectionSnapshotHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/sections/4/history?"+tc.query, nil)
+			rr := httptest.NewRecorder()
+			handler.GetHistory().ServeHTTP(rr, withURLParam(req, "id", "4"))
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.JSONEq(t, `{"success":false,"message":"`+tc.body+`","data":null}`, rr.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+
+	t.Run("#9 Error - Section not found", func(t *testing.T) {
+		mockService := new(tests2.MockSectionSnapshotService)
+		mockService.On("FindHistory", 4, mod.SectionHistoryQuery{}).Return([]mod.SectionHistoryBucket(nil), e.ErrSectionRepositoryNotFound).Once()
+		handler := hd.NewSectionSnapshotHandler(mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/sections/4/history", nil)
+		rr := httptest.NewRecorder()
+		handler.GetHistory().ServeHTTP(rr, withURLParam(req, "id", "4"))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("#10 Error - Range too long for the interval", func(t *testing.T) {
+		mockService := new(tests2.MockSectionSnapshotService)
+		mockService.On("FindHistory", 4, mod.SectionHistoryQuery{Interval: time.Minute}).Return([]mod.SectionHistoryBucket(nil), e.ErrSectionHistoryInvalid).Once()
+		handler := hd.NewSectionSnapshotHandler(mockService)
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/sections/4/history?interval=1m", nil)
+		rr := httptest.NewRecorder()
+		handler.GetHistory().ServeHTTP(rr, withURLParam(req, "id", "4"))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockService.AssertExpectations(t)
+	})
+}
@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"encoding/json"
 	"net/http"
 	"strconv"
 
@@ -59,9 +58,9 @@ func (h *SellerHandler) GetByID() http.HandlerFunc {
 func (h *SellerHandler) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req models.Seller
-		err := json.NewDecoder(r.Body).Decode(&req)
+		err := utils.DecodeJSON(w, r, &req)
 		if err != nil {
-			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestWrongBody.Error())
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 
@@ -100,9 +99,9 @@ func (h *SellerHandler) Update() http.HandlerFunc {
 			return
 		}
 
-		err = json.NewDecoder(r.Body).Decode(&req)
+		err = utils.DecodeJSON(w, r, &req)
 		if err != nil {
-			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestWrongBody.Error())
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 		req.ID = id
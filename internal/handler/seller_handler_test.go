@@ -151,7 +151,7 @@ func TestSellerHandler_Create(t *testing.T) {
 			requestBody:       `{"cid": "charlie", "company_name": "New Company", "address": "123 Main St", "telephone": "555-0101", "locality_id": 1}`,
 			expectServiceCall: false,
 			expectedStatus:    http.StatusBadRequest,
-			expectedBody:      `{"success":false,"message":"handler: body does not meet requirements","data":null}`,
+			expectedBody:      `{"success":false,"message":"handler: body has a field with a wrong type: cid must be an integer, got string","data":null}`,
 		},
 		{
 			name:              "#3 Error - Validation Failed",
@@ -181,6 +181,8 @@ func TestSellerHandler_Create(t *testing.T) {
 			}
 
 			req := httptest.NewRequest(http.MethodPost, "/sellers", bytes.NewBufferString(tt.requestBody))
+
+			req.Header.Set("Content-Type", "application/json")
 			rr := httptest.NewRecorder()
 
 			handler.Create().ServeHTTP(rr, req)
@@ -251,7 +253,7 @@ func TestSellerHandler_Update(t *testing.T) {
 			expectFindCall:   true,
 			expectUpdateCall: false,
 			expectedStatus:   http.StatusBadRequest,
-			expectedBody:     `{"success":false,"message":"handler: body does not meet requirements","data":null}`,
+			expectedBody:     `{"success":false,"message":"handler: body does not meet requirements: malformed JSON at position 17","data":null}`,
 		},
 		{
 			name:             "#5 Error - Update Conflict",
@@ -292,6 +294,8 @@ func TestSellerHandler_Update(t *testing.T) {
 			}
 
 			req := httptest.NewRequest(http.MethodPatch, "/sellers/"+tt.sellerID, bytes.NewBufferString(tt.requestBody))
+
+			req.Header.Set("Content-Type", "application/json")
 			req = addChiURLParam(req, "id", tt.sellerID)
 			rr := httptest.NewRecorder()
 
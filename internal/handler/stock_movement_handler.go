@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewStockMovementHandler creates a new instance of the stock movement handler
+func NewStockMovementHandler(sv internal.StockMovementService) *StockMovementHandler {
+	return &StockMovementHandler{
+		sv: sv,
+	}
+}
+
+// StockMovementHandler serves the stock ledger
+type StockMovementHandler struct {
+	// sv is the service used by the handler
+	sv internal.StockMovementService
+}
+
+// Adjust applies a manual adjustment or write-off to the batch of the path
+func (h *StockMovementHandler) Adjust() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		var req mod.StockAdjustmentRequest
+		if err := utils.DecodeJSON(w, r, &req); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
+			return
+		}
+		if errs := e.ValidateStruct(req); len(errs) > 0 {
+			msgs := make([]string, 0, len(errs))
+			for _, msg := range errs {
+				msgs = append(msgs, msg)
+			}
+			sort.Strings(msgs)
+			utils.BadResponse(w, http.StatusUnprocessableEntity, strings.Join(msgs, ", "))
+			return
+		}
+
+		movement := mod.StockMovement{
+			Type:           req.Type,
+			ProductBatchID: id,
+			Quantity:       req.Quantity,
+			EmployeeID:     req.EmployeeID,
+			Reason:         req.Reason,
+		}
+		if err := h.sv.Adjust(&movement); err != nil {
+			utils.BadResponse(w, stockStatus(err), stockMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusCreated, e.StockAdjusted, movement)
+	}
+}
+
+// GetBatchMovements returns the ledger of the batch of the path
+func (h *StockMovementHandler) GetBatchMovements() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		ledger, err := h.sv.FindLedgerByBatchID(id)
+		if err != nil {
+			utils.BadResponse(w, stockStatus(err), stockMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, ledger)
+	}
+}
+
+// GetDiscrepancies returns the batches and sections flagged by the last reconciliation
+func (h *StockMovementHandler) GetDiscrepancies() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		found, err := h.sv.FindDiscrepancies()
+		if err != nil {
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, found)
+	}
+}
+
+// stockStatus returns the status code of a stock movement error
+func stockStatus(err error) int {
+	switch {
+	case errors.Is(err, e.ErrProductBatchNotFound),
+		errors.Is(err, e.ErrEmployeeRepositoryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, e.ErrStockMovementInvalid):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, e.ErrStockInsufficient),
+		errors.Is(err, e.ErrProductBatchArchived),
+		errors.Is(err, e.ErrSectionCapacityExceeded):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// stockMessage hides the internal errors of the repository
+func stockMessage(err error) string {
+	if stockStatus(err) == http.StatusInternalServerError {
+		return e.ErrRequestInternalServer.Error()
+	}
+	return err.Error()
+}
@@ -0,0 +1,163 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	hd "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/handler"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	tests2 "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStockMovementHandler_Adjust(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		body           string
+		callService    bool
+		mockErr        error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "#1 Success - Write-off",
+			id:             "1",
+			body:           `{"type":"write_off","quantity":-3,"reason":"broken boxes","employee_id":2}`,
+			callService:    true,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"success":true,"message":"handler: stock adjusted","data":{"id":11,"type":"write_off","product_batch_id":1,"section_id":4,"quantity":-3,"employee_id":2,"reason":"broken boxes","created_at":"0001-01-01T00:00:00Z"}}`,
+		},
+		{
+			name:           "#2 Error - Invalid type",
+			id:             "1",
+			body:           `{"type":"pick","quantity":-3,"reason":"broken boxes","employee_id":2}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"success":false,"message":"Type failed on oneof validation","data":null}`,
+		},
+		{
+			name:           "#3 Error - Write-off adding units",
+			id:             "1",
+			body:           `{"type":"write_off","quantity":3,"reason":"broken boxes","employee_id":2}`,
+			callService:    true,
+			mockErr:        e.ErrStockMovementInvalid,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"success":false,"message":"service: a write_off must remove units and an adjustment must change them","data":null}`,
+		},
+		{
+			name:           "#4 Error - More units than the batch",
+			id:             "1",
+			body:           `{"type":"adjustment","quantity":-30,"reason":"recount","employee_id":2}`,
+			callService:    true,
+			mockErr:        e.ErrStockInsufficient,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"success":false,"message":"repository: not enough stock for the quantity","data":null}`,
+		},
+		{
+			name:           "#5 Error - Section full",
+			id:             "1",
+			body:           `{"type":"adjustment","quantity":30,"reason":"recount","employee_id":2}`,
+			callService:    true,
+			mockErr:        fmt.Errorf("%w: 2 units left", e.ErrSectionCapacityExceeded),
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"success":false,"message":"` + e.ErrSectionCapacityExceeded.Error() + `: 2 units left","data":null}`,
+		},
+		{
+			name:           "#6 Error - Archived batch",
+			id:             "1",
+			body:           `{"type":"adjustment","quantity":3,"reason":"recount","employee_id":2}`,
+			callService:    true,
+			mockErr:        e.ErrProductBatchArchived,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"success":false,"message":"` + e.ErrProductBatchArchived.Error() + `","data":null}`,
+		},
+		{
+			name:           "#7 Error - Invalid id",
+			id:             "x",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"success":false,"message":"handler: id must be an integer","data":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(tests2.MockStockMovementService)
+			if tt.callService {
+				mockService.On("Adjust", mock.AnythingOfType("*models.StockMovement")).Return(tt.mockErr).Run(func(args mock.Arguments) {
+					if tt.mockErr == nil {
+						mv := args.Get(0).(*mod.StockMovement)
+						mv.ID, mv.SectionID = 11, 4
+					}
+				}).Once()
+			}
+			handler := hd.NewStockMovementHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/productBatches/"+tt.id+"/adjustments", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			handler.Adjust().ServeHTTP(rr, withURLParam(req, "id", tt.id))
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStockMovementHandler_GetBatchMovements(t *testing.T) {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	ledger := mod.StockLedger{ProductBatchID: 1, CurrentQuantity: 7, Balance: 7, Movements: []mod.StockMovement{
+		{ID: 1, Type: mod.MovementReceipt, ProductBatchID: 1, SectionID: 4, Quantity: 10, ReferenceType: mod.ReferenceInboundOrder, ReferenceID: 3, EmployeeID: 2, CreatedAt: created},
+		{ID: 2, Type: mod.MovementPick, ProductBatchID: 1, SectionID: 4, Quantity: -3, ReferenceType: mod.ReferencePurchaseOrder, ReferenceID: 5, CreatedAt: created},
+	}}
+
+	t.Run("#1 Success", func(t *testing.T) {
+		mockService := new(tests2.MockStockMovementService)
+		mockService.On("FindLedgerByBatchID", 1).Return(ledger, nil).Once()
+		handler := hd.NewStockMovementHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.GetBatchMovements().ServeHTTP(rr, withURLParam(httptest.NewRequest(http.MethodGet, "/v1/productBatches/1/movements", nil), "id", "1"))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"success":true,"message":"handler: data retrieved successfully","data":{"product_batch_id":1,"current_quantity":7,"balance":7,"movements":[`+
+			`{"id":1,"type":"receipt","product_batch_id":1,"section_id":4,"quantity":10,"reference_type":"inbound_order","reference_id":3,"employee_id":2,"created_at":"2024-05-01T10:00:00Z"},`+
+			`{"id":2,"type":"pick","product_batch_id":1,"section_id":4,"quantity":-3,"reference_type":"purchase_order","reference_id":5,"created_at":"2024-05-01T10:00:00Z"}]}}`, rr.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("#2 Error - Batch not found", func(t *testing.T) {
+		mockService := new(tests2.MockStockMovementService)
+		mockService.On("FindLedgerByBatchID", 9).Return(mod.StockLedger{}, e.ErrProductBatchNotFound).Once()
+		handler := hd.NewStockMovementHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.GetBatchMovements().ServeHTTP(rr, withURLParam(httptest.NewRequest(http.MethodGet, "/v1/productBatches/9/movements", nil), "id", "9"))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestStockMovementHandler_GetDiscrepancies(t *testing.T) {
+	detected := time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)
+	mockService := new(tests2.MockStockMovementService)
+	mockService.On("FindDiscrepancies").Return([]mod.StockDiscrepancy{
+		{Entity: mod.DiscrepancySection, EntityID: 4, Stored: 12, Ledger: 7, DetectedAt: detected},
+	}, nil).Once()
+	handler := hd.NewStockMovementHandler(mockService)
+
+	rr := httptest.NewRecorder()
+	handler.GetDiscrepancies().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/stock/discrepancies", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"success":true,"message":"handler: data retrieved successfully","data":[{"entity":"section","entity_id":4,"stored":12,"ledger":7,"detected_at":"2024-05-01T11:00:00Z"}]}`, rr.Body.String())
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewTraceHandler creates a new instance of the trace handler
+func NewTraceHandler(sv internal.TraceService) *TraceHandler {
+	return &TraceHandler{
+		sv: sv,
+	}
+}
+
+// TraceHandler serves the lot traceability and the recalls
+type TraceHandler struct {
+	// sv is the service used by the handler
+	sv internal.TraceService
+}
+
+// GetBatchTrace returns the seller, product and inbound orders of the batch of the path and the purchase orders
+// and buyers it reached
+func (h *TraceHandler) GetBatchTrace() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := common.IdRequests(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		trace, err := h.sv.FindBatchTrace(id)
+		if err != nil {
+			utils.BadResponse(w, traceStatus(err), traceMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, trace)
+	}
+}
+
+// GetPurchaseOrderTrace returns the origin of every batch that served the purchase order of the path
+func (h *TraceHandler) GetPurchaseOrderTrace() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := common.IdRequests(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		trace, err := h.sv.FindPurchaseOrderTrace(id)
+		if err != nil {
+			utils.BadResponse(w, traceStatus(err), traceMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, trace)
+	}
+}
+
+// CreateRecall rejects the batch of the body and its splits and returns the buyers to contact
+func (h *TraceHandler) CreateRecall() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req mod.RecallRequest
+		if err := utils.DecodeJSON(w, r, &req); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
+			return
+		}
+		if errs := e.ValidateStruct(req); len(errs) > 0 {
+			msgs := make([]string, 0, len(errs))
+			for _, msg := range errs {
+				msgs = append(msgs, msg)
+			}
+			sort.Strings(msgs)
+			utils.BadResponse(w, http.StatusUnprocessableEntity, strings.Join(msgs, ", "))
+			return
+		}
+		recall, err := h.sv.Recall(req)
+		if err != nil {
+			utils.BadResponse(w, traceStatus(err), traceMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusCreated, e.RecallCreated, recall)
+	}
+}
+
+// GetRecall returns the recall of the path with the buyers to contact
+func (h *TraceHandler) GetRecall() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := common.IdRequests(r)
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		recall, err := h.sv.FindRecall(id)
+		if err != nil {
+			utils.BadResponse(w, traceStatus(err), traceMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, recall)
+	}
+}
+
+// traceStatus returns the status code of a trace error
+func traceStatus(err error) int {
+	switch {
+	case errors.Is(err, e.ErrProductBatchNotFound),
+		errors.Is(err, e.ErrPORepositoryNotFound),
+		errors.Is(err, e.ErrRecallNotFound),
+		errors.Is(err, e.ErrEmployeeRepositoryNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// traceMessage hides the internal errors of the repository
+func traceMessage(err error) string {
+	if traceStatus(err) == http.StatusInternalServerError {
+		return e.ErrRequestInternalServer.Error()
+	}
+	return err.Error()
+}
@@ -0,0 +1,174 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	hd "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/handler"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	tests2 "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// traceRequest builds a request with the id of the path set in the chi context
+func traceRequest(method, target, id string, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chi.NewRouteContext()))
+	chi.RouteContext(req.Context()).URLParams.Add("id", id)
+	return req
+}
+
+func TestTraceHandler_GetBatchTrace(t *testing.T) {
+	t.Run("#1 Success", func(t *testing.T) {
+		mockService := new(tests2.MockTraceService)
+		mockService.On("FindBatchTrace", 7).Return(mod.BatchTrace{BatchOrigin: mod.BatchOrigin{
+			Seller: &mod.TraceSeller{ID: 5, CompanyName: "Green Farms"}, SplitFrom: 1}, Splits: []int{9}}, nil).Once()
+		handler := hd.NewTraceHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.GetBatchTrace().ServeHTTP(rr, traceRequest(http.MethodGet, "/v1/trace/batch/7", "7", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"company_name":"Green Farms"`)
+		assert.Contains(t, rr.Body.String(), `"splits":[9]`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("#2 Fail - Invalid id", func(t *testing.T) {
+		mockService := new(tests2.MockTraceService)
+		handler := hd.NewTraceHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.GetBatchTrace().ServeHTTP(rr, traceRequest(http.MethodGet, "/v1/trace/batch/x", "x", ""))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		mockService.AssertNotCalled(t, "FindBatchTrace")
+	})
+
+	t.Run("#3 Fail - Batch not found", func(t *testing.T) {
+		mockService := new(tests2.MockTraceService)
+		mockService.On("FindBatchTrace", 7).Return(mod.BatchTrace{}, e.ErrProductBatchNotFound).Once()
+		handler := hd.NewTraceHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.GetBatchTrace().ServeHTTP(rr, traceRequest(http.MethodGet, "/v1/trace/batch/7", "7", ""))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Contains(t, rr.Body.String(), e.ErrProductBatchNotFound.Error())
+	})
+
+	t.Run("#4 Fail - Internal error", func(t *testing.T) {
+		mockService := new(tests2.MockTraceService)
+		mockService.On("FindBatchTrace", 7).Return(mod.BatchTrace{}, e.ErrQueryError).Once()
+		handler := hd.NewTraceHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.GetBatchTrace().ServeHTTP(rr, traceRequest(http.MethodGet, "/v1/trace/batch/7", "7", ""))
+
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Contains(t, rr.Body.String(), e.ErrRequestInternalServer.Error())
+	})
+}
+
+func TestTraceHandler_GetPurchaseOrderTrace(t *testing.T) {
+	t.Run("#1 Success", func(t *testing.T) {
+		mockService := new(tests2.MockTraceService)
+		mockService.On("FindPurchaseOrderTrace", 20).Return(mod.PurchaseOrderTrace{PurchaseOrderID: 20, OrderNumber: "PO-20",
+			Lines: []mod.TraceLine{{OrderDetailID: 30, Quantity: 6}}}, nil).Once()
+		handler := hd.NewTraceHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.GetPurchaseOrderTrace().ServeHTTP(rr, traceRequest(http.MethodGet, "/v1/trace/purchaseOrder/20", "20", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"order_number":"PO-20"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("#2 Fail - Purchase order not found", func(t *testing.T) {
+		mockService := new(tests2.MockTraceService)
+		mockService.On("FindPurchaseOrderTrace", 20).Return(mod.PurchaseOrderTrace{}, e.ErrPORepositoryNotFound).Once()
+		handler := hd.NewTraceHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.GetPurchaseOrderTrace().ServeHTTP(rr, traceRequest(http.MethodGet, "/v1/trace/purchaseOrder/20", "20", ""))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestTraceHandler_CreateRecall(t *testing.T) {
+	at := time.Date(2024, 7, 6, 10, 0, 0, 0, time.UTC)
+	body := `{"product_batch_id":7,"reason":"listeria","employee_id":4}`
+	req := mod.RecallRequest{ProductBatchID: 7, Reason: "listeria", EmployeeID: 4}
+
+	t.Run("#1 Success", func(t *testing.T) {
+		mockService := new(tests2.MockTraceService)
+		mockService.On("Recall", req).Return(mod.Recall{ID: 2, ProductBatchID: 7, Reason: "listeria", EmployeeID: 4, CreatedAt: at,
+			Batches: []int{7, 9}, Buyers: []mod.RecallBuyer{{TraceBuyer: mod.TraceBuyer{ID: 6}, PurchaseOrderIDs: []int{20}, Quantity: 4}}}, nil).Once()
+		handler := hd.NewTraceHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.CreateRecall().ServeHTTP(rr, traceRequest(http.MethodPost, "/v1/recalls", "", body))
+
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Contains(t, rr.Body.String(), e.RecallCreated)
+		assert.Contains(t, rr.Body.String(), `"product_batch_ids":[7,9]`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("#2 Fail - Missing fields", func(t *testing.T) {
+		mockService := new(tests2.MockTraceService)
+		handler := hd.NewTraceHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.CreateRecall().ServeHTTP(rr, traceRequest(http.MethodPost, "/v1/recalls", "", `{"product_batch_id":7}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		mockService.AssertNotCalled(t, "Recall")
+	})
+
+	t.Run("#3 Fail - Employee not found", func(t *testing.T) {
+		mockService := new(tests2.MockTraceService)
+		mockService.On("Recall", req).Return(mod.Recall{}, e.ErrEmployeeRepositoryNotFound).Once()
+		handler := hd.NewTraceHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.CreateRecall().ServeHTTP(rr, traceRequest(http.MethodPost, "/v1/recalls", "", body))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestTraceHandler_GetRecall(t *testing.T) {
+	t.Run("#1 Success", func(t *testing.T) {
+		mockService := new(tests2.MockTraceService)
+		mockService.On("FindRecall", 2).Return(mod.Recall{ID: 2, ProductBatchID: 7, Batches: []int{7}}, nil).Once()
+		handler := hd.NewTraceHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.GetRecall().ServeHTTP(rr, traceRequest(http.MethodGet, "/v1/recalls/2", "2", ""))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("#2 Fail - Recall not found", func(t *testing.T) {
+		mockService := new(tests2.MockTraceService)
+		mockService.On("FindRecall", 2).Return(mod.Recall{}, e.ErrRecallNotFound).Once()
+		handler := hd.NewTraceHandler(mockService)
+
+		rr := httptest.NewRecorder()
+		handler.GetRecall().ServeHTTP(rr, traceRequest(http.MethodGet, "/v1/recalls/2", "2", ""))
+
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assert.Contains(t, rr.Body.String(), e.ErrRecallNotFound.Error())
+	})
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewTransferHandler creates a new instance of the transfer handler
+func NewTransferHandler(sv internal.TransferService) *TransferHandler {
+	return &TransferHandler{
+		sv: sv,
+	}
+}
+
+// TransferHandler moves stock between sections
+type TransferHandler struct {
+	// sv is the service used by the handler
+	sv internal.TransferService
+}
+
+// Create moves all or part of a product batch to another section
+func (h *TransferHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req mod.TransferRequest
+		if err := utils.DecodeJSON(w, r, &req); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
+			return
+		}
+		if errs := e.ValidateStruct(req); len(errs) > 0 {
+			msgs := make([]string, 0, len(errs))
+			for _, msg := range errs {
+				msgs = append(msgs, msg)
+			}
+			sort.Strings(msgs)
+			utils.BadResponse(w, http.StatusUnprocessableEntity, strings.Join(msgs, ", "))
+			return
+		}
+
+		transfer := mod.Transfer{
+			ProductBatchID: req.ProductBatchID,
+			ToSectionID:    req.ToSectionID,
+			Quantity:       req.Quantity,
+			EmployeeID:     req.EmployeeID,
+		}
+		if err := h.sv.Save(&transfer); err != nil {
+			utils.BadResponse(w, transferStatus(err), transferMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusCreated, e.TransferCompleted, transfer)
+	}
+}
+
+// GetAll returns every transfer, newest first
+func (h *TransferHandler) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transfers, err := h.sv.FindAll()
+		if err != nil {
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, transfers)
+	}
+}
+
+// GetByID returns a transfer
+func (h *TransferHandler) GetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+		transfer, err := h.sv.FindByID(id)
+		if err != nil {
+			utils.BadResponse(w, transferStatus(err), transferMessage(err))
+			return
+		}
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, transfer)
+	}
+}
+
+// transferStatus returns the status code of a transfer error
+func transferStatus(err error) int {
+	switch {
+	case errors.Is(err, e.ErrTransferNotFound),
+		errors.Is(err, e.ErrProductBatchNotFound),
+		errors.Is(err, e.ErrSectionRepositoryNotFound),
+		errors.Is(err, e.ErrEmployeeRepositoryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, e.ErrTransferSameSection),
+		errors.Is(err, e.ErrTransferQuantityInvalid):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, e.ErrTransferProductType),
+		errors.Is(err, e.ErrTransferTemperature),
+		errors.Is(err, e.ErrTransferCapacity),
+		errors.Is(err, e.ErrProductBatchHeld):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// transferMessage hides the internal errors of the repository
+func transferMessage(err error) string {
+	if transferStatus(err) == http.StatusInternalServerError {
+		return e.ErrRequestInternalServer.Error()
+	}
+	return err.Error()
+}
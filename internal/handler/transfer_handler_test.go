@@ -0,0 +1,132 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hd "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/handler"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	tests2 "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransferHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		callService    bool
+		mockErr        error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "#1 Success - Partial transfer",
+			body:           `{"product_batch_id":1,"to_section_id":2,"quantity":4,"employee_id":3}`,
+			callService:    true,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"success":true,"message":"handler: transfer completed","data":{"id":7,"product_batch_id":1,"destination_batch_id":9,"from_section_id":5,"to_section_id":2,"quantity":4,"employee_id":3,"created_at":"0001-01-01T00:00:00Z"}}`,
+		},
+		{
+			name:           "#2 Error - Missing fields",
+			body:           `{"quantity":-1}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"success":false,"message":"EmployeeID is required, ProductBatchID is required, Quantity must be greater than or equal to 0, ToSectionID is required","data":null}`,
+		},
+		{
+			name:           "#3 Error - Destination without capacity",
+			body:           `{"product_batch_id":1,"to_section_id":2,"employee_id":3}`,
+			callService:    true,
+			mockErr:        e.ErrTransferCapacity,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"success":false,"message":"repository: destination section does not have capacity for the quantity","data":null}`,
+		},
+		{
+			name:           "#4 Error - Batch not found",
+			body:           `{"product_batch_id":1,"to_section_id":2,"employee_id":3}`,
+			callService:    true,
+			mockErr:        e.ErrProductBatchNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"success":false,"message":"repository: Product Batch not found","data":null}`,
+		},
+		{
+			name:           "#5 Error - Quantity above the batch",
+			body:           `{"product_batch_id":1,"to_section_id":2,"quantity":40,"employee_id":3}`,
+			callService:    true,
+			mockErr:        e.ErrTransferQuantityInvalid,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `{"success":false,"message":"repository: quantity must be between 1 and the batch current quantity","data":null}`,
+		},
+		{
+			name:           "#6 Error - Database failure",
+			body:           `{"product_batch_id":1,"to_section_id":2,"employee_id":3}`,
+			callService:    true,
+			mockErr:        e.ErrQueryError,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"success":false,"message":"handler: internal server error","data":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(tests2.MockTransferService)
+			if tt.callService {
+				mockService.On("Save", mock.AnythingOfType("*models.Transfer")).Return(tt.mockErr).Run(func(args mock.Arguments) {
+					if tt.mockErr == nil {
+						tr := args.Get(0).(*mod.Transfer)
+						tr.ID, tr.DestinationBatchID, tr.FromSectionID = 7, 9, 5
+					}
+				}).Once()
+			}
+			handler := hd.NewTransferHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/transfers", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			handler.Create().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			assert.JSONEq(t, tt.expectedBody, rr.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransferHandler_GetByID(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		mockReturn     mod.Transfer
+		mockErr        error
+		callService    bool
+		expectedStatus int
+	}{
+		{name: "#1 Success", id: "7", callService: true, mockReturn: mod.Transfer{ID: 7}, expectedStatus: http.StatusOK},
+		{name: "#2 Error - Not found", id: "8", callService: true, mockErr: e.ErrTransferNotFound, expectedStatus: http.StatusNotFound},
+		{name: "#3 Error - Invalid id", id: "x", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(tests2.MockTransferService)
+			if tt.callService {
+				id := 7
+				if tt.id == "8" {
+					id = 8
+				}
+				mockService.On("FindByID", id).Return(tt.mockReturn, tt.mockErr).Once()
+			}
+			handler := hd.NewTransferHandler(mockService)
+
+			req := withURLParam(httptest.NewRequest(http.MethodGet, "/v1/transfers/"+tt.id, nil), "id", tt.id)
+			rr := httptest.NewRecorder()
+			handler.GetByID().ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -1,13 +1,12 @@
 package handler
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"sort"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
 	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
@@ -16,14 +15,12 @@ import (
 )
 
 type warehouseHandler struct {
-	sv       internal.WarehouseService
-	validate *validator.Validate
+	sv internal.WarehouseService
 }
 
 func NewWarehouseHandler(sv internal.WarehouseService) internal.WarehouseHandler {
 	return &warehouseHandler{
-		sv:       sv,
-		validate: validator.New(),
+		sv: sv,
 	}
 }
 
@@ -68,17 +65,21 @@ func (h *warehouseHandler) Create() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var warehouse models.Warehouse
 
-		if err := json.NewDecoder(r.Body).Decode(&warehouse); err != nil {
-			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestWrongBody.Error())
+		if err := utils.DecodeJSON(w, r, &warehouse); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 
-		if err := h.validate.Struct(warehouse); err != nil {
+		if err := e.Validator().Struct(warehouse); err != nil {
 			utils.BadResponse(w, http.StatusUnprocessableEntity, "Campos inválidos: "+err.Error())
 			return
 		}
 
 		if err := h.sv.Save(&warehouse); err != nil {
+			if errors.Is(err, e.ErrWarehouseLocalityNotFound) {
+				utils.BadResponse(w, http.StatusConflict, err.Error())
+				return
+			}
 			utils.BadResponse(w, http.StatusConflict, e.ErrWarehouseRepositoryDuplicated.Error())
 			return
 		}
@@ -96,12 +97,12 @@ func (h *warehouseHandler) Update() http.HandlerFunc {
 		}
 
 		var warehouse models.Warehouse
-		if err := json.NewDecoder(r.Body).Decode(&warehouse); err != nil {
-			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestWrongBody.Error())
+		if err := utils.DecodeJSON(w, r, &warehouse); err != nil {
+			utils.BadResponse(w, utils.DecodeStatus(err), err.Error())
 			return
 		}
 
-		if err := h.validate.Struct(warehouse); err != nil {
+		if err := e.Validator().Struct(warehouse); err != nil {
 			utils.BadResponse(w, http.StatusUnprocessableEntity, "Campos inválidos: "+err.Error())
 			return
 		}
@@ -113,6 +114,10 @@ func (h *warehouseHandler) Update() http.HandlerFunc {
 
 		warehouse.ID = id
 		if err := h.sv.Update(&warehouse); err != nil {
+			if errors.Is(err, e.ErrWarehouseLocalityNotFound) {
+				utils.BadResponse(w, http.StatusConflict, err.Error())
+				return
+			}
 			utils.BadResponse(w, http.StatusNotFound, e.ErrWarehouseRepositoryNotFound.Error())
 			return
 		}
@@ -137,3 +142,158 @@ func (h *warehouseHandler) Delete() http.HandlerFunc {
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
+
+// GET /warehouses/reportCapacity?id={id}&sort={utilization|-utilization}
+func (h *warehouseHandler) ReportCapacity() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var id *int
+		if idStr := r.URL.Query().Get("id"); idStr != "" {
+			v, err := strconv.Atoi(idStr)
+			if err != nil {
+				utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+				return
+			}
+			id = &v
+		}
+
+		report, err := h.sv.ReportCapacity(id, r.URL.Query().Get("sort"))
+		switch {
+		case errors.Is(err, e.ErrWarehouseReportSortInvalid):
+			utils.BadResponse(w, http.StatusBadRequest, err.Error())
+			return
+		case errors.Is(err, e.ErrWarehouseRepositoryNotFound):
+			utils.BadResponse(w, http.StatusNotFound, err.Error())
+			return
+		case err != nil:
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			return
+		}
+
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, report)
+	}
+}
+
+// GET /warehouses/{id}/overview
+func (h *warehouseHandler) Overview() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+
+		overview, err := h.sv.FindOverview(id)
+		if err != nil {
+			warehouseReadError(w, err)
+			return
+		}
+
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, overview)
+	}
+}
+
+// GET /warehouses/{id}/sections?product_type_id={id}&below_minimum={bool}
+func (h *warehouseHandler) GetSections() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+
+		var filter models.WarehouseSectionFilter
+		if v := r.URL.Query().Get("product_type_id"); v != "" {
+			productTypeID, err := strconv.Atoi(v)
+			if err != nil {
+				utils.BadResponse(w, http.StatusBadRequest, e.ErrWarehouseFilterInvalid.Error())
+				return
+			}
+			filter.ProductTypeID = &productTypeID
+		}
+		if v := r.URL.Query().Get("below_minimum"); v != "" {
+			if filter.BelowMinimum, err = strconv.ParseBool(v); err != nil {
+				utils.BadResponse(w, http.StatusBadRequest, e.ErrWarehouseFilterInvalid.Error())
+				return
+			}
+		}
+
+		sections, err := h.sv.FindSections(id, filter)
+		if err != nil {
+			warehouseReadError(w, err)
+			return
+		}
+
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, sections)
+	}
+}
+
+// GET /warehouses/{id}/employees?name={name}
+func (h *warehouseHandler) GetEmployees() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrRequestIdMustBeInt.Error())
+			return
+		}
+
+		employees, err := h.sv.FindEmployees(id, r.URL.Query().Get("name"))
+		if err != nil {
+			warehouseReadError(w, err)
+			return
+		}
+
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, employees)
+	}
+}
+
+// Límites del parámetro limit de GET /warehouses/nearest
+const (
+	nearestDefaultLimit = 5
+	nearestMaxLimit     = 50
+)
+
+// GET /warehouses/nearest?locality_id={id}&product_id={id}&limit={n}
+func (h *warehouseHandler) Nearest() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		localityID, errLoc := strconv.Atoi(query.Get("locality_id"))
+		productID, errPrd := strconv.Atoi(query.Get("product_id"))
+		if errLoc != nil || errPrd != nil || localityID < 1 || productID < 1 {
+			utils.BadResponse(w, http.StatusBadRequest, e.ErrWarehouseNearestInvalid.Error())
+			return
+		}
+		limit := nearestDefaultLimit
+		if v := query.Get("limit"); v != "" {
+			var err error
+			limit, err = strconv.Atoi(v)
+			if err != nil || limit < 1 || limit > nearestMaxLimit {
+				utils.BadResponse(w, http.StatusBadRequest, e.ErrWarehouseNearestInvalid.Error())
+				return
+			}
+		}
+
+		warehouses, err := h.sv.FindNearest(localityID, productID, limit)
+		switch {
+		case errors.Is(err, e.ErrLocalityRepositoryNotFound):
+			utils.BadResponse(w, http.StatusNotFound, err.Error())
+			return
+		case errors.Is(err, e.ErrLocalityCoordinatesMissing):
+			utils.BadResponse(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		case err != nil:
+			utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+			return
+		}
+
+		utils.GoodResponse(w, http.StatusOK, e.DataRetrievedSuccess, warehouses)
+	}
+}
+
+// warehouseReadError responde 404 si el warehouse no existe y 500 ante cualquier otro error
+func warehouseReadError(w http.ResponseWriter, err error) {
+	if errors.Is(err, e.ErrWarehouseRepositoryNotFound) {
+		utils.BadResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.BadResponse(w, http.StatusInternalServerError, e.ErrRequestInternalServer.Error())
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -12,12 +13,21 @@ import (
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/tests"
+	tests2 "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
 	"github.com/stretchr/testify/require"
 )
 
+// newLocalityMock returns a locality repository where the locality 1 exists and the locality 5 does not
+func newLocalityMock() *tests2.MockLocalityRepository {
+	loc := &tests2.MockLocalityRepository{}
+	loc.On("ExistsByID", 1).Return(true, nil)
+	loc.On("ExistsByID", 5).Return(false, nil)
+	return loc
+}
+
 func TestWarehouseController_Create(t *testing.T) {
 	mock := tests.NewWarehouseMock()
-	serv := service.NewWarehouseService(mock)
+	serv := service.NewWarehouseService(mock, newLocalityMock())
 	handler := NewWarehouseHandler(serv)
 
 	warehouseOk := models.Warehouse{ID: 0,
@@ -25,7 +35,8 @@ func TestWarehouseController_Create(t *testing.T) {
 		Address:            "a",
 		Telephone:          "1234",
 		MinimumCapacity:    1,
-		MinimumTemperature: 1}
+		MinimumTemperature: 1,
+		LocalityID:         1}
 
 	warehouseEmpty := models.Warehouse{
 		ID:                 0,
@@ -46,7 +57,8 @@ func TestWarehouseController_Create(t *testing.T) {
   "telephone": "1234",
   "warehouse_code": "holiis como prueba",
   "minimum_capacity": 1,
-  "minimum_temperature": 1
+  "minimum_temperature": 1,
+  "locality_id": 1
 
 		}`)
 		req := httptest.NewRequest(http.MethodPost, "/warehouses", body)
@@ -64,7 +76,8 @@ func TestWarehouseController_Create(t *testing.T) {
             "Address": "a",
             "Telephone": "1234",
             "Minimum_Capacity": 1,
-            "Minimum_Temperature": 1
+            "Minimum_Temperature": 1,
+            "Locality_ID": 1
     }
 		}`
 
@@ -79,13 +92,27 @@ func TestWarehouseController_Create(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
-		expected := `{"data":null, "message":"Campos inválidos: Key: 'Warehouse.WarehouseCode' Error:Field validation for 'WarehouseCode' failed on the 'required' tag\nKey: 'Warehouse.Telephone' Error:Field validation for 'Telephone' failed on the 'required' tag\nKey: 'Warehouse.MinimumCapacity' Error:Field validation for 'MinimumCapacity' failed on the 'required' tag\nKey: 'Warehouse.MinimumTemperature' Error:Field validation for 'MinimumTemperature' failed on the 'required' tag", "success":false}`
+		expected := `{"data":null, "message":"Campos inválidos: Key: 'Warehouse.WarehouseCode' Error:Field validation for 'WarehouseCode' failed on the 'required' tag\nKey: 'Warehouse.Telephone' Error:Field validation for 'Telephone' failed on the 'required' tag\nKey: 'Warehouse.MinimumCapacity' Error:Field validation for 'MinimumCapacity' failed on the 'required' tag\nKey: 'Warehouse.MinimumTemperature' Error:Field validation for 'MinimumTemperature' failed on the 'required' tag\nKey: 'Warehouse.LocalityID' Error:Field validation for 'LocalityID' failed on the 'required' tag", "success":false}`
 
 		handler.Create().ServeHTTP(w, req)
 		require.Equal(t, http.StatusUnprocessableEntity, w.Code)
 		require.JSONEq(t, expected, w.Body.String())
 	})
 
+	t.Run("create_locality_not_found", func(t *testing.T) {
+		body := strings.NewReader(`{"Address": "a", "Telephone": "1234", "Warehouse_Code": "nuevo",
+  "Minimum_Capacity": 1, "Minimum_Temperature": 1, "Locality_ID": 5}`)
+		req := httptest.NewRequest(http.MethodPost, "/warehouses", body)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		expected := `{"data":null, "message":"repository: locality not found for warehouse", "success":false}`
+
+		handler.Create().ServeHTTP(w, req)
+		require.Equal(t, http.StatusConflict, w.Code)
+		require.JSONEq(t, expected, w.Body.String())
+	})
+
 	t.Run("create_conflict", func(t *testing.T) {
 		body := strings.NewReader(`{
 			
@@ -93,7 +120,8 @@ func TestWarehouseController_Create(t *testing.T) {
   "Telephone": "1277779",
   "Warehouse_Code": "holiis",
   "Minimum_Capacity": 300,
-  "Minimum_Temperature": 8
+  "Minimum_Temperature": 8,
+  "Locality_ID": 1
 
 		}`)
 		req := httptest.NewRequest(http.MethodPost, "/warehouses", body)
@@ -114,7 +142,7 @@ func TestWarehouseController_Create(t *testing.T) {
 
 func TestWarehouseController_Read(t *testing.T) {
 	mock := tests.NewWarehouseMock()
-	serv := service.NewWarehouseService(mock)
+	serv := service.NewWarehouseService(mock, newLocalityMock())
 	handler := NewWarehouseHandler(serv)
 
 	warehouse := models.Warehouse{ID: 1,
@@ -122,7 +150,8 @@ func TestWarehouseController_Read(t *testing.T) {
 		Address:            "a",
 		Telephone:          "1234",
 		MinimumCapacity:    1,
-		MinimumTemperature: 1}
+		MinimumTemperature: 1,
+		LocalityID:         1}
 	mock.On("GetByID", 1).Return(warehouse, nil)
 	mock.On("GetByID", 3).Return(models.Warehouse{}, e.ErrWarehouseRepositoryNotFound)
 	t.Run("find_by_id_ok", func(t *testing.T) {
@@ -142,7 +171,8 @@ func TestWarehouseController_Read(t *testing.T) {
         "Address": "a",
         "Telephone": "1234",
         "Minimum_Capacity": 1,
-        "Minimum_Temperature": 1
+        "Minimum_Temperature": 1,
+        "Locality_ID": 1
     
 }
 		}`
@@ -188,7 +218,7 @@ func TestWarehouseController_Read(t *testing.T) {
 func TestWarehouseController_ReadAll(t *testing.T) {
 	t.Run("find_all_ok", func(t *testing.T) {
 		mock := tests.NewWarehouseMock()
-		serv := service.NewWarehouseService(mock)
+		serv := service.NewWarehouseService(mock, newLocalityMock())
 		handler := NewWarehouseHandler(serv)
 
 		warehouses := []models.Warehouse{
@@ -197,13 +227,15 @@ func TestWarehouseController_ReadAll(t *testing.T) {
 				Address:            "a",
 				Telephone:          "1234",
 				MinimumCapacity:    1,
-				MinimumTemperature: 1},
+				MinimumTemperature: 1,
+				LocalityID:         1},
 			{ID: 1,
 				WarehouseCode:      "a",
 				Address:            "a",
 				Telephone:          "1234",
 				MinimumCapacity:    1,
-				MinimumTemperature: 1},
+				MinimumTemperature: 1,
+				LocalityID:         1},
 		}
 		mock.On("GetAll").Return(warehouses, nil)
 		req := httptest.NewRequest(http.MethodGet, "/warehouses", nil)
@@ -220,7 +252,8 @@ func TestWarehouseController_ReadAll(t *testing.T) {
             "Address": "a",
             "Telephone": "1234",
             "Minimum_Capacity": 1,
-            "Minimum_Temperature": 1
+            "Minimum_Temperature": 1,
+            "Locality_ID": 1
         },
 		 {
             "ID": 1,
@@ -228,7 +261,8 @@ func TestWarehouseController_ReadAll(t *testing.T) {
             "Address": "a",
             "Telephone": "1234",
             "Minimum_Capacity": 1,
-            "Minimum_Temperature": 1
+            "Minimum_Temperature": 1,
+            "Locality_ID": 1
 	} ]
 		}`
 
@@ -239,7 +273,7 @@ func TestWarehouseController_ReadAll(t *testing.T) {
 
 	t.Run("find_all_empty", func(t *testing.T) {
 		mock := tests.NewWarehouseMock()
-		serv := service.NewWarehouseService(mock)
+		serv := service.NewWarehouseService(mock, newLocalityMock())
 		handler := NewWarehouseHandler(serv)
 
 		warehouses := []models.Warehouse{}
@@ -261,7 +295,7 @@ func TestWarehouseController_ReadAll(t *testing.T) {
 
 func TestWarehouseController_Update(t *testing.T) {
 	mock := tests.NewWarehouseMock()
-	serv := service.NewWarehouseService(mock)
+	serv := service.NewWarehouseService(mock, newLocalityMock())
 	handler := NewWarehouseHandler(serv)
 
 	updatedWarehouse := models.Warehouse{ID: 1,
@@ -269,7 +303,8 @@ func TestWarehouseController_Update(t *testing.T) {
 		Address:            "a",
 		Telephone:          "1234",
 		MinimumCapacity:    1,
-		MinimumTemperature: 1}
+		MinimumTemperature: 1,
+		LocalityID:         1}
 	mock.On("GetByID", 1).Return(updatedWarehouse, nil)
 	mock.On("GetByID", 2).Return(models.Warehouse{}, e.ErrWarehouseRepositoryNotFound)
 
@@ -283,7 +318,8 @@ func TestWarehouseController_Update(t *testing.T) {
   "Telephone": "1234",
   "Warehouse_Code": "a",
   "Minimum_Capacity": 1,
-  "Minimum_Temperature": 1
+  "Minimum_Temperature": 1,
+  "Locality_ID": 1
 
 		}`)
 		req := httptest.NewRequest(http.MethodPatch, "/warehouses/1", body)
@@ -294,7 +330,7 @@ func TestWarehouseController_Update(t *testing.T) {
 		w := httptest.NewRecorder()
 
 		expected := `{
-			"data":{"Address":"a", "ID":1, "Minimum_Capacity":1, "Minimum_Temperature":1, "Telephone":"1234", "Warehouse_Code":"a"}, "message":"success", "success":true
+			"data":{"Address":"a", "ID":1, "Minimum_Capacity":1, "Minimum_Temperature":1, "Telephone":"1234", "Warehouse_Code":"a", "Locality_ID":1}, "message":"success", "success":true
 			
 		}`
 
@@ -308,7 +344,8 @@ func TestWarehouseController_Update(t *testing.T) {
   "Telephone": "1234",
   "Warehouse_Code": "a",
   "Minimum_Capacity": 1,
-  "Minimum_Temperature": 1}`)
+  "Minimum_Temperature": 1,
+  "Locality_ID": 1}`)
 		req := httptest.NewRequest(http.MethodPatch, "/warehouses/2", body)
 		routeCtx := chi.NewRouteContext()
 		routeCtx.URLParams.Add("id", "2")
@@ -325,12 +362,30 @@ func TestWarehouseController_Update(t *testing.T) {
 		require.JSONEq(t, expected, w.Body.String())
 	})
 
+	t.Run("update_locality_not_found", func(t *testing.T) {
+		body := strings.NewReader(`{"Address": "a", "Telephone": "1234", "Warehouse_Code": "a",
+  "Minimum_Capacity": 1, "Minimum_Temperature": 1, "Locality_ID": 5}`)
+		req := httptest.NewRequest(http.MethodPatch, "/warehouses/1", body)
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		expected := `{"data":null, "message":"repository: locality not found for warehouse", "success":false}`
+
+		handler.Update().ServeHTTP(w, req)
+		require.Equal(t, http.StatusConflict, w.Code)
+		require.JSONEq(t, expected, w.Body.String())
+	})
+
 	t.Run("update_invalidedID", func(t *testing.T) {
 		body := strings.NewReader(`{"Address": "a",
   "Telephone": "1234",
   "Warehouse_Code": "a",
   "Minimum_Capacity": 1,
-  "Minimum_Temperature": 1}`)
+  "Minimum_Temperature": 1,
+  "Locality_ID": 1}`)
 		req := httptest.NewRequest(http.MethodPatch, "/warehouses/a", body)
 		routeCtx := chi.NewRouteContext()
 		routeCtx.URLParams.Add("id", "a")
@@ -351,7 +406,7 @@ func TestWarehouseController_Update(t *testing.T) {
 
 func TestWarehouseController_Delete(t *testing.T) {
 	mock := tests.NewWarehouseMock()
-	serv := service.NewWarehouseService(mock)
+	serv := service.NewWarehouseService(mock, newLocalityMock())
 	handler := NewWarehouseHandler(serv)
 
 	mock.On("Delete", 1).Return(nil)
@@ -401,3 +456,337 @@ func TestWarehouseController_Delete(t *testing.T) {
 		require.JSONEq(t, expected, w.Body.String())
 	})
 }
+
+func TestWarehouseController_ReportCapacity(t *testing.T) {
+	reports := func() []models.WarehouseCapacityReport {
+		return []models.WarehouseCapacityReport{
+			{WarehouseID: 1, WarehouseCode: "WH1", MinimumCapacity: 50, SectionsCount: 2, CurrentCapacity: 60, MaximumCapacity: 80, SectionsBelowMinimum: 0},
+			{WarehouseID: 2, WarehouseCode: "WH2", MinimumCapacity: 100, SectionsCount: 1, CurrentCapacity: 10, MaximumCapacity: 40, SectionsBelowMinimum: 1},
+			{WarehouseID: 3, WarehouseCode: "WH3", MinimumCapacity: 10},
+		}
+	}
+	one := 2
+	missing := 9
+
+	cases := []struct {
+		name         string
+		query        string
+		setup        func(m *tests.WarehouseMock)
+		expectedCode int
+		expectedIDs  []int
+		expectedMsg  string
+	}{
+		{
+			name:         "all_sorted_by_id",
+			setup:        func(m *tests.WarehouseMock) { m.On("GetCapacityReport", (*int)(nil)).Return(reports(), nil) },
+			expectedCode: http.StatusOK,
+			expectedIDs:  []int{1, 2, 3},
+		},
+		{
+			name:         "sorted_by_utilization",
+			query:        "?sort=utilization",
+			setup:        func(m *tests.WarehouseMock) { m.On("GetCapacityReport", (*int)(nil)).Return(reports(), nil) },
+			expectedCode: http.StatusOK,
+			expectedIDs:  []int{3, 2, 1},
+		},
+		{
+			name:         "sorted_by_utilization_desc",
+			query:        "?sort=-utilization",
+			setup:        func(m *tests.WarehouseMock) { m.On("GetCapacityReport", (*int)(nil)).Return(reports(), nil) },
+			expectedCode: http.StatusOK,
+			expectedIDs:  []int{1, 2, 3},
+		},
+		{
+			name:         "by_id",
+			query:        "?id=2",
+			setup:        func(m *tests.WarehouseMock) { m.On("GetCapacityReport", &one).Return(reports()[1:2], nil) },
+			expectedCode: http.StatusOK,
+			expectedIDs:  []int{2},
+		},
+		{
+			name:  "id_not_found",
+			query: "?id=9",
+			setup: func(m *tests.WarehouseMock) {
+				m.On("GetCapacityReport", &missing).Return([]models.WarehouseCapacityReport(nil), e.ErrWarehouseRepositoryNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+			expectedMsg:  e.ErrWarehouseRepositoryNotFound.Error(),
+		},
+		{
+			name:         "id_not_int",
+			query:        "?id=a",
+			setup:        func(m *tests.WarehouseMock) {},
+			expectedCode: http.StatusBadRequest,
+			expectedMsg:  e.ErrRequestIdMustBeInt.Error(),
+		},
+		{
+			name:         "sort_invalid",
+			query:        "?sort=code",
+			setup:        func(m *tests.WarehouseMock) {},
+			expectedCode: http.StatusBadRequest,
+			expectedMsg:  e.ErrWarehouseReportSortInvalid.Error(),
+		},
+		{
+			name: "repository_error",
+			setup: func(m *tests.WarehouseMock) {
+				m.On("GetCapacityReport", (*int)(nil)).Return([]models.WarehouseCapacityReport(nil), e.ErrRepositoryDatabase)
+			},
+			expectedCode: http.StatusInternalServerError,
+			expectedMsg:  e.ErrRequestInternalServer.Error(),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := tests.NewWarehouseMock()
+			tc.setup(mock)
+			handler := NewWarehouseHandler(service.NewWarehouseService(mock, newLocalityMock()))
+
+			req := httptest.NewRequest(http.MethodGet, "/warehouses/reportCapacity"+tc.query, nil)
+			w := httptest.NewRecorder()
+			handler.ReportCapacity().ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedCode, w.Code)
+			var body struct {
+				Message string                           `json:"message"`
+				Data    []models.WarehouseCapacityReport `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			if tc.expectedMsg != "" {
+				require.Equal(t, tc.expectedMsg, body.Message)
+				return
+			}
+			ids := make([]int, len(body.Data))
+			for i, rp := range body.Data {
+				ids[i] = rp.WarehouseID
+			}
+			require.Equal(t, tc.expectedIDs, ids)
+			mock.AssertExpectations(t)
+		})
+	}
+
+	t.Run("utilization_and_minimum", func(t *testing.T) {
+		mock := tests.NewWarehouseMock()
+		mock.On("GetCapacityReport", (*int)(nil)).Return(reports(), nil)
+		handler := NewWarehouseHandler(service.NewWarehouseService(mock, newLocalityMock()))
+
+		req := httptest.NewRequest(http.MethodGet, "/warehouses/reportCapacity", nil)
+		w := httptest.NewRecorder()
+		handler.ReportCapacity().ServeHTTP(w, req)
+
+		expected := `{
+			"success": true,
+			"message": "handler: data retrieved successfully",
+			"data": [
+				{"warehouse_id": 1, "warehouse_code": "WH1", "minimum_capacity": 50, "sections_count": 2, "current_capacity": 60, "maximum_capacity": 80, "utilization_percentage": 75, "sections_below_minimum": 0, "meets_minimum_capacity": true},
+				{"warehouse_id": 2, "warehouse_code": "WH2", "minimum_capacity": 100, "sections_count": 1, "current_capacity": 10, "maximum_capacity": 40, "utilization_percentage": 25, "sections_below_minimum": 1, "meets_minimum_capacity": false},
+				{"warehouse_id": 3, "warehouse_code": "WH3", "minimum_capacity": 10, "sections_count": 0, "current_capacity": 0, "maximum_capacity": 0, "utilization_percentage": 0, "sections_below_minimum": 0, "meets_minimum_capacity": false}
+			]
+		}`
+		require.Equal(t, http.StatusOK, w.Code)
+		require.JSONEq(t, expected, w.Body.String())
+	})
+}
+
+// withWarehouseID adds the id URL param to the request
+func withWarehouseID(req *http.Request, id string) *http.Request {
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", id)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+}
+
+func TestWarehouseController_Overview(t *testing.T) {
+	wh := models.Warehouse{ID: 1, WarehouseCode: "WH1", Address: "a", Telephone: "1234", MinimumCapacity: 10, MinimumTemperature: 1, LocalityID: 1}
+	sections := []models.WarehouseSection{{Section: models.Section{ID: 3, SectionNumber: 1, CurrentCapacity: 5, MinimumCapacity: 2, MaximumCapacity: 9, WarehouseID: 1, ProductTypeID: 2}, ActiveBatches: 4}}
+	employees := []models.Employee{{ID: 7, CardNumberID: "C7", FirstName: "Ana", LastName: "Paz", WarehouseID: 1}}
+	orders := []models.InboundOrders{{Id: 8, OrderDate: "2024-05-01", OrderNumber: "IO-8", EmployeeId: 7, ProductBatchId: 2, WarehouseId: 1}}
+
+	t.Run("overview_ok", func(t *testing.T) {
+		mock := tests.NewWarehouseMock()
+		mock.On("GetByID", 1).Return(wh, nil)
+		mock.On("GetSections", 1, models.WarehouseSectionFilter{}).Return(sections, nil)
+		mock.On("GetEmployees", 1, "").Return(employees, nil)
+		mock.On("GetInboundOrders", 1, service.RecentInboundOrders).Return(orders, nil)
+		handler := NewWarehouseHandler(service.NewWarehouseService(mock, newLocalityMock()))
+
+		req := withWarehouseID(httptest.NewRequest(http.MethodGet, "/warehouses/1/overview", nil), "1")
+		w := httptest.NewRecorder()
+		handler.Overview().ServeHTTP(w, req)
+
+		expected := `{"success": true, "message": "handler: data retrieved successfully", "data": {
+			"warehouse": {"ID": 1, "Warehouse_Code": "WH1", "Address": "a", "Telephone": "1234", "Minimum_Capacity": 10, "Minimum_Temperature": 1, "Locality_ID": 1},
+			"sections": [{"id": 3, "section_number": 1, "current_temperature": 0, "minimum_temperature": 0, "current_capacity": 5, "minimum_capacity": 2, "maximum_capacity": 9, "warehouse_id": 1, "product_type_id": 2, "active_batches": 4}],
+			"employees": [{"id": 7, "card_number_id": "C7", "first_name": "Ana", "last_name": "Paz", "warehouse_id": 1}],
+			"recent_inbound_orders": [{"id": 8, "order_date": "2024-05-01", "order_number": "IO-8", "employee_id": 7, "product_batch_id": 2, "warehouse_id": 1}]
+		}}`
+		require.Equal(t, http.StatusOK, w.Code)
+		require.JSONEq(t, expected, w.Body.String())
+	})
+
+	t.Run("overview_not_found", func(t *testing.T) {
+		mock := tests.NewWarehouseMock()
+		mock.On("GetByID", 2).Return(models.Warehouse{}, e.ErrWarehouseRepositoryNotFound)
+		handler := NewWarehouseHandler(service.NewWarehouseService(mock, newLocalityMock()))
+
+		req := withWarehouseID(httptest.NewRequest(http.MethodGet, "/warehouses/2/overview", nil), "2")
+		w := httptest.NewRecorder()
+		handler.Overview().ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+		require.JSONEq(t, `{"data":null, "message":"repository: warehouse not found", "success":false}`, w.Body.String())
+	})
+
+	t.Run("overview_repository_error", func(t *testing.T) {
+		mock := tests.NewWarehouseMock()
+		mock.On("GetByID", 1).Return(wh, nil)
+		mock.On("GetSections", 1, models.WarehouseSectionFilter{}).Return([]models.WarehouseSection(nil), e.ErrRepositoryDatabase)
+		handler := NewWarehouseHandler(service.NewWarehouseService(mock, newLocalityMock()))
+
+		req := withWarehouseID(httptest.NewRequest(http.MethodGet, "/warehouses/1/overview", nil), "1")
+		w := httptest.NewRecorder()
+		handler.Overview().ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("overview_invalid_id", func(t *testing.T) {
+		handler := NewWarehouseHandler(service.NewWarehouseService(tests.NewWarehouseMock(), newLocalityMock()))
+
+		req := withWarehouseID(httptest.NewRequest(http.MethodGet, "/warehouses/a/overview", nil), "a")
+		w := httptest.NewRecorder()
+		handler.Overview().ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestWarehouseController_GetSections(t *testing.T) {
+	wh := models.Warehouse{ID: 1}
+	productType := 2
+
+	cases := []struct {
+		name         string
+		query        string
+		filter       *models.WarehouseSectionFilter
+		expectedCode int
+	}{
+		{name: "without_filters", filter: &models.WarehouseSectionFilter{}, expectedCode: http.StatusOK},
+		{name: "by_product_type", query: "?product_type_id=2", filter: &models.WarehouseSectionFilter{ProductTypeID: &productType}, expectedCode: http.StatusOK},
+		{name: "below_minimum", query: "?below_minimum=true", filter: &models.WarehouseSectionFilter{BelowMinimum: true}, expectedCode: http.StatusOK},
+		{name: "invalid_product_type", query: "?product_type_id=x", expectedCode: http.StatusBadRequest},
+		{name: "invalid_below_minimum", query: "?below_minimum=maybe", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := tests.NewWarehouseMock()
+			if tc.filter != nil {
+				mock.On("GetByID", 1).Return(wh, nil)
+				mock.On("GetSections", 1, *tc.filter).Return([]models.WarehouseSection{}, nil)
+			}
+			handler := NewWarehouseHandler(service.NewWarehouseService(mock, newLocalityMock()))
+
+			req := withWarehouseID(httptest.NewRequest(http.MethodGet, "/warehouses/1/sections"+tc.query, nil), "1")
+			w := httptest.NewRecorder()
+			handler.GetSections().ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedCode, w.Code)
+			mock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWarehouseController_GetEmployees(t *testing.T) {
+	t.Run("employees_by_name", func(t *testing.T) {
+		mock := tests.NewWarehouseMock()
+		mock.On("GetByID", 1).Return(models.Warehouse{ID: 1}, nil)
+		mock.On("GetEmployees", 1, "an").Return([]models.Employee{{ID: 7, CardNumberID: "C7", FirstName: "Ana", LastName: "Paz", WarehouseID: 1}}, nil)
+		handler := NewWarehouseHandler(service.NewWarehouseService(mock, newLocalityMock()))
+
+		req := withWarehouseID(httptest.NewRequest(http.MethodGet, "/warehouses/1/employees?name=an", nil), "1")
+		w := httptest.NewRecorder()
+		handler.GetEmployees().ServeHTTP(w, req)
+
+		expected := `{"success": true, "message": "handler: data retrieved successfully", "data": [
+			{"id": 7, "card_number_id": "C7", "first_name": "Ana", "last_name": "Paz", "warehouse_id": 1}
+		]}`
+		require.Equal(t, http.StatusOK, w.Code)
+		require.JSONEq(t, expected, w.Body.String())
+	})
+
+	t.Run("employees_warehouse_not_found", func(t *testing.T) {
+		mock := tests.NewWarehouseMock()
+		mock.On("GetByID", 2).Return(models.Warehouse{}, e.ErrWarehouseRepositoryNotFound)
+		handler := NewWarehouseHandler(service.NewWarehouseService(mock, newLocalityMock()))
+
+		req := withWarehouseID(httptest.NewRequest(http.MethodGet, "/warehouses/2/employees", nil), "2")
+		w := httptest.NewRecorder()
+		handler.GetEmployees().ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestWarehouseController_Nearest(t *testing.T) {
+	lat, lng := -34.6037, -58.3816
+	localities := newLocalityMock()
+	localities.On("FindByID", 3).Return(models.Locality{ID: 3, Latitude: &lat, Longitude: &lng}, nil)
+	localities.On("FindByID", 4).Return(models.Locality{ID: 4}, nil)
+	localities.On("FindByID", 9).Return(models.Locality{}, e.ErrLocalityRepositoryNotFound)
+
+	mock := tests.NewWarehouseMock()
+	mock.On("GetStockingProduct", 2).Return([]models.WarehouseDistance{
+		{WarehouseID: 1, WarehouseCode: "ROS", LocalityID: 1, Latitude: -32.9442, Longitude: -60.6505, AvailableQuantity: 40},
+		{WarehouseID: 2, WarehouseCode: "CBA", LocalityID: 1, Latitude: -31.4201, Longitude: -64.1888, AvailableQuantity: 15},
+		{WarehouseID: 3, WarehouseCode: "LPL", LocalityID: 1, Latitude: -34.9214, Longitude: -57.9545, AvailableQuantity: 8},
+	}, nil)
+	handler := NewWarehouseHandler(service.NewWarehouseService(mock, localities))
+
+	t.Run("nearest_ok", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/warehouses/nearest?locality_id=3&product_id=2&limit=2", nil)
+		w := httptest.NewRecorder()
+		handler.Nearest().ServeHTTP(w, req)
+
+		expected := `{"success": true, "message": "handler: data retrieved successfully", "data": [
+			{"warehouse_id": 3, "warehouse_code": "LPL", "address": "", "locality_id": 1, "latitude": -34.9214, "longitude": -57.9545, "available_quantity": 8, "distance_km": 52.63},
+			{"warehouse_id": 1, "warehouse_code": "ROS", "address": "", "locality_id": 1, "latitude": -32.9442, "longitude": -60.6505, "available_quantity": 40, "distance_km": 279.32}
+		]}`
+		require.Equal(t, http.StatusOK, w.Code)
+		require.JSONEq(t, expected, w.Body.String())
+	})
+
+	cases := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{name: "nearest_missing_product", query: "locality_id=3", status: http.StatusBadRequest},
+		{name: "nearest_limit_out_of_range", query: "locality_id=3&product_id=2&limit=51", status: http.StatusBadRequest},
+		{name: "nearest_locality_not_found", query: "locality_id=9&product_id=2", status: http.StatusNotFound},
+		{name: "nearest_locality_without_coordinates", query: "locality_id=4&product_id=2", status: http.StatusUnprocessableEntity},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/warehouses/nearest?"+tc.query, nil)
+			w := httptest.NewRecorder()
+			handler.Nearest().ServeHTTP(w, req)
+
+			require.Equal(t, tc.status, w.Code)
+		})
+	}
+}
+
+func TestWarehouseController_CreateInvalidCoordinates(t *testing.T) {
+	handler := NewWarehouseHandler(service.NewWarehouseService(tests.NewWarehouseMock(), newLocalityMock()))
+	body := strings.NewReader(`{"Address": "a", "Telephone": "1234", "Warehouse_Code": "geo",
+  "Minimum_Capacity": 1, "Minimum_Temperature": 1, "Locality_ID": 1, "Latitude": 91, "Longitude": -58.38}`)
+	req := httptest.NewRequest(http.MethodPost, "/warehouses", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.Create().ServeHTTP(w, req)
+
+	expected := `{"data":null, "message":"Campos inválidos: Key: 'Warehouse.Latitude' Error:Field validation for 'Latitude' failed on the 'latitude' tag", "success":false}`
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.JSONEq(t, expected, w.Body.String())
+}
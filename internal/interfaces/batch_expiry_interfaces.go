@@ -0,0 +1,32 @@
+package internal
+
+import (
+	"net/http"
+	"time"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// BatchExpiryRepository finds the batches close to their due date and stores the expiry alerts
+type BatchExpiryRepository interface {
+	// FindExpiring returns the batches of the query, the ones due first first
+	FindExpiring(q mod.ExpiringQuery) ([]mod.ExpiringBatch, error)
+	// Sweep expires the batches due at the given time, warns about the ones within the lead time of their product
+	// and returns the alerts raised
+	Sweep(at time.Time, leadTime time.Duration) ([]mod.Alert, error)
+	// FindAlerts returns the alerts of the filter, the newest first
+	FindAlerts(f mod.AlertFilter) ([]mod.Alert, error)
+}
+
+// BatchExpiryService finds the expiring batches, runs the expiry sweep and lists its alerts
+type BatchExpiryService interface {
+	FindExpiring(days, warehouseID int) ([]mod.ExpiringBatch, error)
+	Sweep() ([]mod.Alert, error)
+	FindAlerts(f mod.AlertFilter) ([]mod.Alert, error)
+}
+
+// BatchExpiryHandler serves the expiring batches and the alerts
+type BatchExpiryHandler interface {
+	GetExpiring() http.HandlerFunc
+	GetAlerts() http.HandlerFunc
+}
@@ -0,0 +1,19 @@
+package internal
+
+import (
+	"net/http"
+
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/internal/events"
+)
+
+// EventPublisher is an interface that contains the methods that the event publishers should support
+type EventPublisher interface {
+	// Publish sends the event of the given topic and type to the subscribers
+	Publish(topic, kind string, data any) events.Event
+}
+
+// EventHandler is an interface that contains the methods that the event handler should support
+type EventHandler interface {
+	// Stream pushes the published events to the client as Server-Sent Events
+	Stream() http.HandlerFunc
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"net/http"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// IncludeRepository is an interface that contains the batched queries used to embed related resources
+type IncludeRepository interface {
+	// SellersByIDs returns the sellers with the given IDs
+	SellersByIDs(ids []int) ([]mod.Seller, error)
+	// LocalitiesByIDs returns the localities with the given IDs
+	LocalitiesByIDs(ids []int) ([]mod.Locality, error)
+	// ProductsByIDs returns the products with the given IDs
+	ProductsByIDs(ids []int) ([]mod.Product, error)
+	// ProductsBySellerIDs returns the products of the given sellers
+	ProductsBySellerIDs(ids []int) ([]mod.Product, error)
+	// RecordsByProductIDs returns the records of the given products
+	RecordsByProductIDs(ids []int) ([]mod.ProductRecord, error)
+	// WarehousesByIDs returns the warehouses with the given IDs
+	WarehousesByIDs(ids []int) ([]mod.Warehouse, error)
+	// SectionsByIDs returns the sections with the given IDs
+	SectionsByIDs(ids []int) ([]mod.Section, error)
+	// BatchesBySectionIDs returns the product batches of the given sections
+	BatchesBySectionIDs(ids []int) ([]mod.ProductBatch, error)
+	// BuyersByIDs returns the buyers with the given IDs
+	BuyersByIDs(ids []int) ([]mod.Buyer, error)
+	// DetailsByPurchaseOrderIDs returns the details of the given purchase orders
+	DetailsByPurchaseOrderIDs(ids []int) ([]mod.OrderDetails, error)
+}
+
+// IncludeService is an interface that contains the methods that the include service should support
+type IncludeService interface {
+	// Validate checks that every include path, e.g. seller.locality, is supported by the resource
+	Validate(resource string, includes []string) error
+	// Expand adds the included relations to the JSON representation of the items
+	Expand(resource string, items []map[string]any, includes []string) error
+	// Keys returns the response keys written by the includes, e.g. seller for seller.locality
+	Keys(resource string, includes []string) []string
+}
+
+// IncludeHandler is an interface that contains the methods that the include handler should support
+type IncludeHandler interface {
+	// Wrap adds include and fields support to a handler responding the given resource
+	Wrap(resource string, next http.HandlerFunc) http.HandlerFunc
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"net/http"
+	"time"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// JobRepository is an interface that contains the methods that the job repository should support
+type JobRepository interface {
+	// AcquireLease takes or extends the lease of the job for the owner until the given time, it returns false
+	// when another owner holds a lease that has not expired at now or when the scheduled activation is not
+	// later than the last one taken. A zero activation is a manual run and skips that check
+	AcquireLease(job, owner string, activation, now, until time.Time) (bool, error)
+	// ReleaseLease ends the lease of the job when it is held by the owner
+	ReleaseLease(job, owner string, now time.Time) error
+	// SetPaused pauses or resumes the scheduled runs of the job
+	SetPaused(job string, paused bool) error
+	// FindPaused returns the paused jobs
+	FindPaused() (map[string]bool, error)
+	// SaveRun records a run of a job
+	SaveRun(run *mod.JobRun) error
+	// FindRuns returns the latest runs of the job, newest first
+	FindRuns(job string, limit int) ([]mod.JobRun, error)
+	// FindLastRuns returns the latest run of every job that ever ran
+	FindLastRuns() (map[string]mod.JobRun, error)
+	// DeleteRunsBefore deletes the runs started before the given time and returns how many were deleted
+	DeleteRunsBefore(t time.Time) (int64, error)
+}
+
+// JobService is an interface that contains the methods that the job service should support
+type JobService interface {
+	// FindAll returns every registered job with its state
+	FindAll() ([]mod.Job, error)
+	// FindRuns returns the latest runs of the job, newest first
+	FindRuns(name string, limit int) ([]mod.JobRun, error)
+	// Trigger starts a run of the job right away
+	Trigger(name string) error
+	// Pause skips the scheduled runs of the job
+	Pause(name string) error
+	// Resume restores the scheduled runs of the job
+	Resume(name string) error
+}
+
+// JobHandler is an interface that contains the methods that the job handler should support
+type JobHandler interface {
+	// GetAll returns every registered job
+	GetAll() http.HandlerFunc
+	// GetRuns returns the run history of a job
+	GetRuns() http.HandlerFunc
+	// Trigger starts a run of a job
+	Trigger() http.HandlerFunc
+	// Pause pauses a job
+	Pause() http.HandlerFunc
+	// Resume resumes a job
+	Resume() http.HandlerFunc
+}
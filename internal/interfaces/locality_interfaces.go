@@ -10,8 +10,16 @@ import (
 type LocalityRepository interface {
 	// FindByID returns the seller with the given ID
 	FindAllLocalities() (result []mod.Locality, err error)
+	// FindByID returns the locality with the given ID
+	FindByID(id int) (locality mod.Locality, err error)
+	// ExistsByID tells if the locality with the given ID exists
+	ExistsByID(id int) (exists bool, err error)
 
 	FindSellersByLocID(id int) (result []mod.SelByLoc, err error)
+	// FindWarehousesByLocID returns the warehouses count of every locality, or of one locality when id is not -1
+	FindWarehousesByLocID(id int) (result []mod.WhByLoc, err error)
+	// FindOverviewByLocID returns the sellers, carries and warehouses count of every locality, or of one locality when id is not -1
+	FindOverviewByLocID(id int) (result []mod.LocalityOverview, err error)
 	// Save saves the given locality
 	Save(locality *mod.Locality) (id int, err error)
 }
@@ -22,6 +30,10 @@ type LocalityService interface {
 	FindAllLocalities() (result []mod.Locality, err error)
 
 	FindSellersByLocID(id int) (result []mod.SelByLoc, err error)
+	// FindWarehousesByLocID returns the warehouses count of every locality, or of one locality when id is not -1
+	FindWarehousesByLocID(id int) (result []mod.WhByLoc, err error)
+	// FindOverviewByLocID returns the sellers, carries and warehouses count of every locality, or of one locality when id is not -1
+	FindOverviewByLocID(id int) (result []mod.LocalityOverview, err error)
 	// Save saves the given locality
 	Save(locality *mod.Locality) (id int, err error)
 }
@@ -32,6 +44,10 @@ type LocalityHandler interface {
 	GetAll() http.HandlerFunc
 
 	GetSelByLocID(id int) http.HandlerFunc
+	// GetWhByLocID returns the warehouses count by locality
+	GetWhByLocID() http.HandlerFunc
+	// GetOverview returns the sellers, carries and warehouses count by locality
+	GetOverview() http.HandlerFunc
 	// Save saves the given locality
 	Create(locality *mod.Locality) http.HandlerFunc
 }
@@ -3,19 +3,48 @@ package internal
 import (
 	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	"net/http"
+	"time"
 )
 
 type ProductBatchRepository interface {
-	FindAll() (batches []mod.ProductBatch, err error)
+	FindAll(f mod.ProductBatchFilter) (batches []mod.ProductBatch, err error)
+	FindByID(id int) (mod.ProductBatch, error)
 	Save(batch *mod.ProductBatch) error
+	// Update applies the patch to a batch that is not archived and returns the updated batch
+	Update(id int, patch mod.ProductBatchPatch) (mod.ProductBatch, error)
+	// Archive writes off the units of the batch and marks it archived at the given time
+	Archive(id int, at time.Time) (mod.ProductBatch, error)
+	// Delete deletes the batch, or retires it when it is archived and referenced by orders, transfers or status
+	// changes. A batch with stock movements is written off and retired so the ledger keeps its history
+	Delete(id int) error
+	// ChangeStatus moves a batch whose status is one of from to the status of the change and records the change
+	ChangeStatus(change *mod.BatchStatusChange, from ...string) (mod.ProductBatch, error)
+	// FindStatusHistory returns the status changes of the batch, oldest first
+	FindStatusHistory(id int) ([]mod.BatchStatusChange, error)
 }
 
 type ProductBatchService interface {
-	FindAll() (batches []mod.ProductBatch, err error)
+	FindAll(f mod.ProductBatchFilter) (batches []mod.ProductBatch, err error)
+	FindByID(id int) (mod.ProductBatch, error)
 	Save(batch *mod.ProductBatch) error
+	Update(id int, patch mod.ProductBatchPatch) (mod.ProductBatch, error)
+	Archive(id int) (mod.ProductBatch, error)
+	Delete(id int) error
+	// Hold quarantines an available or released batch
+	Hold(id int, req mod.BatchHoldRequest) (mod.ProductBatch, error)
+	// Release releases or rejects a quarantined batch
+	Release(id int, req mod.BatchHoldRequest) (mod.ProductBatch, error)
+	FindStatusHistory(id int) ([]mod.BatchStatusChange, error)
 }
 
 type ProductBatchHandler interface {
 	GetAll() http.HandlerFunc
+	GetByID() http.HandlerFunc
 	Create() http.HandlerFunc
+	Update() http.HandlerFunc
+	Archive() http.HandlerFunc
+	Delete() http.HandlerFunc
+	Hold() http.HandlerFunc
+	Release() http.HandlerFunc
+	GetStatusHistory() http.HandlerFunc
 }
@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"net/http"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// ProductTypeRepository is an interface that contains the methods that the product type repository should support
+type ProductTypeRepository interface {
+	// FindAll returns all the product types
+	FindAll() ([]mod.ProductType, error)
+	// FindByID returns the product type with the given ID
+	FindByID(id int) (mod.ProductType, error)
+	// Save saves the given product type and fills its ID
+	Save(pt *mod.ProductType) error
+	// Update updates the given product type
+	Update(pt *mod.ProductType) error
+	// Delete deletes the product type with the given ID, it fails while products or sections use it
+	Delete(id int) error
+}
+
+// ProductTypeService is an interface that contains the methods that the product type service should support
+type ProductTypeService interface {
+	// FindAll returns all the product types
+	FindAll() ([]mod.ProductType, error)
+	// FindByID returns the product type with the given ID
+	FindByID(id int) (mod.ProductType, error)
+	// Save saves the given product type
+	Save(pt *mod.ProductType) error
+	// Update applies the patch to the product type with the given ID and returns it
+	Update(id int, patch mod.ProductTypePatch) (mod.ProductType, error)
+	// Delete deletes the product type with the given ID
+	Delete(id int) error
+}
+
+// ProductTypeHandler is an interface that contains the methods that the product type handler should support
+type ProductTypeHandler interface {
+	GetAll() http.HandlerFunc
+	GetByID() http.HandlerFunc
+	Create() http.HandlerFunc
+	Update() http.HandlerFunc
+	Delete() http.HandlerFunc
+}
@@ -3,11 +3,19 @@ package internal
 import mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 
 type PurchaseOrderRepository interface {
-	// Save saves the given purchase order
+	// FindAll returns all the purchase orders without their details
+	FindAll() ([]mod.PurchaseOrder, error)
+	// FindByID returns the purchase order with the given ID without its details
+	FindByID(id int) (mod.PurchaseOrder, error)
+	// Save saves the given purchase order and allocates its details to the batches of their products
 	Save(purhcaseOrder *mod.PurchaseOrder) error
 }
 
 type PurchaseOrderService interface {
+	// FindAll returns all the purchase orders without their details
+	FindAll() ([]mod.PurchaseOrder, error)
+	// FindByID returns the purchase order with the given ID without its details
+	FindByID(id int) (mod.PurchaseOrder, error)
 	// Save saves the given purchase order
 	Save(purhcaseOrder *mod.PurchaseOrder) error
 }
@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// ReportGenerator builds the rows of a report type
+type ReportGenerator func(ctx context.Context, params mod.ReportParams) ([]map[string]any, error)
+
+// ReportService is an interface that contains the methods that the report service should support
+type ReportService interface {
+	// Enqueue validates the parameters and queues the report of the given type
+	Enqueue(reportType string, params mod.ReportParams) (mod.ReportJob, error)
+	// FindByID returns the job with the given ID
+	FindByID(id string) (mod.ReportJob, error)
+	// Open returns the file of a finished report, the caller must close it
+	Open(id string) (mod.ReportJob, io.ReadSeekCloser, error)
+	// Cleanup deletes the jobs and files finished before the given time and returns how many were deleted
+	Cleanup(before time.Time) (int, error)
+}
+
+// ReportHandler is an interface that contains the methods that the report handler should support
+type ReportHandler interface {
+	// Create queues a report
+	Create() http.HandlerFunc
+	// GetJob returns the status and progress of a report
+	GetJob() http.HandlerFunc
+	// Download serves the file of a finished report
+	Download() http.HandlerFunc
+}
@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"net/http"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// SearchRepository is an interface that contains the methods that the search repository should support
+type SearchRepository interface {
+	// Search returns up to limit entities of the given type matching any of the terms
+	Search(entity string, terms []string, limit int) ([]mod.SearchResult, error)
+}
+
+// SearchService is an interface that contains the methods that the search service should support
+type SearchService interface {
+	// Search returns the ranked entities of the given types matching the query
+	Search(q string, types []string, limit int) ([]mod.SearchResult, error)
+}
+
+// SearchHandler is an interface that contains the methods that the search handler should support
+type SearchHandler interface {
+	// Search returns the ranked entities matching the q parameter
+	Search() http.HandlerFunc
+}
@@ -19,7 +19,9 @@ type SectionRepository interface {
 	// Delete deletes the section with the given ID
 	Delete(id int) error
 	//ReportProducts it will return the quantity of products of each section
-	ReportProducts(ids []int) ([]mod.ReportProductsResponse, error)
+	ReportProducts(f mod.SectionReportFilter) ([]mod.ReportProductsResponse, error)
+	// FindSlotCandidates returns the product of the request and the sections of its product type with room for it
+	FindSlotCandidates(req mod.SlottingRequest) (mod.SlottingProduct, []mod.SlottingSection, error)
 }
 
 // SectionService is an interface that contains the methods that the section service should support
@@ -34,7 +36,9 @@ type SectionService interface {
 	Update(id int, fields map[string]interface{}) (*mod.Section, error)
 	// Delete deletes the section with the given ID
 	Delete(id int) error
-	ReportProducts(ids []int) ([]mod.ReportProductsResponse, error)
+	ReportProducts(f mod.SectionReportFilter) ([]mod.ReportProductsResponse, error)
+	// Recommend returns the sections that can take the batch of the request, best first
+	Recommend(req mod.SlottingRequest) ([]mod.SectionRecommendation, error)
 }
 
 // SectionHandler is an interface that contains the methods that the section service should support
@@ -50,4 +54,6 @@ type SectionHandler interface {
 	// Delete deletes the section with the given ID
 	Delete() http.HandlerFunc
 	ReportProducts() http.HandlerFunc
+	// Recommend returns the ranked sections for an incoming batch
+	Recommend() http.HandlerFunc
 }
@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"net/http"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// SectionReadingRepository stores the temperature telemetry of the sections
+type SectionReadingRepository interface {
+	// Save stores the readings, given oldest first, updates the hourly rollups, sets the current temperature of
+	// the section to its latest reading and opens or closes its alerts
+	Save(sectionID int, readings []mod.SectionReading) (mod.SectionReadingsResult, error)
+	// FindReadings returns the readings of the section in the range, oldest first
+	FindReadings(sectionID int, rg mod.SectionReadingRange) ([]mod.SectionReading, error)
+	// FindRollups returns the hourly rollups of the section in the range, oldest first
+	FindRollups(sectionID int, rg mod.SectionReadingRange) ([]mod.SectionReadingRollup, error)
+	// FindAlerts returns the alerts of the section, newest first, only the open or closed ones when open is not nil
+	FindAlerts(sectionID int, open *bool) ([]mod.SectionAlert, error)
+}
+
+// SectionReadingService ingests and reads the temperature telemetry of the sections
+type SectionReadingService interface {
+	Ingest(sectionID int, batch mod.SectionReadingBatch) (mod.SectionReadingsResult, error)
+	FindReadings(sectionID int, rg mod.SectionReadingRange) ([]mod.SectionReading, error)
+	FindRollups(sectionID int, rg mod.SectionReadingRange) ([]mod.SectionReadingRollup, error)
+	FindAlerts(sectionID int, open *bool) ([]mod.SectionAlert, error)
+}
+
+// SectionReadingHandler serves the section telemetry endpoints
+type SectionReadingHandler interface {
+	Create() http.HandlerFunc
+	GetReadings() http.HandlerFunc
+	GetRollups() http.HandlerFunc
+	GetAlerts() http.HandlerFunc
+}
@@ -0,0 +1,27 @@
+package internal
+
+import (
+	"net/http"
+	"time"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// SectionSnapshotRepository stores the occupancy and temperature history of the sections
+type SectionSnapshotRepository interface {
+	// SnapshotAll takes a periodic snapshot of every section at the given time and returns how many it took
+	SnapshotAll(at time.Time) (int, error)
+	// FindHistory returns the snapshots of the section in [q.From, q.To) aggregated by q.Interval, oldest first
+	FindHistory(sectionID int, q mod.SectionHistoryQuery) ([]mod.SectionHistoryBucket, error)
+}
+
+// SectionSnapshotService takes and reads the section snapshots
+type SectionSnapshotService interface {
+	Snapshot() (int, error)
+	FindHistory(sectionID int, q mod.SectionHistoryQuery) ([]mod.SectionHistoryBucket, error)
+}
+
+// SectionSnapshotHandler serves the section history endpoint
+type SectionSnapshotHandler interface {
+	GetHistory() http.HandlerFunc
+}
@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"net/http"
+	"time"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// StockMovementRepository reads and writes the stock ledger
+type StockMovementRepository interface {
+	// Adjust applies a manual movement to the batch and its section and records it, it fills the ID and the section
+	Adjust(m *mod.StockMovement) error
+	// FindLedgerByBatchID returns the movements of the batch, oldest first, with its balances
+	FindLedgerByBatchID(batchID int) (mod.StockLedger, error)
+	// Reconcile replaces the discrepancies with the batches and sections whose counters disagree with the ledger
+	Reconcile(at time.Time) ([]mod.StockDiscrepancy, error)
+	// FindDiscrepancies returns the discrepancies found by the last reconciliation
+	FindDiscrepancies() ([]mod.StockDiscrepancy, error)
+}
+
+// StockMovementService reads and writes the stock ledger
+type StockMovementService interface {
+	Adjust(m *mod.StockMovement) error
+	FindLedgerByBatchID(batchID int) (mod.StockLedger, error)
+	Reconcile() ([]mod.StockDiscrepancy, error)
+	FindDiscrepancies() ([]mod.StockDiscrepancy, error)
+}
+
+// StockMovementHandler serves the stock ledger endpoints
+type StockMovementHandler interface {
+	Adjust() http.HandlerFunc
+	GetBatchMovements() http.HandlerFunc
+	GetDiscrepancies() http.HandlerFunc
+}
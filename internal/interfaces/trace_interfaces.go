@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"net/http"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// TraceRepository follows the batches from their seller to the buyers of the purchase orders they served and
+// stores the recalls
+type TraceRepository interface {
+	// FindBatchTrace returns the origin of the batch and the purchase orders served by it and its splits
+	FindBatchTrace(id int) (mod.BatchTrace, error)
+	// FindPurchaseOrderTrace returns the origin of every batch allocated to the purchase order
+	FindPurchaseOrderTrace(id int) (mod.PurchaseOrderTrace, error)
+	// Recall rejects every batch of the split chain of the recalled batch, records the recall and fills its batches and buyers
+	Recall(recall *mod.Recall) error
+	// FindRecall returns a recall with its batches and the buyers that received them
+	FindRecall(id int) (mod.Recall, error)
+}
+
+// TraceService traces the batches and recalls them
+type TraceService interface {
+	FindBatchTrace(id int) (mod.BatchTrace, error)
+	FindPurchaseOrderTrace(id int) (mod.PurchaseOrderTrace, error)
+	Recall(req mod.RecallRequest) (mod.Recall, error)
+	FindRecall(id int) (mod.Recall, error)
+}
+
+// TraceHandler serves the traces and the recalls
+type TraceHandler interface {
+	GetBatchTrace() http.HandlerFunc
+	GetPurchaseOrderTrace() http.HandlerFunc
+	CreateRecall() http.HandlerFunc
+	GetRecall() http.HandlerFunc
+}
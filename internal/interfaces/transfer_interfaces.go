@@ -0,0 +1,30 @@
+package internal
+
+import (
+	"net/http"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// TransferRepository moves stock between sections
+type TransferRepository interface {
+	// Save moves the units of the transfer in a single transaction and records it. It fills the ID, the source
+	// section, the destination batch and, when it is 0, the quantity
+	Save(t *mod.Transfer) error
+	FindAll() ([]mod.Transfer, error)
+	FindByID(id int) (mod.Transfer, error)
+}
+
+// TransferService moves stock between sections
+type TransferService interface {
+	Save(t *mod.Transfer) error
+	FindAll() ([]mod.Transfer, error)
+	FindByID(id int) (mod.Transfer, error)
+}
+
+// TransferHandler serves the transfers endpoints
+type TransferHandler interface {
+	Create() http.HandlerFunc
+	GetAll() http.HandlerFunc
+	GetByID() http.HandlerFunc
+}
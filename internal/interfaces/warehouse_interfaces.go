@@ -12,6 +12,11 @@ type WarehouseHandler interface {
 	Create() http.HandlerFunc
 	Update() http.HandlerFunc
 	Delete() http.HandlerFunc
+	ReportCapacity() http.HandlerFunc
+	Overview() http.HandlerFunc
+	GetSections() http.HandlerFunc
+	GetEmployees() http.HandlerFunc
+	Nearest() http.HandlerFunc
 }
 
 type WarehouseService interface {
@@ -20,6 +25,16 @@ type WarehouseService interface {
 	Save(warehouse *models.Warehouse) error
 	Update(warehouse *models.Warehouse) error
 	Delete(id int) error
+	// ReportCapacity returns the capacity report of the warehouse, or of every warehouse when id is nil,
+	// sorted by id or by utilization
+	ReportCapacity(id *int, sort string) ([]models.WarehouseCapacityReport, error)
+	// FindOverview returns the warehouse with its sections, employees and latest inbound orders
+	FindOverview(id int) (models.WarehouseOverview, error)
+	FindSections(id int, filter models.WarehouseSectionFilter) ([]models.WarehouseSection, error)
+	// FindEmployees returns the employees of the warehouse whose first or last name contains name
+	FindEmployees(id int, name string) ([]models.Employee, error)
+	// FindNearest returns the limit warehouses closest to the locality that stock non-expired batches of the product
+	FindNearest(localityID, productID, limit int) ([]models.WarehouseDistance, error)
 }
 
 type WarehouseRepository interface {
@@ -30,4 +45,12 @@ type WarehouseRepository interface {
 	Update(wh *models.Warehouse) error
 	Delete(id int) error
 	ExistsWarehouseCode(code string) (bool, error)
+	GetCapacityReport(id *int) ([]models.WarehouseCapacityReport, error)
+	GetSections(id int, filter models.WarehouseSectionFilter) ([]models.WarehouseSection, error)
+	GetEmployees(id int, name string) ([]models.Employee, error)
+	// GetInboundOrders returns the latest inbound orders of the warehouse, newest first
+	GetInboundOrders(id int, limit int) ([]models.InboundOrders, error)
+	// GetStockingProduct returns the warehouses with coordinates that stock non-expired batches of the product,
+	// without their distance
+	GetStockingProduct(productID int) ([]models.WarehouseDistance, error)
 }
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewBatchExpiryRepo creates a new instance of the batch expiry repository
+func NewBatchExpiryRepo(db *sql.DB) *BatchExpiryDB {
+	return &BatchExpiryDB{db: db}
+}
+
+// BatchExpiryDB is the implementation of the expiry sweep and its alerts, see
+// docs/SQL/migrations/0012_batch_expiry_alerts.sql
+type BatchExpiryDB struct {
+	db *sql.DB
+}
+
+// stockedBatches joins the batches that still have units and were not archived with their warehouse and seller
+const stockedBatches = "FROM `product_batches` AS pb " +
+	"INNER JOIN `sections` AS s ON s.`id` = pb.`section_id` " +
+	"INNER JOIN `products` AS p ON p.`id` = pb.`product_id` " +
+	"WHERE pb.`current_quantity` > 0 AND pb.`archived_at` IS NULL AND pb.`retired_at` IS NULL"
+
+// withinLeadTime is the condition of a batch due within the lead time of its product, its args are the start of
+// the window and the lead time in seconds
+const withinLeadTime = "pb.`due_date` <= DATE_ADD(?, INTERVAL ROUND(? * p.`expiration_rate`) SECOND)"
+
+// FindExpiring returns the batches with units due after q.At and within the window of the query
+func (r *BatchExpiryDB) FindExpiring(q mod.ExpiringQuery) ([]mod.ExpiringBatch, error) {
+	query := "SELECT pb.`id`, pb.`batch_number`, pb.`product_id`, p.`seller_id`, pb.`section_id`, s.`warehouse_id`, pb.`current_quantity`, pb.`due_date` " +
+		stockedBatches + " AND pb.`due_date` > ?"
+	args := []any{q.At}
+	if q.Days > 0 {
+		query += " AND pb.`due_date` <= ?"
+		args = append(args, q.At.AddDate(0, 0, q.Days))
+	} else {
+		query += " AND " + withinLeadTime
+		args = append(args, q.At, int64(q.LeadTime/time.Second))
+	}
+	if q.WarehouseID > 0 {
+		query += " AND s.`warehouse_id` = ?"
+		args = append(args, q.WarehouseID)
+	}
+	rows, err := r.db.Query(query+" ORDER BY pb.`due_date`, pb.`id`", args...)
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	batches := []mod.ExpiringBatch{}
+	for rows.Next() {
+		var b mod.ExpiringBatch
+		if err = rows.Scan(&b.ProductBatchID, &b.BatchNumber, &b.ProductID, &b.SellerID, &b.SectionID, &b.WarehouseID, &b.CurrentQuantity, &b.DueDate); err != nil {
+			return nil, e.ErrParseError
+		}
+		batches = append(batches, b)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return batches, nil
+}
+
+// sweptBatch is a batch found by the sweep with the warehouse and seller its alert is grouped by
+type sweptBatch struct {
+	id, warehouseID, sellerID, quantity int
+}
+
+// sweepQueries select, oldest due first, the batches the sweep moves for every alert type, their args are the
+// time of the sweep and the lead time in seconds
+var sweepQueries = map[string]string{
+	mod.AlertBatchesExpired: "SELECT pb.`id`, s.`warehouse_id`, p.`seller_id`, pb.`current_quantity` " + stockedBatches +
+		" AND pb.`expired_at` IS NULL AND pb.`due_date` <= ? ORDER BY s.`warehouse_id`, p.`seller_id`, pb.`due_date`, pb.`id` FOR UPDATE",
+	mod.AlertBatchesExpiring: "SELECT pb.`id`, s.`warehouse_id`, p.`seller_id`, pb.`current_quantity` " + stockedBatches +
+		" AND pb.`expiry_warned_at` IS NULL AND pb.`due_date` > ? AND " + withinLeadTime +
+		" ORDER BY s.`warehouse_id`, p.`seller_id`, pb.`due_date`, pb.`id` FOR UPDATE",
+}
+
+// sweepColumns are the columns the sweep sets on the batches of every alert type, so a batch is warned and
+// expired once
+var sweepColumns = map[string]string{
+	mod.AlertBatchesExpired:  "expired_at",
+	mod.AlertBatchesExpiring: "expiry_warned_at",
+}
+
+// Sweep locks the batches due at the given time and the ones within the lead time of their product that were not
+// warned yet, marks them expired or warned and raises one alert per type, warehouse and seller in one transaction
+func (r *BatchExpiryDB) Sweep(at time.Time, leadTime time.Duration) (alerts []mod.Alert, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			alerts, err = nil, e.ErrQueryError
+		}
+	}()
+
+	alerts = []mod.Alert{}
+	for _, kind := range []string{mod.AlertBatchesExpired, mod.AlertBatchesExpiring} {
+		args := []any{at}
+		if kind == mod.AlertBatchesExpiring {
+			args = append(args, at, int64(leadTime/time.Second))
+		}
+		batches, err := sweepBatches(tx, sweepQueries[kind], args...)
+		if err != nil {
+			return nil, err
+		}
+		if len(batches) == 0 {
+			continue
+		}
+		ids := make([]int, len(batches))
+		for i, b := range batches {
+			ids[i] = b.id
+		}
+		placeholders, idArgs := common.InClause(ids)
+		if _, err = tx.Exec(fmt.Sprintf("UPDATE `product_batches` SET `%s` = ? WHERE `id` IN (%s)", sweepColumns[kind], placeholders),
+			append([]any{at}, idArgs...)...); err != nil {
+			return nil, e.ErrQueryError
+		}
+		raised, err := raiseAlerts(tx, kind, at, batches)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, raised...)
+	}
+	return alerts, nil
+}
+
+// sweepBatches reads the batches of a sweep query before the updates, the connection is busy while the rows are open
+func sweepBatches(tx *sql.Tx, query string, args ...any) ([]sweptBatch, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	var batches []sweptBatch
+	for rows.Next() {
+		var b sweptBatch
+		if err = rows.Scan(&b.id, &b.warehouseID, &b.sellerID, &b.quantity); err != nil {
+			return nil, e.ErrParseError
+		}
+		batches = append(batches, b)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return batches, nil
+}
+
+// raiseAlerts stores an alert per warehouse and seller of the batches, which come sorted by both
+func raiseAlerts(tx *sql.Tx, kind string, at time.Time, batches []sweptBatch) ([]mod.Alert, error) {
+	var alerts []mod.Alert
+	for i, b := range batches {
+		if i == 0 || b.warehouseID != batches[i-1].warehouseID || b.sellerID != batches[i-1].sellerID {
+			alerts = append(alerts, mod.Alert{Type: kind, WarehouseID: b.warehouseID, SellerID: b.sellerID, ProductBatchIDs: []int{}, CreatedAt: at})
+		}
+		alert := &alerts[len(alerts)-1]
+		alert.Quantity += b.quantity
+		alert.ProductBatchIDs = append(alert.ProductBatchIDs, b.id)
+	}
+	for i := range alerts {
+		ids, err := json.Marshal(alerts[i].ProductBatchIDs)
+		if err != nil {
+			return nil, e.ErrParseError
+		}
+		res, err := tx.Exec("INSERT INTO `alerts` (`type`, `warehouse_id`, `seller_id`, `quantity`, `product_batch_ids`, `created_at`) VALUES (?, ?, ?, ?, ?, ?)",
+			alerts[i].Type, alerts[i].WarehouseID, alerts[i].SellerID, alerts[i].Quantity, string(ids), alerts[i].CreatedAt)
+		if err != nil {
+			return nil, e.ErrInsertError
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, e.ErrInsertError
+		}
+		alerts[i].ID = int(id)
+	}
+	return alerts, nil
+}
+
+// FindAlerts returns the alerts of the filter, the newest first
+func (r *BatchExpiryDB) FindAlerts(f mod.AlertFilter) ([]mod.Alert, error) {
+	query := "SELECT `id`, `type`, `warehouse_id`, `seller_id`, `quantity`, `product_batch_ids`, `created_at` FROM `alerts`"
+	var where []string
+	var args []any
+	if f.Type != "" {
+		where = append(where, "`type` = ?")
+		args = append(args, f.Type)
+	}
+	if f.WarehouseID > 0 {
+		where = append(where, "`warehouse_id` = ?")
+		args = append(args, f.WarehouseID)
+	}
+	if f.SellerID > 0 {
+		where = append(where, "`seller_id` = ?")
+		args = append(args, f.SellerID)
+	}
+	if !f.From.IsZero() {
+		where = append(where, "`created_at` >= ?")
+		args = append(args, f.From)
+	}
+	if !f.To.IsZero() {
+		where = append(where, "`created_at` < ?")
+		args = append(args, f.To)
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	rows, err := r.db.Query(query+" ORDER BY `created_at` DESC, `id` DESC", args...)
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	alerts := []mod.Alert{}
+	for rows.Next() {
+		var a mod.Alert
+		var ids []byte
+		if err = rows.Scan(&a.ID, &a.Type, &a.WarehouseID, &a.SellerID, &a.Quantity, &ids, &a.CreatedAt); err != nil {
+			return nil, e.ErrParseError
+		}
+		if err = json.Unmarshal(ids, &a.ProductBatchIDs); err != nil {
+			return nil, e.ErrParseError
+		}
+		alerts = append(alerts, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return alerts, nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchExpiryDB_FindExpiring(t *testing.T) {
+	at := time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC)
+	due := time.Date(2024, 7, 3, 10, 0, 0, 0, time.UTC)
+	columns := []string{"id", "batch_number", "product_id", "seller_id", "section_id", "warehouse_id", "current_quantity", "due_date"}
+
+	t.Run("window of days in a warehouse", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectQuery(regexp.QuoteMeta("AND pb.`due_date` > ? AND pb.`due_date` <= ? AND s.`warehouse_id` = ? ORDER BY pb.`due_date`, pb.`id`")).
+			WithArgs(at, at.AddDate(0, 0, 3), 2).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 10, 4, 5, 6, 2, 20, due))
+
+		batches, err := NewBatchExpiryRepo(db).FindExpiring(mod.ExpiringQuery{Days: 3, WarehouseID: 2, At: at, LeadTime: time.Hour})
+
+		require.NoError(t, err)
+		require.Equal(t, []mod.ExpiringBatch{{ProductBatchID: 1, BatchNumber: 10, ProductID: 4, SellerID: 5, SectionID: 6, WarehouseID: 2,
+			CurrentQuantity: 20, DueDate: due}}, batches)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("lead time scaled by the expiration rate", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectQuery(regexp.QuoteMeta("AND pb.`due_date` > ? AND pb.`due_date` <= DATE_ADD(?, INTERVAL ROUND(? * p.`expiration_rate`) SECOND) ORDER BY")).
+			WithArgs(at, at, int64(7*24*3600)).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		batches, err := NewBatchExpiryRepo(db).FindExpiring(mod.ExpiringQuery{At: at, LeadTime: 7 * 24 * time.Hour})
+
+		require.NoError(t, err)
+		require.Empty(t, batches)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectQuery("FROM `product_batches`").WillReturnError(sql.ErrConnDone)
+
+		_, err = NewBatchExpiryRepo(db).FindExpiring(mod.ExpiringQuery{At: at})
+
+		require.ErrorIs(t, err, e.ErrQueryError)
+	})
+}
+
+func TestBatchExpiryDB_Sweep(t *testing.T) {
+	at := time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC)
+	lead := 48 * time.Hour
+	columns := []string{"id", "warehouse_id", "seller_id", "current_quantity"}
+	expiredQuery := regexp.QuoteMeta("AND pb.`expired_at` IS NULL AND pb.`due_date` <= ? ORDER BY s.`warehouse_id`, p.`seller_id`")
+	expiringQuery := regexp.QuoteMeta("AND pb.`expiry_warned_at` IS NULL AND pb.`due_date` > ? AND pb.`due_date` <= DATE_ADD(?, INTERVAL ROUND(? * p.`expiration_rate`) SECOND)")
+	alertInsert := regexp.QuoteMeta("INSERT INTO `alerts` (`type`, `warehouse_id`, `seller_id`, `quantity`, `product_batch_ids`, `created_at`) VALUES (?, ?, ?, ?, ?, ?)")
+
+	t.Run("expires and warns grouped by warehouse and seller", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery(expiredQuery).WithArgs(at).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 2, 5, 10).AddRow(3, 2, 5, 4).AddRow(4, 2, 6, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_batches` SET `expired_at` = ? WHERE `id` IN (?,?,?)")).
+			WithArgs(at, 1, 3, 4).WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(alertInsert).WithArgs(mod.AlertBatchesExpired, 2, 5, 14, "[1,3]", at).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(alertInsert).WithArgs(mod.AlertBatchesExpired, 2, 6, 1, "[4]", at).WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectQuery(expiringQuery).WithArgs(at, at, int64(48*3600)).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(8, 3, 5, 30))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_batches` SET `expiry_warned_at` = ? WHERE `id` IN (?)")).
+			WithArgs(at, 8).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(alertInsert).WithArgs(mod.AlertBatchesExpiring, 3, 5, 30, "[8]", at).WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectCommit()
+
+		alerts, err := NewBatchExpiryRepo(db).Sweep(at, lead)
+
+		require.NoError(t, err)
+		require.Equal(t, []mod.Alert{
+			{ID: 1, Type: mod.AlertBatchesExpired, WarehouseID: 2, SellerID: 5, Quantity: 14, ProductBatchIDs: []int{1, 3}, CreatedAt: at},
+			{ID: 2, Type: mod.AlertBatchesExpired, WarehouseID: 2, SellerID: 6, Quantity: 1, ProductBatchIDs: []int{4}, CreatedAt: at},
+			{ID: 3, Type: mod.AlertBatchesExpiring, WarehouseID: 3, SellerID: 5, Quantity: 30, ProductBatchIDs: []int{8}, CreatedAt: at},
+		}, alerts)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nothing to sweep", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery(expiredQuery).WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectQuery(expiringQuery).WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectCommit()
+
+		alerts, err := NewBatchExpiryRepo(db).Sweep(at, lead)
+
+		require.NoError(t, err)
+		require.Empty(t, alerts)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("alert error rolls back", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery(expiredQuery).WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 2, 5, 10))
+		mock.ExpectExec("UPDATE `product_batches`").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(alertInsert).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		_, err = NewBatchExpiryRepo(db).Sweep(at, lead)
+
+		require.ErrorIs(t, err, e.ErrInsertError)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestBatchExpiryDB_FindAlerts(t *testing.T) {
+	at := time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC)
+	columns := []string{"id", "type", "warehouse_id", "seller_id", "quantity", "product_batch_ids", "created_at"}
+
+	t.Run("filters", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectQuery(regexp.QuoteMeta("FROM `alerts` WHERE `type` = ? AND `warehouse_id` = ? AND `seller_id` = ? AND `created_at` >= ? "+
+			"ORDER BY `created_at` DESC, `id` DESC")).
+			WithArgs(mod.AlertBatchesExpired, 2, 5, at).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, mod.AlertBatchesExpired, 2, 5, 14, []byte("[1,3]"), at))
+
+		alerts, err := NewBatchExpiryRepo(db).FindAlerts(mod.AlertFilter{Type: mod.AlertBatchesExpired, WarehouseID: 2, SellerID: 5, From: at})
+
+		require.NoError(t, err)
+		require.Equal(t, []mod.Alert{{ID: 1, Type: mod.AlertBatchesExpired, WarehouseID: 2, SellerID: 5, Quantity: 14,
+			ProductBatchIDs: []int{1, 3}, CreatedAt: at}}, alerts)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no alerts", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectQuery(regexp.QuoteMeta("FROM `alerts` ORDER BY")).WillReturnRows(sqlmock.NewRows(columns))
+
+		alerts, err := NewBatchExpiryRepo(db).FindAlerts(mod.AlertFilter{})
+
+		require.NoError(t, err)
+		require.Equal(t, []mod.Alert{}, alerts)
+	})
+}
@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"time"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/cache"
+)
+
+// readThrough returns the cached value for the id or loads it and stores it when found
+func readThrough[V any](c *cache.LRU[int, V], id int, load func(int) (V, error)) (V, error) {
+	if v, ok := c.Get(id); ok {
+		return v, nil
+	}
+	v, err := load(id)
+	if err != nil {
+		return v, err
+	}
+	c.Set(id, v)
+	return v, nil
+}
+
+// NewCachedProductRepo wraps a product repository with a read-through cache
+func NewCachedProductRepo(rp internal.ProductRepository, c *cache.LRU[int, mod.Product]) *CachedProductRepo {
+	return &CachedProductRepo{
+		ProductRepository: rp,
+		cache:             c,
+	}
+}
+
+// CachedProductRepo caches FindByID and invalidates the entry on every write
+type CachedProductRepo struct {
+	internal.ProductRepository
+	cache *cache.LRU[int, mod.Product]
+}
+
+// FindByID returns the product from the cache or the wrapped repository
+func (r *CachedProductRepo) FindByID(id int) (mod.Product, error) {
+	return readThrough(r.cache, id, r.ProductRepository.FindByID)
+}
+
+// Save saves the product and invalidates its entry
+func (r *CachedProductRepo) Save(product *mod.Product) error {
+	err := r.ProductRepository.Save(product)
+	r.cache.Delete(product.ID)
+	return err
+}
+
+// Update updates the product and invalidates its entry
+func (r *CachedProductRepo) Update(product *mod.Product) error {
+	defer r.cache.Delete(product.ID)
+	return r.ProductRepository.Update(product)
+}
+
+// Delete deletes the product and invalidates its entry
+func (r *CachedProductRepo) Delete(id int) error {
+	defer r.cache.Delete(id)
+	return r.ProductRepository.Delete(id)
+}
+
+// NewCachedSellerRepo wraps a seller repository with a read-through cache
+func NewCachedSellerRepo(rp internal.SellerRepository, c *cache.LRU[int, mod.Seller]) *CachedSellerRepo {
+	return &CachedSellerRepo{
+		SellerRepository: rp,
+		cache:            c,
+	}
+}
+
+// CachedSellerRepo caches FindByID and invalidates the entry on every write
+type CachedSellerRepo struct {
+	internal.SellerRepository
+	cache *cache.LRU[int, mod.Seller]
+}
+
+// FindByID returns the seller from the cache or the wrapped repository
+func (r *CachedSellerRepo) FindByID(id int) (mod.Seller, error) {
+	return readThrough(r.cache, id, r.SellerRepository.FindByID)
+}
+
+// Save saves the seller and invalidates its entry
+func (r *CachedSellerRepo) Save(seller *mod.Seller) (int, error) {
+	id, err := r.SellerRepository.Save(seller)
+	r.cache.Delete(id)
+	return id, err
+}
+
+// Update updates the seller and invalidates its entry
+func (r *CachedSellerRepo) Update(seller *mod.Seller) error {
+	defer r.cache.Delete(seller.ID)
+	return r.SellerRepository.Update(seller)
+}
+
+// Delete deletes the seller and invalidates its entry
+func (r *CachedSellerRepo) Delete(id int) error {
+	defer r.cache.Delete(id)
+	return r.SellerRepository.Delete(id)
+}
+
+// NewCachedSectionRepo wraps a section repository with a read-through cache
+func NewCachedSectionRepo(rp internal.SectionRepository, c *cache.LRU[int, mod.Section]) *CachedSectionRepo {
+	return &CachedSectionRepo{
+		SectionRepository: rp,
+		cache:             c,
+	}
+}
+
+// CachedSectionRepo caches FindByID and invalidates the entry on every write
+type CachedSectionRepo struct {
+	internal.SectionRepository
+	cache *cache.LRU[int, mod.Section]
+}
+
+// FindByID returns the section from the cache or the wrapped repository
+func (r *CachedSectionRepo) FindByID(id int) (mod.Section, error) {
+	return readThrough(r.cache, id, r.SectionRepository.FindByID)
+}
+
+// Save saves the section and invalidates its entry
+func (r *CachedSectionRepo) Save(section *mod.Section) error {
+	err := r.SectionRepository.Save(section)
+	r.cache.Delete(section.ID)
+	return err
+}
+
+// Update updates the section and invalidates its entry
+func (r *CachedSectionRepo) Update(id int, fields map[string]interface{}) (*mod.Section, error) {
+	defer r.cache.Delete(id)
+	return r.SectionRepository.Update(id, fields)
+}
+
+// Delete deletes the section and invalidates its entry
+func (r *CachedSectionRepo) Delete(id int) error {
+	defer r.cache.Delete(id)
+	return r.SectionRepository.Delete(id)
+}
+
+// NewCachedWarehouseRepo wraps a warehouse repository with a read-through cache
+func NewCachedWarehouseRepo(rp internal.WarehouseRepository, c *cache.LRU[int, mod.Warehouse]) *CachedWarehouseRepo {
+	return &CachedWarehouseRepo{
+		WarehouseRepository: rp,
+		cache:               c,
+	}
+}
+
+// CachedWarehouseRepo caches GetByID and invalidates the entry on every write
+type CachedWarehouseRepo struct {
+	internal.WarehouseRepository
+	cache *cache.LRU[int, mod.Warehouse]
+}
+
+// GetByID returns the warehouse from the cache or the wrapped repository
+func (r *CachedWarehouseRepo) GetByID(id int) (mod.Warehouse, error) {
+	return readThrough(r.cache, id, r.WarehouseRepository.GetByID)
+}
+
+// Save saves the warehouse and invalidates its entry
+func (r *CachedWarehouseRepo) Save(wh *mod.Warehouse) error {
+	err := r.WarehouseRepository.Save(wh)
+	r.cache.Delete(wh.ID)
+	return err
+}
+
+// Update updates the warehouse and invalidates its entry
+func (r *CachedWarehouseRepo) Update(wh *mod.Warehouse) error {
+	defer r.cache.Delete(wh.ID)
+	return r.WarehouseRepository.Update(wh)
+}
+
+// Delete deletes the warehouse and invalidates its entry
+func (r *CachedWarehouseRepo) Delete(id int) error {
+	defer r.cache.Delete(id)
+	return r.WarehouseRepository.Delete(id)
+}
+
+// NewCachedTransferRepo wraps a transfer repository so the transfers invalidate the cached sections they change
+func NewCachedTransferRepo(rp internal.TransferRepository, sections *cache.LRU[int, mod.Section]) *CachedTransferRepo {
+	return &CachedTransferRepo{
+		TransferRepository: rp,
+		sections:           sections,
+	}
+}
+
+// CachedTransferRepo invalidates the source and destination sections of every transfer
+type CachedTransferRepo struct {
+	internal.TransferRepository
+	sections *cache.LRU[int, mod.Section]
+}
+
+// Save moves the stock and invalidates both sections, their capacity changed
+func (r *CachedTransferRepo) Save(t *mod.Transfer) error {
+	err := r.TransferRepository.Save(t)
+	r.sections.Delete(t.FromSectionID)
+	r.sections.Delete(t.ToSectionID)
+	return err
+}
+
+// NewCachedPurchaseOrderRepo wraps a purchase order repository so the picks invalidate the cached sections they
+// take units from
+func NewCachedPurchaseOrderRepo(rp internal.PurchaseOrderRepository, sections *cache.LRU[int, mod.Section]) *CachedPurchaseOrderRepo {
+	return &CachedPurchaseOrderRepo{
+		PurchaseOrderRepository: rp,
+		sections:                sections,
+	}
+}
+
+// CachedPurchaseOrderRepo invalidates the sections picked by every purchase order
+type CachedPurchaseOrderRepo struct {
+	internal.PurchaseOrderRepository
+	sections *cache.LRU[int, mod.Section]
+}
+
+// Save saves the purchase order and invalidates the sections it picked from, their capacity changed
+func (r *CachedPurchaseOrderRepo) Save(order *mod.PurchaseOrder) error {
+	err := r.PurchaseOrderRepository.Save(order)
+	for _, id := range order.SectionIDs() {
+		r.sections.Delete(id)
+	}
+	return err
+}
+
+// NewCachedStockMovementRepo wraps a stock movement repository so the adjustments invalidate the cached section
+// of the batch
+func NewCachedStockMovementRepo(rp internal.StockMovementRepository, sections *cache.LRU[int, mod.Section]) *CachedStockMovementRepo {
+	return &CachedStockMovementRepo{
+		StockMovementRepository: rp,
+		sections:                sections,
+	}
+}
+
+// CachedStockMovementRepo invalidates the section of every adjusted batch
+type CachedStockMovementRepo struct {
+	internal.StockMovementRepository
+	sections *cache.LRU[int, mod.Section]
+}
+
+// Adjust applies the movement and invalidates the section, its capacity changed
+func (r *CachedStockMovementRepo) Adjust(m *mod.StockMovement) error {
+	err := r.StockMovementRepository.Adjust(m)
+	r.sections.Delete(m.SectionID)
+	return err
+}
+
+// NewCachedSectionReadingRepo wraps a section reading repository so the ingestion invalidates the cached section
+func NewCachedSectionReadingRepo(rp internal.SectionReadingRepository, sections *cache.LRU[int, mod.Section]) *CachedSectionReadingRepo {
+	return &CachedSectionReadingRepo{
+		SectionReadingRepository: rp,
+		sections:                 sections,
+	}
+}
+
+// CachedSectionReadingRepo invalidates the section of every ingested batch of readings
+type CachedSectionReadingRepo struct {
+	internal.SectionReadingRepository
+	sections *cache.LRU[int, mod.Section]
+}
+
+// Save stores the readings and invalidates the section, its current temperature changed
+func (r *CachedSectionReadingRepo) Save(sectionID int, readings []mod.SectionReading) (mod.SectionReadingsResult, error) {
+	result, err := r.SectionReadingRepository.Save(sectionID, readings)
+	r.sections.Delete(sectionID)
+	return result, err
+}
+
+// NewCachedProductBatchRepo wraps a product batch repository so the batches that take or release room invalidate
+// the cached sections
+func NewCachedProductBatchRepo(rp internal.ProductBatchRepository, sections *cache.LRU[int, mod.Section]) *CachedProductBatchRepo {
+	return &CachedProductBatchRepo{
+		ProductBatchRepository: rp,
+		sections:               sections,
+	}
+}
+
+// CachedProductBatchRepo invalidates the sections whose capacity the batches change
+type CachedProductBatchRepo struct {
+	internal.ProductBatchRepository
+	sections *cache.LRU[int, mod.Section]
+}
+
+// Save saves the batch and invalidates its section, the batch took room in it
+func (r *CachedProductBatchRepo) Save(batch *mod.ProductBatch) error {
+	err := r.ProductBatchRepository.Save(batch)
+	r.sections.Delete(batch.SectionId)
+	return err
+}
+
+// Update updates the batch and invalidates its section, a new quantity takes or releases room in it
+func (r *CachedProductBatchRepo) Update(id int, patch mod.ProductBatchPatch) (mod.ProductBatch, error) {
+	batch, err := r.ProductBatchRepository.Update(id, patch)
+	if err == nil {
+		r.sections.Delete(batch.SectionId)
+	}
+	return batch, err
+}
+
+// Archive archives the batch and invalidates its section, the written off units released room in it
+func (r *CachedProductBatchRepo) Archive(id int, at time.Time) (mod.ProductBatch, error) {
+	batch, err := r.ProductBatchRepository.Archive(id, at)
+	if err == nil {
+		r.sections.Delete(batch.SectionId)
+	}
+	return batch, err
+}
+
+// Delete deletes the batch and purges the sections, the section it released is not known here
+func (r *CachedProductBatchRepo) Delete(id int) error {
+	err := r.ProductBatchRepository.Delete(id)
+	if err == nil {
+		r.sections.Purge()
+	}
+	return err
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/cache"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/tests"
+	m "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedProductRepo_FindByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	c := cache.New[int, mod.Product](10, time.Minute)
+	repo := NewCachedProductRepo(NewProductRepo(db), c)
+
+	query := regexp.QuoteMeta("SELECT `id`, `product_code`, `description`, `height`, `length`, `width`, `net_weight`, `expiration_rate`, `freezing_rate`, `recommended_freezing_temperature`, `product_type_id`, `seller_id` FROM frescos_db.products WHERE id = ?;")
+	columns := []string{"id", "product_code", "description", "height", "length", "width", "net_weight", "expiration_rate", "freezing_rate", "recommended_freezing_temperature", "product_type_id", "seller_id"}
+
+	t.Run("second read is served from the cache", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "P001", "Product 1", 10.0, 20.0, 5.0, 2.0, 0.1, 0.05, -18.0, 1, 101))
+
+		first, err := repo.FindByID(1)
+		require.NoError(t, err)
+		second, err := repo.FindByID(1)
+		require.NoError(t, err)
+		require.Equal(t, first, second)
+		require.Equal(t, uint64(1), c.Stats().Hits)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(2).WillReturnError(e.ErrQueryError)
+		mock.ExpectQuery(query).WithArgs(2).WillReturnError(e.ErrQueryError)
+
+		_, err := repo.FindByID(2)
+		require.ErrorIs(t, err, e.ErrProductRepositoryNotFound)
+		_, err = repo.FindByID(2)
+		require.ErrorIs(t, err, e.ErrProductRepositoryNotFound)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("delete invalidates the entry", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "P001", "Product 1", 10.0, 20.0, 5.0, 2.0, 0.1, 0.05, -18.0, 1, 101))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM frescos_db.products WHERE id = ?;")).WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		require.NoError(t, repo.Delete(1))
+		_, ok := c.Get(1)
+		require.False(t, ok)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCachedSectionRepo_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	c := cache.New[int, mod.Section](10, time.Minute)
+	repo := NewCachedSectionRepo(NewSectionRepo(db), c)
+
+	mock.ExpectQuery(regexp.QuoteMeta(m.SectionSelectWhereExpectedQuery)).WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(m.SectionTableStruct).AddRow(m.SectionDataValuesSelectByID...))
+	_, err = repo.FindByID(2)
+	require.NoError(t, err)
+	require.Equal(t, 1, c.Len())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE sections SET section_number = ? WHERE id=2")).WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(sectionSnapshotInsert).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta(m.SectionSelectWhereExpectedQuery)).WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(m.SectionTableStruct).AddRow(m.SectionDataValuesSelectByID...))
+	_, err = repo.Update(2, map[string]interface{}{"section_number": 3})
+	require.NoError(t, err)
+	require.Equal(t, 0, c.Len())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCachedWarehouseRepo(t *testing.T) {
+	wh := mod.Warehouse{ID: 1, WarehouseCode: "WH-001", Address: "a", Telephone: "1", MinimumCapacity: 1, MinimumTemperature: 1}
+	rp := tests.NewWarehouseMock()
+	rp.On("GetByID", 1).Return(wh, nil).Once()
+	rp.On("Update", &wh).Return(nil).Once()
+	rp.On("GetByID", 1).Return(wh, nil).Once()
+	c := cache.New[int, mod.Warehouse](10, time.Minute)
+	repo := NewCachedWarehouseRepo(rp, c)
+
+	for i := 0; i < 3; i++ {
+		got, err := repo.GetByID(1)
+		require.NoError(t, err)
+		require.Equal(t, wh, got)
+	}
+	require.NoError(t, repo.Update(&wh))
+	_, err := repo.GetByID(1)
+	require.NoError(t, err)
+	rp.AssertExpectations(t)
+}
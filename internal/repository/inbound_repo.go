@@ -17,11 +17,27 @@ func NewInboundRepo(database *sql.DB) *InboundDB {
 	}
 }
 
-func (r *InboundDB) Save(order *mod.InboundOrders) (*mod.InboundOrders, error) {
+// Save inserts the order and, the first time the batch is received, records its initial quantity in the stock
+// ledger in the same transaction
+func (r *InboundDB) Save(order *mod.InboundOrders) (saved *mod.InboundOrders, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", e.ErrInboundOrderInternal, err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			saved, err = nil, fmt.Errorf("%w: %v", e.ErrInboundOrderInternal, err)
+		}
+	}()
+
 	query := `INSERT INTO inbound_orders (order_date, order_number, employee_id, product_batch_id, warehouse_id)
 	          VALUES (?, ?, ?, ?, ?)`
 
-	res, err := r.db.Exec(query, order.OrderDate, order.OrderNumber, order.EmployeeId, order.ProductBatchId, order.WarehouseId)
+	res, err := tx.Exec(query, order.OrderDate, order.OrderNumber, order.EmployeeId, order.ProductBatchId, order.WarehouseId)
 	if err != nil {
 		// La base de datos es la que nos dirá el tipo de error
 		// Aquí deberías inspeccionar el error para saber qué ha fallado
@@ -34,9 +50,35 @@ func (r *InboundDB) Save(order *mod.InboundOrders) (*mod.InboundOrders, error) {
 	}
 	order.Id = int(lastID)
 
+	if err = r.receive(tx, order); err != nil {
+		return nil, err
+	}
 	return order, nil
 }
 
+// receive records the receipt of the batch of the order, a batch that already has movements (its opening
+// balance, its creation or an earlier order) is not received again
+func (r *InboundDB) receive(tx *sql.Tx, order *mod.InboundOrders) error {
+	query := `SELECT pb.initial_quantity, pb.section_id,
+	              EXISTS(SELECT 1 FROM stock_movements AS sm WHERE sm.product_batch_id = pb.id)
+	          FROM product_batches AS pb WHERE pb.id = ? FOR UPDATE`
+
+	var quantity, section int
+	var received bool
+	if err := tx.QueryRow(query, order.ProductBatchId).Scan(&quantity, &section, &received); err != nil {
+		return fmt.Errorf("%w: failed to read product batch: %v", e.ErrInboundOrderInternal, err)
+	}
+	if received {
+		return nil
+	}
+	receipt := mod.StockMovement{Type: mod.MovementReceipt, ProductBatchID: order.ProductBatchId, SectionID: section,
+		Quantity: quantity, ReferenceType: mod.ReferenceInboundOrder, ReferenceID: order.Id, EmployeeID: order.EmployeeId}
+	if err := insertMovement(tx, &receipt); err != nil {
+		return fmt.Errorf("%w: %v", e.ErrInboundOrderInternal, err)
+	}
+	return nil
+}
+
 func (r *InboundDB) FindOrdersByEmployee(employeeID int) ([]mod.EmployeeReport, error) {
 	query := `
         SELECT
@@ -23,6 +23,8 @@ func setupMockInboundRepo(t *testing.T) (*InboundDB, sqlmock.Sqlmock, func()) {
 func TestInboundDB_Save(t *testing.T) {
 	query := `INSERT INTO inbound_orders (order_date, order_number, employee_id, product_batch_id, warehouse_id)
               VALUES (?, ?, ?, ?, ?)`
+	receiptQuery := regexp.QuoteMeta("FROM product_batches AS pb WHERE pb.id = ? FOR UPDATE")
+	receiptCols := []string{"initial_quantity", "section_id", "received"}
 
 	baseOrder := func() *mod.InboundOrders {
 		return &mod.InboundOrders{
@@ -44,20 +46,60 @@ func TestInboundDB_Save(t *testing.T) {
 		{
 			name: "HappyPath",
 			setup: func(mock sqlmock.Sqlmock, order *mod.InboundOrders) {
+				mock.ExpectBegin()
 				mock.ExpectExec(regexp.QuoteMeta(query)).
 					WithArgs(order.OrderDate, order.OrderNumber, order.EmployeeId, order.ProductBatchId, order.WarehouseId).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(receiptQuery).WithArgs(order.ProductBatchId).
+					WillReturnRows(sqlmock.NewRows(receiptCols).AddRow(50, 3, false))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movements`")).
+					WithArgs(mod.MovementReceipt, order.ProductBatchId, 3, 50, mod.ReferenceInboundOrder, 1, order.EmployeeId, "").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			input:       baseOrder(),
 			expectedID:  1,
 			expectedErr: nil,
 		},
+		{
+			name: "HappyPath_BatchAlreadyReceived",
+			setup: func(mock sqlmock.Sqlmock, order *mod.InboundOrders) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(order.OrderDate, order.OrderNumber, order.EmployeeId, order.ProductBatchId, order.WarehouseId).
+					WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectQuery(receiptQuery).WithArgs(order.ProductBatchId).
+					WillReturnRows(sqlmock.NewRows(receiptCols).AddRow(50, 3, true))
+				mock.ExpectCommit()
+			},
+			input:       baseOrder(),
+			expectedID:  2,
+			expectedErr: nil,
+		},
+		{
+			name: "Err_ReceiptFailed",
+			setup: func(mock sqlmock.Sqlmock, order *mod.InboundOrders) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(order.OrderDate, order.OrderNumber, order.EmployeeId, order.ProductBatchId, order.WarehouseId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(receiptQuery).WithArgs(order.ProductBatchId).
+					WillReturnRows(sqlmock.NewRows(receiptCols).AddRow(50, 3, false))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movements`")).WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			input:       baseOrder(),
+			expectedID:  0,
+			expectedErr: e.ErrInboundOrderInternal,
+		},
 		{
 			name: "Err_ExecFailed",
 			setup: func(mock sqlmock.Sqlmock, order *mod.InboundOrders) {
+				mock.ExpectBegin()
 				mock.ExpectExec(regexp.QuoteMeta(query)).
 					WithArgs(order.OrderDate, order.OrderNumber, order.EmployeeId, order.ProductBatchId, order.WarehouseId).
 					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
 			},
 			input:       baseOrder(),
 			expectedID:  0,
@@ -66,9 +108,11 @@ func TestInboundDB_Save(t *testing.T) {
 		{
 			name: "Err_LastInsertIdFailed",
 			setup: func(mock sqlmock.Sqlmock, order *mod.InboundOrders) {
+				mock.ExpectBegin()
 				mock.ExpectExec(regexp.QuoteMeta(query)).
 					WithArgs(order.OrderDate, order.OrderNumber, order.EmployeeId, order.ProductBatchId, order.WarehouseId).
 					WillReturnResult(sqlmock.NewErrorResult(errors.New("last id error")))
+				mock.ExpectRollback()
 			},
 			input:       baseOrder(),
 			expectedID:  0,
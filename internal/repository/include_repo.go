@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewIncludeRepo creates a new instance of the include repository
+func NewIncludeRepo(db *sql.DB) *IncludeDB {
+	return &IncludeDB{
+		db: db,
+	}
+}
+
+// IncludeDB is the implementation of the include database, every method runs a single IN query
+// so embedding a relation costs one query no matter how many items are in the response
+type IncludeDB struct {
+	db *sql.DB
+}
+
+// queryIn runs query, whose %s is replaced by the placeholders of ids, and scans every row
+func queryIn[T any](db *sql.DB, query string, ids []int, scan func(rows *sql.Rows, item *T) error) ([]T, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	placeholders, args := common.InClause(ids)
+	rows, err := db.Query(fmt.Sprintf(query, placeholders), args...)
+	if err != nil {
+		return nil, errors.Join(e.ErrQueryError, err)
+	}
+	defer rows.Close()
+
+	var result []T
+	for rows.Next() {
+		var item T
+		if err = scan(rows, &item); err != nil {
+			return nil, errors.Join(e.ErrParseError, err)
+		}
+		result = append(result, item)
+	}
+	return result, rows.Err()
+}
+
+func scanSeller(rows *sql.Rows, s *mod.Seller) error {
+	return rows.Scan(&s.ID, &s.CID, &s.CompanyName, &s.Address, &s.Telephone, &s.Locality)
+}
+
+func scanProduct(rows *sql.Rows, p *mod.Product) error {
+	return rows.Scan(&p.ID, &p.ProductCode, &p.Description, &p.Height, &p.Length, &p.Width, &p.Weight, &p.ExpirationRate, &p.FreezingRate, &p.RecomFreezTemp, &p.ProductTypeID, &p.SellerID)
+}
+
+// SellersByIDs returns the sellers with the given IDs
+func (r *IncludeDB) SellersByIDs(ids []int) ([]mod.Seller, error) {
+	return queryIn(r.db, "SELECT `id`, `cid`, `company_name`, `address`, `telephone`, `locality_id` FROM `sellers` WHERE `id` IN (%s)", ids, scanSeller)
+}
+
+// LocalitiesByIDs returns the localities with the given IDs
+func (r *IncludeDB) LocalitiesByIDs(ids []int) ([]mod.Locality, error) {
+	return queryIn(r.db, "SELECT `id`, `locality_name`, `province_name`, `country_name` FROM `localities` WHERE `id` IN (%s)", ids,
+		func(rows *sql.Rows, l *mod.Locality) error {
+			return rows.Scan(&l.ID, &l.Name, &l.Province, &l.Country)
+		})
+}
+
+// ProductsByIDs returns the products with the given IDs
+func (r *IncludeDB) ProductsByIDs(ids []int) ([]mod.Product, error) {
+	return queryIn(r.db, "SELECT `id`, `product_code`, `description`, `height`, `length`, `width`, `net_weight`, `expiration_rate`, `freezing_rate`, `recommended_freezing_temperature`, `product_type_id`, `seller_id` FROM `products` WHERE `id` IN (%s)", ids, scanProduct)
+}
+
+// ProductsBySellerIDs returns the products of the given sellers
+func (r *IncludeDB) ProductsBySellerIDs(ids []int) ([]mod.Product, error) {
+	return queryIn(r.db, "SELECT `id`, `product_code`, `description`, `height`, `length`, `width`, `net_weight`, `expiration_rate`, `freezing_rate`, `recommended_freezing_temperature`, `product_type_id`, `seller_id` FROM `products` WHERE `seller_id` IN (%s)", ids, scanProduct)
+}
+
+// RecordsByProductIDs returns the records of the given products
+func (r *IncludeDB) RecordsByProductIDs(ids []int) ([]mod.ProductRecord, error) {
+	return queryIn(r.db, "SELECT `id`, `last_update_date`, `purchase_price`, `sale_price`, `product_id` FROM `product_records` WHERE `product_id` IN (%s)", ids,
+		func(rows *sql.Rows, pr *mod.ProductRecord) error {
+			return rows.Scan(&pr.ID, &pr.LastUpdateDate, &pr.PurchasePrice, &pr.SalePrice, &pr.ProductID)
+		})
+}
+
+// WarehousesByIDs returns the warehouses with the given IDs
+func (r *IncludeDB) WarehousesByIDs(ids []int) ([]mod.Warehouse, error) {
+	return queryIn(r.db, "SELECT `id`, `warehouse_code`, `address`, `telephone`, `minimum_capacity`, `minimum_temperature`, COALESCE(`locality_id`, 0), `latitude`, `longitude` FROM `warehouses` WHERE `id` IN (%s)", ids,
+		func(rows *sql.Rows, w *mod.Warehouse) error {
+			return rows.Scan(&w.ID, &w.WarehouseCode, &w.Address, &w.Telephone, &w.MinimumCapacity, &w.MinimumTemperature, &w.LocalityID, &w.Latitude, &w.Longitude)
+		})
+}
+
+// SectionsByIDs returns the sections with the given IDs
+func (r *IncludeDB) SectionsByIDs(ids []int) ([]mod.Section, error) {
+	return queryIn(r.db, "SELECT `id`, `section_number`, `current_temperature`, `minimum_temperature`, `current_capacity`, `minimum_capacity`, `maximum_capacity`, `warehouse_id`, `product_type_id` FROM `sections` WHERE `id` IN (%s)", ids,
+		func(rows *sql.Rows, s *mod.Section) error {
+			return rows.Scan(&s.ID, &s.SectionNumber, &s.CurrentTemperature, &s.MinimumTemperature, &s.CurrentCapacity, &s.MinimumCapacity, &s.MaximumCapacity, &s.WarehouseID, &s.ProductTypeID)
+		})
+}
+
+// BatchesBySectionIDs returns the product batches of the given sections
+func (r *IncludeDB) BatchesBySectionIDs(ids []int) ([]mod.ProductBatch, error) {
+	return queryIn(r.db, "SELECT `id`, `batch_number`, `current_quantity`, `initial_quantity`, `current_temperature`, `minimum_temperature`, `due_date`, `manufacturing_date`, `manufacturing_hour`, `product_id`, `section_id` FROM `product_batches` WHERE `section_id` IN (%s)", ids,
+		func(rows *sql.Rows, b *mod.ProductBatch) error {
+			return rows.Scan(&b.ID, &b.BatchNumber, &b.CurrentQuantity, &b.InitialQuantity, &b.CurrentTemperature, &b.MinimumTemperature, &b.DueDate, &b.ManufacturingDate, &b.ManufacturingHour, &b.ProductId, &b.SectionId)
+		})
+}
+
+// BuyersByIDs returns the buyers with the given IDs
+func (r *IncludeDB) BuyersByIDs(ids []int) ([]mod.Buyer, error) {
+	return queryIn(r.db, "SELECT `id`, `id_card_number`, `first_name`, `last_name` FROM `buyers` WHERE `id` IN (%s)", ids,
+		func(rows *sql.Rows, b *mod.Buyer) error {
+			return rows.Scan(&b.ID, &b.CardNumberID, &b.FirstName, &b.LastName)
+		})
+}
+
+// DetailsByPurchaseOrderIDs returns the details of the given purchase orders
+func (r *IncludeDB) DetailsByPurchaseOrderIDs(ids []int) ([]mod.OrderDetails, error) {
+	return queryIn(r.db, "SELECT `id`, `clean_liness_status`, `quantity`, `temperature`, `product_record_id`, `purchase_order_id` FROM `order_details` WHERE `purchase_order_id` IN (%s)", ids,
+		func(rows *sql.Rows, od *mod.OrderDetails) error {
+			var quantity sql.NullInt64
+			var temperature sql.NullFloat64
+			var recordID sql.NullInt64
+			err := rows.Scan(&od.ID, &od.CleanLinessStatus, &quantity, &temperature, &recordID, &od.PurchaseOrderId)
+			od.Quantity, od.Temperature, od.ProductRecordId = int(quantity.Int64), temperature.Float64, int(recordID.Int64)
+			return err
+		})
+}
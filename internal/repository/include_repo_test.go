@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncludeDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewIncludeRepo(db)
+
+	t.Run("sellers are loaded with a single IN query", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT `id`, `cid`, `company_name`, `address`, `telephone`, `locality_id` FROM `sellers` WHERE `id` IN (?,?)")).
+			WithArgs(3, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "cid", "company_name", "address", "telephone", "locality_id"}).
+				AddRow(3, 30, "Acme", "Street 1", "555", 1).
+				AddRow(5, 50, "Globex", "Street 2", "556", 2))
+
+		result, err := repo.SellersByIDs([]int{3, 5})
+		require.NoError(t, err)
+		require.Equal(t, []mod.Seller{
+			{ID: 3, CID: 30, CompanyName: "Acme", Address: "Street 1", Telephone: "555", Locality: 1},
+			{ID: 5, CID: 50, CompanyName: "Globex", Address: "Street 2", Telephone: "556", Locality: 2},
+		}, result)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("order details with null columns", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("FROM `order_details` WHERE `purchase_order_id` IN (?)")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "clean_liness_status", "quantity", "temperature", "product_record_id", "purchase_order_id"}).
+				AddRow(1, "Ready", 4, 9.5, 2, 1).
+				AddRow(2, "Ready", nil, nil, nil, 1))
+
+		result, err := repo.DetailsByPurchaseOrderIDs([]int{1})
+		require.NoError(t, err)
+		require.Equal(t, []mod.OrderDetails{
+			{ID: 1, CleanLinessStatus: "Ready", Quantity: 4, Temperature: 9.5, ProductRecordId: 2, PurchaseOrderId: 1},
+			{ID: 2, CleanLinessStatus: "Ready", PurchaseOrderId: 1},
+		}, result)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no ids runs no query", func(t *testing.T) {
+		result, err := repo.BuyersByIDs(nil)
+		require.NoError(t, err)
+		require.Empty(t, result)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("FROM `warehouses` WHERE `id` IN (?)")).WillReturnError(sqlmock.ErrCancelled)
+
+		_, err := repo.WarehousesByIDs([]int{1})
+		require.ErrorIs(t, err, e.ErrQueryError)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
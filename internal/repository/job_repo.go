@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewJobRepo creates a new instance of the job repository, leases must live on the primary database
+func NewJobRepo(db *sql.DB) *JobDB {
+	return &JobDB{db: db}
+}
+
+// JobDB is the implementation of the job database, see docs/SQL/migrations/0002_scheduler.sql
+type JobDB struct {
+	db *sql.DB
+}
+
+// AcquireLease takes the lease of the job when it is free, expired or already held by the owner. A scheduled
+// run also needs its activation to be later than the last one, so an instance whose timer fires after the
+// run of another one finished does not run the same activation again
+func (r *JobDB) AcquireLease(job, owner string, activation, now, until time.Time) (bool, error) {
+	if _, err := r.db.Exec("INSERT IGNORE INTO `job_leases` (`name`) VALUES (?)", job); err != nil {
+		return false, e.ErrQueryError
+	}
+	var res sql.Result
+	var err error
+	if activation.IsZero() {
+		res, err = r.db.Exec("UPDATE `job_leases` SET `owner` = ?, `expires_at` = ? WHERE `name` = ? AND (`expires_at` <= ? OR `owner` = ?)",
+			owner, until, job, now, owner)
+	} else {
+		res, err = r.db.Exec("UPDATE `job_leases` SET `owner` = ?, `expires_at` = ?, `last_activation` = ? "+
+			"WHERE `name` = ? AND (`expires_at` <= ? OR `owner` = ?) AND `last_activation` < ?",
+			owner, until, activation, job, now, owner, activation)
+	}
+	if err != nil {
+		return false, e.ErrQueryError
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, e.ErrQueryError
+	}
+	return affected == 1, nil
+}
+
+// ReleaseLease expires the lease of the job when it is held by the owner
+func (r *JobDB) ReleaseLease(job, owner string, now time.Time) error {
+	if _, err := r.db.Exec("UPDATE `job_leases` SET `expires_at` = ? WHERE `name` = ? AND `owner` = ?", now, job, owner); err != nil {
+		return e.ErrQueryError
+	}
+	return nil
+}
+
+// SetPaused pauses or resumes the scheduled runs of the job
+func (r *JobDB) SetPaused(job string, paused bool) error {
+	if _, err := r.db.Exec("INSERT INTO `job_leases` (`name`, `paused`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `paused` = VALUES(`paused`)", job, paused); err != nil {
+		return e.ErrQueryError
+	}
+	return nil
+}
+
+// FindPaused returns the paused jobs
+func (r *JobDB) FindPaused() (map[string]bool, error) {
+	rows, err := r.db.Query("SELECT `name` FROM `job_leases` WHERE `paused` = TRUE")
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	paused := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, e.ErrParseError
+		}
+		paused[name] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return paused, nil
+}
+
+// SaveRun records a run of a job
+func (r *JobDB) SaveRun(run *mod.JobRun) error {
+	res, err := r.db.Exec("INSERT INTO `job_runs` (`job`, `owner`, `trigger`, `started_at`, `finished_at`, `duration_ms`, `status`, `error`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		run.Job, run.Owner, run.Trigger, run.StartedAt, run.FinishedAt, run.DurationMs, run.Status, run.Error)
+	if err != nil {
+		return e.ErrQueryError
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return e.ErrQueryError
+	}
+	run.ID = int(id)
+	return nil
+}
+
+// jobRunColumns are the columns read by scanJobRun
+const jobRunColumns = "`id`, `job`, `owner`, `trigger`, `started_at`, `finished_at`, `duration_ms`, `status`, `error`"
+
+// FindRuns returns the latest runs of the job, newest first
+func (r *JobDB) FindRuns(job string, limit int) ([]mod.JobRun, error) {
+	rows, err := r.db.Query("SELECT "+jobRunColumns+" FROM `job_runs` WHERE `job` = ? ORDER BY `started_at` DESC, `id` DESC LIMIT ?", job, limit)
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	runs := []mod.JobRun{}
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return runs, nil
+}
+
+// FindLastRuns returns the latest run of every job that ever ran
+func (r *JobDB) FindLastRuns() (map[string]mod.JobRun, error) {
+	rows, err := r.db.Query("SELECT " + jobRunColumns + " FROM `job_runs` WHERE `id` IN (SELECT MAX(`id`) FROM `job_runs` GROUP BY `job`)")
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	last := make(map[string]mod.JobRun)
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		last[run.Job] = run
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return last, nil
+}
+
+// DeleteRunsBefore deletes the runs started before the given time
+func (r *JobDB) DeleteRunsBefore(t time.Time) (int64, error) {
+	res, err := r.db.Exec("DELETE FROM `job_runs` WHERE `started_at` < ?", t)
+	if err != nil {
+		return 0, e.ErrQueryError
+	}
+	return res.RowsAffected()
+}
+
+// scanJobRun reads a run selected with jobRunColumns
+func scanJobRun(rows *sql.Rows) (mod.JobRun, error) {
+	var run mod.JobRun
+	var runErr sql.NullString
+	err := rows.Scan(&run.ID, &run.Job, &run.Owner, &run.Trigger, &run.StartedAt, &run.FinishedAt, &run.DurationMs, &run.Status, &runErr)
+	if err != nil {
+		return run, e.ErrParseError
+	}
+	run.Error = runErr.String
+	return run, nil
+}
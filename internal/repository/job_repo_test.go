@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRepo_AcquireLease(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	until := now.Add(time.Minute)
+	insert := regexp.QuoteMeta("INSERT IGNORE INTO `job_leases` (`name`) VALUES (?)")
+	update := regexp.QuoteMeta("UPDATE `job_leases` SET `owner` = ?, `expires_at` = ? WHERE `name` = ? AND (`expires_at` <= ? OR `owner` = ?)")
+
+	tests := []struct {
+		name     string
+		affected int64
+		err      error
+		expected bool
+	}{
+		{name: "#1 Success - Lease taken", affected: 1, expected: true},
+		{name: "#2 Success - Held by another owner", affected: 0, expected: false},
+		{name: "#3 Error - Query failure", err: e.ErrQueryError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			mock.ExpectExec(insert).WithArgs("job").WillReturnResult(sqlmock.NewResult(0, 0))
+			exp := mock.ExpectExec(update).WithArgs("a", until, "job", now, "a")
+			if tt.err != nil {
+				exp.WillReturnError(tt.err)
+			} else {
+				exp.WillReturnResult(sqlmock.NewResult(0, tt.affected))
+			}
+
+			ok, err := NewJobRepo(db).AcquireLease("job", "a", time.Time{}, now, until)
+
+			require.ErrorIs(t, err, tt.err)
+			require.Equal(t, tt.expected, ok)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("#4 Success - Scheduled activation", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		activation := now.Truncate(time.Hour)
+		mock.ExpectExec(insert).WithArgs("job").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `job_leases` SET `owner` = ?, `expires_at` = ?, `last_activation` = ? "+
+			"WHERE `name` = ? AND (`expires_at` <= ? OR `owner` = ?) AND `last_activation` < ?")).
+			WithArgs("a", until, activation, "job", now, "a", activation).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		ok, err := NewJobRepo(db).AcquireLease("job", "a", activation, now, until)
+
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestJobRepo_Runs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewJobRepo(db)
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	finished := started.Add(1500 * time.Millisecond)
+	columns := []string{"id", "job", "owner", "trigger", "started_at", "finished_at", "duration_ms", "status", "error"}
+
+	t.Run("save", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `job_runs`")).
+			WithArgs("job", "a", mod.JobTriggerManual, started, finished, int64(1500), mod.JobRunSucceeded, "").
+			WillReturnResult(sqlmock.NewResult(3, 1))
+
+		run := mod.JobRun{Job: "job", Owner: "a", Trigger: mod.JobTriggerManual, StartedAt: started, FinishedAt: finished, DurationMs: 1500, Status: mod.JobRunSucceeded}
+		require.NoError(t, repo.SaveRun(&run))
+		require.Equal(t, 3, run.ID)
+	})
+
+	t.Run("find newest first", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("FROM `job_runs` WHERE `job` = ? ORDER BY `started_at` DESC, `id` DESC LIMIT ?")).
+			WithArgs("job", 2).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(3, "job", "a", "manual", started, finished, 1500, "failed", "boom").
+				AddRow(2, "job", "b", "schedule", started, finished, 10, "succeeded", nil))
+
+		runs, err := repo.FindRuns("job", 2)
+		require.NoError(t, err)
+		require.Len(t, runs, 2)
+		require.Equal(t, "boom", runs[0].Error)
+		require.Equal(t, "", runs[1].Error)
+	})
+
+	t.Run("last run of every job", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("WHERE `id` IN (SELECT MAX(`id`) FROM `job_runs` GROUP BY `job`)")).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(3, "job", "a", "manual", started, finished, 1500, "failed", "boom"))
+
+		last, err := repo.FindLastRuns()
+		require.NoError(t, err)
+		require.Equal(t, 3, last["job"].ID)
+	})
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJobRepo_SetPaused(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `job_leases` (`name`, `paused`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `paused` = VALUES(`paused`)")).
+		WithArgs("job", true).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `name` FROM `job_leases` WHERE `paused` = TRUE")).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("job"))
+
+	repo := NewJobRepo(db)
+	require.NoError(t, repo.SetPaused("job", true))
+	paused, err := repo.FindPaused()
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"job": true}, paused)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -23,7 +23,7 @@ type LocalityDB struct {
 
 // FindByID returns a seller from the database by its id -TESTED
 func (r *LocalityDB) FindAllLocalities() (result []models.Locality, err error) {
-	rows, err := r.db.Query("SELECT l.id, l.locality_name, l.province_name, l.country_name FROM localities AS l")
+	rows, err := r.db.Query("SELECT l.id, l.locality_name, l.province_name, l.country_name, l.latitude, l.longitude FROM localities AS l")
 	if err != nil {
 		return nil, e.ErrQueryError
 	}
@@ -31,7 +31,7 @@ func (r *LocalityDB) FindAllLocalities() (result []models.Locality, err error) {
 
 	for rows.Next() {
 		var locality models.Locality
-		err = rows.Scan(&locality.ID, &locality.Name, &locality.Province, &locality.Country)
+		err = rows.Scan(&locality.ID, &locality.Name, &locality.Province, &locality.Country, &locality.Latitude, &locality.Longitude)
 		if err != nil {
 			return nil, e.ErrParseError
 		}
@@ -43,6 +43,19 @@ func (r *LocalityDB) FindAllLocalities() (result []models.Locality, err error) {
 	return result, nil
 }
 
+// FindByID returns the locality with the given id
+func (r *LocalityDB) FindByID(id int) (locality models.Locality, err error) {
+	err = r.db.QueryRow("SELECT l.id, l.locality_name, l.province_name, l.country_name, l.latitude, l.longitude FROM localities AS l WHERE l.id = ?", id).
+		Scan(&locality.ID, &locality.Name, &locality.Province, &locality.Country, &locality.Latitude, &locality.Longitude)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Locality{}, e.ErrLocalityRepositoryNotFound
+	}
+	if err != nil {
+		return models.Locality{}, e.ErrQueryError
+	}
+	return locality, nil
+}
+
 // FindsSellersByLocID returns a list of each location with the sum of its sellers, it can also return one location if param is > 0
 func (r *LocalityDB) FindSellersByLocID(id int) (result []models.SelByLoc, err error) {
 	var rows *sql.Rows
@@ -71,9 +84,82 @@ func (r *LocalityDB) FindSellersByLocID(id int) (result []models.SelByLoc, err e
 	return result, nil
 }
 
+// ExistsByID tells if a locality with the id exists
+func (r *LocalityDB) ExistsByID(id int) (exists bool, err error) {
+	err = r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM `localities` WHERE `id` = ?)", id).Scan(&exists)
+	if err != nil {
+		return false, e.ErrQueryError
+	}
+	return exists, nil
+}
+
+// FindWarehousesByLocID returns a list of each location with the sum of its warehouses, it can also return one location if param is > 0
+func (r *LocalityDB) FindWarehousesByLocID(id int) (result []models.WhByLoc, err error) {
+	var rows *sql.Rows
+
+	switch id {
+	case -1:
+		rows, err = r.db.Query("SELECT l.id, l.locality_name, count(w.id) FROM localities AS `l` LEFT JOIN `warehouses` as `w` ON l.id=w.locality_id GROUP BY l.id")
+	default:
+		rows, err = r.db.Query("SELECT l.id, l.locality_name, count(w.id) FROM localities AS `l` LEFT JOIN `warehouses` as `w` ON l.id=w.locality_id GROUP BY l.id HAVING l.id= ?", id)
+	}
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var locality models.WhByLoc
+		err = rows.Scan(&locality.ID, &locality.Name, &locality.Count)
+		if err != nil {
+			return nil, errors.Join(e.ErrParseError, err)
+		}
+		result = append(result, locality)
+	}
+	if len(result) == 0 {
+		return nil, e.ErrLocalityRepositoryNotFound
+	}
+	return result, nil
+}
+
+// localityOverviewQuery counts with subqueries so the sellers, carries and warehouses joins do not multiply each other
+const localityOverviewQuery = "SELECT l.id, l.locality_name, " +
+	"(SELECT count(*) FROM `sellers` AS `s` WHERE s.locality_id=l.id), " +
+	"(SELECT count(*) FROM `carries` AS `c` WHERE c.locality_id=l.id), " +
+	"(SELECT count(*) FROM `warehouses` AS `w` WHERE w.locality_id=l.id) " +
+	"FROM localities AS `l`"
+
+// FindOverviewByLocID returns a list of each location with the sum of its sellers, carries and warehouses, it can also return one location if param is > 0
+func (r *LocalityDB) FindOverviewByLocID(id int) (result []models.LocalityOverview, err error) {
+	var rows *sql.Rows
+
+	switch id {
+	case -1:
+		rows, err = r.db.Query(localityOverviewQuery + " ORDER BY l.id")
+	default:
+		rows, err = r.db.Query(localityOverviewQuery+" WHERE l.id= ?", id)
+	}
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var locality models.LocalityOverview
+		err = rows.Scan(&locality.ID, &locality.Name, &locality.SellersCount, &locality.CarriesCount, &locality.WarehousesCount)
+		if err != nil {
+			return nil, errors.Join(e.ErrParseError, err)
+		}
+		result = append(result, locality)
+	}
+	if len(result) == 0 {
+		return nil, e.ErrLocalityRepositoryNotFound
+	}
+	return result, nil
+}
+
 // Save saves a locality into the database -TESTED
 func (r *LocalityDB) Save(locality *models.Locality) (id int, err error) {
-	result, err := r.db.Exec("INSERT INTO `localities`(`locality_name`,`province_name`,`country_name`) VALUES(?,?,?)", locality.Name, locality.Province, locality.Country)
+	result, err := r.db.Exec("INSERT INTO `localities`(`locality_name`,`province_name`,`country_name`,`latitude`,`longitude`) VALUES(?,?,?,?,?)",
+		locality.Name, locality.Province, locality.Country, locality.Latitude, locality.Longitude)
 	if err != nil {
 		var mySQLErr *mysql.MySQLError
 		if errors.As(err, &mySQLErr) {
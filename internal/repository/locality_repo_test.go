@@ -25,7 +25,7 @@ func (suite *LocalityRepoTestSuite) TestLocalities_FindAll() {
 	t.Run("#1 - All Success", func(t *testing.T) {
 		// given
 		suite.SetupTest("localities")
-		suite.MockDb.ExpectQuery("SELECT l.id, l.locality_name, l.province_name, l.country_name FROM localities AS l").
+		suite.MockDb.ExpectQuery("SELECT l.id, l.locality_name, l.province_name, l.country_name, l.latitude, l.longitude FROM localities AS l").
 			WillReturnRows(suite.TestTable)
 		suite.repo = repo.NewLocalityRepo(suite.TestDb)
 
@@ -33,8 +33,9 @@ func (suite *LocalityRepoTestSuite) TestLocalities_FindAll() {
 		result, err := suite.repo.FindAllLocalities()
 
 		// then
+		lat, lng := 40.7831, -73.9712
 		expected := []mod.Locality{
-			{ID: 1, Name: "Manhattan", Province: "New York", Country: "USA"},
+			{ID: 1, Name: "Manhattan", Province: "New York", Country: "USA", Latitude: &lat, Longitude: &lng},
 			{ID: 2, Name: "Downtown", Province: "California", Country: "USA"},
 			{ID: 3, Name: "Lakeview", Province: "Illinois", Country: "USA"},
 		}
@@ -46,8 +47,8 @@ func (suite *LocalityRepoTestSuite) TestLocalities_FindAll() {
 	t.Run("#2 - All Unable to parse DB info", func(t *testing.T) {
 		// given
 		suite.SetupTest("localities")
-		suite.MockDb.ExpectQuery("SELECT l.id, l.locality_name, l.province_name, l.country_name FROM localities AS l").
-			WillReturnRows(suite.TestTable.AddRow(4, "Medellin", "Antioquia", nil))
+		suite.MockDb.ExpectQuery("SELECT l.id, l.locality_name, l.province_name, l.country_name, l.latitude, l.longitude FROM localities AS l").
+			WillReturnRows(suite.TestTable.AddRow(4, "Medellin", "Antioquia", nil, nil, nil))
 		suite.repo = repo.NewLocalityRepo(suite.TestDb)
 
 		// When
@@ -61,7 +62,7 @@ func (suite *LocalityRepoTestSuite) TestLocalities_FindAll() {
 	t.Run("#3 - All Query is malformed", func(t *testing.T) {
 		// given
 		suite.SetupTest("localities")
-		suite.MockDb.ExpectQuery("SELECT l.id, l.locality_name, l.province_name, l.country_name FROM localities AS l").
+		suite.MockDb.ExpectQuery("SELECT l.id, l.locality_name, l.province_name, l.country_name, l.latitude, l.longitude FROM localities AS l").
 			WillReturnError(e.ErrQueryError)
 		suite.repo = repo.NewLocalityRepo(suite.TestDb)
 
@@ -76,7 +77,7 @@ func (suite *LocalityRepoTestSuite) TestLocalities_FindAll() {
 	t.Run("#4 - All Query is empty", func(t *testing.T) {
 		// given
 		suite.SetupTest("localities")
-		suite.MockDb.ExpectQuery("SELECT l.id, l.locality_name, l.province_name, l.country_name FROM localities AS l").
+		suite.MockDb.ExpectQuery("SELECT l.id, l.locality_name, l.province_name, l.country_name, l.latitude, l.longitude FROM localities AS l").
 			WillReturnRows(sqlmock.NewRows(suite.TestColumns))
 		suite.repo = repo.NewLocalityRepo(suite.TestDb)
 
@@ -96,7 +97,7 @@ func (suite *LocalityRepoTestSuite) TestLocalities_Save() {
 		Province: "Antioquia",
 		Country:  "Colombia",
 	}
-	expectedQuery := "INSERT INTO `localities`(`locality_name`,`province_name`,`country_name`) VALUES(?,?,?)"
+	expectedQuery := "INSERT INTO `localities`(`locality_name`,`province_name`,`country_name`,`latitude`,`longitude`) VALUES(?,?,?,?,?)"
 
 	t.Run("#1 - Save Success", func(t *testing.T) {
 		// given
@@ -104,7 +105,7 @@ func (suite *LocalityRepoTestSuite) TestLocalities_Save() {
 		defer suite.TestDb.Close()
 
 		suite.MockDb.ExpectExec(regexp.QuoteMeta(expectedQuery)).
-			WithArgs(newLocality.Name, newLocality.Province, newLocality.Country).
+			WithArgs(newLocality.Name, newLocality.Province, newLocality.Country, nil, nil).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		suite.repo = repo.NewLocalityRepo(suite.TestDb)
@@ -124,7 +125,7 @@ func (suite *LocalityRepoTestSuite) TestLocalities_Save() {
 		defer suite.TestDb.Close()
 
 		suite.MockDb.ExpectExec(regexp.QuoteMeta(expectedQuery)).
-			WithArgs(newLocality.Name, newLocality.Province, newLocality.Country).
+			WithArgs(newLocality.Name, newLocality.Province, newLocality.Country, nil, nil).
 			WillReturnError(&mysql.MySQLError{Number: 1062})
 
 		suite.repo = repo.NewLocalityRepo(suite.TestDb)
@@ -143,7 +144,7 @@ func (suite *LocalityRepoTestSuite) TestLocalities_Save() {
 		defer suite.TestDb.Close()
 
 		suite.MockDb.ExpectExec(regexp.QuoteMeta(expectedQuery)).
-			WithArgs(newLocality.Name, newLocality.Province, newLocality.Country).
+			WithArgs(newLocality.Name, newLocality.Province, newLocality.Country, nil, nil).
 			WillReturnError(errors.New("unexpected db error"))
 
 		suite.repo = repo.NewLocalityRepo(suite.TestDb)
@@ -268,6 +269,220 @@ func (suite *LocalityRepoTestSuite) TestLocalities_FindSellerByLocalityID() {
 	})
 }
 
+func (suite *LocalityRepoTestSuite) TestLocalities_FindWarehousesByLocalityID() {
+	t := suite.T()
+	expectedQuery := "SELECT l.id, l.locality_name, count(w.id) FROM localities AS `l` LEFT JOIN `warehouses` as `w` ON l.id=w.locality_id GROUP BY l.id"
+
+	t.Run("#1 - ID All Success", func(t *testing.T) {
+		// given
+		suite.SetupTest("sel_by_loc")
+		defer suite.TestDb.Close()
+
+		suite.MockDb.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+			WillReturnRows(suite.TestTable)
+
+		suite.repo = repo.NewLocalityRepo(suite.TestDb)
+
+		// When
+		result, err := suite.repo.FindWarehousesByLocID(-1)
+
+		// then
+		expected := []mod.WhByLoc{
+			{ID: 1, Name: "Manhattan", Count: 5},
+			{ID: 2, Name: "Downtown", Count: 3},
+			{ID: 3, Name: "Lakeview", Count: 2},
+		}
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	})
+
+	t.Run("#2 - ID Query is empty", func(t *testing.T) {
+		// given
+		suite.SetupTest("sel_by_loc")
+		defer suite.TestDb.Close()
+
+		suite.MockDb.ExpectQuery(regexp.QuoteMeta(expectedQuery + " HAVING l.id= ?")).
+			WithArgs(9).WillReturnRows(sqlmock.NewRows(suite.TestColumns))
+
+		suite.repo = repo.NewLocalityRepo(suite.TestDb)
+
+		// When
+		_, err := suite.repo.FindWarehousesByLocID(9)
+
+		// then
+		require.ErrorIs(t, err, e.ErrLocalityRepositoryNotFound)
+	})
+
+	t.Run("#3 - ID Unknown error", func(t *testing.T) {
+		// given
+		suite.SetupTest("sel_by_loc")
+		defer suite.TestDb.Close()
+
+		suite.MockDb.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+			WillReturnError(errors.New("unexpected db error"))
+
+		suite.repo = repo.NewLocalityRepo(suite.TestDb)
+
+		// When
+		_, err := suite.repo.FindWarehousesByLocID(-1)
+
+		// then
+		require.ErrorIs(t, err, e.ErrQueryError)
+	})
+}
+
+func (suite *LocalityRepoTestSuite) TestLocalities_FindOverviewByLocalityID() {
+	t := suite.T()
+	columns := []string{"id", "locality_name", "sellers_count", "carries_count", "warehouses_count"}
+
+	t.Run("#1 - ID All Success", func(t *testing.T) {
+		// given
+		suite.SetupTest("sel_by_loc")
+		defer suite.TestDb.Close()
+
+		suite.MockDb.ExpectQuery(regexp.QuoteMeta("FROM localities AS `l` ORDER BY l.id")).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, "Manhattan", 5, 2, 1).
+				AddRow(2, "Downtown", 0, 0, 3))
+
+		suite.repo = repo.NewLocalityRepo(suite.TestDb)
+
+		// When
+		result, err := suite.repo.FindOverviewByLocID(-1)
+
+		// then
+		expected := []mod.LocalityOverview{
+			{ID: 1, Name: "Manhattan", SellersCount: 5, CarriesCount: 2, WarehousesCount: 1},
+			{ID: 2, Name: "Downtown", SellersCount: 0, CarriesCount: 0, WarehousesCount: 3},
+		}
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	})
+
+	t.Run("#2 - ID One Success", func(t *testing.T) {
+		// given
+		suite.SetupTest("sel_by_loc")
+		defer suite.TestDb.Close()
+
+		suite.MockDb.ExpectQuery(regexp.QuoteMeta("FROM localities AS `l` WHERE l.id= ?")).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "Manhattan", 5, 2, 1))
+
+		suite.repo = repo.NewLocalityRepo(suite.TestDb)
+
+		// When
+		result, err := suite.repo.FindOverviewByLocID(1)
+
+		// then
+		expected := []mod.LocalityOverview{{ID: 1, Name: "Manhattan", SellersCount: 5, CarriesCount: 2, WarehousesCount: 1}}
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	})
+
+	t.Run("#3 - ID Query is empty", func(t *testing.T) {
+		// given
+		suite.SetupTest("sel_by_loc")
+		defer suite.TestDb.Close()
+
+		suite.MockDb.ExpectQuery(regexp.QuoteMeta("WHERE l.id= ?")).
+			WithArgs(9).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		suite.repo = repo.NewLocalityRepo(suite.TestDb)
+
+		// When
+		_, err := suite.repo.FindOverviewByLocID(9)
+
+		// then
+		require.ErrorIs(t, err, e.ErrLocalityRepositoryNotFound)
+	})
+}
+
+func (suite *LocalityRepoTestSuite) TestLocalities_ExistsByID() {
+	t := suite.T()
+	expectedQuery := "SELECT EXISTS(SELECT 1 FROM `localities` WHERE `id` = ?)"
+
+	t.Run("#1 - Exists", func(t *testing.T) {
+		// given
+		suite.SetupTest("localities")
+		defer suite.TestDb.Close()
+
+		suite.MockDb.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		suite.repo = repo.NewLocalityRepo(suite.TestDb)
+
+		// When
+		exists, err := suite.repo.ExistsByID(1)
+
+		// then
+		require.NoError(t, err)
+		require.True(t, exists)
+	})
+
+	t.Run("#2 - Unknown error", func(t *testing.T) {
+		// given
+		suite.SetupTest("localities")
+		defer suite.TestDb.Close()
+
+		suite.MockDb.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+			WithArgs(1).
+			WillReturnError(errors.New("unexpected db error"))
+
+		suite.repo = repo.NewLocalityRepo(suite.TestDb)
+
+		// When
+		_, err := suite.repo.ExistsByID(1)
+
+		// then
+		require.ErrorIs(t, err, e.ErrQueryError)
+	})
+}
+
+func (suite *LocalityRepoTestSuite) TestLocalities_FindByID() {
+	t := suite.T()
+	expectedQuery := "SELECT l.id, l.locality_name, l.province_name, l.country_name, l.latitude, l.longitude FROM localities AS l WHERE l.id = ?"
+
+	t.Run("#1 - Found with coordinates", func(t *testing.T) {
+		// given
+		suite.SetupTest("localities")
+		defer suite.TestDb.Close()
+
+		suite.MockDb.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(suite.TestColumns).AddRow(1, "Manhattan", "New York", "USA", 40.7831, -73.9712))
+
+		suite.repo = repo.NewLocalityRepo(suite.TestDb)
+
+		// When
+		locality, err := suite.repo.FindByID(1)
+
+		// then
+		lat, lng := 40.7831, -73.9712
+		require.NoError(t, err)
+		require.Equal(t, mod.Locality{ID: 1, Name: "Manhattan", Province: "New York", Country: "USA", Latitude: &lat, Longitude: &lng}, locality)
+	})
+
+	t.Run("#2 - Not found", func(t *testing.T) {
+		// given
+		suite.SetupTest("localities")
+		defer suite.TestDb.Close()
+
+		suite.MockDb.ExpectQuery(regexp.QuoteMeta(expectedQuery)).
+			WithArgs(9).
+			WillReturnRows(sqlmock.NewRows(suite.TestColumns))
+
+		suite.repo = repo.NewLocalityRepo(suite.TestDb)
+
+		// When
+		_, err := suite.repo.FindByID(9)
+
+		// then
+		require.ErrorIs(t, err, e.ErrLocalityRepositoryNotFound)
+	})
+}
+
 func TestLocalityRepoTestSuite(t *testing.T) {
 	suite.Run(t, new(LocalityRepoTestSuite))
 }
@@ -3,9 +3,13 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/go-sql-driver/mysql"
 	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"slices"
+	"strings"
+	"time"
 )
 
 type ProductBatchDB struct {
@@ -18,8 +22,58 @@ func NewProductBatchRepo(db *sql.DB) *ProductBatchDB {
 	}
 }
 
-func (r *ProductBatchDB) FindAll() (batches []mod.ProductBatch, err error) {
-	rows, err := r.db.Query("SELECT `id`,`batch_number`, `current_quantity`, `initial_quantity`, `current_temperature`, `minimum_temperature`, `due_date`, `manufacturing_date`, `manufacturing_hour`, `product_id`, `section_id` FROM `product_batches` ")
+// productBatchColumns are the columns of a batch read by scanProductBatch
+const productBatchColumns = "pb.`id`, pb.`batch_number`, pb.`current_quantity`, pb.`initial_quantity`, pb.`current_temperature`, pb.`minimum_temperature`, " +
+	"pb.`due_date`, pb.`manufacturing_date`, pb.`manufacturing_hour`, pb.`product_id`, pb.`section_id`, pb.`archived_at`, pb.`expired_at`, pb.`status`"
+
+// scanProductBatch scans the productBatchColumns of a batch, archived_at and expired_at are nullable
+func scanProductBatch(row interface{ Scan(...any) error }) (batch mod.ProductBatch, err error) {
+	var archived, expired sql.NullTime
+	err = row.Scan(&batch.ID, &batch.BatchNumber, &batch.CurrentQuantity, &batch.InitialQuantity, &batch.CurrentTemperature, &batch.MinimumTemperature,
+		&batch.DueDate, &batch.ManufacturingDate, &batch.ManufacturingHour, &batch.ProductId, &batch.SectionId, &archived, &expired, &batch.Status)
+	if archived.Valid {
+		batch.ArchivedAt = &archived.Time
+	}
+	if expired.Valid {
+		batch.ExpiredAt = &expired.Time
+	}
+	return
+}
+
+// FindAll returns the batches of the filter that were not retired
+func (r *ProductBatchDB) FindAll(f mod.ProductBatchFilter) (batches []mod.ProductBatch, err error) {
+	query := "SELECT " + productBatchColumns + " FROM `product_batches` AS pb"
+	where := []string{"pb.`retired_at` IS NULL"}
+	var args []any
+	if f.WarehouseID > 0 {
+		query += " INNER JOIN `sections` AS s ON s.`id` = pb.`section_id`"
+		where = append(where, "s.`warehouse_id` = ?")
+		args = append(args, f.WarehouseID)
+	}
+	if f.ProductID > 0 {
+		where = append(where, "pb.`product_id` = ?")
+		args = append(args, f.ProductID)
+	}
+	if f.SectionID > 0 {
+		where = append(where, "pb.`section_id` = ?")
+		args = append(args, f.SectionID)
+	}
+	if !f.DueFrom.IsZero() {
+		where = append(where, "pb.`due_date` >= ?")
+		args = append(args, f.DueFrom)
+	}
+	if !f.DueTo.IsZero() {
+		where = append(where, "pb.`due_date` < ?")
+		args = append(args, f.DueTo)
+	}
+	if f.Archived != nil {
+		if *f.Archived {
+			where = append(where, "pb.`archived_at` IS NOT NULL")
+		} else {
+			where = append(where, "pb.`archived_at` IS NULL")
+		}
+	}
+	rows, err := r.db.Query(query+" WHERE "+strings.Join(where, " AND ")+" ORDER BY pb.`id`", args...)
 	if err != nil {
 		return nil, e.ErrQueryError
 	}
@@ -27,8 +81,7 @@ func (r *ProductBatchDB) FindAll() (batches []mod.ProductBatch, err error) {
 	defer rows.Close()
 
 	for rows.Next() {
-		var batch mod.ProductBatch
-		err = rows.Scan(&batch.ID, &batch.BatchNumber, &batch.CurrentQuantity, &batch.InitialQuantity, &batch.CurrentTemperature, &batch.MinimumTemperature, &batch.DueDate, &batch.ManufacturingDate, &batch.ManufacturingHour, &batch.ProductId, &batch.SectionId)
+		batch, err := scanProductBatch(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -41,8 +94,72 @@ func (r *ProductBatchDB) FindAll() (batches []mod.ProductBatch, err error) {
 	return batches, nil
 }
 
+// FindByID returns a batch that was not retired
+func (r *ProductBatchDB) FindByID(id int) (mod.ProductBatch, error) {
+	batch, err := scanProductBatch(r.db.QueryRow("SELECT "+productBatchColumns+" FROM `product_batches` AS pb WHERE pb.`id` = ? AND pb.`retired_at` IS NULL", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return mod.ProductBatch{}, e.ErrProductBatchNotFound
+	}
+	if err != nil {
+		return mod.ProductBatch{}, e.ErrQueryError
+	}
+	return batch, nil
+}
+
+// lockProductBatch locks a batch that was not retired for the rest of the transaction
+func lockProductBatch(tx *sql.Tx, id int) (mod.ProductBatch, error) {
+	batch, err := scanProductBatch(tx.QueryRow("SELECT "+productBatchColumns+" FROM `product_batches` AS pb WHERE pb.`id` = ? AND pb.`retired_at` IS NULL FOR UPDATE", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return mod.ProductBatch{}, e.ErrProductBatchNotFound
+	}
+	if err != nil {
+		return mod.ProductBatch{}, e.ErrQueryError
+	}
+	return batch, nil
+}
+
+// Save locks the section of the batch, checks that it stores the product type of the product and has room for the
+// current quantity and inserts the batch, takes the room in the section and records the receipt in the stock
+// ledger in the same transaction
 func (r *ProductBatchDB) Save(batch *mod.ProductBatch) (err error) {
-	result, err := r.db.Exec("INSERT INTO `product_batches` (`batch_number`,`current_quantity`,`initial_quantity`,`current_temperature`, `minimum_temperature`, `due_date`, `manufacturing_date`, `manufacturing_hour`, `product_id`, `section_id`) VALUES(?,?,?,?,?,?,?,?,?,?)",
+	tx, err := r.db.Begin()
+	if err != nil {
+		return e.ErrQueryError
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			err = e.ErrQueryError
+		}
+	}()
+
+	var current, maximum, sectionType, productType int
+	err = tx.QueryRow("SELECT `current_capacity`, `maximum_capacity`, `product_type_id` FROM `sections` WHERE `id` = ? FOR UPDATE", (*batch).SectionId).
+		Scan(&current, &maximum, &sectionType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return e.ErrForeignKeyError
+	}
+	if err != nil {
+		return e.ErrQueryError
+	}
+	err = tx.QueryRow("SELECT `product_type_id` FROM `products` WHERE `id` = ?", (*batch).ProductId).Scan(&productType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return e.ErrForeignKeyError
+	}
+	if err != nil {
+		return e.ErrQueryError
+	}
+	if productType != sectionType {
+		return e.ErrProductBatchSectionType
+	}
+	if current+(*batch).CurrentQuantity > maximum {
+		return fmt.Errorf("%w: %d units left", e.ErrSectionCapacityExceeded, max(maximum-current, 0))
+	}
+
+	result, err := tx.Exec("INSERT INTO `product_batches` (`batch_number`,`current_quantity`,`initial_quantity`,`current_temperature`, `minimum_temperature`, `due_date`, `manufacturing_date`, `manufacturing_hour`, `product_id`, `section_id`) VALUES(?,?,?,?,?,?,?,?,?,?)",
 		(*batch).BatchNumber, (*batch).CurrentQuantity, (*batch).InitialQuantity, (*batch).CurrentTemperature, (*batch).MinimumTemperature, (*batch).DueDate, (*batch).ManufacturingDate, (*batch).ManufacturingHour, (*batch).ProductId, (*batch).SectionId)
 	if err != nil {
 		var mySQLErr *mysql.MySQLError
@@ -54,13 +171,300 @@ func (r *ProductBatchDB) Save(batch *mod.ProductBatch) (err error) {
 				return e.ErrProductBatchDuplicated
 			}
 		}
-		return
+		return e.ErrInsertError
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return e.ErrProductBatchNotFound
+		return e.ErrInsertError
 	}
 	(*batch).ID = int(id)
-	return
+	(*batch).Status = mod.BatchAvailable
+
+	if _, err = tx.Exec("UPDATE `sections` SET `current_capacity` = `current_capacity` + ? WHERE `id` = ?", (*batch).CurrentQuantity, (*batch).SectionId); err != nil {
+		return e.ErrQueryError
+	}
+	if err = snapshotSections(tx, time.Now().UTC(), (*batch).SectionId); err != nil {
+		return err
+	}
+	receipt := mod.StockMovement{Type: mod.MovementReceipt, ProductBatchID: (*batch).ID, SectionID: (*batch).SectionId, Quantity: (*batch).CurrentQuantity}
+	return insertMovement(tx, &receipt)
+}
+
+// Update locks the batch and applies the patch, a change of the current quantity takes or releases the room in
+// the section and is recorded as an adjustment in the stock ledger in the same transaction
+func (r *ProductBatchDB) Update(id int, patch mod.ProductBatchPatch) (batch mod.ProductBatch, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return mod.ProductBatch{}, e.ErrQueryError
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			batch, err = mod.ProductBatch{}, e.ErrQueryError
+		}
+	}()
+
+	batch, err = lockProductBatch(tx, id)
+	if err != nil {
+		return mod.ProductBatch{}, err
+	}
+	if batch.ArchivedAt != nil {
+		return mod.ProductBatch{}, e.ErrProductBatchArchived
+	}
+
+	if patch.CurrentTemperature != nil {
+		batch.CurrentTemperature = *patch.CurrentTemperature
+	}
+	delta := 0
+	if patch.CurrentQuantity != nil {
+		delta = *patch.CurrentQuantity - batch.CurrentQuantity
+		batch.CurrentQuantity = *patch.CurrentQuantity
+	}
+	if delta > 0 {
+		var current, maximum int
+		if err = tx.QueryRow("SELECT `current_capacity`, `maximum_capacity` FROM `sections` WHERE `id` = ? FOR UPDATE", batch.SectionId).
+			Scan(&current, &maximum); err != nil {
+			return mod.ProductBatch{}, e.ErrQueryError
+		}
+		if current+delta > maximum {
+			return mod.ProductBatch{}, fmt.Errorf("%w: %d units left", e.ErrSectionCapacityExceeded, max(maximum-current, 0))
+		}
+	}
+
+	if _, err = tx.Exec("UPDATE `product_batches` SET `current_quantity` = ?, `current_temperature` = ? WHERE `id` = ?",
+		batch.CurrentQuantity, batch.CurrentTemperature, id); err != nil {
+		return mod.ProductBatch{}, e.ErrQueryError
+	}
+	if delta == 0 {
+		return batch, nil
+	}
+	if _, err = tx.Exec("UPDATE `sections` SET `current_capacity` = `current_capacity` + ? WHERE `id` = ?", delta, batch.SectionId); err != nil {
+		return mod.ProductBatch{}, e.ErrQueryError
+	}
+	if err = snapshotSections(tx, time.Now().UTC(), batch.SectionId); err != nil {
+		return mod.ProductBatch{}, err
+	}
+	adjustment := mod.StockMovement{Type: mod.MovementAdjustment, ProductBatchID: id, SectionID: batch.SectionId, Quantity: delta, Reason: "batch updated"}
+	if err = insertMovement(tx, &adjustment); err != nil {
+		return mod.ProductBatch{}, err
+	}
+	return batch, nil
+}
+
+// Archive locks the batch, writes off its remaining units, releasing them from the section, and marks it archived
+// at the given time
+func (r *ProductBatchDB) Archive(id int, at time.Time) (batch mod.ProductBatch, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return mod.ProductBatch{}, e.ErrQueryError
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			batch, err = mod.ProductBatch{}, e.ErrQueryError
+		}
+	}()
+
+	batch, err = lockProductBatch(tx, id)
+	if err != nil {
+		return mod.ProductBatch{}, err
+	}
+	if batch.ArchivedAt != nil {
+		return mod.ProductBatch{}, e.ErrProductBatchArchived
+	}
+
+	if _, err = tx.Exec("UPDATE `product_batches` SET `current_quantity` = 0, `archived_at` = ? WHERE `id` = ?", at, id); err != nil {
+		return mod.ProductBatch{}, e.ErrQueryError
+	}
+	remaining := batch.CurrentQuantity
+	batch.CurrentQuantity, batch.ArchivedAt = 0, &at
+	if remaining == 0 {
+		return batch, nil
+	}
+	if _, err = tx.Exec("UPDATE `sections` SET `current_capacity` = `current_capacity` - ? WHERE `id` = ?", remaining, batch.SectionId); err != nil {
+		return mod.ProductBatch{}, e.ErrQueryError
+	}
+	if err = snapshotSections(tx, at, batch.SectionId); err != nil {
+		return mod.ProductBatch{}, err
+	}
+	writeOff := mod.StockMovement{Type: mod.MovementWriteOff, ProductBatchID: id, SectionID: batch.SectionId, Quantity: -remaining, Reason: "batch archived"}
+	if err = insertMovement(tx, &writeOff); err != nil {
+		return mod.ProductBatch{}, err
+	}
+	return batch, nil
+}
+
+// Delete locks the batch and deletes it, releasing its units from the section. A batch referenced by an inbound
+// order, a transfer, an order allocation or a status change cannot be deleted, once archived it is retired instead:
+// it keeps its row for the references and disappears from the reads. The ledger is append-only, so a batch with
+// stock movements is written off and retired the same way instead of being deleted
+func (r *ProductBatchDB) Delete(id int) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return e.ErrQueryError
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			err = e.ErrQueryError
+		}
+	}()
+
+	batch, err := lockProductBatch(tx, id)
+	if err != nil {
+		return err
+	}
+	var referenced bool
+	if err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM `inbound_orders` WHERE `product_batch_id` = ?) "+
+		"OR EXISTS(SELECT 1 FROM `transfers` WHERE `product_batch_id` = ? OR `destination_batch_id` = ?) "+
+		"OR EXISTS(SELECT 1 FROM `order_detail_allocations` WHERE `product_batch_id` = ?) "+
+		"OR EXISTS(SELECT 1 FROM `product_batch_status_changes` WHERE `product_batch_id` = ?)", id, id, id, id, id).Scan(&referenced); err != nil {
+		return e.ErrQueryError
+	}
+	if referenced {
+		if batch.ArchivedAt == nil {
+			return e.ErrProductBatchInUse
+		}
+		if _, err = tx.Exec("UPDATE `product_batches` SET `retired_at` = UTC_TIMESTAMP(3) WHERE `id` = ?", id); err != nil {
+			return e.ErrQueryError
+		}
+		return nil
+	}
+
+	var moved bool
+	if err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM `stock_movements` WHERE `product_batch_id` = ?)", id).Scan(&moved); err != nil {
+		return e.ErrQueryError
+	}
+	if moved {
+		if _, err = tx.Exec("UPDATE `product_batches` SET `current_quantity` = 0, `archived_at` = COALESCE(`archived_at`, UTC_TIMESTAMP(3)), "+
+			"`retired_at` = UTC_TIMESTAMP(3) WHERE `id` = ?", id); err != nil {
+			return e.ErrQueryError
+		}
+		if batch.CurrentQuantity == 0 {
+			return nil
+		}
+		if _, err = tx.Exec("UPDATE `sections` SET `current_capacity` = `current_capacity` - ? WHERE `id` = ?", batch.CurrentQuantity, batch.SectionId); err != nil {
+			return e.ErrQueryError
+		}
+		if err = snapshotSections(tx, time.Now().UTC(), batch.SectionId); err != nil {
+			return err
+		}
+		writeOff := mod.StockMovement{Type: mod.MovementWriteOff, ProductBatchID: id, SectionID: batch.SectionId, Quantity: -batch.CurrentQuantity, Reason: "batch deleted"}
+		return insertMovement(tx, &writeOff)
+	}
+
+	if _, err = tx.Exec("DELETE FROM `product_batches` WHERE `id` = ?", id); err != nil {
+		var mySQLErr *mysql.MySQLError
+		if errors.As(err, &mySQLErr) && mySQLErr.Number == 1451 {
+			return e.ErrProductBatchInUse
+		}
+		return e.ErrQueryError
+	}
+	if _, err = tx.Exec("UPDATE `sections` SET `current_capacity` = `current_capacity` - ? WHERE `id` = ?", batch.CurrentQuantity, batch.SectionId); err != nil {
+		return e.ErrQueryError
+	}
+	return snapshotSections(tx, time.Now().UTC(), batch.SectionId)
+}
+
+// ChangeStatus locks the batch, checks that its current status is one of from and that the employee exists, moves it
+// to the status of the change and records the change in the same transaction
+func (r *ProductBatchDB) ChangeStatus(change *mod.BatchStatusChange, from ...string) (batch mod.ProductBatch, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return mod.ProductBatch{}, e.ErrQueryError
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			batch, err = mod.ProductBatch{}, e.ErrQueryError
+		}
+	}()
+
+	batch, err = lockProductBatch(tx, change.ProductBatchID)
+	if err != nil {
+		return mod.ProductBatch{}, err
+	}
+	if batch.ArchivedAt != nil {
+		return mod.ProductBatch{}, e.ErrProductBatchArchived
+	}
+	if !slices.Contains(from, batch.Status) {
+		return mod.ProductBatch{}, fmt.Errorf("%w: %s to %s", e.ErrProductBatchStatus, batch.Status, change.ToStatus)
+	}
+
+	var employee bool
+	if err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM `employees` WHERE `id` = ?)", change.EmployeeID).Scan(&employee); err != nil {
+		return mod.ProductBatch{}, e.ErrQueryError
+	}
+	if !employee {
+		return mod.ProductBatch{}, e.ErrEmployeeRepositoryNotFound
+	}
+
+	if _, err = tx.Exec("UPDATE `product_batches` SET `status` = ? WHERE `id` = ?", change.ToStatus, change.ProductBatchID); err != nil {
+		return mod.ProductBatch{}, e.ErrQueryError
+	}
+	change.FromStatus = batch.Status
+	if err = insertStatusChange(tx, change); err != nil {
+		return mod.ProductBatch{}, err
+	}
+	batch.Status = change.ToStatus
+	return batch, nil
+}
+
+// insertStatusChange records a change of the status of a batch, the repositories that change the status call it in
+// the same transaction
+func insertStatusChange(tx *sql.Tx, change *mod.BatchStatusChange) error {
+	res, err := tx.Exec("INSERT INTO `product_batch_status_changes` (`product_batch_id`, `from_status`, `to_status`, `reason`, `employee_id`, `created_at`) "+
+		"VALUES (?, ?, ?, ?, ?, ?)", change.ProductBatchID, change.FromStatus, change.ToStatus, change.Reason, change.EmployeeID, change.CreatedAt)
+	if err != nil {
+		return e.ErrInsertError
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return e.ErrInsertError
+	}
+	change.ID = int(id)
+	return nil
+}
+
+// FindStatusHistory returns the status changes of a batch that was not retired, oldest first
+func (r *ProductBatchDB) FindStatusHistory(id int) ([]mod.BatchStatusChange, error) {
+	var exists bool
+	if err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM `product_batches` WHERE `id` = ? AND `retired_at` IS NULL)", id).Scan(&exists); err != nil {
+		return nil, e.ErrQueryError
+	}
+	if !exists {
+		return nil, e.ErrProductBatchNotFound
+	}
+
+	rows, err := r.db.Query("SELECT `id`, `product_batch_id`, `from_status`, `to_status`, `reason`, `employee_id`, `created_at` "+
+		"FROM `product_batch_status_changes` WHERE `product_batch_id` = ? ORDER BY `id`", id)
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	changes := []mod.BatchStatusChange{}
+	for rows.Next() {
+		var c mod.BatchStatusChange
+		if err = rows.Scan(&c.ID, &c.ProductBatchID, &c.FromStatus, &c.ToStatus, &c.Reason, &c.EmployeeID, &c.CreatedAt); err != nil {
+			return nil, e.ErrParseError
+		}
+		changes = append(changes, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return changes, nil
 }
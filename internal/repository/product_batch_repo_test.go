@@ -1,17 +1,27 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
 	m "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/require"
 
 	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
 )
 
+var (
+	batchSectionLock = regexp.QuoteMeta("SELECT `current_capacity`, `maximum_capacity`, `product_type_id` FROM `sections` WHERE `id` = ? FOR UPDATE")
+	batchProductType = regexp.QuoteMeta("SELECT `product_type_id` FROM `products` WHERE `id` = ?")
+	batchSectionTake = regexp.QuoteMeta("UPDATE `sections` SET `current_capacity` = `current_capacity` + ? WHERE `id` = ?")
+)
+
 // Helper
 func setupMockProductBatchRepo(t *testing.T) (*ProductBatchDB, sqlmock.Sqlmock, func()) {
 	db, mock, err := sqlmock.New()
@@ -22,8 +32,10 @@ func setupMockProductBatchRepo(t *testing.T) (*ProductBatchDB, sqlmock.Sqlmock,
 
 // -- FIND ALL
 func TestProductBatchDB_FindAll(t *testing.T) {
+	archived, archivedAt := true, time.Date(2024, 7, 6, 0, 0, 0, 0, time.UTC)
 	tests := []struct {
 		name        string
+		filter      mod.ProductBatchFilter
 		mockQuery   func(mock sqlmock.Sqlmock)
 		expected    []mod.ProductBatch
 		expectedErr error
@@ -48,6 +60,7 @@ func TestProductBatchDB_FindAll(t *testing.T) {
 					ManufacturingHour:  "08:00:00",
 					ProductId:          1,
 					SectionId:          1,
+					Status:             mod.BatchAvailable,
 				},
 				{
 					ID:                 2,
@@ -61,10 +74,47 @@ func TestProductBatchDB_FindAll(t *testing.T) {
 					ManufacturingHour:  "09:30:00",
 					ProductId:          2,
 					SectionId:          2,
+					Status:             mod.BatchAvailable,
 				},
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "Filters by warehouse, product, due range and archived",
+			filter: mod.ProductBatchFilter{
+				ProductID:   1,
+				WarehouseID: 2,
+				DueFrom:     time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+				DueTo:       time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+				Archived:    &archived,
+			},
+			mockQuery: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(m.ProductBatchTableStruct).
+					AddRow(1, 1, 0, 200, 2, -5, time.Date(2024, 07, 05, 17, 00, 00, 0, time.UTC), time.Date(2024, 06, 1, 0, 00, 00, 0, time.UTC), "08:00:00", 1, 1, archivedAt, nil, mod.BatchAvailable)
+				mock.ExpectQuery(m.ProductBatchSelectExpectedQuery+regexp.QuoteMeta(" INNER JOIN `sections` AS s ON s.`id` = pb.`section_id` "+
+					"WHERE pb.`retired_at` IS NULL AND s.`warehouse_id` = ? AND pb.`product_id` = ? AND pb.`due_date` >= ? AND pb.`due_date` < ? "+
+					"AND pb.`archived_at` IS NOT NULL ORDER BY pb.`id`")).
+					WithArgs(2, 1, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)).
+					WillReturnRows(rows)
+			},
+			expected: []mod.ProductBatch{
+				{
+					ID:                 1,
+					BatchNumber:        1,
+					CurrentQuantity:    0,
+					InitialQuantity:    200,
+					CurrentTemperature: 2,
+					MinimumTemperature: -5,
+					DueDate:            time.Date(2024, 07, 05, 17, 00, 00, 0, time.UTC),
+					ManufacturingDate:  time.Date(2024, 06, 1, 0, 00, 00, 0, time.UTC),
+					ManufacturingHour:  "08:00:00",
+					ProductId:          1,
+					SectionId:          1,
+					ArchivedAt:         &archivedAt,
+					Status:             mod.BatchAvailable,
+				},
+			},
+		},
 		{
 			name: "Err empty DB",
 			mockQuery: func(mock sqlmock.Sqlmock) {
@@ -90,7 +140,7 @@ func TestProductBatchDB_FindAll(t *testing.T) {
 			defer teardown()
 			tc.mockQuery(mock)
 
-			batches, err := repo.FindAll()
+			batches, err := repo.FindAll(tc.filter)
 
 			if tc.expectedErr != nil {
 				require.Error(t, err)
@@ -133,11 +183,23 @@ func TestProductBatchDB_Save(t *testing.T) {
 		{
 			name: "HappyPath",
 			setup: func(mock sqlmock.Sqlmock, batch *mod.ProductBatch) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchSectionLock).WithArgs(batch.SectionId).
+					WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity", "product_type_id"}).AddRow(10, 20, 3))
+				mock.ExpectQuery(batchProductType).WithArgs(batch.ProductId).
+					WillReturnRows(sqlmock.NewRows([]string{"product_type_id"}).AddRow(3))
 				mock.ExpectExec("INSERT INTO `product_batches`").
 					WithArgs(batch.BatchNumber, batch.CurrentQuantity, batch.InitialQuantity,
 						batch.CurrentTemperature, batch.MinimumTemperature, batch.DueDate,
 						batch.ManufacturingDate, batch.ManufacturingHour, batch.ProductId, batch.SectionId).
 					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(batchSectionTake).WithArgs(batch.CurrentQuantity, batch.SectionId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(sectionSnapshotInsert).WithArgs(mod.SnapshotUpdate, sqlmock.AnyArg(), batch.SectionId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(stockMovementInsert).WithArgs(mod.MovementReceipt, 1, batch.SectionId, batch.CurrentQuantity, "", 0, 0, "").
+					WillReturnResult(sqlmock.NewResult(5, 1))
+				mock.ExpectCommit()
 			},
 			wantID:  1,
 			wantErr: nil,
@@ -145,11 +207,17 @@ func TestProductBatchDB_Save(t *testing.T) {
 		{
 			name: "ErrDuplicated",
 			setup: func(mock sqlmock.Sqlmock, batch *mod.ProductBatch) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchSectionLock).WithArgs(batch.SectionId).
+					WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity", "product_type_id"}).AddRow(10, 20, 3))
+				mock.ExpectQuery(batchProductType).WithArgs(batch.ProductId).
+					WillReturnRows(sqlmock.NewRows([]string{"product_type_id"}).AddRow(3))
 				mock.ExpectExec("INSERT INTO `product_batches`").
 					WithArgs(batch.BatchNumber, batch.CurrentQuantity, batch.InitialQuantity,
 						batch.CurrentTemperature, batch.MinimumTemperature, batch.DueDate,
 						batch.ManufacturingDate, batch.ManufacturingHour, batch.ProductId, batch.SectionId).
 					WillReturnError(e.DupErr)
+				mock.ExpectRollback()
 			},
 			wantID:  0,
 			wantErr: e.ErrProductBatchDuplicated,
@@ -157,27 +225,94 @@ func TestProductBatchDB_Save(t *testing.T) {
 		{
 			name: "ErrForeignKey",
 			setup: func(mock sqlmock.Sqlmock, batch *mod.ProductBatch) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchSectionLock).WithArgs(batch.SectionId).
+					WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity", "product_type_id"}).AddRow(10, 20, 3))
+				mock.ExpectQuery(batchProductType).WithArgs(batch.ProductId).
+					WillReturnRows(sqlmock.NewRows([]string{"product_type_id"}).AddRow(3))
 				mock.ExpectExec("INSERT INTO `product_batches`").
 					WithArgs(batch.BatchNumber, batch.CurrentQuantity, batch.InitialQuantity,
 						batch.CurrentTemperature, batch.MinimumTemperature, batch.DueDate,
 						batch.ManufacturingDate, batch.ManufacturingHour, batch.ProductId, batch.SectionId).
 					WillReturnError(e.FkErr)
+				mock.ExpectRollback()
 			},
 			wantID:  0,
 			wantErr: e.ErrForeignKeyError,
 		},
 		{
-			name: "ErrNotFound (LastInsertId error)",
+			name: "ErrInsert (driver error)",
+			setup: func(mock sqlmock.Sqlmock, batch *mod.ProductBatch) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchSectionLock).WithArgs(batch.SectionId).
+					WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity", "product_type_id"}).AddRow(10, 20, 3))
+				mock.ExpectQuery(batchProductType).WithArgs(batch.ProductId).
+					WillReturnRows(sqlmock.NewRows([]string{"product_type_id"}).AddRow(3))
+				mock.ExpectExec("INSERT INTO `product_batches`").
+					WithArgs(batch.BatchNumber, batch.CurrentQuantity, batch.InitialQuantity,
+						batch.CurrentTemperature, batch.MinimumTemperature, batch.DueDate,
+						batch.ManufacturingDate, batch.ManufacturingHour, batch.ProductId, batch.SectionId).
+					WillReturnError(errors.New("connection reset"))
+				mock.ExpectRollback()
+			},
+			wantID:  0,
+			wantErr: e.ErrInsertError,
+		},
+		{
+			name: "ErrInsert (LastInsertId error)",
 			setup: func(mock sqlmock.Sqlmock, batch *mod.ProductBatch) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchSectionLock).WithArgs(batch.SectionId).
+					WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity", "product_type_id"}).AddRow(10, 20, 3))
+				mock.ExpectQuery(batchProductType).WithArgs(batch.ProductId).
+					WillReturnRows(sqlmock.NewRows([]string{"product_type_id"}).AddRow(3))
 				// FakeResult implements RowsAffected/LastInsertId simulating failure
 				mock.ExpectExec("INSERT INTO `product_batches`").
 					WithArgs(batch.BatchNumber, batch.CurrentQuantity, batch.InitialQuantity,
 						batch.CurrentTemperature, batch.MinimumTemperature, batch.DueDate,
 						batch.ManufacturingDate, batch.ManufacturingHour, batch.ProductId, batch.SectionId).
 					WillReturnResult(e.FakeResult{})
+				mock.ExpectRollback()
 			},
 			wantID:  0,
-			wantErr: e.ErrProductBatchNotFound,
+			wantErr: e.ErrInsertError,
+		},
+		{
+			name: "ErrCapacityExceeded",
+			setup: func(mock sqlmock.Sqlmock, batch *mod.ProductBatch) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchSectionLock).WithArgs(batch.SectionId).
+					WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity", "product_type_id"}).AddRow(19, 20, 3))
+				mock.ExpectQuery(batchProductType).WithArgs(batch.ProductId).
+					WillReturnRows(sqlmock.NewRows([]string{"product_type_id"}).AddRow(3))
+				mock.ExpectRollback()
+			},
+			wantID:  0,
+			wantErr: fmt.Errorf("%w: 1 units left", e.ErrSectionCapacityExceeded),
+		},
+		{
+			name: "ErrSectionMissing",
+			setup: func(mock sqlmock.Sqlmock, batch *mod.ProductBatch) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchSectionLock).WithArgs(batch.SectionId).
+					WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity", "product_type_id"}))
+				mock.ExpectRollback()
+			},
+			wantID:  0,
+			wantErr: e.ErrForeignKeyError,
+		},
+		{
+			name: "ErrSectionStoresAnotherType",
+			setup: func(mock sqlmock.Sqlmock, batch *mod.ProductBatch) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchSectionLock).WithArgs(batch.SectionId).
+					WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity", "product_type_id"}).AddRow(0, 20, 3))
+				mock.ExpectQuery(batchProductType).WithArgs(batch.ProductId).
+					WillReturnRows(sqlmock.NewRows([]string{"product_type_id"}).AddRow(4))
+				mock.ExpectRollback()
+			},
+			wantID:  0,
+			wantErr: e.ErrProductBatchSectionType,
 		},
 	}
 
@@ -200,3 +335,422 @@ func TestProductBatchDB_Save(t *testing.T) {
 		})
 	}
 }
+
+// -- FIND BY ID
+func TestProductBatchDB_FindByID(t *testing.T) {
+	query := m.ProductBatchSelectExpectedQuery + regexp.QuoteMeta(" WHERE pb.`id` = ? AND pb.`retired_at` IS NULL")
+
+	t.Run("HappyPath", func(t *testing.T) {
+		repo, mock, teardown := setupMockProductBatchRepo(t)
+		defer teardown()
+		mock.ExpectQuery(query).WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(m.ProductBatchTableStruct).AddRow(m.ProductBatchDataValuesSelect[1]...))
+
+		batch, err := repo.FindByID(2)
+		require.NoError(t, err)
+		require.Equal(t, 2, batch.ID)
+		require.Equal(t, 310, batch.CurrentQuantity)
+		require.Nil(t, batch.ArchivedAt)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ErrNotFound", func(t *testing.T) {
+		repo, mock, teardown := setupMockProductBatchRepo(t)
+		defer teardown()
+		mock.ExpectQuery(query).WithArgs(9).WillReturnRows(sqlmock.NewRows(m.ProductBatchTableStruct))
+
+		_, err := repo.FindByID(9)
+		require.ErrorIs(t, err, e.ErrProductBatchNotFound)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// batchLockRow returns the locked available batch 1 of section 3 with the given current quantity
+func batchLockRow(current int, archivedAt any) *sqlmock.Rows {
+	return batchStatusRow(current, archivedAt, mod.BatchAvailable)
+}
+
+// batchStatusRow returns the locked batch 1 of section 3 with the given current quantity and status
+func batchStatusRow(current int, archivedAt any, status string) *sqlmock.Rows {
+	return sqlmock.NewRows(m.ProductBatchTableStruct).
+		AddRow(1, 1, current, 10, 2, -5, time.Date(2024, 07, 05, 17, 00, 00, 0, time.UTC), time.Date(2024, 06, 1, 0, 00, 00, 0, time.UTC), "08:00:00", 1, 3, archivedAt, nil, status)
+}
+
+var batchLock = m.ProductBatchSelectExpectedQuery + regexp.QuoteMeta(" WHERE pb.`id` = ? AND pb.`retired_at` IS NULL FOR UPDATE")
+
+// -- UPDATE
+func TestProductBatchDB_Update(t *testing.T) {
+	batchUpdate := regexp.QuoteMeta("UPDATE `product_batches` SET `current_quantity` = ?, `current_temperature` = ? WHERE `id` = ?")
+	sectionLock := regexp.QuoteMeta("SELECT `current_capacity`, `maximum_capacity` FROM `sections` WHERE `id` = ? FOR UPDATE")
+	sectionMove := regexp.QuoteMeta("UPDATE `sections` SET `current_capacity` = `current_capacity` + ? WHERE `id` = ?")
+	intPtr := func(n int) *int { return &n }
+
+	tests := []struct {
+		name    string
+		patch   mod.ProductBatchPatch
+		setup   func(sqlmock.Sqlmock)
+		wantQty int
+		wantErr error
+	}{
+		{
+			name:  "Temperature only does not touch the section",
+			patch: mod.ProductBatchPatch{CurrentTemperature: intPtr(0)},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(12, nil))
+				mock.ExpectExec(batchUpdate).WithArgs(12, 0, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			wantQty: 12,
+		},
+		{
+			name:  "More units take room in the section and are recorded",
+			patch: mod.ProductBatchPatch{CurrentQuantity: intPtr(15)},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(12, nil))
+				mock.ExpectQuery(sectionLock).WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity"}).AddRow(40, 50))
+				mock.ExpectExec(batchUpdate).WithArgs(15, 2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(sectionMove).WithArgs(3, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(sectionSnapshotInsert).WithArgs(mod.SnapshotUpdate, sqlmock.AnyArg(), 3).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(stockMovementInsert).WithArgs(mod.MovementAdjustment, 1, 3, 3, "", 0, 0, "batch updated").
+					WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectCommit()
+			},
+			wantQty: 15,
+		},
+		{
+			name:  "ErrCapacityExceeded",
+			patch: mod.ProductBatchPatch{CurrentQuantity: intPtr(20)},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(12, nil))
+				mock.ExpectQuery(sectionLock).WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity"}).AddRow(45, 50))
+				mock.ExpectRollback()
+			},
+			wantErr: e.ErrSectionCapacityExceeded,
+		},
+		{
+			name:  "ErrArchived",
+			patch: mod.ProductBatchPatch{CurrentQuantity: intPtr(15)},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(0, time.Date(2024, 7, 6, 0, 0, 0, 0, time.UTC)))
+				mock.ExpectRollback()
+			},
+			wantErr: e.ErrProductBatchArchived,
+		},
+		{
+			name:  "ErrNotFound",
+			patch: mod.ProductBatchPatch{CurrentQuantity: intPtr(15)},
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(sqlmock.NewRows(m.ProductBatchTableStruct))
+				mock.ExpectRollback()
+			},
+			wantErr: e.ErrProductBatchNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, teardown := setupMockProductBatchRepo(t)
+			defer teardown()
+			tc.setup(mock)
+
+			batch, err := repo.Update(1, tc.patch)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tc.wantQty, batch.CurrentQuantity)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// -- ARCHIVE
+func TestProductBatchDB_Archive(t *testing.T) {
+	at := time.Date(2024, 7, 6, 10, 0, 0, 0, time.UTC)
+	batchArchive := regexp.QuoteMeta("UPDATE `product_batches` SET `current_quantity` = 0, `archived_at` = ? WHERE `id` = ?")
+
+	t.Run("HappyPath writes off the remaining units", func(t *testing.T) {
+		repo, mock, teardown := setupMockProductBatchRepo(t)
+		defer teardown()
+		mock.ExpectBegin()
+		mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(8, nil))
+		mock.ExpectExec(batchArchive).WithArgs(at, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `sections` SET `current_capacity` = `current_capacity` - ? WHERE `id` = ?")).
+			WithArgs(8, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(sectionSnapshotInsert).WithArgs(mod.SnapshotUpdate, at, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(stockMovementInsert).WithArgs(mod.MovementWriteOff, 1, 3, -8, "", 0, 0, "batch archived").
+			WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectCommit()
+
+		batch, err := repo.Archive(1, at)
+		require.NoError(t, err)
+		require.Equal(t, 0, batch.CurrentQuantity)
+		require.Equal(t, &at, batch.ArchivedAt)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Empty batch has nothing to write off", func(t *testing.T) {
+		repo, mock, teardown := setupMockProductBatchRepo(t)
+		defer teardown()
+		mock.ExpectBegin()
+		mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(0, nil))
+		mock.ExpectExec(batchArchive).WithArgs(at, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		_, err := repo.Archive(1, at)
+		require.NoError(t, err)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ErrArchived", func(t *testing.T) {
+		repo, mock, teardown := setupMockProductBatchRepo(t)
+		defer teardown()
+		mock.ExpectBegin()
+		mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(0, at))
+		mock.ExpectRollback()
+
+		_, err := repo.Archive(1, at)
+		require.ErrorIs(t, err, e.ErrProductBatchArchived)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// -- DELETE
+func TestProductBatchDB_Delete(t *testing.T) {
+	references := regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM `inbound_orders` WHERE `product_batch_id` = ?) " +
+		"OR EXISTS(SELECT 1 FROM `transfers` WHERE `product_batch_id` = ? OR `destination_batch_id` = ?) " +
+		"OR EXISTS(SELECT 1 FROM `order_detail_allocations` WHERE `product_batch_id` = ?) " +
+		"OR EXISTS(SELECT 1 FROM `product_batch_status_changes` WHERE `product_batch_id` = ?)")
+	movements := regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM `stock_movements` WHERE `product_batch_id` = ?)")
+	batchRetire := regexp.QuoteMeta("UPDATE `product_batches` SET `current_quantity` = 0, `archived_at` = COALESCE(`archived_at`, UTC_TIMESTAMP(3)), " +
+		"`retired_at` = UTC_TIMESTAMP(3) WHERE `id` = ?")
+	batchDelete := regexp.QuoteMeta("DELETE FROM `product_batches` WHERE `id` = ?")
+	capacityRelease := regexp.QuoteMeta("UPDATE `sections` SET `current_capacity` = `current_capacity` - ? WHERE `id` = ?")
+	referenced := func(found bool) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"referenced"}).AddRow(found)
+	}
+	moved := func(found bool) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"moved"}).AddRow(found)
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name: "HappyPath releases the units from the section",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(8, nil))
+				mock.ExpectQuery(references).WithArgs(1, 1, 1, 1, 1).WillReturnRows(referenced(false))
+				mock.ExpectQuery(movements).WithArgs(1).WillReturnRows(moved(false))
+				mock.ExpectExec(batchDelete).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(capacityRelease).WithArgs(8, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(sectionSnapshotInsert).WithArgs(mod.SnapshotUpdate, sqlmock.AnyArg(), 3).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "With movements is written off and retired",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(8, nil))
+				mock.ExpectQuery(references).WithArgs(1, 1, 1, 1, 1).WillReturnRows(referenced(false))
+				mock.ExpectQuery(movements).WithArgs(1).WillReturnRows(moved(true))
+				mock.ExpectExec(batchRetire).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(capacityRelease).WithArgs(8, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(sectionSnapshotInsert).WithArgs(mod.SnapshotUpdate, sqlmock.AnyArg(), 3).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(stockMovementInsert).WithArgs(mod.MovementWriteOff, 1, 3, -8, "", 0, 0, "batch deleted").
+					WillReturnResult(sqlmock.NewResult(9, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Archived and referenced is retired",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(0, time.Date(2024, 7, 6, 0, 0, 0, 0, time.UTC)))
+				mock.ExpectQuery(references).WithArgs(1, 1, 1, 1, 1).WillReturnRows(referenced(true))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_batches` SET `retired_at` = UTC_TIMESTAMP(3) WHERE `id` = ?")).
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ErrNotFound",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(sqlmock.NewRows(m.ProductBatchTableStruct))
+				mock.ExpectRollback()
+			},
+			wantErr: e.ErrProductBatchNotFound,
+		},
+		{
+			name: "ErrInUse when referenced and not archived",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(8, nil))
+				mock.ExpectQuery(references).WithArgs(1, 1, 1, 1, 1).WillReturnRows(referenced(true))
+				mock.ExpectRollback()
+			},
+			wantErr: e.ErrProductBatchInUse,
+		},
+		{
+			name: "ErrInUse on foreign key",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(8, nil))
+				mock.ExpectQuery(references).WithArgs(1, 1, 1, 1, 1).WillReturnRows(referenced(false))
+				mock.ExpectQuery(movements).WithArgs(1).WillReturnRows(moved(false))
+				mock.ExpectExec(batchDelete).WithArgs(1).WillReturnError(&mysql.MySQLError{Number: 1451})
+				mock.ExpectRollback()
+			},
+			wantErr: e.ErrProductBatchInUse,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, teardown := setupMockProductBatchRepo(t)
+			defer teardown()
+			tc.setup(mock)
+
+			err := repo.Delete(1)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// -- CHANGE STATUS
+func TestProductBatchDB_ChangeStatus(t *testing.T) {
+	at := time.Date(2024, 7, 6, 10, 0, 0, 0, time.UTC)
+	employeeExists := regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM `employees` WHERE `id` = ?)")
+	statusUpdate := regexp.QuoteMeta("UPDATE `product_batches` SET `status` = ? WHERE `id` = ?")
+	changeInsert := regexp.QuoteMeta("INSERT INTO `product_batch_status_changes` (`product_batch_id`, `from_status`, `to_status`, `reason`, `employee_id`, `created_at`) VALUES (?, ?, ?, ?, ?, ?)")
+	hold := func() *mod.BatchStatusChange {
+		return &mod.BatchStatusChange{ProductBatchID: 1, ToStatus: mod.BatchQuarantined, Reason: "arrived at 9°C", EmployeeID: 4, CreatedAt: at}
+	}
+
+	t.Run("HappyPath records the change", func(t *testing.T) {
+		repo, mock, teardown := setupMockProductBatchRepo(t)
+		defer teardown()
+		mock.ExpectBegin()
+		mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(8, nil))
+		mock.ExpectQuery(employeeExists).WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectExec(statusUpdate).WithArgs(mod.BatchQuarantined, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(changeInsert).WithArgs(1, mod.BatchAvailable, mod.BatchQuarantined, "arrived at 9°C", 4, at).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectCommit()
+
+		change := hold()
+		batch, err := repo.ChangeStatus(change, mod.BatchAvailable, mod.BatchReleased)
+		require.NoError(t, err)
+		require.Equal(t, mod.BatchQuarantined, batch.Status)
+		require.Equal(t, 3, change.ID)
+		require.Equal(t, mod.BatchAvailable, change.FromStatus)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	tests := []struct {
+		name    string
+		setup   func(sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name: "ErrStatus when the batch is not in a from status",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchStatusRow(8, nil, mod.BatchRejected))
+				mock.ExpectRollback()
+			},
+			wantErr: e.ErrProductBatchStatus,
+		},
+		{
+			name: "ErrArchived",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(0, at))
+				mock.ExpectRollback()
+			},
+			wantErr: e.ErrProductBatchArchived,
+		},
+		{
+			name: "ErrEmployeeNotFound",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(batchLockRow(8, nil))
+				mock.ExpectQuery(employeeExists).WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectRollback()
+			},
+			wantErr: e.ErrEmployeeRepositoryNotFound,
+		},
+		{
+			name: "ErrNotFound",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(batchLock).WithArgs(1).WillReturnRows(sqlmock.NewRows(m.ProductBatchTableStruct))
+				mock.ExpectRollback()
+			},
+			wantErr: e.ErrProductBatchNotFound,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, teardown := setupMockProductBatchRepo(t)
+			defer teardown()
+			tc.setup(mock)
+
+			_, err := repo.ChangeStatus(hold(), mod.BatchAvailable, mod.BatchReleased)
+			require.ErrorIs(t, err, tc.wantErr)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// -- STATUS HISTORY
+func TestProductBatchDB_FindStatusHistory(t *testing.T) {
+	at := time.Date(2024, 7, 6, 10, 0, 0, 0, time.UTC)
+	batchExists := regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM `product_batches` WHERE `id` = ? AND `retired_at` IS NULL)")
+	history := regexp.QuoteMeta("FROM `product_batch_status_changes` WHERE `product_batch_id` = ? ORDER BY `id`")
+
+	t.Run("HappyPath", func(t *testing.T) {
+		repo, mock, teardown := setupMockProductBatchRepo(t)
+		defer teardown()
+		mock.ExpectQuery(batchExists).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(history).WithArgs(1).WillReturnRows(
+			sqlmock.NewRows([]string{"id", "product_batch_id", "from_status", "to_status", "reason", "employee_id", "created_at"}).
+				AddRow(1, 1, mod.BatchAvailable, mod.BatchQuarantined, "seller reported contamination", 4, at).
+				AddRow(2, 1, mod.BatchQuarantined, mod.BatchReleased, "lab test negative", 5, at.Add(time.Hour)))
+
+		changes, err := repo.FindStatusHistory(1)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		require.Equal(t, mod.BatchReleased, changes[1].ToStatus)
+		require.Equal(t, 5, changes[1].EmployeeID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ErrNotFound", func(t *testing.T) {
+		repo, mock, teardown := setupMockProductBatchRepo(t)
+		defer teardown()
+		mock.ExpectQuery(batchExists).WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		_, err := repo.FindStatusHistory(9)
+		require.ErrorIs(t, err, e.ErrProductBatchNotFound)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
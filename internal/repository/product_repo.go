@@ -78,6 +78,9 @@ func (r *ProductDB) Save(product *mod.Product) (err error) {
 		if strings.Contains(err.Error(), "foreign key constraint fails") && strings.Contains(err.Error(), "products_ibfk_1") {
 			return e.ErrSellerRepositoryNotFound
 		}
+		if strings.Contains(err.Error(), "foreign key constraint fails") && strings.Contains(err.Error(), "fk_products_product_type") {
+			return e.ErrProductTypeNotFound
+		}
 		return
 	}
 	id, err := result.LastInsertId()
@@ -108,6 +111,9 @@ func (r *ProductDB) Update(product *mod.Product) (err error) {
 		if strings.Contains(err.Error(), "foreign key constraint fails") && strings.Contains(err.Error(), "products_ibfk_1") {
 			return e.ErrSellerRepositoryNotFound
 		}
+		if strings.Contains(err.Error(), "foreign key constraint fails") && strings.Contains(err.Error(), "fk_products_product_type") {
+			return e.ErrProductTypeNotFound
+		}
 		return
 	}
 	return
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewProductTypeRepo creates a new instance of the product type repository
+func NewProductTypeRepo(db *sql.DB) *ProductTypeDB {
+	return &ProductTypeDB{db: db}
+}
+
+// ProductTypeDB is the implementation of the product type registry, see docs/SQL/migrations/0008_product_types.sql
+type ProductTypeDB struct {
+	db *sql.DB
+}
+
+// FindAll returns all the product types ordered by id
+func (r *ProductTypeDB) FindAll() ([]mod.ProductType, error) {
+	rows, err := r.db.Query("SELECT `id`, `name`, `minimum_temperature`, `maximum_temperature`, `hazard_class` FROM `product_types` ORDER BY `id`")
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	types := []mod.ProductType{}
+	for rows.Next() {
+		pt := mod.ProductType{MinimumTemperature: new(float64), MaximumTemperature: new(float64)}
+		if err = rows.Scan(&pt.ID, &pt.Name, pt.MinimumTemperature, pt.MaximumTemperature, &pt.HazardClass); err != nil {
+			return nil, e.ErrParseError
+		}
+		types = append(types, pt)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return types, nil
+}
+
+// FindByID returns the product type with the given id
+func (r *ProductTypeDB) FindByID(id int) (mod.ProductType, error) {
+	pt := mod.ProductType{MinimumTemperature: new(float64), MaximumTemperature: new(float64)}
+	err := r.db.QueryRow("SELECT `id`, `name`, `minimum_temperature`, `maximum_temperature`, `hazard_class` FROM `product_types` WHERE `id` = ?", id).
+		Scan(&pt.ID, &pt.Name, pt.MinimumTemperature, pt.MaximumTemperature, &pt.HazardClass)
+	if errors.Is(err, sql.ErrNoRows) {
+		return mod.ProductType{}, e.ErrProductTypeNotFound
+	}
+	if err != nil {
+		return mod.ProductType{}, e.ErrQueryError
+	}
+	return pt, nil
+}
+
+// Save inserts the product type and fills its id
+func (r *ProductTypeDB) Save(pt *mod.ProductType) error {
+	res, err := r.db.Exec("INSERT INTO `product_types` (`name`, `minimum_temperature`, `maximum_temperature`, `hazard_class`) VALUES (?, ?, ?, ?)",
+		pt.Name, *pt.MinimumTemperature, *pt.MaximumTemperature, pt.HazardClass)
+	if err != nil {
+		var mySQLErr *mysql.MySQLError
+		if errors.As(err, &mySQLErr) && mySQLErr.Number == 1062 {
+			return e.ErrProductTypeDuplicated
+		}
+		return e.ErrInsertError
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return e.ErrInsertError
+	}
+	pt.ID = int(id)
+	return nil
+}
+
+// Update replaces the fields of the product type with the given id
+func (r *ProductTypeDB) Update(pt *mod.ProductType) error {
+	res, err := r.db.Exec("UPDATE `product_types` SET `name` = ?, `minimum_temperature` = ?, `maximum_temperature` = ?, `hazard_class` = ? WHERE `id` = ?",
+		pt.Name, *pt.MinimumTemperature, *pt.MaximumTemperature, pt.HazardClass, pt.ID)
+	if err != nil {
+		var mySQLErr *mysql.MySQLError
+		if errors.As(err, &mySQLErr) && mySQLErr.Number == 1062 {
+			return e.ErrProductTypeDuplicated
+		}
+		return e.ErrQueryError
+	}
+	// an update that changes nothing affects no rows, only a missing type is an error
+	if n, _ := res.RowsAffected(); n == 0 {
+		if _, err = r.FindByID(pt.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete deletes the product type, the foreign keys of products and sections keep it while it is used
+func (r *ProductTypeDB) Delete(id int) error {
+	res, err := r.db.Exec("DELETE FROM `product_types` WHERE `id` = ?", id)
+	if err != nil {
+		var mySQLErr *mysql.MySQLError
+		if errors.As(err, &mySQLErr) && mySQLErr.Number == 1451 {
+			return e.ErrProductTypeInUse
+		}
+		return e.ErrQueryError
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return e.ErrProductTypeNotFound
+	}
+	return nil
+}
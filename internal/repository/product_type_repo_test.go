@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+var productTypeColumns = []string{"id", "name", "minimum_temperature", "maximum_temperature", "hazard_class"}
+
+func TestProductTypeDB_FindByID(t *testing.T) {
+	query := regexp.QuoteMeta("FROM `product_types` WHERE `id` = ?")
+
+	t.Run("found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectQuery(query).WithArgs(1).WillReturnRows(sqlmock.NewRows(productTypeColumns).AddRow(1, "Frozen", -18, -12, "none"))
+
+		pt, err := NewProductTypeRepo(db).FindByID(1)
+		require.NoError(t, err)
+		require.Equal(t, "Frozen", pt.Name)
+		require.Equal(t, -18.0, *pt.MinimumTemperature)
+		require.Equal(t, -12.0, *pt.MaximumTemperature)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectQuery(query).WithArgs(9).WillReturnRows(sqlmock.NewRows(productTypeColumns))
+
+		_, err = NewProductTypeRepo(db).FindByID(9)
+		require.ErrorIs(t, err, e.ErrProductTypeNotFound)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductTypeDB_Save(t *testing.T) {
+	insert := regexp.QuoteMeta("INSERT INTO `product_types` (`name`, `minimum_temperature`, `maximum_temperature`, `hazard_class`) VALUES (?, ?, ?, ?)")
+	minimum, maximum := 0.0, 4.0
+
+	t.Run("fills the id", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectExec(insert).WithArgs("Chilled", 0.0, 4.0, "none").WillReturnResult(sqlmock.NewResult(5, 1))
+
+		pt := mod.ProductType{Name: "Chilled", MinimumTemperature: &minimum, MaximumTemperature: &maximum, HazardClass: "none"}
+		require.NoError(t, NewProductTypeRepo(db).Save(&pt))
+		require.Equal(t, 5, pt.ID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("duplicated name", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectExec(insert).WillReturnError(&mysql.MySQLError{Number: 1062})
+
+		pt := mod.ProductType{Name: "Chilled", MinimumTemperature: &minimum, MaximumTemperature: &maximum, HazardClass: "none"}
+		require.ErrorIs(t, NewProductTypeRepo(db).Save(&pt), e.ErrProductTypeDuplicated)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestProductTypeDB_Delete(t *testing.T) {
+	del := regexp.QuoteMeta("DELETE FROM `product_types` WHERE `id` = ?")
+	tests := []struct {
+		name    string
+		setup   func(sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name:  "deleted",
+			setup: func(mock sqlmock.Sqlmock) { mock.ExpectExec(del).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1)) },
+		},
+		{
+			name:    "not found",
+			setup:   func(mock sqlmock.Sqlmock) { mock.ExpectExec(del).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0)) },
+			wantErr: e.ErrProductTypeNotFound,
+		},
+		{
+			name: "used by products or sections",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(del).WithArgs(1).WillReturnError(&mysql.MySQLError{Number: 1451})
+			},
+			wantErr: e.ErrProductTypeInUse,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			tc.setup(mock)
+
+			err = NewProductTypeRepo(db).Delete(1)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/internal/events"
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// NewPublishedSectionRepo wraps a section repository so its committed writes are published
+func NewPublishedSectionRepo(rp internal.SectionRepository, pub internal.EventPublisher) *PublishedSectionRepo {
+	return &PublishedSectionRepo{
+		SectionRepository: rp,
+		pub:               pub,
+	}
+}
+
+// PublishedSectionRepo publishes the created, updated and deleted sections on the sections topic
+type PublishedSectionRepo struct {
+	internal.SectionRepository
+	pub internal.EventPublisher
+}
+
+// Save saves the section and publishes it
+func (r *PublishedSectionRepo) Save(section *mod.Section) error {
+	if err := r.SectionRepository.Save(section); err != nil {
+		return err
+	}
+	r.pub.Publish(events.TopicSections, "section.created", *section)
+	return nil
+}
+
+// Update updates the section and publishes its new capacity and temperatures
+func (r *PublishedSectionRepo) Update(id int, fields map[string]interface{}) (*mod.Section, error) {
+	section, err := r.SectionRepository.Update(id, fields)
+	if err != nil {
+		return section, err
+	}
+	r.pub.Publish(events.TopicSections, "section.updated", *section)
+	return section, nil
+}
+
+// Delete deletes the section and publishes its id
+func (r *PublishedSectionRepo) Delete(id int) error {
+	if err := r.SectionRepository.Delete(id); err != nil {
+		return err
+	}
+	r.pub.Publish(events.TopicSections, "section.deleted", map[string]int{"id": id})
+	return nil
+}
+
+// publishSections reads the sections changed by a write that did not go through the section repository and
+// publishes them as updated. sections must read the primary without a cache, the write was just committed
+func publishSections(pub internal.EventPublisher, sections internal.SectionRepository, ids ...int) {
+	for _, id := range ids {
+		section, err := sections.FindByID(id)
+		if err != nil {
+			continue
+		}
+		pub.Publish(events.TopicSections, "section.updated", section)
+	}
+}
+
+// NewPublishedProductBatchRepo wraps a product batch repository so its committed writes and the sections they
+// change are published
+func NewPublishedProductBatchRepo(rp internal.ProductBatchRepository, sections internal.SectionRepository, pub internal.EventPublisher) *PublishedProductBatchRepo {
+	return &PublishedProductBatchRepo{
+		ProductBatchRepository: rp,
+		sections:               sections,
+		pub:                    pub,
+	}
+}
+
+// PublishedProductBatchRepo publishes the created, updated, archived and deleted product batches and their status
+// changes on the productBatches topic and the sections whose occupancy they changed on the sections topic
+type PublishedProductBatchRepo struct {
+	internal.ProductBatchRepository
+	sections internal.SectionRepository
+	pub      internal.EventPublisher
+}
+
+// Save saves the product batch and publishes it with its section, the batch took room in it
+func (r *PublishedProductBatchRepo) Save(batch *mod.ProductBatch) error {
+	if err := r.ProductBatchRepository.Save(batch); err != nil {
+		return err
+	}
+	r.pub.Publish(events.TopicProductBatches, "productBatch.created", *batch)
+	publishSections(r.pub, r.sections, batch.SectionId)
+	return nil
+}
+
+// Update updates the product batch and publishes it, with its section when the quantity changed
+func (r *PublishedProductBatchRepo) Update(id int, patch mod.ProductBatchPatch) (mod.ProductBatch, error) {
+	batch, err := r.ProductBatchRepository.Update(id, patch)
+	if err != nil {
+		return mod.ProductBatch{}, err
+	}
+	r.pub.Publish(events.TopicProductBatches, "productBatch.updated", batch)
+	if patch.CurrentQuantity != nil {
+		publishSections(r.pub, r.sections, batch.SectionId)
+	}
+	return batch, nil
+}
+
+// Archive archives the product batch and publishes it with its section, the written off units released room in it
+func (r *PublishedProductBatchRepo) Archive(id int, at time.Time) (mod.ProductBatch, error) {
+	batch, err := r.ProductBatchRepository.Archive(id, at)
+	if err != nil {
+		return mod.ProductBatch{}, err
+	}
+	r.pub.Publish(events.TopicProductBatches, "productBatch.archived", batch)
+	publishSections(r.pub, r.sections, batch.SectionId)
+	return batch, nil
+}
+
+// Delete deletes the product batch and publishes its id and its section, the section is read before the delete
+func (r *PublishedProductBatchRepo) Delete(id int) error {
+	batch, found := r.ProductBatchRepository.FindByID(id)
+	if err := r.ProductBatchRepository.Delete(id); err != nil {
+		return err
+	}
+	r.pub.Publish(events.TopicProductBatches, "productBatch.deleted", map[string]int{"id": id})
+	if found == nil {
+		publishSections(r.pub, r.sections, batch.SectionId)
+	}
+	return nil
+}
+
+// ChangeStatus changes the status of the product batch and publishes the recorded change
+func (r *PublishedProductBatchRepo) ChangeStatus(change *mod.BatchStatusChange, from ...string) (mod.ProductBatch, error) {
+	batch, err := r.ProductBatchRepository.ChangeStatus(change, from...)
+	if err != nil {
+		return mod.ProductBatch{}, err
+	}
+	r.pub.Publish(events.TopicProductBatches, "productBatch.statusChanged", *change)
+	return batch, nil
+}
+
+// NewPublishedInboundRepo wraps an inbound order repository so its committed writes are published
+func NewPublishedInboundRepo(rp internal.InboundRepository, pub internal.EventPublisher) *PublishedInboundRepo {
+	return &PublishedInboundRepo{
+		InboundRepository: rp,
+		pub:               pub,
+	}
+}
+
+// PublishedInboundRepo publishes the created inbound orders on the inboundOrders topic
+type PublishedInboundRepo struct {
+	internal.InboundRepository
+	pub internal.EventPublisher
+}
+
+// Save saves the inbound order and publishes it
+func (r *PublishedInboundRepo) Save(inbound *mod.InboundOrders) (*mod.InboundOrders, error) {
+	saved, err := r.InboundRepository.Save(inbound)
+	if err != nil {
+		return saved, err
+	}
+	r.pub.Publish(events.TopicInboundOrders, "inboundOrder.created", *saved)
+	return saved, nil
+}
+
+// NewPublishedPurchaseOrderRepo wraps a purchase order repository so its committed writes are published
+func NewPublishedPurchaseOrderRepo(rp internal.PurchaseOrderRepository, sections internal.SectionRepository, pub internal.EventPublisher) *PublishedPurchaseOrderRepo {
+	return &PublishedPurchaseOrderRepo{
+		PurchaseOrderRepository: rp,
+		sections:                sections,
+		pub:                     pub,
+	}
+}
+
+// PublishedPurchaseOrderRepo publishes the created purchase orders on the purchaseOrders topic and the sections
+// their picks released room in on the sections topic
+type PublishedPurchaseOrderRepo struct {
+	internal.PurchaseOrderRepository
+	sections internal.SectionRepository
+	pub      internal.EventPublisher
+}
+
+// Save saves the purchase order and publishes it with the sections it picked from
+func (r *PublishedPurchaseOrderRepo) Save(order *mod.PurchaseOrder) error {
+	if err := r.PurchaseOrderRepository.Save(order); err != nil {
+		return err
+	}
+	r.pub.Publish(events.TopicPurchaseOrders, "purchaseOrder.created", *order)
+	publishSections(r.pub, r.sections, order.SectionIDs()...)
+	return nil
+}
+
+// NewPublishedTransferRepo wraps a transfer repository so the sections it changes are published
+func NewPublishedTransferRepo(rp internal.TransferRepository, sections internal.SectionRepository, pub internal.EventPublisher) *PublishedTransferRepo {
+	return &PublishedTransferRepo{
+		TransferRepository: rp,
+		sections:           sections,
+		pub:                pub,
+	}
+}
+
+// PublishedTransferRepo publishes the source and destination sections of every transfer on the sections topic
+type PublishedTransferRepo struct {
+	internal.TransferRepository
+	sections internal.SectionRepository
+	pub      internal.EventPublisher
+}
+
+// Save moves the stock and publishes both sections, their capacity changed
+func (r *PublishedTransferRepo) Save(t *mod.Transfer) error {
+	if err := r.TransferRepository.Save(t); err != nil {
+		return err
+	}
+	publishSections(r.pub, r.sections, t.FromSectionID, t.ToSectionID)
+	return nil
+}
+
+// NewPublishedSectionReadingRepo wraps a section reading repository so the ingestion and its alerts are published
+func NewPublishedSectionReadingRepo(rp internal.SectionReadingRepository, sections internal.SectionRepository, pub internal.EventPublisher) *PublishedSectionReadingRepo {
+	return &PublishedSectionReadingRepo{
+		SectionReadingRepository: rp,
+		sections:                 sections,
+		pub:                      pub,
+	}
+}
+
+// PublishedSectionReadingRepo publishes the section of every ingested batch of readings on the sections topic and
+// the temperature alerts it opened or closed on the alerts topic
+type PublishedSectionReadingRepo struct {
+	internal.SectionReadingRepository
+	sections internal.SectionRepository
+	pub      internal.EventPublisher
+}
+
+// Save stores the readings and publishes the section with its new temperature and the alerts
+func (r *PublishedSectionReadingRepo) Save(sectionID int, readings []mod.SectionReading) (mod.SectionReadingsResult, error) {
+	result, err := r.SectionReadingRepository.Save(sectionID, readings)
+	if err != nil {
+		return result, err
+	}
+	publishSections(r.pub, r.sections, sectionID)
+	for _, alert := range result.AlertsOpened {
+		r.pub.Publish(events.TopicAlerts, "sectionAlert.opened", alert)
+	}
+	for _, id := range result.AlertsClosed {
+		r.pub.Publish(events.TopicAlerts, "sectionAlert.closed", map[string]int{"id": id, "section_id": sectionID})
+	}
+	return result, nil
+}
+
+// NewPublishedStockMovementRepo wraps a stock movement repository so the sections of the adjustments are published
+func NewPublishedStockMovementRepo(rp internal.StockMovementRepository, sections internal.SectionRepository, pub internal.EventPublisher) *PublishedStockMovementRepo {
+	return &PublishedStockMovementRepo{
+		StockMovementRepository: rp,
+		sections:                sections,
+		pub:                     pub,
+	}
+}
+
+// PublishedStockMovementRepo publishes the section of every adjusted batch on the sections topic
+type PublishedStockMovementRepo struct {
+	internal.StockMovementRepository
+	sections internal.SectionRepository
+	pub      internal.EventPublisher
+}
+
+// Adjust applies the movement and publishes the section of the batch, its capacity changed
+func (r *PublishedStockMovementRepo) Adjust(m *mod.StockMovement) error {
+	if err := r.StockMovementRepository.Adjust(m); err != nil {
+		return err
+	}
+	publishSections(r.pub, r.sections, m.SectionID)
+	return nil
+}
+
+// NewPublishedBatchExpiryRepo wraps a batch expiry repository so the alerts of its sweeps are published
+func NewPublishedBatchExpiryRepo(rp internal.BatchExpiryRepository, pub internal.EventPublisher) *PublishedBatchExpiryRepo {
+	return &PublishedBatchExpiryRepo{
+		BatchExpiryRepository: rp,
+		pub:                   pub,
+	}
+}
+
+// PublishedBatchExpiryRepo publishes the raised alerts on the alerts topic
+type PublishedBatchExpiryRepo struct {
+	internal.BatchExpiryRepository
+	pub internal.EventPublisher
+}
+
+// Sweep runs the sweep and publishes every alert it raised
+func (r *PublishedBatchExpiryRepo) Sweep(at time.Time, leadTime time.Duration) ([]mod.Alert, error) {
+	alerts, err := r.BatchExpiryRepository.Sweep(at, leadTime)
+	if err != nil {
+		return nil, err
+	}
+	for _, alert := range alerts {
+		r.pub.Publish(events.TopicAlerts, "alert.raised", alert)
+	}
+	return alerts, nil
+}
+
+// NewPublishedTraceRepo wraps a trace repository so its recalls are published
+func NewPublishedTraceRepo(rp internal.TraceRepository, pub internal.EventPublisher) *PublishedTraceRepo {
+	return &PublishedTraceRepo{
+		TraceRepository: rp,
+		pub:             pub,
+	}
+}
+
+// PublishedTraceRepo publishes the recalls with the buyers to contact on the alerts topic
+type PublishedTraceRepo struct {
+	internal.TraceRepository
+	pub internal.EventPublisher
+}
+
+// Recall records the recall and publishes it
+func (r *PublishedTraceRepo) Recall(recall *mod.Recall) error {
+	if err := r.TraceRepository.Recall(recall); err != nil {
+		return err
+	}
+	r.pub.Publish(events.TopicAlerts, "recall.created", *recall)
+	return nil
+}
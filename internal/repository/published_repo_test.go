@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/internal/events"
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishedSectionRepo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	broker := events.NewBroker(10)
+	sub, _ := broker.Subscribe([]string{events.TopicSections}, 0)
+	repo := NewPublishedSectionRepo(NewSectionRepo(db), broker)
+
+	deleteQuery := regexp.QuoteMeta("DELETE FROM `sections` WHERE `id` = ?")
+
+	t.Run("committed writes are published", func(t *testing.T) {
+		mock.ExpectExec(deleteQuery).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		require.NoError(t, repo.Delete(1))
+		ev := <-sub.C
+		require.Equal(t, "section.deleted", ev.Type)
+		require.Equal(t, map[string]int{"id": 1}, ev.Data)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed writes are not published", func(t *testing.T) {
+		mock.ExpectExec(deleteQuery).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		require.Error(t, repo.Delete(2))
+		require.Len(t, sub.C, 0)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestPublishedProductBatchRepo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	broker := events.NewBroker(10)
+	sub, _ := broker.Subscribe(nil, 0)
+	sections := sectionsStub{sections: map[int]mod.Section{1: {ID: 1, CurrentCapacity: 5}}}
+	repo := NewPublishedProductBatchRepo(NewProductBatchRepo(db), sections, broker)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM `sections`").WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity", "product_type_id"}).AddRow(0, 10, 1))
+	mock.ExpectQuery("FROM `products`").WillReturnRows(sqlmock.NewRows([]string{"product_type_id"}).AddRow(1))
+	mock.ExpectExec("INSERT INTO `product_batches`").WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectExec("UPDATE `sections`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(sectionSnapshotInsert).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `stock_movements`").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	batch := mod.ProductBatch{BatchNumber: 1, CurrentQuantity: 5, ProductId: 1, SectionId: 1}
+	require.NoError(t, repo.Save(&batch))
+	ev := <-sub.C
+	require.Equal(t, events.TopicProductBatches, ev.Topic)
+	require.Equal(t, 9, ev.Data.(mod.ProductBatch).ID)
+	ev = <-sub.C
+	require.Equal(t, "section.updated", ev.Type)
+	require.Equal(t, sections.sections[1], ev.Data)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublishedBatchExpiryRepo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	broker := events.NewBroker(10)
+	sub, _ := broker.Subscribe([]string{events.TopicAlerts}, 0)
+	repo := NewPublishedBatchExpiryRepo(NewBatchExpiryRepo(db), broker)
+	at := time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("`expired_at` IS NULL").WillReturnRows(sqlmock.NewRows([]string{"id", "warehouse_id", "seller_id", "current_quantity"}).AddRow(1, 2, 5, 10))
+	mock.ExpectExec("UPDATE `product_batches`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO `alerts`").WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectQuery("`expiry_warned_at` IS NULL").WillReturnRows(sqlmock.NewRows([]string{"id", "warehouse_id", "seller_id", "current_quantity"}))
+	mock.ExpectCommit()
+
+	alerts, err := repo.Sweep(at, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	ev := <-sub.C
+	require.Equal(t, "alert.raised", ev.Type)
+	require.Equal(t, 4, ev.Data.(mod.Alert).ID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// sectionsStub serves the sections read back by the published repositories after a write
+type sectionsStub struct {
+	internal.SectionRepository
+	sections map[int]mod.Section
+}
+
+func (s sectionsStub) FindByID(id int) (mod.Section, error) {
+	section, ok := s.sections[id]
+	if !ok {
+		return mod.Section{}, e.ErrSectionRepositoryNotFound
+	}
+	return section, nil
+}
+
+func TestPublishedTransferRepo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	broker := events.NewBroker(10)
+	sub, _ := broker.Subscribe([]string{events.TopicSections}, 0)
+	sections := sectionsStub{sections: map[int]mod.Section{1: {ID: 1, CurrentCapacity: 40}, 2: {ID: 2, CurrentCapacity: 30}}}
+	repo := NewPublishedTransferRepo(NewTransferRepo(db), sections, broker)
+
+	expectTransferLocks(mock, -8, 20, 100, 3)
+	mock.ExpectExec("UPDATE `product_batches`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `sections`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `sections`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(sectionSnapshotInsert).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO `transfers`").WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec(stockMovementInsert).WillReturnResult(sqlmock.NewResult(20, 1))
+	mock.ExpectExec(stockMovementInsert).WillReturnResult(sqlmock.NewResult(21, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Save(&mod.Transfer{ProductBatchID: 1, ToSectionID: 2, EmployeeID: 4}))
+	for _, id := range []int{1, 2} {
+		ev := <-sub.C
+		require.Equal(t, "section.updated", ev.Type)
+		require.Equal(t, sections.sections[id], ev.Data)
+	}
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublishedStockMovementRepo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	broker := events.NewBroker(10)
+	sub, _ := broker.Subscribe([]string{events.TopicSections}, 0)
+	sections := sectionsStub{sections: map[int]mod.Section{4: {ID: 4, CurrentCapacity: 7}}}
+	repo := NewPublishedStockMovementRepo(NewStockMovementRepo(db), sections, broker)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM `product_batches`").WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id", "closed"}).AddRow(10, 4, false))
+	mock.ExpectQuery("FROM `sections`").WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity"}).AddRow(10, 50))
+	mock.ExpectQuery("FROM `employees`").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectExec("UPDATE `product_batches`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `sections`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(sectionSnapshotInsert).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(stockMovementInsert).WillReturnResult(sqlmock.NewResult(11, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Adjust(&mod.StockMovement{Type: mod.MovementWriteOff, ProductBatchID: 1, Quantity: -3, EmployeeID: 2}))
+	ev := <-sub.C
+	require.Equal(t, "section.updated", ev.Type)
+	require.Equal(t, sections.sections[4], ev.Data)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/go-sql-driver/mysql"
 	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
@@ -20,6 +21,49 @@ type PurchaseOrderDB struct {
 	db *sql.DB
 }
 
+// purchaseOrderColumns are the columns read by FindAll and FindByID
+const purchaseOrderColumns = "SELECT `id`, `order_number`, `order_date`, `tracking_code`, `buyer_id` FROM purchase_orders"
+
+// scanPurchaseOrder scans a purchase order, order_date and buyer_id are nullable
+func scanPurchaseOrder(row interface{ Scan(...any) error }) (po mod.PurchaseOrder, err error) {
+	var orderDate sql.NullTime
+	var buyerID sql.NullInt64
+	err = row.Scan(&po.ID, &po.OrderNumber, &orderDate, &po.TrackingCode, &buyerID)
+	po.OrderDate = mod.Date(orderDate.Time)
+	po.BuyerId = int(buyerID.Int64)
+	return
+}
+
+// FindAll returns all purchase orders from the database
+func (r *PurchaseOrderDB) FindAll() (purchaseOrders []mod.PurchaseOrder, err error) {
+	rows, err := r.db.Query(purchaseOrderColumns)
+	if err != nil {
+		return nil, errors.Join(e.ErrQueryError, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		po, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return nil, errors.Join(e.ErrParseError, err)
+		}
+		purchaseOrders = append(purchaseOrders, po)
+	}
+	return purchaseOrders, rows.Err()
+}
+
+// FindByID returns a purchase order from the database by its id
+func (r *PurchaseOrderDB) FindByID(id int) (mod.PurchaseOrder, error) {
+	po, err := scanPurchaseOrder(r.db.QueryRow(purchaseOrderColumns+" WHERE id = ?", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return mod.PurchaseOrder{}, e.ErrPORepositoryNotFound
+		}
+		return mod.PurchaseOrder{}, errors.Join(e.ErrQueryError, err)
+	}
+	return po, nil
+}
+
 func (r *PurchaseOrderDB) Save(purchaseOrder *mod.PurchaseOrder) (err error) {
 
 	tx, _ := r.db.Begin()
@@ -62,12 +106,19 @@ func (r *PurchaseOrderDB) Save(purchaseOrder *mod.PurchaseOrder) (err error) {
 		if err != nil {
 			break
 		}
+		err = r.pick(tx, &od, purchaseOrder.AllowBackorder)
+		if err != nil {
+			break
+		}
 		purchaseOrder.ProductsDetails[idx] = od
 	}
 
 	if err != nil {
 		return err
 	}
+	if err = snapshotSections(tx, time.Now().UTC(), purchaseOrder.SectionIDs()...); err != nil {
+		return err
+	}
 
 	(*purchaseOrder).ID = int(lastInsertId)
 
@@ -103,3 +154,79 @@ func (r *PurchaseOrderDB) insertOrderDetail(tx *sql.Tx, orderDetails *mod.OrderD
 
 	return nil
 }
+
+// pickedBatch is a batch of the product of an order detail that still has units
+type pickedBatch struct {
+	id, sectionID, quantity int
+}
+
+// pick allocates the quantity of the order detail to the batches of its product first-expired-first-out: the
+// batches due first are picked first and the expired ones and the ones on hold are skipped. Every batch picked gives up its units and
+// the room in its section, and is recorded as an allocation of the detail and a pick movement. When the batches
+// do not cover the quantity the order is rejected, or the missing units are backordered if the order allows it
+func (r *PurchaseOrderDB) pick(tx *sql.Tx, orderDetails *mod.OrderDetails, backorder bool) error {
+	rows, err := tx.Query(
+		"SELECT pb.`id`, pb.`section_id`, pb.`current_quantity` FROM `product_batches` AS pb "+
+			"JOIN `product_records` AS pr ON pr.`product_id` = pb.`product_id` "+
+			"WHERE pr.`id` = ? AND pb.`current_quantity` > 0 AND pb.`due_date` > UTC_TIMESTAMP() AND pb.`status` IN ('available', 'released') ORDER BY pb.`due_date`, pb.`id` FOR UPDATE",
+		(*orderDetails).ProductRecordId,
+	)
+	if err != nil {
+		return err
+	}
+	// the batches are read before the updates, the connection is busy while the rows are open
+	var batches []pickedBatch
+	for rows.Next() {
+		var b pickedBatch
+		if err = rows.Scan(&b.id, &b.sectionID, &b.quantity); err != nil {
+			rows.Close()
+			return err
+		}
+		batches = append(batches, b)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	remaining := (*orderDetails).Quantity
+	available := 0
+	for _, b := range batches {
+		available += b.quantity
+	}
+	if available < remaining && !backorder {
+		return fmt.Errorf("%w: product record %d has %d of %d units", e.ErrStockInsufficient, (*orderDetails).ProductRecordId, available, remaining)
+	}
+
+	(*orderDetails).Allocations = []mod.OrderAllocation{}
+	for _, b := range batches {
+		if remaining == 0 {
+			break
+		}
+		quantity := min(remaining, b.quantity)
+		if _, err = tx.Exec("UPDATE `product_batches` SET `current_quantity` = `current_quantity` - ? WHERE `id` = ?", quantity, b.id); err != nil {
+			return err
+		}
+		if _, err = tx.Exec("UPDATE `sections` SET `current_capacity` = `current_capacity` - ? WHERE `id` = ?", quantity, b.sectionID); err != nil {
+			return err
+		}
+		if _, err = tx.Exec("INSERT INTO `order_detail_allocations` (`order_detail_id`, `product_batch_id`, `quantity`) VALUES (?, ?, ?)",
+			(*orderDetails).ID, b.id, quantity); err != nil {
+			return err
+		}
+		movement := mod.StockMovement{Type: mod.MovementPick, ProductBatchID: b.id, SectionID: b.sectionID, Quantity: -quantity,
+			ReferenceType: mod.ReferencePurchaseOrder, ReferenceID: (*orderDetails).PurchaseOrderId}
+		if err = insertMovement(tx, &movement); err != nil {
+			return err
+		}
+		(*orderDetails).Allocations = append((*orderDetails).Allocations, mod.OrderAllocation{ProductBatchID: b.id, SectionID: b.sectionID, Quantity: quantity})
+		remaining -= quantity
+	}
+	if remaining > 0 {
+		(*orderDetails).BackorderedQuantity = remaining
+		if _, err = tx.Exec("UPDATE `order_details` SET `backordered_quantity` = ? WHERE `id` = ?", remaining, (*orderDetails).ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
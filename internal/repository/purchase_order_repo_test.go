@@ -83,10 +83,22 @@ func (s *TestPurchaseOrderRepo) TestSaveBuyerRepo() {
 			WithArgs(newPurchaseOrder.ProductsDetails[0].CleanLinessStatus, newPurchaseOrder.ProductsDetails[0].Quantity, newPurchaseOrder.ProductsDetails[0].Temperature, newPurchaseOrder.ProductsDetails[0].ProductRecordId, 21).
 			WillReturnResult(sqlmock.NewResult(31, 1))
 
+		// the first line takes the 6 units of the batch due first and 4 of the next one
+		s.MockDb.ExpectQuery(expectedQueryPick).WithArgs(3001).
+			WillReturnRows(sqlmock.NewRows(pickColumns).AddRow(7, 2, 6).AddRow(8, 3, 20))
+		expectPick(s.MockDb, 31, 7, 2, 6)
+		expectPick(s.MockDb, 31, 8, 3, 4)
+
 		s.MockDb.ExpectExec(regexp.QuoteMeta(expectedQueryOrderDetail)).
 			WithArgs(newPurchaseOrder.ProductsDetails[1].CleanLinessStatus, newPurchaseOrder.ProductsDetails[1].Quantity, newPurchaseOrder.ProductsDetails[1].Temperature, newPurchaseOrder.ProductsDetails[1].ProductRecordId, 21).
 			WillReturnResult(sqlmock.NewResult(32, 1))
 
+		s.MockDb.ExpectQuery(expectedQueryPick).WithArgs(3002).
+			WillReturnRows(sqlmock.NewRows(pickColumns).AddRow(9, 2, 5))
+		expectPick(s.MockDb, 32, 9, 2, 5)
+		s.MockDb.ExpectExec(sectionSnapshotInsert).WithArgs(mod.SnapshotUpdate, sqlmock.AnyArg(), 2, 3).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
 		s.MockDb.ExpectCommit()
 
 		// When
@@ -97,6 +109,8 @@ func (s *TestPurchaseOrderRepo) TestSaveBuyerRepo() {
 		require.Equal(s.T(), 21, newPurchaseOrder.ID)
 		require.Equal(s.T(), 31, newPurchaseOrder.ProductsDetails[0].ID)
 		require.Equal(s.T(), 32, newPurchaseOrder.ProductsDetails[1].ID)
+		require.Equal(s.T(), []mod.OrderAllocation{{ProductBatchID: 7, SectionID: 2, Quantity: 6}, {ProductBatchID: 8, SectionID: 3, Quantity: 4}}, newPurchaseOrder.ProductsDetails[0].Allocations)
+		require.Equal(s.T(), []mod.OrderAllocation{{ProductBatchID: 9, SectionID: 2, Quantity: 5}}, newPurchaseOrder.ProductsDetails[1].Allocations)
 
 		// 8. Verifica todas las expectativas
 		require.NoError(s.T(), s.MockDb.ExpectationsWereMet())
@@ -232,6 +246,112 @@ func (s *TestPurchaseOrderRepo) TestSaveBuyerRepo() {
 		require.ErrorIs(t, err, mysqlErr)
 		require.NoError(t, s.MockDb.ExpectationsWereMet())
 	})
+
+	t.Run("Case 10: Fail - Not enough stock for the order detail", func(t *testing.T) {
+		s.SetupTest()
+		s.MockDb.ExpectBegin()
+		s.MockDb.ExpectExec(regexp.QuoteMeta(expectedQueryPurchaseOrder)).
+			WillReturnResult(sqlmock.NewResult(21, 1))
+		s.MockDb.ExpectExec(regexp.QuoteMeta(expectedQueryOrderDetail)).
+			WillReturnResult(sqlmock.NewResult(31, 1))
+		s.MockDb.ExpectQuery(expectedQueryPick).WithArgs(3001).
+			WillReturnRows(sqlmock.NewRows(pickColumns).AddRow(7, 2, 6))
+		s.MockDb.ExpectRollback()
+
+		err := s.Repo.Save(&newPurchaseOrder)
+		require.ErrorIs(t, err, e.ErrStockInsufficient)
+		require.EqualError(t, err, e.ErrStockInsufficient.Error()+": product record 3001 has 6 of 10 units")
+		require.NoError(t, s.MockDb.ExpectationsWereMet())
+	})
+
+	t.Run("Case 11: Success - Missing units are backordered", func(t *testing.T) {
+		s.SetupTest()
+		backordered := newPurchaseOrder
+		backordered.AllowBackorder = true
+		backordered.ProductsDetails = []mod.OrderDetails{newPurchaseOrder.ProductsDetails[0]}
+
+		s.MockDb.ExpectBegin()
+		s.MockDb.ExpectExec(regexp.QuoteMeta(expectedQueryPurchaseOrder)).
+			WillReturnResult(sqlmock.NewResult(21, 1))
+		s.MockDb.ExpectExec(regexp.QuoteMeta(expectedQueryOrderDetail)).
+			WillReturnResult(sqlmock.NewResult(31, 1))
+		s.MockDb.ExpectQuery(expectedQueryPick).WithArgs(3001).
+			WillReturnRows(sqlmock.NewRows(pickColumns).AddRow(7, 2, 6))
+		expectPick(s.MockDb, 31, 7, 2, 6)
+		s.MockDb.ExpectExec(regexp.QuoteMeta("UPDATE `order_details` SET `backordered_quantity` = ? WHERE `id` = ?")).
+			WithArgs(4, 31).WillReturnResult(sqlmock.NewResult(0, 1))
+		s.MockDb.ExpectExec(sectionSnapshotInsert).WithArgs(mod.SnapshotUpdate, sqlmock.AnyArg(), 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		s.MockDb.ExpectCommit()
+
+		err := s.Repo.Save(&backordered)
+		require.NoError(t, err)
+		require.Equal(t, 4, backordered.ProductsDetails[0].BackorderedQuantity)
+		require.Equal(t, []mod.OrderAllocation{{ProductBatchID: 7, SectionID: 2, Quantity: 6}}, backordered.ProductsDetails[0].Allocations)
+		require.NoError(t, s.MockDb.ExpectationsWereMet())
+	})
+}
+
+func (s *TestPurchaseOrderRepo) TestFindPurchaseOrders() {
+	t := s.T()
+	query := "SELECT `id`, `order_number`, `order_date`, `tracking_code`, `buyer_id` FROM purchase_orders"
+	orderDate := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("find all", func(t *testing.T) {
+		s.MockDb.ExpectQuery(regexp.QuoteMeta(query)).
+			WillReturnRows(sqlmock.NewRows(s.TestColumns).
+				AddRow(1, "PO-2024-001", orderDate, "TRACK-123", 1).
+				AddRow(2, "PO-2024-002", nil, "TRACK-456", nil))
+
+		result, err := s.Repo.FindAll()
+
+		require.NoError(t, err)
+		require.Equal(t, []mod.PurchaseOrder{
+			{ID: 1, OrderNumber: "PO-2024-001", OrderDate: mod.Date(orderDate), TrackingCode: "TRACK-123", BuyerId: 1},
+			{ID: 2, OrderNumber: "PO-2024-002", TrackingCode: "TRACK-456"},
+		}, result)
+		require.NoError(t, s.MockDb.ExpectationsWereMet())
+	})
+
+	t.Run("find by id", func(t *testing.T) {
+		s.MockDb.ExpectQuery(regexp.QuoteMeta(query + " WHERE id = ?")).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(s.TestColumns).AddRow(1, "PO-2024-001", orderDate, "TRACK-123", 1))
+
+		result, err := s.Repo.FindByID(1)
+
+		require.NoError(t, err)
+		require.Equal(t, "PO-2024-001", result.OrderNumber)
+		require.NoError(t, s.MockDb.ExpectationsWereMet())
+	})
+
+	t.Run("find by id not found", func(t *testing.T) {
+		s.MockDb.ExpectQuery(regexp.QuoteMeta(query + " WHERE id = ?")).WithArgs(9).
+			WillReturnRows(sqlmock.NewRows(s.TestColumns))
+
+		_, err := s.Repo.FindByID(9)
+
+		require.ErrorIs(t, err, e.ErrPORepositoryNotFound)
+		require.NoError(t, s.MockDb.ExpectationsWereMet())
+	})
+}
+
+var (
+	expectedQueryPick = regexp.QuoteMeta("WHERE pr.`id` = ? AND pb.`current_quantity` > 0 AND pb.`due_date` > UTC_TIMESTAMP() AND pb.`status` IN ('available', 'released') ORDER BY pb.`due_date`, pb.`id` FOR UPDATE")
+	pickColumns       = []string{"id", "section_id", "current_quantity"}
+)
+
+// expectPick expects the units taken out of a batch and its section, the allocation of the detail and the pick
+// movement of the order 21
+func expectPick(mock sqlmock.Sqlmock, detailID, batchID, sectionID, quantity int) {
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_batches` SET `current_quantity` = `current_quantity` - ? WHERE `id` = ?")).
+		WithArgs(quantity, batchID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `sections` SET `current_capacity` = `current_capacity` - ? WHERE `id` = ?")).
+		WithArgs(quantity, sectionID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `order_detail_allocations` (`order_detail_id`, `product_batch_id`, `quantity`) VALUES (?, ?, ?)")).
+		WithArgs(detailID, batchID, quantity).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_movements`")).
+		WithArgs(mod.MovementPick, batchID, sectionID, -quantity, mod.ReferencePurchaseOrder, 21, 0, "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
 }
 
 func TestRepoPOSuite(t *testing.T) {
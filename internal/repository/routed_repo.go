@@ -0,0 +1,803 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/internal/database"
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// routed holds the pieces shared by every routed repository
+type routed[T any] struct {
+	replica T
+	rt      *database.Router
+}
+
+// NewRoutedProductRepo sends product reads to the replica and writes to the primary
+func NewRoutedProductRepo(primary, replica internal.ProductRepository, rt *database.Router) *RoutedProductRepo {
+	return &RoutedProductRepo{ProductRepository: primary, routed: routed[internal.ProductRepository]{replica, rt}}
+}
+
+// RoutedProductRepo is the read/write splitting implementation of the product repository
+type RoutedProductRepo struct {
+	internal.ProductRepository
+	routed[internal.ProductRepository]
+}
+
+// FindAll returns all products from the reader connection
+func (r *RoutedProductRepo) FindAll() ([]mod.Product, error) {
+	return database.Route(r.rt, r.ProductRepository, r.replica).FindAll()
+}
+
+// FindByID returns a product from the reader connection
+func (r *RoutedProductRepo) FindByID(id int) (mod.Product, error) {
+	return database.Route(r.rt, r.ProductRepository, r.replica).FindByID(id)
+}
+
+// Save saves a product in the primary
+func (r *RoutedProductRepo) Save(product *mod.Product) error {
+	defer r.rt.MarkWrite()
+	return r.ProductRepository.Save(product)
+}
+
+// Update updates a product in the primary
+func (r *RoutedProductRepo) Update(product *mod.Product) error {
+	defer r.rt.MarkWrite()
+	return r.ProductRepository.Update(product)
+}
+
+// Delete deletes a product in the primary
+func (r *RoutedProductRepo) Delete(id int) error {
+	defer r.rt.MarkWrite()
+	return r.ProductRepository.Delete(id)
+}
+
+// NewRoutedSellerRepo sends seller reads to the replica and writes to the primary
+func NewRoutedSellerRepo(primary, replica internal.SellerRepository, rt *database.Router) *RoutedSellerRepo {
+	return &RoutedSellerRepo{SellerRepository: primary, routed: routed[internal.SellerRepository]{replica, rt}}
+}
+
+// RoutedSellerRepo is the read/write splitting implementation of the seller repository
+type RoutedSellerRepo struct {
+	internal.SellerRepository
+	routed[internal.SellerRepository]
+}
+
+// FindAll returns all sellers from the reader connection
+func (r *RoutedSellerRepo) FindAll() ([]mod.Seller, error) {
+	return database.Route(r.rt, r.SellerRepository, r.replica).FindAll()
+}
+
+// FindByID returns a seller from the reader connection
+func (r *RoutedSellerRepo) FindByID(id int) (mod.Seller, error) {
+	return database.Route(r.rt, r.SellerRepository, r.replica).FindByID(id)
+}
+
+// Save saves a seller in the primary
+func (r *RoutedSellerRepo) Save(seller *mod.Seller) (int, error) {
+	defer r.rt.MarkWrite()
+	return r.SellerRepository.Save(seller)
+}
+
+// Update updates a seller in the primary
+func (r *RoutedSellerRepo) Update(seller *mod.Seller) error {
+	defer r.rt.MarkWrite()
+	return r.SellerRepository.Update(seller)
+}
+
+// Delete deletes a seller in the primary
+func (r *RoutedSellerRepo) Delete(id int) error {
+	defer r.rt.MarkWrite()
+	return r.SellerRepository.Delete(id)
+}
+
+// NewRoutedSectionRepo sends section reads and reports to the replica and writes to the primary
+func NewRoutedSectionRepo(primary, replica internal.SectionRepository, rt *database.Router) *RoutedSectionRepo {
+	return &RoutedSectionRepo{SectionRepository: primary, routed: routed[internal.SectionRepository]{replica, rt}}
+}
+
+// RoutedSectionRepo is the read/write splitting implementation of the section repository
+type RoutedSectionRepo struct {
+	internal.SectionRepository
+	routed[internal.SectionRepository]
+}
+
+// FindAll returns all sections from the reader connection
+func (r *RoutedSectionRepo) FindAll() ([]mod.Section, error) {
+	return database.Route(r.rt, r.SectionRepository, r.replica).FindAll()
+}
+
+// FindByID returns a section from the reader connection
+func (r *RoutedSectionRepo) FindByID(id int) (mod.Section, error) {
+	return database.Route(r.rt, r.SectionRepository, r.replica).FindByID(id)
+}
+
+// ReportProducts runs the products report on the replica, even right after a write
+func (r *RoutedSectionRepo) ReportProducts(f mod.SectionReportFilter) ([]mod.ReportProductsResponse, error) {
+	return database.RouteStale(r.rt, r.SectionRepository, r.replica).ReportProducts(f)
+}
+
+// FindSlotCandidates reads the slotting candidates on the reader connection
+func (r *RoutedSectionRepo) FindSlotCandidates(req mod.SlottingRequest) (mod.SlottingProduct, []mod.SlottingSection, error) {
+	return database.Route(r.rt, r.SectionRepository, r.replica).FindSlotCandidates(req)
+}
+
+// Save saves a section in the primary
+func (r *RoutedSectionRepo) Save(section *mod.Section) error {
+	defer r.rt.MarkWrite()
+	return r.SectionRepository.Save(section)
+}
+
+// Update updates a section in the primary, the updated row is read back from the primary too
+func (r *RoutedSectionRepo) Update(id int, fields map[string]interface{}) (*mod.Section, error) {
+	defer r.rt.MarkWrite()
+	return r.SectionRepository.Update(id, fields)
+}
+
+// Delete deletes a section in the primary
+func (r *RoutedSectionRepo) Delete(id int) error {
+	defer r.rt.MarkWrite()
+	return r.SectionRepository.Delete(id)
+}
+
+// NewRoutedWarehouseRepo sends warehouse reads to the replica and writes to the primary
+func NewRoutedWarehouseRepo(primary, replica internal.WarehouseRepository, rt *database.Router) *RoutedWarehouseRepo {
+	return &RoutedWarehouseRepo{WarehouseRepository: primary, routed: routed[internal.WarehouseRepository]{replica, rt}}
+}
+
+// RoutedWarehouseRepo is the read/write splitting implementation of the warehouse repository,
+// uniqueness checks keep reading from the primary
+type RoutedWarehouseRepo struct {
+	internal.WarehouseRepository
+	routed[internal.WarehouseRepository]
+}
+
+// GetAll returns all warehouses from the reader connection
+func (r *RoutedWarehouseRepo) GetAll() ([]mod.Warehouse, error) {
+	return database.Route(r.rt, r.WarehouseRepository, r.replica).GetAll()
+}
+
+// GetByID returns a warehouse from the reader connection
+func (r *RoutedWarehouseRepo) GetByID(id int) (mod.Warehouse, error) {
+	return database.Route(r.rt, r.WarehouseRepository, r.replica).GetByID(id)
+}
+
+// GetCapacityReport runs the capacity report on the replica, even right after a write
+func (r *RoutedWarehouseRepo) GetCapacityReport(id *int) ([]mod.WarehouseCapacityReport, error) {
+	return database.RouteStale(r.rt, r.WarehouseRepository, r.replica).GetCapacityReport(id)
+}
+
+// GetSections returns the sections of a warehouse from the reader connection
+func (r *RoutedWarehouseRepo) GetSections(id int, filter mod.WarehouseSectionFilter) ([]mod.WarehouseSection, error) {
+	return database.Route(r.rt, r.WarehouseRepository, r.replica).GetSections(id, filter)
+}
+
+// GetEmployees returns the employees of a warehouse from the reader connection
+func (r *RoutedWarehouseRepo) GetEmployees(id int, name string) ([]mod.Employee, error) {
+	return database.Route(r.rt, r.WarehouseRepository, r.replica).GetEmployees(id, name)
+}
+
+// GetInboundOrders returns the latest inbound orders of a warehouse from the reader connection
+func (r *RoutedWarehouseRepo) GetInboundOrders(id int, limit int) ([]mod.InboundOrders, error) {
+	return database.Route(r.rt, r.WarehouseRepository, r.replica).GetInboundOrders(id, limit)
+}
+
+// GetStockingProduct returns the warehouses that stock a product from the reader connection
+func (r *RoutedWarehouseRepo) GetStockingProduct(productID int) ([]mod.WarehouseDistance, error) {
+	return database.Route(r.rt, r.WarehouseRepository, r.replica).GetStockingProduct(productID)
+}
+
+// Save saves a warehouse in the primary
+func (r *RoutedWarehouseRepo) Save(wh *mod.Warehouse) error {
+	defer r.rt.MarkWrite()
+	return r.WarehouseRepository.Save(wh)
+}
+
+// Update updates a warehouse in the primary
+func (r *RoutedWarehouseRepo) Update(wh *mod.Warehouse) error {
+	defer r.rt.MarkWrite()
+	return r.WarehouseRepository.Update(wh)
+}
+
+// Delete deletes a warehouse in the primary
+func (r *RoutedWarehouseRepo) Delete(id int) error {
+	defer r.rt.MarkWrite()
+	return r.WarehouseRepository.Delete(id)
+}
+
+// NewRoutedBuyerRepo sends buyer reads and reports to the replica and writes to the primary
+func NewRoutedBuyerRepo(primary, replica internal.BuyerRepository, rt *database.Router) *RoutedBuyerRepo {
+	return &RoutedBuyerRepo{BuyerRepository: primary, routed: routed[internal.BuyerRepository]{replica, rt}}
+}
+
+// RoutedBuyerRepo is the read/write splitting implementation of the buyer repository
+type RoutedBuyerRepo struct {
+	internal.BuyerRepository
+	routed[internal.BuyerRepository]
+}
+
+// FindAll returns all buyers from the reader connection
+func (r *RoutedBuyerRepo) FindAll() ([]mod.Buyer, error) {
+	return database.Route(r.rt, r.BuyerRepository, r.replica).FindAll()
+}
+
+// FindByID returns a buyer from the reader connection
+func (r *RoutedBuyerRepo) FindByID(id int) (mod.Buyer, error) {
+	return database.Route(r.rt, r.BuyerRepository, r.replica).FindByID(id)
+}
+
+// GetPurchaseOrderReport runs the purchase orders report on the replica, even right after a write
+func (r *RoutedBuyerRepo) GetPurchaseOrderReport(id *int) ([]mod.BuyerReportPO, error) {
+	return database.RouteStale(r.rt, r.BuyerRepository, r.replica).GetPurchaseOrderReport(id)
+}
+
+// Save saves a buyer in the primary
+func (r *RoutedBuyerRepo) Save(buyer *mod.Buyer) error {
+	defer r.rt.MarkWrite()
+	return r.BuyerRepository.Save(buyer)
+}
+
+// Update updates a buyer in the primary
+func (r *RoutedBuyerRepo) Update(buyer *mod.Buyer) error {
+	defer r.rt.MarkWrite()
+	return r.BuyerRepository.Update(buyer)
+}
+
+// Delete deletes a buyer in the primary
+func (r *RoutedBuyerRepo) Delete(id int) error {
+	defer r.rt.MarkWrite()
+	return r.BuyerRepository.Delete(id)
+}
+
+// NewRoutedPurchaseOrderRepo sends purchase order reads to the replica and writes to the primary
+func NewRoutedPurchaseOrderRepo(primary, replica internal.PurchaseOrderRepository, rt *database.Router) *RoutedPurchaseOrderRepo {
+	return &RoutedPurchaseOrderRepo{PurchaseOrderRepository: primary, routed: routed[internal.PurchaseOrderRepository]{replica, rt}}
+}
+
+// RoutedPurchaseOrderRepo is the read/write splitting implementation of the purchase order repository
+type RoutedPurchaseOrderRepo struct {
+	internal.PurchaseOrderRepository
+	routed[internal.PurchaseOrderRepository]
+}
+
+// FindAll returns all purchase orders from the reader connection
+func (r *RoutedPurchaseOrderRepo) FindAll() ([]mod.PurchaseOrder, error) {
+	return database.Route(r.rt, r.PurchaseOrderRepository, r.replica).FindAll()
+}
+
+// FindByID returns a purchase order from the reader connection
+func (r *RoutedPurchaseOrderRepo) FindByID(id int) (mod.PurchaseOrder, error) {
+	return database.Route(r.rt, r.PurchaseOrderRepository, r.replica).FindByID(id)
+}
+
+// Save saves a purchase order in the primary
+func (r *RoutedPurchaseOrderRepo) Save(purchaseOrder *mod.PurchaseOrder) error {
+	defer r.rt.MarkWrite()
+	return r.PurchaseOrderRepository.Save(purchaseOrder)
+}
+
+// NewRoutedEmployeeRepo sends employee reads to the replica and writes to the primary
+func NewRoutedEmployeeRepo(primary, replica internal.EmployeeRepository, rt *database.Router) *RoutedEmployeeRepo {
+	return &RoutedEmployeeRepo{EmployeeRepository: primary, routed: routed[internal.EmployeeRepository]{replica, rt}}
+}
+
+// RoutedEmployeeRepo is the read/write splitting implementation of the employee repository
+type RoutedEmployeeRepo struct {
+	internal.EmployeeRepository
+	routed[internal.EmployeeRepository]
+}
+
+// FindAll returns all employees from the reader connection
+func (r *RoutedEmployeeRepo) FindAll() ([]mod.Employee, error) {
+	return database.Route(r.rt, r.EmployeeRepository, r.replica).FindAll()
+}
+
+// FindByID returns an employee from the reader connection
+func (r *RoutedEmployeeRepo) FindByID(id int) (mod.Employee, error) {
+	return database.Route(r.rt, r.EmployeeRepository, r.replica).FindByID(id)
+}
+
+// Save saves an employee in the primary
+func (r *RoutedEmployeeRepo) Save(employee *mod.Employee) error {
+	defer r.rt.MarkWrite()
+	return r.EmployeeRepository.Save(employee)
+}
+
+// Update updates an employee in the primary
+func (r *RoutedEmployeeRepo) Update(id int, employee *mod.Employee) error {
+	defer r.rt.MarkWrite()
+	return r.EmployeeRepository.Update(id, employee)
+}
+
+// Delete deletes an employee in the primary
+func (r *RoutedEmployeeRepo) Delete(id int) error {
+	defer r.rt.MarkWrite()
+	return r.EmployeeRepository.Delete(id)
+}
+
+// NewRoutedInboundRepo sends the inbound orders report to the replica and writes to the primary
+func NewRoutedInboundRepo(primary, replica internal.InboundRepository, rt *database.Router) *RoutedInboundRepo {
+	return &RoutedInboundRepo{InboundRepository: primary, routed: routed[internal.InboundRepository]{replica, rt}}
+}
+
+// RoutedInboundRepo is the read/write splitting implementation of the inbound order repository
+type RoutedInboundRepo struct {
+	internal.InboundRepository
+	routed[internal.InboundRepository]
+}
+
+// FindOrdersByEmployee runs the inbound orders report on the replica, even right after a write
+func (r *RoutedInboundRepo) FindOrdersByEmployee(id int) ([]mod.EmployeeReport, error) {
+	return database.RouteStale(r.rt, r.InboundRepository, r.replica).FindOrdersByEmployee(id)
+}
+
+// Save saves an inbound order in the primary
+func (r *RoutedInboundRepo) Save(inbound *mod.InboundOrders) (*mod.InboundOrders, error) {
+	defer r.rt.MarkWrite()
+	return r.InboundRepository.Save(inbound)
+}
+
+// NewRoutedLocalityRepo sends locality reads and reports to the replica and writes to the primary
+func NewRoutedLocalityRepo(primary, replica internal.LocalityRepository, rt *database.Router) *RoutedLocalityRepo {
+	return &RoutedLocalityRepo{LocalityRepository: primary, routed: routed[internal.LocalityRepository]{replica, rt}}
+}
+
+// RoutedLocalityRepo is the read/write splitting implementation of the locality repository
+type RoutedLocalityRepo struct {
+	internal.LocalityRepository
+	routed[internal.LocalityRepository]
+}
+
+// FindAllLocalities returns all localities from the reader connection
+func (r *RoutedLocalityRepo) FindAllLocalities() ([]mod.Locality, error) {
+	return database.Route(r.rt, r.LocalityRepository, r.replica).FindAllLocalities()
+}
+
+// FindByID returns a locality from the reader connection
+func (r *RoutedLocalityRepo) FindByID(id int) (mod.Locality, error) {
+	return database.Route(r.rt, r.LocalityRepository, r.replica).FindByID(id)
+}
+
+// FindSellersByLocID runs the sellers report on the reader connection
+func (r *RoutedLocalityRepo) FindSellersByLocID(id int) ([]mod.SelByLoc, error) {
+	return database.Route(r.rt, r.LocalityRepository, r.replica).FindSellersByLocID(id)
+}
+
+// FindWarehousesByLocID runs the warehouses report on the reader connection
+func (r *RoutedLocalityRepo) FindWarehousesByLocID(id int) ([]mod.WhByLoc, error) {
+	return database.Route(r.rt, r.LocalityRepository, r.replica).FindWarehousesByLocID(id)
+}
+
+// FindOverviewByLocID runs the overview report on the replica, even right after a write
+func (r *RoutedLocalityRepo) FindOverviewByLocID(id int) ([]mod.LocalityOverview, error) {
+	return database.RouteStale(r.rt, r.LocalityRepository, r.replica).FindOverviewByLocID(id)
+}
+
+// Save saves a locality in the primary
+func (r *RoutedLocalityRepo) Save(locality *mod.Locality) (int, error) {
+	defer r.rt.MarkWrite()
+	return r.LocalityRepository.Save(locality)
+}
+
+// NewRoutedCarryRepo sends carry reads and reports to the replica and writes to the primary
+func NewRoutedCarryRepo(primary, replica internal.CarryRepository, rt *database.Router) *RoutedCarryRepo {
+	return &RoutedCarryRepo{CarryRepository: primary, routed: routed[internal.CarryRepository]{replica, rt}}
+}
+
+// RoutedCarryRepo is the read/write splitting implementation of the carry repository,
+// existence checks keep reading from the primary
+type RoutedCarryRepo struct {
+	internal.CarryRepository
+	routed[internal.CarryRepository]
+}
+
+// GetAll returns all carries from the reader connection
+func (r *RoutedCarryRepo) GetAll() ([]mod.Carry, error) {
+	return database.Route(r.rt, r.CarryRepository, r.replica).GetAll()
+}
+
+// GetByID returns a carry from the reader connection
+func (r *RoutedCarryRepo) GetByID(id int) (mod.Carry, error) {
+	return database.Route(r.rt, r.CarryRepository, r.replica).GetByID(id)
+}
+
+// GetReportByLocality runs the carries report of a locality on the replica, even right after a write
+func (r *RoutedCarryRepo) GetReportByLocality(localityID int) ([]mod.LocalityCarryReport, error) {
+	return database.RouteStale(r.rt, r.CarryRepository, r.replica).GetReportByLocality(localityID)
+}
+
+// GetReportByLocalityAll runs the carries report on the replica, even right after a write
+func (r *RoutedCarryRepo) GetReportByLocalityAll() ([]mod.LocalityCarryReport, error) {
+	return database.RouteStale(r.rt, r.CarryRepository, r.replica).GetReportByLocalityAll()
+}
+
+// Save saves a carry in the primary
+func (r *RoutedCarryRepo) Save(c *mod.Carry) error {
+	defer r.rt.MarkWrite()
+	return r.CarryRepository.Save(c)
+}
+
+// Update updates a carry in the primary
+func (r *RoutedCarryRepo) Update(c *mod.Carry) error {
+	defer r.rt.MarkWrite()
+	return r.CarryRepository.Update(c)
+}
+
+// Delete deletes a carry in the primary
+func (r *RoutedCarryRepo) Delete(id int) error {
+	defer r.rt.MarkWrite()
+	return r.CarryRepository.Delete(id)
+}
+
+// NewRoutedProductBatchRepo sends product batch reads to the replica and writes to the primary
+func NewRoutedProductBatchRepo(primary, replica internal.ProductBatchRepository, rt *database.Router) *RoutedProductBatchRepo {
+	return &RoutedProductBatchRepo{ProductBatchRepository: primary, routed: routed[internal.ProductBatchRepository]{replica, rt}}
+}
+
+// RoutedProductBatchRepo is the read/write splitting implementation of the product batch repository
+type RoutedProductBatchRepo struct {
+	internal.ProductBatchRepository
+	routed[internal.ProductBatchRepository]
+}
+
+// FindAll returns the product batches of the filter from the reader connection
+func (r *RoutedProductBatchRepo) FindAll(f mod.ProductBatchFilter) ([]mod.ProductBatch, error) {
+	return database.Route(r.rt, r.ProductBatchRepository, r.replica).FindAll(f)
+}
+
+// FindByID returns a product batch from the reader connection
+func (r *RoutedProductBatchRepo) FindByID(id int) (mod.ProductBatch, error) {
+	return database.Route(r.rt, r.ProductBatchRepository, r.replica).FindByID(id)
+}
+
+// Save saves a product batch in the primary
+func (r *RoutedProductBatchRepo) Save(batch *mod.ProductBatch) error {
+	defer r.rt.MarkWrite()
+	return r.ProductBatchRepository.Save(batch)
+}
+
+// Update updates a product batch in the primary
+func (r *RoutedProductBatchRepo) Update(id int, patch mod.ProductBatchPatch) (mod.ProductBatch, error) {
+	defer r.rt.MarkWrite()
+	return r.ProductBatchRepository.Update(id, patch)
+}
+
+// Archive archives a product batch in the primary
+func (r *RoutedProductBatchRepo) Archive(id int, at time.Time) (mod.ProductBatch, error) {
+	defer r.rt.MarkWrite()
+	return r.ProductBatchRepository.Archive(id, at)
+}
+
+// Delete deletes the product batch in the primary
+func (r *RoutedProductBatchRepo) Delete(id int) error {
+	defer r.rt.MarkWrite()
+	return r.ProductBatchRepository.Delete(id)
+}
+
+// ChangeStatus changes the status of a product batch in the primary
+func (r *RoutedProductBatchRepo) ChangeStatus(change *mod.BatchStatusChange, from ...string) (mod.ProductBatch, error) {
+	defer r.rt.MarkWrite()
+	return r.ProductBatchRepository.ChangeStatus(change, from...)
+}
+
+// FindStatusHistory returns the status changes of a product batch from the reader connection
+func (r *RoutedProductBatchRepo) FindStatusHistory(id int) ([]mod.BatchStatusChange, error) {
+	return database.Route(r.rt, r.ProductBatchRepository, r.replica).FindStatusHistory(id)
+}
+
+// NewRoutedProductRecordRepo sends product record reads to the replica and writes to the primary
+func NewRoutedProductRecordRepo(primary, replica internal.ProductRecordRepository, rt *database.Router) *RoutedProductRecordRepo {
+	return &RoutedProductRecordRepo{ProductRecordRepository: primary, routed: routed[internal.ProductRecordRepository]{replica, rt}}
+}
+
+// RoutedProductRecordRepo is the read/write splitting implementation of the product record repository
+type RoutedProductRecordRepo struct {
+	internal.ProductRecordRepository
+	routed[internal.ProductRecordRepository]
+}
+
+// FindAllPR returns all product records from the reader connection
+func (r *RoutedProductRecordRepo) FindAllPR() (map[int]mod.ProductRecord, error) {
+	return database.Route(r.rt, r.ProductRecordRepository, r.replica).FindAllPR()
+}
+
+// FindAllByProductIDPR returns the records of a product from the reader connection
+func (r *RoutedProductRecordRepo) FindAllByProductIDPR(productID int) (map[int]mod.ProductRecord, error) {
+	return database.Route(r.rt, r.ProductRecordRepository, r.replica).FindAllByProductIDPR(productID)
+}
+
+// SavePR saves a product record in the primary
+func (r *RoutedProductRecordRepo) SavePR(productRecord *mod.ProductRecord) error {
+	defer r.rt.MarkWrite()
+	return r.ProductRecordRepository.SavePR(productRecord)
+}
+
+// NewRoutedSearchRepo sends searches to the replica
+func NewRoutedSearchRepo(primary, replica internal.SearchRepository, rt *database.Router) *RoutedSearchRepo {
+	return &RoutedSearchRepo{SearchRepository: primary, routed: routed[internal.SearchRepository]{replica, rt}}
+}
+
+// RoutedSearchRepo is the read/write splitting implementation of the search repository
+type RoutedSearchRepo struct {
+	internal.SearchRepository
+	routed[internal.SearchRepository]
+}
+
+// Search runs the search on the replica, even right after a write
+func (r *RoutedSearchRepo) Search(entity string, terms []string, limit int) ([]mod.SearchResult, error) {
+	return database.RouteStale(r.rt, r.SearchRepository, r.replica).Search(entity, terms, limit)
+}
+
+// NewRoutedIncludeRepo sends the include queries to the replica
+func NewRoutedIncludeRepo(primary, replica internal.IncludeRepository, rt *database.Router) *RoutedIncludeRepo {
+	return &RoutedIncludeRepo{IncludeRepository: primary, routed: routed[internal.IncludeRepository]{replica, rt}}
+}
+
+// RoutedIncludeRepo is the read/write splitting implementation of the include repository
+type RoutedIncludeRepo struct {
+	internal.IncludeRepository
+	routed[internal.IncludeRepository]
+}
+
+// reader returns the repository that must serve the next query
+func (r *RoutedIncludeRepo) reader() internal.IncludeRepository {
+	return database.Route(r.rt, r.IncludeRepository, r.replica)
+}
+
+// SellersByIDs runs on the reader connection
+func (r *RoutedIncludeRepo) SellersByIDs(ids []int) ([]mod.Seller, error) {
+	return r.reader().SellersByIDs(ids)
+}
+
+// LocalitiesByIDs runs on the reader connection
+func (r *RoutedIncludeRepo) LocalitiesByIDs(ids []int) ([]mod.Locality, error) {
+	return r.reader().LocalitiesByIDs(ids)
+}
+
+// ProductsByIDs runs on the reader connection
+func (r *RoutedIncludeRepo) ProductsByIDs(ids []int) ([]mod.Product, error) {
+	return r.reader().ProductsByIDs(ids)
+}
+
+// ProductsBySellerIDs runs on the reader connection
+func (r *RoutedIncludeRepo) ProductsBySellerIDs(ids []int) ([]mod.Product, error) {
+	return r.reader().ProductsBySellerIDs(ids)
+}
+
+// RecordsByProductIDs runs on the reader connection
+func (r *RoutedIncludeRepo) RecordsByProductIDs(ids []int) ([]mod.ProductRecord, error) {
+	return r.reader().RecordsByProductIDs(ids)
+}
+
+// WarehousesByIDs runs on the reader connection
+func (r *RoutedIncludeRepo) WarehousesByIDs(ids []int) ([]mod.Warehouse, error) {
+	return r.reader().WarehousesByIDs(ids)
+}
+
+// SectionsByIDs runs on the reader connection
+func (r *RoutedIncludeRepo) SectionsByIDs(ids []int) ([]mod.Section, error) {
+	return r.reader().SectionsByIDs(ids)
+}
+
+// BatchesBySectionIDs runs on the reader connection
+func (r *RoutedIncludeRepo) BatchesBySectionIDs(ids []int) ([]mod.ProductBatch, error) {
+	return r.reader().BatchesBySectionIDs(ids)
+}
+
+// BuyersByIDs runs on the reader connection
+func (r *RoutedIncludeRepo) BuyersByIDs(ids []int) ([]mod.Buyer, error) {
+	return r.reader().BuyersByIDs(ids)
+}
+
+// DetailsByPurchaseOrderIDs runs on the reader connection
+func (r *RoutedIncludeRepo) DetailsByPurchaseOrderIDs(ids []int) ([]mod.OrderDetails, error) {
+	return r.reader().DetailsByPurchaseOrderIDs(ids)
+}
+
+// NewRoutedTransferRepo sends transfer reads to the replica and transfers to the primary
+func NewRoutedTransferRepo(primary, replica internal.TransferRepository, rt *database.Router) *RoutedTransferRepo {
+	return &RoutedTransferRepo{TransferRepository: primary, routed: routed[internal.TransferRepository]{replica, rt}}
+}
+
+// RoutedTransferRepo is the read/write splitting implementation of the transfer repository
+type RoutedTransferRepo struct {
+	internal.TransferRepository
+	routed[internal.TransferRepository]
+}
+
+// FindAll returns all transfers from the reader connection
+func (r *RoutedTransferRepo) FindAll() ([]mod.Transfer, error) {
+	return database.Route(r.rt, r.TransferRepository, r.replica).FindAll()
+}
+
+// FindByID returns a transfer from the reader connection
+func (r *RoutedTransferRepo) FindByID(id int) (mod.Transfer, error) {
+	return database.Route(r.rt, r.TransferRepository, r.replica).FindByID(id)
+}
+
+// Save moves the stock in the primary
+func (r *RoutedTransferRepo) Save(t *mod.Transfer) error {
+	defer r.rt.MarkWrite()
+	return r.TransferRepository.Save(t)
+}
+
+// NewRoutedStockMovementRepo sends ledger reads to the replica and adjustments and reconciliations to the primary
+func NewRoutedStockMovementRepo(primary, replica internal.StockMovementRepository, rt *database.Router) *RoutedStockMovementRepo {
+	return &RoutedStockMovementRepo{StockMovementRepository: primary, routed: routed[internal.StockMovementRepository]{replica, rt}}
+}
+
+// RoutedStockMovementRepo is the read/write splitting implementation of the stock movement repository. Reconcile
+// stays in the primary, a lagging replica would report discrepancies that do not exist
+type RoutedStockMovementRepo struct {
+	internal.StockMovementRepository
+	routed[internal.StockMovementRepository]
+}
+
+// FindLedgerByBatchID returns the movements of a batch from the reader connection
+func (r *RoutedStockMovementRepo) FindLedgerByBatchID(batchID int) (mod.StockLedger, error) {
+	return database.Route(r.rt, r.StockMovementRepository, r.replica).FindLedgerByBatchID(batchID)
+}
+
+// FindDiscrepancies returns the last discrepancies from the reader connection
+func (r *RoutedStockMovementRepo) FindDiscrepancies() ([]mod.StockDiscrepancy, error) {
+	return database.Route(r.rt, r.StockMovementRepository, r.replica).FindDiscrepancies()
+}
+
+// Adjust applies the movement in the primary
+func (r *RoutedStockMovementRepo) Adjust(m *mod.StockMovement) error {
+	defer r.rt.MarkWrite()
+	return r.StockMovementRepository.Adjust(m)
+}
+
+// NewRoutedSectionReadingRepo sends the telemetry history to the replica and the ingestion to the primary
+func NewRoutedSectionReadingRepo(primary, replica internal.SectionReadingRepository, rt *database.Router) *RoutedSectionReadingRepo {
+	return &RoutedSectionReadingRepo{SectionReadingRepository: primary, routed: routed[internal.SectionReadingRepository]{replica, rt}}
+}
+
+// RoutedSectionReadingRepo is the read/write splitting implementation of the section reading repository
+type RoutedSectionReadingRepo struct {
+	internal.SectionReadingRepository
+	routed[internal.SectionReadingRepository]
+}
+
+// FindReadings returns the readings of a section from the reader connection
+func (r *RoutedSectionReadingRepo) FindReadings(sectionID int, rg mod.SectionReadingRange) ([]mod.SectionReading, error) {
+	return database.Route(r.rt, r.SectionReadingRepository, r.replica).FindReadings(sectionID, rg)
+}
+
+// FindRollups returns the rollups of a section from the replica, even right after a write
+func (r *RoutedSectionReadingRepo) FindRollups(sectionID int, rg mod.SectionReadingRange) ([]mod.SectionReadingRollup, error) {
+	return database.RouteStale(r.rt, r.SectionReadingRepository, r.replica).FindRollups(sectionID, rg)
+}
+
+// FindAlerts returns the alerts of a section from the reader connection
+func (r *RoutedSectionReadingRepo) FindAlerts(sectionID int, open *bool) ([]mod.SectionAlert, error) {
+	return database.Route(r.rt, r.SectionReadingRepository, r.replica).FindAlerts(sectionID, open)
+}
+
+// Save stores the readings in the primary
+func (r *RoutedSectionReadingRepo) Save(sectionID int, readings []mod.SectionReading) (mod.SectionReadingsResult, error) {
+	defer r.rt.MarkWrite()
+	return r.SectionReadingRepository.Save(sectionID, readings)
+}
+
+// NewRoutedSectionSnapshotRepo sends the section history to the replica and the snapshots to the primary
+func NewRoutedSectionSnapshotRepo(primary, replica internal.SectionSnapshotRepository, rt *database.Router) *RoutedSectionSnapshotRepo {
+	return &RoutedSectionSnapshotRepo{SectionSnapshotRepository: primary, routed: routed[internal.SectionSnapshotRepository]{replica, rt}}
+}
+
+// RoutedSectionSnapshotRepo is the read/write splitting implementation of the section snapshot repository
+type RoutedSectionSnapshotRepo struct {
+	internal.SectionSnapshotRepository
+	routed[internal.SectionSnapshotRepository]
+}
+
+// FindHistory returns the history of a section from the replica, even right after a write
+func (r *RoutedSectionSnapshotRepo) FindHistory(sectionID int, q mod.SectionHistoryQuery) ([]mod.SectionHistoryBucket, error) {
+	return database.RouteStale(r.rt, r.SectionSnapshotRepository, r.replica).FindHistory(sectionID, q)
+}
+
+// SnapshotAll takes the snapshots in the primary
+func (r *RoutedSectionSnapshotRepo) SnapshotAll(at time.Time) (int, error) {
+	defer r.rt.MarkWrite()
+	return r.SectionSnapshotRepository.SnapshotAll(at)
+}
+
+// NewRoutedProductTypeRepo sends product type reads to the replica and writes to the primary
+func NewRoutedProductTypeRepo(primary, replica internal.ProductTypeRepository, rt *database.Router) *RoutedProductTypeRepo {
+	return &RoutedProductTypeRepo{ProductTypeRepository: primary, routed: routed[internal.ProductTypeRepository]{replica, rt}}
+}
+
+// RoutedProductTypeRepo is the read/write splitting implementation of the product type repository
+type RoutedProductTypeRepo struct {
+	internal.ProductTypeRepository
+	routed[internal.ProductTypeRepository]
+}
+
+// FindAll returns the product types from the reader connection
+func (r *RoutedProductTypeRepo) FindAll() ([]mod.ProductType, error) {
+	return database.Route(r.rt, r.ProductTypeRepository, r.replica).FindAll()
+}
+
+// FindByID returns a product type from the reader connection
+func (r *RoutedProductTypeRepo) FindByID(id int) (mod.ProductType, error) {
+	return database.Route(r.rt, r.ProductTypeRepository, r.replica).FindByID(id)
+}
+
+// Save saves the product type in the primary
+func (r *RoutedProductTypeRepo) Save(pt *mod.ProductType) error {
+	defer r.rt.MarkWrite()
+	return r.ProductTypeRepository.Save(pt)
+}
+
+// Update updates the product type in the primary
+func (r *RoutedProductTypeRepo) Update(pt *mod.ProductType) error {
+	defer r.rt.MarkWrite()
+	return r.ProductTypeRepository.Update(pt)
+}
+
+// Delete deletes the product type in the primary
+func (r *RoutedProductTypeRepo) Delete(id int) error {
+	defer r.rt.MarkWrite()
+	return r.ProductTypeRepository.Delete(id)
+}
+
+// NewRoutedBatchExpiryRepo sends the expiring batches and the alerts to the replica and the sweep to the primary
+func NewRoutedBatchExpiryRepo(primary, replica internal.BatchExpiryRepository, rt *database.Router) *RoutedBatchExpiryRepo {
+	return &RoutedBatchExpiryRepo{BatchExpiryRepository: primary, routed: routed[internal.BatchExpiryRepository]{replica, rt}}
+}
+
+// RoutedBatchExpiryRepo is the read/write splitting implementation of the batch expiry repository
+type RoutedBatchExpiryRepo struct {
+	internal.BatchExpiryRepository
+	routed[internal.BatchExpiryRepository]
+}
+
+// FindExpiring returns the expiring batches from the reader connection
+func (r *RoutedBatchExpiryRepo) FindExpiring(q mod.ExpiringQuery) ([]mod.ExpiringBatch, error) {
+	return database.Route(r.rt, r.BatchExpiryRepository, r.replica).FindExpiring(q)
+}
+
+// FindAlerts returns the alerts from the reader connection
+func (r *RoutedBatchExpiryRepo) FindAlerts(f mod.AlertFilter) ([]mod.Alert, error) {
+	return database.Route(r.rt, r.BatchExpiryRepository, r.replica).FindAlerts(f)
+}
+
+// Sweep runs the expiry sweep in the primary
+func (r *RoutedBatchExpiryRepo) Sweep(at time.Time, leadTime time.Duration) ([]mod.Alert, error) {
+	defer r.rt.MarkWrite()
+	return r.BatchExpiryRepository.Sweep(at, leadTime)
+}
+
+// NewRoutedTraceRepo sends the traces and the recalls read to the replica and the new recalls to the primary
+func NewRoutedTraceRepo(primary, replica internal.TraceRepository, rt *database.Router) *RoutedTraceRepo {
+	return &RoutedTraceRepo{TraceRepository: primary, routed: routed[internal.TraceRepository]{replica, rt}}
+}
+
+// RoutedTraceRepo is the read/write splitting implementation of the trace repository
+type RoutedTraceRepo struct {
+	internal.TraceRepository
+	routed[internal.TraceRepository]
+}
+
+// FindBatchTrace returns the trace of a batch from the reader connection
+func (r *RoutedTraceRepo) FindBatchTrace(id int) (mod.BatchTrace, error) {
+	return database.Route(r.rt, r.TraceRepository, r.replica).FindBatchTrace(id)
+}
+
+// FindPurchaseOrderTrace returns the trace of a purchase order from the reader connection
+func (r *RoutedTraceRepo) FindPurchaseOrderTrace(id int) (mod.PurchaseOrderTrace, error) {
+	return database.Route(r.rt, r.TraceRepository, r.replica).FindPurchaseOrderTrace(id)
+}
+
+// FindRecall returns a recall from the reader connection
+func (r *RoutedTraceRepo) FindRecall(id int) (mod.Recall, error) {
+	return database.Route(r.rt, r.TraceRepository, r.replica).FindRecall(id)
+}
+
+// Recall records a recall in the primary
+func (r *RoutedTraceRepo) Recall(recall *mod.Recall) error {
+	defer r.rt.MarkWrite()
+	return r.TraceRepository.Recall(recall)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/internal/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutedProductRepo(t *testing.T) {
+	primary, primaryMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer primary.Close()
+	replica, replicaMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer replica.Close()
+	rt := database.NewRouter(primary, replica, time.Hour)
+	repo := NewRoutedProductRepo(NewProductRepo(primary), NewProductRepo(replica), rt)
+
+	query := regexp.QuoteMeta("SELECT `id`, `product_code`, `description`, `height`, `length`, `width`, `net_weight`, `expiration_rate`, `freezing_rate`, `recommended_freezing_temperature`, `product_type_id`, `seller_id` FROM frescos_db.products WHERE id = ?;")
+	columns := []string{"id", "product_code", "description", "height", "length", "width", "net_weight", "expiration_rate", "freezing_rate", "recommended_freezing_temperature", "product_type_id", "seller_id"}
+
+	t.Run("reads go to the replica", func(t *testing.T) {
+		replicaMock.ExpectQuery(query).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "P001", "Product 1", 10.0, 20.0, 5.0, 2.0, 0.1, 0.05, -18.0, 1, 101))
+
+		_, err := repo.FindByID(1)
+		require.NoError(t, err)
+		require.NoError(t, replicaMock.ExpectationsWereMet())
+		require.NoError(t, primaryMock.ExpectationsWereMet())
+	})
+
+	t.Run("writes and the reads after them go to the primary", func(t *testing.T) {
+		primaryMock.ExpectQuery(query).WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "P002", "Product 2", 10.0, 20.0, 5.0, 2.0, 0.1, 0.05, -18.0, 1, 101))
+		primaryMock.ExpectExec(regexp.QuoteMeta("DELETE FROM frescos_db.products WHERE id = ?;")).WithArgs(2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		primaryMock.ExpectQuery(query).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "P001", "Product 1", 10.0, 20.0, 5.0, 2.0, 0.1, 0.05, -18.0, 1, 101))
+
+		require.NoError(t, repo.Delete(2))
+		_, err := repo.FindByID(1)
+		require.NoError(t, err)
+		require.NoError(t, primaryMock.ExpectationsWereMet())
+		require.NoError(t, replicaMock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-sql-driver/mysql"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// searchTarget describes how an entity type is searched, columns must match its FULLTEXT index
+type searchTarget struct {
+	table   string
+	title   string
+	columns []string
+}
+
+// searchTargets are the searchable entities, see docs/SQL/migrations/0001_search_fulltext.sql
+var searchTargets = map[string]searchTarget{
+	"products":   {table: "products", title: "product_code", columns: []string{"product_code", "description"}},
+	"sellers":    {table: "sellers", title: "company_name", columns: []string{"company_name", "cid"}},
+	"buyers":     {table: "buyers", title: "CONCAT(first_name, ' ', last_name)", columns: []string{"first_name", "last_name", "id_card_number"}},
+	"warehouses": {table: "warehouses", title: "warehouse_code", columns: []string{"warehouse_code", "address"}},
+	"carries":    {table: "carries", title: "company_name", columns: []string{"company_name"}},
+}
+
+// errNoFulltextIndex is the MySQL error returned when MATCH has no FULLTEXT index to use
+const errNoFulltextIndex = 1191
+
+// NewSearchRepo creates a new instance of the search repository, with fulltext false the LIKE search is always used
+func NewSearchRepo(db *sql.DB, fulltext bool) *SearchDB {
+	r := &SearchDB{db: db}
+	r.fulltext.Store(fulltext)
+	return r
+}
+
+// SearchDB is the implementation of the search database. It uses the FULLTEXT indexes and falls back to
+// LIKE queries for good when they are missing, e.g. when the migrations were not applied
+type SearchDB struct {
+	db       *sql.DB
+	fulltext atomic.Bool
+}
+
+// Search returns up to limit entities of the given type matching any of the terms
+func (r *SearchDB) Search(entity string, terms []string, limit int) ([]mod.SearchResult, error) {
+	target, ok := searchTargets[entity]
+	if !ok {
+		return nil, e.ErrSearchTypeInvalid
+	}
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	if r.fulltext.Load() {
+		query, args := fulltextSearch(target, terms, limit)
+		result, err := r.query(entity, target, query, args)
+		var mySQLErr *mysql.MySQLError
+		if !errors.As(err, &mySQLErr) || mySQLErr.Number != errNoFulltextIndex {
+			return result, err
+		}
+		r.fulltext.Store(false)
+	}
+	query, args := likeSearch(target, terms, limit)
+	return r.query(entity, target, query, args)
+}
+
+// fulltextSearch builds the MATCH query of the target. BOOLEAN MODE does not sort by relevance, so the rows are
+// ordered by their score before the limit keeps the best ones
+func fulltextSearch(target searchTarget, terms []string, limit int) (string, []any) {
+	cols := strings.Join(target.columns, ", ")
+	match := fmt.Sprintf("MATCH(%s) AGAINST(? IN BOOLEAN MODE)", cols)
+	query := fmt.Sprintf("SELECT id, %s, %s, %s AS score FROM %s WHERE %s ORDER BY score DESC, id LIMIT ?", target.title, cols, match, target.table, match)
+	against := common.FulltextQuery(terms)
+	return query, []any{against, against, limit}
+}
+
+// likeSearch builds the LIKE query of the target, any term in any column is a match. The score favours the
+// columns equal to a term and then the ones starting with it, like common.RankSearchResults, so the limit keeps
+// the best rows
+func likeSearch(target searchTarget, terms []string, limit int) (string, []any) {
+	var scores, conds []string
+	var scoreArgs, condArgs []any
+	for _, t := range terms {
+		for _, col := range target.columns {
+			scores = append(scores, fmt.Sprintf("CASE WHEN %s = ? THEN 3 WHEN %s LIKE ? THEN 2 ELSE 0 END", col, col))
+			scoreArgs = append(scoreArgs, t, common.PrefixPattern(t))
+			conds = append(conds, col+" LIKE ?")
+			condArgs = append(condArgs, common.LikePattern(t))
+		}
+	}
+	query := fmt.Sprintf("SELECT id, %s, %s, %s AS score FROM %s WHERE %s ORDER BY score DESC, id LIMIT ?",
+		target.title, strings.Join(target.columns, ", "), strings.Join(scores, " + "), target.table, strings.Join(conds, " OR "))
+	return query, append(append(scoreArgs, condArgs...), limit)
+}
+
+// query runs a search query and maps its rows to results
+func (r *SearchDB) query(entity string, target searchTarget, query string, args []any) ([]mod.SearchResult, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		var mySQLErr *mysql.MySQLError
+		if errors.As(err, &mySQLErr) {
+			return nil, err
+		}
+		return nil, errors.Join(e.ErrQueryError, err)
+	}
+	defer rows.Close()
+
+	var result []mod.SearchResult
+	for rows.Next() {
+		var id int
+		var title sql.NullString
+		var score float64
+		values := make([]sql.NullString, len(target.columns))
+		dest := []any{&id, &title}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		// the score only orders the rows, the results are ranked by common.RankSearchResults
+		dest = append(dest, &score)
+		if err = rows.Scan(dest...); err != nil {
+			return nil, errors.Join(e.ErrParseError, err)
+		}
+		res := mod.SearchResult{Type: entity, ID: id, Title: title.String, Fields: make(map[string]string)}
+		for i, col := range target.columns {
+			res.Fields[col] = values[i].String
+		}
+		result = append(result, res)
+	}
+	return result, nil
+}
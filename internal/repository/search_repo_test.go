@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchDB_Search(t *testing.T) {
+	fulltextQuery := regexp.QuoteMeta("SELECT id, product_code, product_code, description, MATCH(product_code, description) AGAINST(? IN BOOLEAN MODE) AS score " +
+		"FROM products WHERE MATCH(product_code, description) AGAINST(? IN BOOLEAN MODE) ORDER BY score DESC, id LIMIT ?")
+	likeQuery := regexp.QuoteMeta("SELECT id, product_code, product_code, description, " +
+		"CASE WHEN product_code = ? THEN 3 WHEN product_code LIKE ? THEN 2 ELSE 0 END + CASE WHEN description = ? THEN 3 WHEN description LIKE ? THEN 2 ELSE 0 END AS score " +
+		"FROM products WHERE product_code LIKE ? OR description LIKE ? ORDER BY score DESC, id LIMIT ?")
+	likeArgs := []driver.Value{"peas", "peas%", "peas", "peas%", "%peas%", "%peas%", 10}
+	columns := []string{"id", "title", "product_code", "description", "score"}
+	want := []mod.SearchResult{
+		{Type: "products", ID: 1, Title: "P001", Fields: map[string]string{"product_code": "P001", "description": "Green peas"}},
+	}
+
+	t.Run("uses the FULLTEXT index", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		repo := NewSearchRepo(db, true)
+
+		mock.ExpectQuery(fulltextQuery).WithArgs("peas*", "peas*", 10).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "P001", "P001", "Green peas", 1.5))
+
+		got, err := repo.Search("products", []string{"peas"}, 10)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("falls back to LIKE when the index is missing", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		repo := NewSearchRepo(db, true)
+
+		mock.ExpectQuery(fulltextQuery).WithArgs("peas*", "peas*", 10).
+			WillReturnError(&mysql.MySQLError{Number: 1191, Message: "Can't find FULLTEXT index matching the column list"})
+		mock.ExpectQuery(likeQuery).WithArgs(likeArgs...).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "P001", "P001", "Green peas", 1.5))
+		mock.ExpectQuery(likeQuery).WithArgs(likeArgs...).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		got, err := repo.Search("products", []string{"peas"}, 10)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+		// the following searches go straight to LIKE
+		got, err = repo.Search("products", []string{"peas"}, 10)
+		require.NoError(t, err)
+		require.Empty(t, got)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		repo := NewSearchRepo(db, false)
+
+		mock.ExpectQuery(likeQuery).WillReturnError(sqlmock.ErrCancelled)
+
+		_, err = repo.Search("products", []string{"peas"}, 10)
+		require.ErrorIs(t, err, e.ErrQueryError)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := NewSearchRepo(nil, true).Search("orders", []string{"peas"}, 10)
+		require.ErrorIs(t, err, e.ErrSearchTypeInvalid)
+	})
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewSectionReadingRepo creates a new instance of the section reading repository
+func NewSectionReadingRepo(db *sql.DB) *SectionReadingDB {
+	return &SectionReadingDB{db: db}
+}
+
+// SectionReadingDB is the implementation of the section telemetry, see docs/SQL/migrations/0007_section_readings.sql
+type SectionReadingDB struct {
+	db *sql.DB
+}
+
+// Save locks the section and stores the readings with their rollups and alerts in one transaction, so two
+// sensors of the same section cannot open the same alert twice
+func (r *SectionReadingDB) Save(sectionID int, readings []mod.SectionReading) (result mod.SectionReadingsResult, err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return mod.SectionReadingsResult{}, e.ErrQueryError
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			result, err = mod.SectionReadingsResult{}, e.ErrQueryError
+		}
+	}()
+
+	var minimum float64
+	var productType int
+	err = tx.QueryRow("SELECT `minimum_temperature`, `product_type_id` FROM `sections` WHERE `id` = ? FOR UPDATE", sectionID).Scan(&minimum, &productType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return mod.SectionReadingsResult{}, e.ErrSectionRepositoryNotFound
+	}
+	if err != nil {
+		return mod.SectionReadingsResult{}, e.ErrQueryError
+	}
+	// a section whose product type is not registered only gets the minimum temperature alert
+	var typeMin, typeMax float64
+	hasTypeRange := true
+	err = tx.QueryRow("SELECT `minimum_temperature`, `maximum_temperature` FROM `product_types` WHERE `id` = ?", productType).
+		Scan(&typeMin, &typeMax)
+	if errors.Is(err, sql.ErrNoRows) {
+		hasTypeRange, err = false, nil
+	}
+	if err != nil {
+		return mod.SectionReadingsResult{}, e.ErrQueryError
+	}
+
+	open, err := openAlerts(tx, sectionID)
+	if err != nil {
+		return mod.SectionReadingsResult{}, err
+	}
+
+	result = mod.SectionReadingsResult{AlertsOpened: []mod.SectionAlert{}, AlertsClosed: []int{}}
+	for i := range readings {
+		rd := &readings[i]
+		rd.SectionID = sectionID
+		res, err := tx.Exec("INSERT INTO `section_readings` (`section_id`, `temperature`, `recorded_at`) VALUES (?, ?, ?)",
+			sectionID, rd.Temperature, rd.RecordedAt)
+		if err != nil {
+			return mod.SectionReadingsResult{}, e.ErrInsertError
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return mod.SectionReadingsResult{}, e.ErrInsertError
+		}
+		rd.ID = int(id)
+
+		if _, err = tx.Exec("INSERT INTO `section_reading_rollups` (`section_id`, `bucket_start`, `min_temperature`, `max_temperature`, `sum_temperature`, `readings_count`) "+
+			"VALUES (?, ?, ?, ?, ?, 1) ON DUPLICATE KEY UPDATE `min_temperature` = LEAST(`min_temperature`, VALUES(`min_temperature`)), "+
+			"`max_temperature` = GREATEST(`max_temperature`, VALUES(`max_temperature`)), `sum_temperature` = `sum_temperature` + VALUES(`sum_temperature`), "+
+			"`readings_count` = `readings_count` + 1",
+			sectionID, rd.RecordedAt.Truncate(time.Hour), rd.Temperature, rd.Temperature, rd.Temperature); err != nil {
+			return mod.SectionReadingsResult{}, e.ErrInsertError
+		}
+
+		if err = trackAlert(tx, open, &result, *rd, mod.AlertBelowMinimum, rd.Temperature < minimum, minimum); err != nil {
+			return mod.SectionReadingsResult{}, err
+		}
+		if !hasTypeRange {
+			continue
+		}
+		// the product type range is crossed on the side of the reading
+		typeThreshold := typeMin
+		if rd.Temperature > typeMax {
+			typeThreshold = typeMax
+		}
+		if err = trackAlert(tx, open, &result, *rd, mod.AlertOutsideProductType, rd.Temperature < typeMin || rd.Temperature > typeMax, typeThreshold); err != nil {
+			return mod.SectionReadingsResult{}, err
+		}
+	}
+	result.Accepted = len(readings)
+
+	// the batch may carry readings older than the ones already stored, the current temperature is the latest of all
+	if _, err = tx.Exec("UPDATE `sections` SET `current_temperature` = (SELECT `temperature` FROM `section_readings` "+
+		"WHERE `section_id` = ? ORDER BY `recorded_at` DESC, `id` DESC LIMIT 1) WHERE `id` = ?", sectionID, sectionID); err != nil {
+		return mod.SectionReadingsResult{}, e.ErrQueryError
+	}
+	if err = tx.QueryRow("SELECT `current_temperature` FROM `sections` WHERE `id` = ?", sectionID).Scan(&result.CurrentTemperature); err != nil {
+		return mod.SectionReadingsResult{}, e.ErrQueryError
+	}
+	if err = snapshotSections(tx, time.Now().UTC(), sectionID); err != nil {
+		return mod.SectionReadingsResult{}, err
+	}
+	return result, nil
+}
+
+// openAlerts returns the open alerts of the section by type, locked until the end of the transaction
+func openAlerts(tx *sql.Tx, sectionID int) (map[string]mod.SectionAlert, error) {
+	rows, err := tx.Query("SELECT `id`, `section_id`, `type`, `temperature`, `threshold`, `opened_at` FROM `section_alerts` "+
+		"WHERE `section_id` = ? AND `closed_at` IS NULL ORDER BY `id` FOR UPDATE", sectionID)
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	open := map[string]mod.SectionAlert{}
+	for rows.Next() {
+		var a mod.SectionAlert
+		if err = rows.Scan(&a.ID, &a.SectionID, &a.Type, &a.Temperature, &a.Threshold, &a.OpenedAt); err != nil {
+			return nil, e.ErrParseError
+		}
+		open[a.Type] = a
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return open, nil
+}
+
+// trackAlert opens an alert of the type when the reading breaches its threshold and none is open, and closes the
+// open one when the reading is back in range
+func trackAlert(tx *sql.Tx, open map[string]mod.SectionAlert, result *mod.SectionReadingsResult, rd mod.SectionReading, typ string, breached bool, threshold float64) error {
+	alert, isOpen := open[typ]
+	switch {
+	case breached && !isOpen:
+		alert = mod.SectionAlert{SectionID: rd.SectionID, Type: typ, Temperature: rd.Temperature, Threshold: threshold, OpenedAt: rd.RecordedAt}
+		res, err := tx.Exec("INSERT INTO `section_alerts` (`section_id`, `type`, `temperature`, `threshold`, `opened_at`) VALUES (?, ?, ?, ?, ?)",
+			alert.SectionID, alert.Type, alert.Temperature, alert.Threshold, alert.OpenedAt)
+		if err != nil {
+			return e.ErrInsertError
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return e.ErrInsertError
+		}
+		alert.ID = int(id)
+		open[typ] = alert
+		result.AlertsOpened = append(result.AlertsOpened, alert)
+	case !breached && isOpen:
+		if _, err := tx.Exec("UPDATE `section_alerts` SET `closed_at` = ? WHERE `id` = ?", rd.RecordedAt, alert.ID); err != nil {
+			return e.ErrQueryError
+		}
+		delete(open, typ)
+		result.AlertsClosed = append(result.AlertsClosed, alert.ID)
+	}
+	return nil
+}
+
+// FindReadings returns the readings of the section recorded in [from, to), oldest first
+func (r *SectionReadingDB) FindReadings(sectionID int, rg mod.SectionReadingRange) ([]mod.SectionReading, error) {
+	rows, err := r.db.Query("SELECT `id`, `section_id`, `temperature`, `recorded_at` FROM `section_readings` "+
+		"WHERE `section_id` = ? AND `recorded_at` >= ? AND `recorded_at` < ? ORDER BY `recorded_at`, `id`", sectionID, rg.From, rg.To)
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	readings := []mod.SectionReading{}
+	for rows.Next() {
+		var rd mod.SectionReading
+		if err = rows.Scan(&rd.ID, &rd.SectionID, &rd.Temperature, &rd.RecordedAt); err != nil {
+			return nil, e.ErrParseError
+		}
+		readings = append(readings, rd)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return readings, nil
+}
+
+// FindRollups returns the hourly rollups of the section whose hour starts in [from, to), oldest first
+func (r *SectionReadingDB) FindRollups(sectionID int, rg mod.SectionReadingRange) ([]mod.SectionReadingRollup, error) {
+	rows, err := r.db.Query("SELECT `section_id`, `bucket_start`, `min_temperature`, `max_temperature`, "+
+		"ROUND(`sum_temperature` / `readings_count`, 2), `readings_count` FROM `section_reading_rollups` "+
+		"WHERE `section_id` = ? AND `bucket_start` >= ? AND `bucket_start` < ? ORDER BY `bucket_start`",
+		sectionID, rg.From.Truncate(time.Hour), rg.To)
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	rollups := []mod.SectionReadingRollup{}
+	for rows.Next() {
+		var ru mod.SectionReadingRollup
+		if err = rows.Scan(&ru.SectionID, &ru.BucketStart, &ru.MinTemperature, &ru.MaxTemperature, &ru.AvgTemperature, &ru.ReadingsCount); err != nil {
+			return nil, e.ErrParseError
+		}
+		rollups = append(rollups, ru)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return rollups, nil
+}
+
+// FindAlerts returns the alerts of the section, newest first, only the open or the closed ones when open is set
+func (r *SectionReadingDB) FindAlerts(sectionID int, open *bool) ([]mod.SectionAlert, error) {
+	query := "SELECT `id`, `section_id`, `type`, `temperature`, `threshold`, `opened_at`, `closed_at` FROM `section_alerts` WHERE `section_id` = ?"
+	if open != nil {
+		if *open {
+			query += " AND `closed_at` IS NULL"
+		} else {
+			query += " AND `closed_at` IS NOT NULL"
+		}
+	}
+	rows, err := r.db.Query(query+" ORDER BY `opened_at` DESC, `id` DESC", sectionID)
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	alerts := []mod.SectionAlert{}
+	for rows.Next() {
+		var a mod.SectionAlert
+		var closed sql.NullTime
+		if err = rows.Scan(&a.ID, &a.SectionID, &a.Type, &a.Temperature, &a.Threshold, &a.OpenedAt, &closed); err != nil {
+			return nil, e.ErrParseError
+		}
+		if closed.Valid {
+			a.ClosedAt = &closed.Time
+		}
+		alerts = append(alerts, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return alerts, nil
+}
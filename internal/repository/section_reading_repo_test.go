@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	readingSectionLock = regexp.QuoteMeta("SELECT `minimum_temperature`, `product_type_id` FROM `sections` WHERE `id` = ? FOR UPDATE")
+	readingTypeRange   = regexp.QuoteMeta("SELECT `minimum_temperature`, `maximum_temperature` FROM `product_types` WHERE `id` = ?")
+	readingOpenAlerts  = regexp.QuoteMeta("FROM `section_alerts` WHERE `section_id` = ? AND `closed_at` IS NULL ORDER BY `id` FOR UPDATE")
+	readingInsert      = regexp.QuoteMeta("INSERT INTO `section_readings` (`section_id`, `temperature`, `recorded_at`) VALUES (?, ?, ?)")
+	readingRollup      = regexp.QuoteMeta("INSERT INTO `section_reading_rollups`")
+	readingAlertInsert = regexp.QuoteMeta("INSERT INTO `section_alerts`")
+	readingAlertClose  = regexp.QuoteMeta("UPDATE `section_alerts` SET `closed_at` = ? WHERE `id` = ?")
+	readingCurrentSet  = regexp.QuoteMeta("UPDATE `sections` SET `current_temperature` = (SELECT `temperature` FROM `section_readings`")
+	readingCurrentGet  = regexp.QuoteMeta("SELECT `current_temperature` FROM `sections` WHERE `id` = ?")
+)
+
+var alertColumns = []string{"id", "section_id", "type", "temperature", "threshold", "opened_at"}
+
+func TestSectionReadingDB_Save(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 15, 0, 0, time.UTC)
+
+	t.Run("reading below the minimum opens an alert and the next one in range closes it", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(readingSectionLock).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"minimum_temperature", "product_type_id"}).AddRow(0, 3))
+		mock.ExpectQuery(readingTypeRange).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"minimum_temperature", "maximum_temperature"}).AddRow(-5, 8))
+		mock.ExpectQuery(readingOpenAlerts).WithArgs(1).WillReturnRows(sqlmock.NewRows(alertColumns))
+		mock.ExpectExec(readingInsert).WithArgs(1, -2.5, at).WillReturnResult(sqlmock.NewResult(20, 1))
+		mock.ExpectExec(readingRollup).WithArgs(1, at.Truncate(time.Hour), -2.5, -2.5, -2.5).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(readingAlertInsert).WithArgs(1, mod.AlertBelowMinimum, -2.5, 0.0, at).WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectExec(readingInsert).WithArgs(1, 1.0, at.Add(time.Minute)).WillReturnResult(sqlmock.NewResult(21, 1))
+		mock.ExpectExec(readingRollup).WithArgs(1, at.Truncate(time.Hour), 1.0, 1.0, 1.0).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(readingAlertClose).WithArgs(at.Add(time.Minute), 7).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(readingCurrentSet).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(readingCurrentGet).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"current_temperature"}).AddRow(1.0))
+		mock.ExpectExec(sectionSnapshotInsert).WithArgs(mod.SnapshotUpdate, sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		readings := []mod.SectionReading{{Temperature: -2.5, RecordedAt: at}, {Temperature: 1, RecordedAt: at.Add(time.Minute)}}
+		result, err := NewSectionReadingRepo(db).Save(1, readings)
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Accepted)
+		require.Equal(t, 1.0, result.CurrentTemperature)
+		require.Len(t, result.AlertsOpened, 1)
+		require.Equal(t, 7, result.AlertsOpened[0].ID)
+		require.Equal(t, []int{7}, result.AlertsClosed)
+		require.Equal(t, 21, readings[1].ID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("open alert stays open while the readings are below the minimum", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(readingSectionLock).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"minimum_temperature", "product_type_id"}).AddRow(0, 3))
+		mock.ExpectQuery(readingTypeRange).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"minimum_temperature", "maximum_temperature"}).AddRow(-5, 8))
+		mock.ExpectQuery(readingOpenAlerts).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(alertColumns).AddRow(7, 1, mod.AlertBelowMinimum, -2.5, 0, at.Add(-time.Hour)))
+		mock.ExpectExec(readingInsert).WithArgs(1, -1.0, at).WillReturnResult(sqlmock.NewResult(22, 1))
+		mock.ExpectExec(readingRollup).WithArgs(1, at.Truncate(time.Hour), -1.0, -1.0, -1.0).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(readingCurrentSet).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(readingCurrentGet).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"current_temperature"}).AddRow(-1.0))
+		mock.ExpectExec(sectionSnapshotInsert).WithArgs(mod.SnapshotUpdate, sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		result, err := NewSectionReadingRepo(db).Save(1, []mod.SectionReading{{Temperature: -1, RecordedAt: at}})
+		require.NoError(t, err)
+		require.Empty(t, result.AlertsOpened)
+		require.Empty(t, result.AlertsClosed)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("missing section", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(readingSectionLock).WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"minimum_temperature", "product_type_id"}))
+		mock.ExpectRollback()
+
+		_, err = NewSectionReadingRepo(db).Save(9, []mod.SectionReading{{Temperature: 1, RecordedAt: at}})
+		require.ErrorIs(t, err, e.ErrSectionRepositoryNotFound)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSectionReadingDB_FindRollups(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	from := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	to := from.Add(2 * time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM `section_reading_rollups` WHERE `section_id` = ? AND `bucket_start` >= ? AND `bucket_start` < ?")).
+		WithArgs(1, from.Truncate(time.Hour), to).
+		WillReturnRows(sqlmock.NewRows([]string{"section_id", "bucket_start", "min", "max", "avg", "count"}).
+			AddRow(1, from.Truncate(time.Hour), -1.0, 3.0, 1.25, 4))
+
+	rollups, err := NewSectionReadingRepo(db).FindRollups(1, mod.SectionReadingRange{From: from, To: to})
+	require.NoError(t, err)
+	require.Equal(t, []mod.SectionReadingRollup{{SectionID: 1, BucketStart: from.Truncate(time.Hour), MinTemperature: -1, MaxTemperature: 3, AvgTemperature: 1.25, ReadingsCount: 4}}, rollups)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSectionReadingDB_FindAlerts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	opened := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	closed := opened.Add(time.Hour)
+	open := false
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE `section_id` = ? AND `closed_at` IS NOT NULL ORDER BY `opened_at` DESC, `id` DESC")).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(append(alertColumns, "closed_at")).AddRow(7, 1, mod.AlertBelowMinimum, -2.5, 0, opened, closed))
+
+	alerts, err := NewSectionReadingRepo(db).FindAlerts(1, &open)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	require.Equal(t, closed, *alerts[0].ClosedAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSectionReadingDB_Save_ProductTypeRange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	at := time.Date(2024, 5, 1, 10, 15, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(readingSectionLock).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"minimum_temperature", "product_type_id"}).AddRow(0, 3))
+	mock.ExpectQuery(readingTypeRange).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"minimum_temperature", "maximum_temperature"}).AddRow(-5, 8))
+	mock.ExpectQuery(readingOpenAlerts).WithArgs(1).WillReturnRows(sqlmock.NewRows(alertColumns))
+	mock.ExpectExec(readingInsert).WithArgs(1, 9.5, at).WillReturnResult(sqlmock.NewResult(30, 1))
+	mock.ExpectExec(readingRollup).WithArgs(1, at.Truncate(time.Hour), 9.5, 9.5, 9.5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(readingAlertInsert).WithArgs(1, mod.AlertOutsideProductType, 9.5, 8.0, at).WillReturnResult(sqlmock.NewResult(8, 1))
+	mock.ExpectExec(readingCurrentSet).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(readingCurrentGet).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"current_temperature"}).AddRow(9.5))
+	mock.ExpectExec(sectionSnapshotInsert).WithArgs(mod.SnapshotUpdate, sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := NewSectionReadingRepo(db).Save(1, []mod.SectionReading{{Temperature: 9.5, RecordedAt: at}})
+	require.NoError(t, err)
+	require.Equal(t, []mod.SectionAlert{{ID: 8, SectionID: 1, Type: mod.AlertOutsideProductType, Temperature: 9.5, Threshold: 8, OpenedAt: at}}, result.AlertsOpened)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSectionReadingDB_Save_WithoutProductType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	at := time.Date(2024, 5, 1, 10, 15, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(readingSectionLock).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"minimum_temperature", "product_type_id"}).AddRow(0, 3))
+	mock.ExpectQuery(readingTypeRange).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"minimum_temperature", "maximum_temperature"}))
+	mock.ExpectQuery(readingOpenAlerts).WithArgs(1).WillReturnRows(sqlmock.NewRows(alertColumns))
+	mock.ExpectExec(readingInsert).WithArgs(1, 40.0, at).WillReturnResult(sqlmock.NewResult(31, 1))
+	mock.ExpectExec(readingRollup).WithArgs(1, at.Truncate(time.Hour), 40.0, 40.0, 40.0).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(readingCurrentSet).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(readingCurrentGet).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"current_temperature"}).AddRow(40.0))
+	mock.ExpectExec(sectionSnapshotInsert).WithArgs(mod.SnapshotUpdate, sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	result, err := NewSectionReadingRepo(db).Save(1, []mod.SectionReading{{Temperature: 40, RecordedAt: at}})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Accepted)
+	require.Empty(t, result.AlertsOpened)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
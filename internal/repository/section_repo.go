@@ -7,7 +7,10 @@ import (
 	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
 	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"math"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // NewSectionRepo creates a new instance of the Section repository
@@ -66,6 +69,9 @@ func (r *SectionDB) Save(section *mod.Section) (err error) {
 	if err != nil {
 		var mySQLErr *mysql.MySQLError
 		if errors.As(err, &mySQLErr) {
+			if mySQLErr.Number == 1452 && strings.Contains(mySQLErr.Message, "fk_sections_product_type") {
+				return e.ErrProductTypeNotFound
+			}
 			if mySQLErr.Number == 1452 {
 				return e.ErrForeignKeyError
 			}
@@ -86,15 +92,23 @@ func (r *SectionDB) Save(section *mod.Section) (err error) {
 	return
 }
 
-// Update updates a section in the database
+// Update updates a section in the database and snapshots it in the same transaction when it changed
 func (r *SectionDB) Update(id int, fields map[string]interface{}) (result *mod.Section, err error) {
 	//Build query
 	query, args := common.BuildPatchQuery("sections", fields, strconv.Itoa(id), nil)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
 	// execute the query
-	res, err := r.db.Exec(query, args...)
+	res, err := tx.Exec(query, args...)
 	if err != nil {
+		tx.Rollback()
 		var mySQLErr *mysql.MySQLError
 		if errors.As(err, &mySQLErr) {
+			if mySQLErr.Number == 1452 && strings.Contains(mySQLErr.Message, "fk_sections_product_type") {
+				return nil, e.ErrProductTypeNotFound
+			}
 			if mySQLErr.Number == 1452 {
 				return nil, e.ErrForeignKeyError
 			}
@@ -104,12 +118,22 @@ func (r *SectionDB) Update(id int, fields map[string]interface{}) (result *mod.S
 		}
 		return
 	}
+	// every update that changes the section leaves a snapshot in its history
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected > 0 {
+		if _, err = tx.Exec(insertSectionSnapshot+" WHERE `id` = ?", mod.SnapshotUpdate, time.Now().UTC(), id); err != nil {
+			tx.Rollback()
+			return nil, e.ErrInsertError
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, e.ErrQueryError
+	}
 
 	sec, err := r.FindByID(id)
 	if err != nil {
 		return nil, e.ErrSectionRepositoryNotFound
 	}
-	rowsAffected, _ := res.RowsAffected()
 	if int(rowsAffected) == 0 {
 		return nil, e.ErrNoRowsAffected
 	}
@@ -129,11 +153,12 @@ func (r *SectionDB) Delete(id int) (err error) { // execute the query
 	return nil
 }
 
-func (r *SectionDB) ReportProducts(ids []int) ([]mod.ReportProductsResponse, error) {
-	query, args := common.GetQueryReport(ids)
+// ReportProducts returns the stock report of the sections of the filter, it fails when a section of f.IDs does not exist
+func (r *SectionDB) ReportProducts(f mod.SectionReportFilter) ([]mod.ReportProductsResponse, error) {
+	query, args := common.GetQueryReport(f)
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, e.ErrQueryError
 	}
 	defer rows.Close()
 
@@ -142,21 +167,77 @@ func (r *SectionDB) ReportProducts(ids []int) ([]mod.ReportProductsResponse, err
 
 	for rows.Next() {
 		var result mod.ReportProductsResponse
-		err := rows.Scan(&result.SectionId, &result.SectionNumber, &result.ProductsCount)
-		if err != nil {
-			return nil, err
+		result.Expiring = make([]mod.ExpiryWindow, len(f.Windows))
+		dest := []any{&result.SectionId, &result.SectionNumber, &result.WarehouseID, &result.ProductTypeID,
+			&result.MaximumCapacity, &result.ProductsCount, &result.BatchesCount, &result.CurrentQuantity,
+			&result.HeldBatches, &result.HeldQuantity, &result.ExpiredBatches, &result.ExpiredQuantity}
+		for i, days := range f.Windows {
+			result.Expiring[i].Days = days
+			dest = append(dest, &result.Expiring[i].Batches, &result.Expiring[i].Quantity)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, e.ErrParseError
+		}
+		if result.MaximumCapacity > 0 {
+			result.FillPercentage = math.Round(float64(result.CurrentQuantity)*10000/float64(result.MaximumCapacity)) / 100
 		}
 		results = append(results, result)
 		foundIDs[result.SectionId] = true
 	}
+	if err := rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
 
-	if len(ids) > 0 {
-		for _, id := range ids {
-			if !foundIDs[id] {
-				return nil, e.ErrSectionRepositoryNotFound
-			}
+	for _, id := range f.IDs {
+		if !foundIDs[id] {
+			return nil, e.ErrSectionRepositoryNotFound
 		}
 	}
 
 	return results, nil
 }
+
+// FindSlotCandidates returns the product of the request and the sections that store its product type and have room
+// for the quantity, with the batches of the product each one already holds
+func (r *SectionDB) FindSlotCandidates(req mod.SlottingRequest) (mod.SlottingProduct, []mod.SlottingSection, error) {
+	product := mod.SlottingProduct{ID: req.ProductID}
+	err := r.db.QueryRow("SELECT `product_type_id`, `recommended_freezing_temperature` FROM `products` WHERE `id` = ?", req.ProductID).
+		Scan(&product.ProductTypeID, &product.RecomFreezTemp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return mod.SlottingProduct{}, nil, e.ErrProductRepositoryNotFound
+	}
+	if err != nil {
+		return mod.SlottingProduct{}, nil, e.ErrQueryError
+	}
+
+	query := "SELECT s.`id`, s.`section_number`, s.`warehouse_id`, s.`current_temperature`, s.`minimum_temperature`, " +
+		"s.`current_capacity`, s.`maximum_capacity`, COUNT(pb.`id`) FROM `sections` AS s " +
+		"LEFT JOIN `product_batches` AS pb ON pb.`section_id` = s.`id` AND pb.`product_id` = ? AND pb.`current_quantity` > 0 " +
+		"WHERE s.`product_type_id` = ? AND s.`maximum_capacity` - s.`current_capacity` >= ?"
+	args := []any{req.ProductID, product.ProductTypeID, req.Quantity}
+	if req.WarehouseID > 0 {
+		query += " AND s.`warehouse_id` = ?"
+		args = append(args, req.WarehouseID)
+	}
+	query += " GROUP BY s.`id`, s.`section_number`, s.`warehouse_id`, s.`current_temperature`, s.`minimum_temperature`, " +
+		"s.`current_capacity`, s.`maximum_capacity` ORDER BY s.`id`"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return mod.SlottingProduct{}, nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	candidates := []mod.SlottingSection{}
+	for rows.Next() {
+		var c mod.SlottingSection
+		if err = rows.Scan(&c.SectionID, &c.SectionNumber, &c.WarehouseID, &c.CurrentTemperature, &c.MinimumTemperature,
+			&c.CurrentCapacity, &c.MaximumCapacity, &c.ProductBatches); err != nil {
+			return mod.SlottingProduct{}, nil, e.ErrParseError
+		}
+		candidates = append(candidates, c)
+	}
+	if err = rows.Err(); err != nil {
+		return mod.SlottingProduct{}, nil, e.ErrQueryError
+	}
+	return product, candidates, nil
+}
@@ -7,6 +7,7 @@ import (
 	m "github.com/smartineztri_meli/W17-G2-Bootcamp/tests/mock"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
@@ -316,9 +317,14 @@ func TestSectionDB_Update(t *testing.T) {
 			fields: map[string]interface{}{"current_capacity": 77, "minimum_capacity": 35},
 			setupMock: func(mock sqlmock.Sqlmock, id int, fields map[string]interface{}, mockSec mod.Section) {
 				_, args := common.BuildPatchQuery("sections", fields, strconv.Itoa(id), nil)
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE sections SET").
 					WithArgs(toDriverValueSlice(args)...).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(sectionSnapshotInsert).
+					WithArgs(mod.SnapshotUpdate, sqlmock.AnyArg(), id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
 
 				findRows := sqlmock.NewRows([]string{
 					"id", "section_number", "current_temperature", "minimum_temperature",
@@ -352,9 +358,11 @@ func TestSectionDB_Update(t *testing.T) {
 			fields: map[string]interface{}{"minimum_temperature": -12},
 			setupMock: func(mock sqlmock.Sqlmock, id int, fields map[string]interface{}, mockSec mod.Section) {
 				_, args := common.BuildPatchQuery("sections", fields, strconv.Itoa(id), nil)
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE sections SET").
 					WithArgs(toDriverValueSlice(args)...).
 					WillReturnError(e.FkErr)
+				mock.ExpectRollback()
 			},
 			expected:    &mod.Section{},
 			expectedErr: e.ErrForeignKeyError,
@@ -365,9 +373,11 @@ func TestSectionDB_Update(t *testing.T) {
 			fields: map[string]interface{}{"minimum_temperature": -12},
 			setupMock: func(mock sqlmock.Sqlmock, id int, fields map[string]interface{}, mockSec mod.Section) {
 				_, args := common.BuildPatchQuery("sections", fields, strconv.Itoa(id), nil)
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE sections SET").
 					WithArgs(toDriverValueSlice(args)...).
 					WillReturnError(e.DupErr)
+				mock.ExpectRollback()
 			},
 			expected:    &mod.Section{},
 			expectedErr: e.ErrSectionRepositoryDuplicated,
@@ -378,9 +388,11 @@ func TestSectionDB_Update(t *testing.T) {
 			fields: map[string]interface{}{"minimum_temperature": -12},
 			setupMock: func(mock sqlmock.Sqlmock, id int, fields map[string]interface{}, mockSec mod.Section) {
 				_, args := common.BuildPatchQuery("sections", fields, strconv.Itoa(id), nil)
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE sections SET").
 					WithArgs(toDriverValueSlice(args)...).
 					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
 				findRows := sqlmock.NewRows([]string{
 					"id", "section_number", "current_temperature", "minimum_temperature",
 					"current_capacity", "minimum_capacity", "maximum_capacity",
@@ -402,9 +414,11 @@ func TestSectionDB_Update(t *testing.T) {
 			fields: map[string]interface{}{"minimum_temperature": -12},
 			setupMock: func(mock sqlmock.Sqlmock, id int, fields map[string]interface{}, mockSec mod.Section) {
 				_, args := common.BuildPatchQuery("sections", fields, strconv.Itoa(id), nil)
+				mock.ExpectBegin()
 				mock.ExpectExec("UPDATE sections SET").
 					WithArgs(toDriverValueSlice(args)...).
 					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
 
 				mock.ExpectQuery(m.SectionSelectWhereExpectedQuery).
 					WithArgs(id).
@@ -413,6 +427,24 @@ func TestSectionDB_Update(t *testing.T) {
 			expected:    &mod.Section{},
 			expectedErr: e.ErrSectionRepositoryNotFound,
 		},
+		{
+			name:   "snapshot error",
+			id:     1,
+			fields: map[string]interface{}{"current_capacity": 77},
+			setupMock: func(mock sqlmock.Sqlmock, id int, fields map[string]interface{}, mockSec mod.Section) {
+				_, args := common.BuildPatchQuery("sections", fields, strconv.Itoa(id), nil)
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE sections SET").
+					WithArgs(toDriverValueSlice(args)...).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(sectionSnapshotInsert).
+					WithArgs(mod.SnapshotUpdate, sqlmock.AnyArg(), id).
+					WillReturnError(sql.ErrConnDone)
+				mock.ExpectRollback()
+			},
+			expected:    &mod.Section{},
+			expectedErr: e.ErrInsertError,
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -433,83 +465,100 @@ func TestSectionDB_Update(t *testing.T) {
 }
 
 func TestSectionDB_ReportProducts(t *testing.T) {
-	toDriverValueSlice := func(args []int) []driver.Value {
-		result := make([]driver.Value, len(args))
-		for i, v := range args {
-			result[i] = v
-		}
-		return result
+	at := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	week := at.AddDate(0, 0, 7)
+	month := at.AddDate(0, 0, 30)
+	// reportArgs are the due date boundaries of the expired and the 7 and 30 days columns, then the filters
+	reportArgs := func(filters ...driver.Value) []driver.Value {
+		return append([]driver.Value{at, at, at, week, at, week, at, month, at, month}, filters...)
+	}
+	columns := []string{"id", "section_number", "warehouse_id", "product_type_id", "maximum_capacity", "products",
+		"batches", "quantity", "held_batches", "held_quantity", "expired_batches", "expired_quantity", "batches_7", "quantity_7", "batches_30", "quantity_30"}
+	expiring := func(week, weekQuantity, month, monthQuantity int) []mod.ExpiryWindow {
+		return []mod.ExpiryWindow{{Days: 7, Batches: week, Quantity: weekQuantity}, {Days: 30, Batches: month, Quantity: monthQuantity}}
 	}
 
 	type testCase struct {
 		name       string
-		setupMock  func(sqlmock.Sqlmock, []int)
-		ids        []int
+		setupMock  func(sqlmock.Sqlmock)
+		filter     mod.SectionReportFilter
 		wantResult []mod.ReportProductsResponse
 		wantErr    error
 	}
 
 	tests := []testCase{
 		{
-			name: "happy path - all IDs found",
-			ids:  []int{1, 2},
-			setupMock: func(mock sqlmock.Sqlmock, ids []int) {
-				// Row with products for id=1 and id=2
-				rows := sqlmock.NewRows([]string{"section_id", "section_number", "products_count"}).
-					AddRow(1, 101, 7).
-					AddRow(2, 202, 3)
+			name:   "happy path - all IDs found",
+			filter: mod.SectionReportFilter{IDs: []int{1, 2}},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, 101, 1, 2, 300, 7, 9, 100, 1, 15, 1, 10, 2, 20, 4, 40).
+					AddRow(2, 202, 1, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)
 				mock.ExpectQuery("SELECT (.+) FROM (.+)").
-					WithArgs(toDriverValueSlice(ids)...).
+					WithArgs(reportArgs(1, 2)...).
+					WillReturnRows(rows)
+			},
+			wantResult: []mod.ReportProductsResponse{
+				{SectionId: 1, SectionNumber: 101, WarehouseID: 1, ProductTypeID: 2, ProductsCount: 7, BatchesCount: 9,
+					CurrentQuantity: 100, HeldBatches: 1, HeldQuantity: 15, MaximumCapacity: 300, FillPercentage: 33.33, ExpiredBatches: 1, ExpiredQuantity: 10,
+					Expiring: expiring(2, 20, 4, 40)},
+				{SectionId: 2, SectionNumber: 202, WarehouseID: 1, ProductTypeID: 2, Expiring: expiring(0, 0, 0, 0)},
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "filter by warehouse and product type",
+			filter: mod.SectionReportFilter{WarehouseID: 3, ProductTypeID: 4},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).
+					AddRow(5, 505, 3, 4, 50, 1, 1, 50, 0, 0, 0, 0, 0, 0, 1, 50)
+				mock.ExpectQuery("SELECT (.+) FROM (.+) WHERE s.warehouse_id = \\? AND s.product_type_id = \\?").
+					WithArgs(reportArgs(3, 4)...).
 					WillReturnRows(rows)
 			},
 			wantResult: []mod.ReportProductsResponse{
-				{SectionId: 1, SectionNumber: 101, ProductsCount: 7},
-				{SectionId: 2, SectionNumber: 202, ProductsCount: 3},
+				{SectionId: 5, SectionNumber: 505, WarehouseID: 3, ProductTypeID: 4, ProductsCount: 1, BatchesCount: 1,
+					CurrentQuantity: 50, MaximumCapacity: 50, FillPercentage: 100, Expiring: expiring(0, 0, 1, 50)},
 			},
 			wantErr: nil,
 		},
 		{
-			name: "error from db.Query",
-			ids:  []int{1, 2},
-			setupMock: func(mock sqlmock.Sqlmock, ids []int) {
+			name:   "error from db.Query",
+			filter: mod.SectionReportFilter{IDs: []int{1, 2}},
+			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery("SELECT (.+) FROM (.+)").
-					WithArgs(toDriverValueSlice(ids)...).
-					WillReturnError(e.ErrQueryError)
+					WithArgs(reportArgs(1, 2)...).
+					WillReturnError(sql.ErrConnDone)
 			},
 			wantResult: nil,
 			wantErr:    e.ErrQueryError,
 		},
 		{
-			name: "some IDs not found",
-			ids:  []int{1, 2, 3},
-			setupMock: func(mock sqlmock.Sqlmock, ids []int) {
+			name:   "some IDs not found",
+			filter: mod.SectionReportFilter{IDs: []int{1, 2, 3}},
+			setupMock: func(mock sqlmock.Sqlmock) {
 				// Only id 1 and 2 found in query result, id 3 is missing!
-				rows := sqlmock.NewRows([]string{"section_id", "section_number", "products_count"}).
-					AddRow(1, 101, 7).
-					AddRow(2, 202, 3)
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, 101, 1, 2, 300, 7, 9, 100, 1, 15, 1, 10, 2, 20, 4, 40).
+					AddRow(2, 202, 1, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)
 				mock.ExpectQuery("SELECT (.+) FROM (.+)").
-					WithArgs(toDriverValueSlice(ids)...).
+					WithArgs(reportArgs(1, 2, 3)...).
 					WillReturnRows(rows)
 			},
 			wantResult: nil,
 			wantErr:    e.ErrSectionRepositoryNotFound,
 		},
 		{
-			name: "no ids filter, returns all",
-			ids:  nil,
-			setupMock: func(mock sqlmock.Sqlmock, ids []int) {
-				rows := sqlmock.NewRows([]string{"section_id", "section_number", "products_count"}).
-					AddRow(2, 202, 2).
-					AddRow(4, 404, 0)
+			name:   "scan error",
+			filter: mod.SectionReportFilter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "section_number", "products_count"}).AddRow(2, 202, 2)
 				mock.ExpectQuery("SELECT (.+) FROM (.+)").
-					WithoutArgs().
+					WithArgs(reportArgs()...).
 					WillReturnRows(rows)
 			},
-			wantResult: []mod.ReportProductsResponse{
-				{SectionId: 2, SectionNumber: 202, ProductsCount: 2},
-				{SectionId: 4, SectionNumber: 404, ProductsCount: 0},
-			},
-			wantErr: nil,
+			wantResult: nil,
+			wantErr:    e.ErrParseError,
 		},
 	}
 
@@ -517,8 +566,10 @@ func TestSectionDB_ReportProducts(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			repo, mock, teardown := setupMockSectionRepo(t)
 			defer teardown()
-			tc.setupMock(mock, tc.ids)
-			got, err := repo.ReportProducts(tc.ids)
+			tc.setupMock(mock)
+			tc.filter.At = at
+			tc.filter.Windows = []int{7, 30}
+			got, err := repo.ReportProducts(tc.filter)
 			if tc.wantErr != nil {
 				require.Error(t, err)
 				require.EqualError(t, err, tc.wantErr.Error())
@@ -530,3 +581,52 @@ func TestSectionDB_ReportProducts(t *testing.T) {
 		})
 	}
 }
+
+func TestSectionDB_FindSlotCandidates(t *testing.T) {
+	productQuery := "SELECT `product_type_id`, `recommended_freezing_temperature` FROM `products` WHERE `id` = ?"
+	columns := []string{"id", "section_number", "warehouse_id", "current_temperature", "minimum_temperature",
+		"current_capacity", "maximum_capacity", "batches"}
+
+	t.Run("candidates of a warehouse", func(t *testing.T) {
+		repo, mock, teardown := setupMockSectionRepo(t)
+		defer teardown()
+		mock.ExpectQuery(productQuery).WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"product_type_id", "recommended_freezing_temperature"}).AddRow(2, -18.0))
+		mock.ExpectQuery("SELECT (.+) FROM `sections` AS s LEFT JOIN `product_batches` AS pb (.+) WHERE s.`product_type_id` = \\? "+
+			"AND s.`maximum_capacity` - s.`current_capacity` >= \\? AND s.`warehouse_id` = \\? GROUP BY (.+)").
+			WithArgs(4, 2, 10, 1).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(3, 13, 1, -20.0, -25.0, 40, 100, 2))
+
+		product, candidates, err := repo.FindSlotCandidates(mod.SlottingRequest{ProductID: 4, Quantity: 10, WarehouseID: 1})
+
+		require.NoError(t, err)
+		require.Equal(t, mod.SlottingProduct{ID: 4, ProductTypeID: 2, RecomFreezTemp: -18}, product)
+		require.Equal(t, []mod.SlottingSection{{SectionID: 3, SectionNumber: 13, WarehouseID: 1, CurrentTemperature: -20,
+			MinimumTemperature: -25, CurrentCapacity: 40, MaximumCapacity: 100, ProductBatches: 2}}, candidates)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("product not found", func(t *testing.T) {
+		repo, mock, teardown := setupMockSectionRepo(t)
+		defer teardown()
+		mock.ExpectQuery(productQuery).WithArgs(4).WillReturnError(sql.ErrNoRows)
+
+		_, _, err := repo.FindSlotCandidates(mod.SlottingRequest{ProductID: 4, Quantity: 10})
+
+		require.ErrorIs(t, err, e.ErrProductRepositoryNotFound)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("sections query error", func(t *testing.T) {
+		repo, mock, teardown := setupMockSectionRepo(t)
+		defer teardown()
+		mock.ExpectQuery(productQuery).WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"product_type_id", "recommended_freezing_temperature"}).AddRow(2, -18.0))
+		mock.ExpectQuery("SELECT (.+) FROM `sections`").WithArgs(4, 2, 10).WillReturnError(sql.ErrConnDone)
+
+		_, _, err := repo.FindSlotCandidates(mod.SlottingRequest{ProductID: 4, Quantity: 10})
+
+		require.ErrorIs(t, err, e.ErrQueryError)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewSectionSnapshotRepo creates a new instance of the section snapshot repository
+func NewSectionSnapshotRepo(db *sql.DB) *SectionSnapshotDB {
+	return &SectionSnapshotDB{db: db}
+}
+
+// SectionSnapshotDB is the implementation of the section history, see docs/SQL/migrations/0009_section_snapshots.sql.
+// The periodic snapshots are taken by the section_snapshots job, SectionDB.Update takes one in the transaction
+// of every update and the other writes that change a section take one with snapshotSections: batch creation,
+// updates, archives and deletes, stock adjustments, transfers, readings and picks
+type SectionSnapshotDB struct {
+	db *sql.DB
+}
+
+// insertSectionSnapshot copies the occupancy and temperature of the sections into the history, the statement
+// ends in the WHERE of the sections to copy
+const insertSectionSnapshot = "INSERT INTO `section_snapshots` (`section_id`, `current_capacity`, `maximum_capacity`, `current_temperature`, `source`, `taken_at`) " +
+	"SELECT `id`, `current_capacity`, `maximum_capacity`, `current_temperature`, ?, ? FROM `sections`"
+
+// snapshotSections takes an update snapshot of the sections in the transaction of a write that changed their
+// occupancy or temperature without going through SectionDB.Update
+func snapshotSections(tx *sql.Tx, at time.Time, ids ...int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	in, args := common.InClause(ids)
+	if _, err := tx.Exec(insertSectionSnapshot+" WHERE `id` IN ("+in+")", append([]any{mod.SnapshotUpdate, at}, args...)...); err != nil {
+		return e.ErrInsertError
+	}
+	return nil
+}
+
+// SnapshotAll takes a periodic snapshot of every section
+func (r *SectionSnapshotDB) SnapshotAll(at time.Time) (int, error) {
+	res, err := r.db.Exec(insertSectionSnapshot, mod.SnapshotPeriodic, at)
+	if err != nil {
+		return 0, e.ErrInsertError
+	}
+	taken, err := res.RowsAffected()
+	if err != nil {
+		return 0, e.ErrInsertError
+	}
+	return int(taken), nil
+}
+
+// FindHistory groups the snapshots of the section in buckets of q.Interval counted from the Unix epoch, so the
+// buckets of a day start at midnight UTC
+func (r *SectionSnapshotDB) FindHistory(sectionID int, q mod.SectionHistoryQuery) ([]mod.SectionHistoryBucket, error) {
+	seconds := int64(q.Interval / time.Second)
+	rows, err := r.db.Query("SELECT FLOOR(TIMESTAMPDIFF(SECOND, '1970-01-01 00:00:00', `taken_at`) / ?) AS `bucket`, COUNT(*), "+
+		"MAX(`maximum_capacity`), MIN(`current_capacity`), ROUND(AVG(`current_capacity`), 2), MAX(`current_capacity`), "+
+		"MIN(`current_temperature`), ROUND(AVG(`current_temperature`), 2), MAX(`current_temperature`) FROM `section_snapshots` "+
+		"WHERE `section_id` = ? AND `taken_at` >= ? AND `taken_at` < ? GROUP BY `bucket` ORDER BY `bucket`",
+		seconds, sectionID, q.From, q.To)
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	buckets := []mod.SectionHistoryBucket{}
+	for rows.Next() {
+		b := mod.SectionHistoryBucket{SectionID: sectionID}
+		var bucket int64
+		if err = rows.Scan(&bucket, &b.Snapshots, &b.MaximumCapacity, &b.MinCapacity, &b.AvgCapacity, &b.MaxCapacity,
+			&b.MinTemperature, &b.AvgTemperature, &b.MaxTemperature); err != nil {
+			return nil, e.ErrParseError
+		}
+		b.BucketStart = time.Unix(bucket*seconds, 0).UTC()
+		buckets = append(buckets, b)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return buckets, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// sectionSnapshotInsert matches the snapshots taken by SnapshotAll, SectionDB.Update and snapshotSections
+const sectionSnapshotInsert = "INSERT INTO `section_snapshots`"
+
+func TestSectionSnapshotDB_SnapshotAll(t *testing.T) {
+	at := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("snapshots every section", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectExec(sectionSnapshotInsert+" (.+) FROM `sections`$").
+			WithArgs(mod.SnapshotPeriodic, at).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		taken, err := NewSectionSnapshotRepo(db).SnapshotAll(at)
+
+		require.NoError(t, err)
+		require.Equal(t, 3, taken)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("insert error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectExec(sectionSnapshotInsert).WillReturnError(sql.ErrConnDone)
+
+		_, err = NewSectionSnapshotRepo(db).SnapshotAll(at)
+
+		require.ErrorIs(t, err, e.ErrInsertError)
+	})
+}
+
+func TestSectionSnapshotDB_FindHistory(t *testing.T) {
+	from := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	q := mod.SectionHistoryQuery{From: from, To: from.Add(48 * time.Hour), Interval: 24 * time.Hour}
+	columns := []string{"bucket", "snapshots", "maximum_capacity", "min_capacity", "avg_capacity", "max_capacity",
+		"min_temperature", "avg_temperature", "max_temperature"}
+
+	t.Run("daily buckets", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		day := from.Unix() / 86400
+		mock.ExpectQuery("SELECT FLOOR(.+) FROM `section_snapshots` WHERE `section_id` = \\? (.+) GROUP BY `bucket`").
+			WithArgs(int64(86400), 4, q.From, q.To).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(day, 24, 100, 10, 25.5, 40, -20.0, -18.25, -15.0).
+				AddRow(day+1, 2, 120, 50, 55.0, 60, -19.0, -19.0, -19.0))
+
+		history, err := NewSectionSnapshotRepo(db).FindHistory(4, q)
+
+		require.NoError(t, err)
+		require.Equal(t, []mod.SectionHistoryBucket{
+			{SectionID: 4, BucketStart: from, Snapshots: 24, MaximumCapacity: 100, MinCapacity: 10, AvgCapacity: 25.5,
+				MaxCapacity: 40, MinTemperature: -20, AvgTemperature: -18.25, MaxTemperature: -15},
+			{SectionID: 4, BucketStart: from.Add(24 * time.Hour), Snapshots: 2, MaximumCapacity: 120, MinCapacity: 50,
+				AvgCapacity: 55, MaxCapacity: 60, MinTemperature: -19, AvgTemperature: -19, MaxTemperature: -19},
+		}, history)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectQuery("SELECT FLOOR").WillReturnError(sql.ErrConnDone)
+
+		_, err = NewSectionSnapshotRepo(db).FindHistory(4, q)
+
+		require.ErrorIs(t, err, e.ErrQueryError)
+	})
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewStockMovementRepo creates a new instance of the stock movement repository
+func NewStockMovementRepo(db *sql.DB) *StockMovementDB {
+	return &StockMovementDB{db: db}
+}
+
+// StockMovementDB is the implementation of the stock ledger, see docs/SQL/migrations/0005_stock_movements.sql.
+// The repositories that change current_quantity or current_capacity record their movements with insertMovement
+// in the same transaction
+type StockMovementDB struct {
+	db *sql.DB
+}
+
+// insertMovement appends a movement to the ledger, the empty reference, employee and reason are stored as NULL
+func insertMovement(tx *sql.Tx, m *mod.StockMovement) error {
+	res, err := tx.Exec("INSERT INTO `stock_movements` (`type`, `product_batch_id`, `section_id`, `quantity`, `reference_type`, `reference_id`, `employee_id`, `reason`) "+
+		"VALUES (?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, 0), NULLIF(?, 0), NULLIF(?, ''))",
+		m.Type, m.ProductBatchID, m.SectionID, m.Quantity, m.ReferenceType, m.ReferenceID, m.EmployeeID, m.Reason)
+	if err != nil {
+		return e.ErrInsertError
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return e.ErrInsertError
+	}
+	m.ID = int(id)
+	return nil
+}
+
+// Adjust locks the batch and its section, checks that the batch is still active, that the movement does not leave
+// it with negative units nor its section above the maximum capacity and applies it to the batch, its section and
+// the ledger
+func (r *StockMovementDB) Adjust(m *mod.StockMovement) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return e.ErrQueryError
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			err = e.ErrQueryError
+		}
+	}()
+
+	var current int
+	var closed bool
+	err = tx.QueryRow("SELECT `current_quantity`, `section_id`, `archived_at` IS NOT NULL OR `retired_at` IS NOT NULL FROM `product_batches` WHERE `id` = ? FOR UPDATE", m.ProductBatchID).
+		Scan(&current, &m.SectionID, &closed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return e.ErrProductBatchNotFound
+	}
+	if err != nil {
+		return e.ErrQueryError
+	}
+	if closed {
+		return e.ErrProductBatchArchived
+	}
+	if current+m.Quantity < 0 {
+		return e.ErrStockInsufficient
+	}
+
+	var capacity, maximum int
+	if err = tx.QueryRow("SELECT `current_capacity`, `maximum_capacity` FROM `sections` WHERE `id` = ? FOR UPDATE", m.SectionID).
+		Scan(&capacity, &maximum); err != nil {
+		return e.ErrQueryError
+	}
+	if capacity+m.Quantity > maximum {
+		return fmt.Errorf("%w: %d units left", e.ErrSectionCapacityExceeded, max(maximum-capacity, 0))
+	}
+
+	var employee bool
+	if err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM `employees` WHERE `id` = ?)", m.EmployeeID).Scan(&employee); err != nil {
+		return e.ErrQueryError
+	}
+	if !employee {
+		return e.ErrEmployeeRepositoryNotFound
+	}
+
+	if _, err = tx.Exec("UPDATE `product_batches` SET `current_quantity` = `current_quantity` + ? WHERE `id` = ?", m.Quantity, m.ProductBatchID); err != nil {
+		return e.ErrQueryError
+	}
+	if _, err = tx.Exec("UPDATE `sections` SET `current_capacity` = `current_capacity` + ? WHERE `id` = ?", m.Quantity, m.SectionID); err != nil {
+		return e.ErrQueryError
+	}
+	if err = snapshotSections(tx, time.Now().UTC(), m.SectionID); err != nil {
+		return err
+	}
+	return insertMovement(tx, m)
+}
+
+// FindLedgerByBatchID returns the movements of the batch, oldest first, with its stored and derived balances
+func (r *StockMovementDB) FindLedgerByBatchID(batchID int) (mod.StockLedger, error) {
+	ledger := mod.StockLedger{ProductBatchID: batchID, Movements: []mod.StockMovement{}}
+	err := r.db.QueryRow("SELECT `current_quantity` FROM `product_batches` WHERE `id` = ?", batchID).Scan(&ledger.CurrentQuantity)
+	if errors.Is(err, sql.ErrNoRows) {
+		return mod.StockLedger{}, e.ErrProductBatchNotFound
+	}
+	if err != nil {
+		return mod.StockLedger{}, e.ErrQueryError
+	}
+
+	rows, err := r.db.Query("SELECT `id`, `type`, `product_batch_id`, `section_id`, `quantity`, COALESCE(`reference_type`, ''), COALESCE(`reference_id`, 0), "+
+		"COALESCE(`employee_id`, 0), COALESCE(`reason`, ''), `created_at` FROM `stock_movements` WHERE `product_batch_id` = ? ORDER BY `id`", batchID)
+	if err != nil {
+		return mod.StockLedger{}, e.ErrQueryError
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m mod.StockMovement
+		if err = rows.Scan(&m.ID, &m.Type, &m.ProductBatchID, &m.SectionID, &m.Quantity, &m.ReferenceType, &m.ReferenceID,
+			&m.EmployeeID, &m.Reason, &m.CreatedAt); err != nil {
+			return mod.StockLedger{}, e.ErrParseError
+		}
+		ledger.Balance += m.Quantity
+		ledger.Movements = append(ledger.Movements, m)
+	}
+	if err = rows.Err(); err != nil {
+		return mod.StockLedger{}, e.ErrQueryError
+	}
+	return ledger, nil
+}
+
+// discrepancyQueries compare the stored counters of the batches and the sections with the sums of the ledger
+var discrepancyQueries = map[string]string{
+	mod.DiscrepancyBatch: "SELECT pb.`id`, pb.`current_quantity`, COALESCE(SUM(sm.`quantity`), 0) AS `ledger` " +
+		"FROM `product_batches` AS pb LEFT JOIN `stock_movements` AS sm ON sm.`product_batch_id` = pb.`id` " +
+		"GROUP BY pb.`id`, pb.`current_quantity` HAVING pb.`current_quantity` <> `ledger` ORDER BY pb.`id`",
+	mod.DiscrepancySection: "SELECT s.`id`, s.`current_capacity`, COALESCE(SUM(sm.`quantity`), 0) AS `ledger` " +
+		"FROM `sections` AS s LEFT JOIN `stock_movements` AS sm ON sm.`section_id` = s.`id` " +
+		"GROUP BY s.`id`, s.`current_capacity` HAVING s.`current_capacity` <> `ledger` ORDER BY s.`id`",
+}
+
+// Reconcile compares the counters with the ledger and replaces the stored discrepancies with the ones found
+func (r *StockMovementDB) Reconcile(at time.Time) (found []mod.StockDiscrepancy, err error) {
+	found = []mod.StockDiscrepancy{}
+	for _, entity := range []string{mod.DiscrepancyBatch, mod.DiscrepancySection} {
+		rows, err := r.db.Query(discrepancyQueries[entity])
+		if err != nil {
+			return nil, e.ErrQueryError
+		}
+		for rows.Next() {
+			d := mod.StockDiscrepancy{Entity: entity, DetectedAt: at}
+			if err = rows.Scan(&d.EntityID, &d.Stored, &d.Ledger); err != nil {
+				rows.Close()
+				return nil, e.ErrParseError
+			}
+			found = append(found, d)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, e.ErrQueryError
+		}
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			err = e.ErrQueryError
+		}
+	}()
+	if _, err = tx.Exec("DELETE FROM `stock_discrepancies`"); err != nil {
+		return nil, e.ErrQueryError
+	}
+	for _, d := range found {
+		if _, err = tx.Exec("INSERT INTO `stock_discrepancies` (`entity`, `entity_id`, `stored`, `ledger`, `detected_at`) VALUES (?, ?, ?, ?, ?)",
+			d.Entity, d.EntityID, d.Stored, d.Ledger, d.DetectedAt); err != nil {
+			return nil, e.ErrInsertError
+		}
+	}
+	return found, nil
+}
+
+// FindDiscrepancies returns the discrepancies found by the last reconciliation
+func (r *StockMovementDB) FindDiscrepancies() ([]mod.StockDiscrepancy, error) {
+	rows, err := r.db.Query("SELECT `entity`, `entity_id`, `stored`, `ledger`, `detected_at` FROM `stock_discrepancies` ORDER BY `entity`, `entity_id`")
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	found := []mod.StockDiscrepancy{}
+	for rows.Next() {
+		var d mod.StockDiscrepancy
+		if err = rows.Scan(&d.Entity, &d.EntityID, &d.Stored, &d.Ledger, &d.DetectedAt); err != nil {
+			return nil, e.ErrParseError
+		}
+		found = append(found, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return found, nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	stockBatchLock   = regexp.QuoteMeta("SELECT `current_quantity`, `section_id`, `archived_at` IS NOT NULL OR `retired_at` IS NOT NULL FROM `product_batches` WHERE `id` = ? FOR UPDATE")
+	stockSectionLock = regexp.QuoteMeta("SELECT `current_capacity`, `maximum_capacity` FROM `sections` WHERE `id` = ? FOR UPDATE")
+)
+
+func TestStockMovementDB_Adjust(t *testing.T) {
+	t.Run("write-off takes the units out of the batch and its section", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(stockBatchLock).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id", "closed"}).AddRow(10, 4, false))
+		mock.ExpectQuery(stockSectionLock).WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity"}).AddRow(30, 50))
+		mock.ExpectQuery(transferEmployeeQuery).WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_batches` SET `current_quantity` = `current_quantity` + ? WHERE `id` = ?")).WithArgs(-3, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `sections` SET `current_capacity` = `current_capacity` + ? WHERE `id` = ?")).WithArgs(-3, 4).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(sectionSnapshotInsert).WithArgs(mod.SnapshotUpdate, sqlmock.AnyArg(), 4).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(stockMovementInsert).WithArgs(mod.MovementWriteOff, 1, 4, -3, "", 0, 2, "broken boxes").
+			WillReturnResult(sqlmock.NewResult(11, 1))
+		mock.ExpectCommit()
+
+		m := mod.StockMovement{Type: mod.MovementWriteOff, ProductBatchID: 1, Quantity: -3, EmployeeID: 2, Reason: "broken boxes"}
+		require.NoError(t, NewStockMovementRepo(db).Adjust(&m))
+		require.Equal(t, 11, m.ID)
+		require.Equal(t, 4, m.SectionID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("batch cannot go below zero", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(stockBatchLock).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id", "closed"}).AddRow(2, 4, false))
+		mock.ExpectRollback()
+
+		err = NewStockMovementRepo(db).Adjust(&mod.StockMovement{Type: mod.MovementAdjustment, ProductBatchID: 1, Quantity: -3, EmployeeID: 2})
+		require.ErrorIs(t, err, e.ErrStockInsufficient)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("section cannot go above its maximum capacity", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(stockBatchLock).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id", "closed"}).AddRow(10, 4, false))
+		mock.ExpectQuery(stockSectionLock).WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"current_capacity", "maximum_capacity"}).AddRow(48, 50))
+		mock.ExpectRollback()
+
+		err = NewStockMovementRepo(db).Adjust(&mod.StockMovement{Type: mod.MovementAdjustment, ProductBatchID: 1, Quantity: 5, EmployeeID: 2})
+		require.ErrorIs(t, err, e.ErrSectionCapacityExceeded)
+		require.ErrorContains(t, err, "2 units left")
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("archived or retired batch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(stockBatchLock).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id", "closed"}).AddRow(0, 4, true))
+		mock.ExpectRollback()
+
+		err = NewStockMovementRepo(db).Adjust(&mod.StockMovement{Type: mod.MovementAdjustment, ProductBatchID: 1, Quantity: 3, EmployeeID: 2})
+		require.ErrorIs(t, err, e.ErrProductBatchArchived)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("missing batch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(stockBatchLock).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"current_quantity", "section_id"}))
+		mock.ExpectRollback()
+
+		err = NewStockMovementRepo(db).Adjust(&mod.StockMovement{Type: mod.MovementAdjustment, ProductBatchID: 1, Quantity: 3, EmployeeID: 2})
+		require.ErrorIs(t, err, e.ErrProductBatchNotFound)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestStockMovementDB_FindLedgerByBatchID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `current_quantity` FROM `product_batches` WHERE `id` = ?")).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"current_quantity"}).AddRow(8))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM `stock_movements` WHERE `product_batch_id` = ? ORDER BY `id`")).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type", "product_batch_id", "section_id", "quantity", "reference_type", "reference_id", "employee_id", "reason", "created_at"}).
+			AddRow(1, mod.MovementReceipt, 1, 4, 10, mod.ReferenceInboundOrder, 3, 2, "", created).
+			AddRow(2, mod.MovementPick, 1, 4, -3, mod.ReferencePurchaseOrder, 5, 0, "", created))
+
+	ledger, err := NewStockMovementRepo(db).FindLedgerByBatchID(1)
+	require.NoError(t, err)
+	require.Equal(t, 8, ledger.CurrentQuantity)
+	require.Equal(t, 7, ledger.Balance)
+	require.Len(t, ledger.Movements, 2)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStockMovementDB_Reconcile(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	at := time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)
+	columns := []string{"id", "stored", "ledger"}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM `product_batches` AS pb LEFT JOIN `stock_movements`")).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 8, 7))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM `sections` AS s LEFT JOIN `stock_movements`")).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(4, 12, 7))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `stock_discrepancies`")).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_discrepancies`")).WithArgs(mod.DiscrepancyBatch, 1, 8, 7, at).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `stock_discrepancies`")).WithArgs(mod.DiscrepancySection, 4, 12, 7, at).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	found, err := NewStockMovementRepo(db).Reconcile(at)
+	require.NoError(t, err)
+	require.Equal(t, []mod.StockDiscrepancy{
+		{Entity: mod.DiscrepancyBatch, EntityID: 1, Stored: 8, Ledger: 7, DetectedAt: at},
+		{Entity: mod.DiscrepancySection, EntityID: 4, Stored: 12, Ledger: 7, DetectedAt: at},
+	}, found)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
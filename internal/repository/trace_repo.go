@@ -0,0 +1,401 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewTraceRepo creates a new instance of the trace repository
+func NewTraceRepo(db *sql.DB) *TraceDB {
+	return &TraceDB{db: db}
+}
+
+// TraceDB is the implementation of the lot traceability and the recalls, see docs/SQL/migrations/0014_recalls.sql.
+// A batch is traced up to its seller and inbound orders and down to the buyers through order_detail_allocations,
+// the transfers that split it are followed both ways
+type TraceDB struct {
+	db *sql.DB
+}
+
+// traceQuerier runs the trace queries on the database or inside the transaction of a recall
+type traceQuerier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// queryIDs runs query and scans the single id column of every row
+func queryIDs(q traceQuerier, query string, args ...any) ([]int, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	var found []int
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			return nil, e.ErrParseError
+		}
+		found = append(found, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return found, nil
+}
+
+// findSplits returns the batches split from the batch by partial transfers, following the splits of the splits
+func findSplits(q traceQuerier, id int) ([]int, error) {
+	splits := []int{}
+	seen := map[int]bool{id: true}
+	for frontier := []int{id}; len(frontier) > 0; {
+		placeholders, args := common.InClause(frontier)
+		found, err := queryIDs(q, "SELECT `destination_batch_id` FROM `transfers` "+
+			"WHERE `product_batch_id` IN ("+placeholders+") AND `destination_batch_id` <> `product_batch_id` ORDER BY `id`", args...)
+		if err != nil {
+			return nil, err
+		}
+		frontier = nil
+		for _, split := range found {
+			if !seen[split] {
+				seen[split] = true
+				splits = append(splits, split)
+				frontier = append(frontier, split)
+			}
+		}
+	}
+	return splits, nil
+}
+
+// findSplitChain returns the batch followed by the batches it was split from, up to the first batch of its chain
+func findSplitChain(q traceQuerier, id int) ([]int, error) {
+	chain := []int{id}
+	for current := id; ; {
+		var source int
+		err := q.QueryRow("SELECT `product_batch_id` FROM `transfers` WHERE `destination_batch_id` = ? AND `product_batch_id` <> `destination_batch_id` LIMIT 1", current).
+			Scan(&source)
+		if errors.Is(err, sql.ErrNoRows) || slices.Contains(chain, source) {
+			return chain, nil
+		}
+		if err != nil {
+			return nil, e.ErrQueryError
+		}
+		chain = append(chain, source)
+		current = source
+	}
+}
+
+// findOrigin returns the seller, product and inbound orders of a batch, a split batch is followed up to the first
+// batch of its chain, the one the inbound orders received
+func findOrigin(q traceQuerier, id int) (origin mod.BatchOrigin, err error) {
+	var sellerID sql.NullInt64
+	var company, telephone sql.NullString
+	err = q.QueryRow("SELECT pb.`id`, pb.`batch_number`, pb.`status`, pb.`due_date`, pb.`section_id`, p.`id`, p.`product_code`, p.`description`, "+
+		"s.`id`, s.`company_name`, s.`telephone` FROM `product_batches` AS pb "+
+		"INNER JOIN `products` AS p ON p.`id` = pb.`product_id` LEFT JOIN `sellers` AS s ON s.`id` = p.`seller_id` WHERE pb.`id` = ?", id).
+		Scan(&origin.Batch.ID, &origin.Batch.BatchNumber, &origin.Batch.Status, &origin.Batch.DueDate, &origin.Batch.SectionID,
+			&origin.Product.ID, &origin.Product.ProductCode, &origin.Product.Description, &sellerID, &company, &telephone)
+	if errors.Is(err, sql.ErrNoRows) {
+		return mod.BatchOrigin{}, e.ErrProductBatchNotFound
+	}
+	if err != nil {
+		return mod.BatchOrigin{}, e.ErrQueryError
+	}
+	if sellerID.Valid {
+		origin.Seller = &mod.TraceSeller{ID: int(sellerID.Int64), CompanyName: company.String, Telephone: telephone.String}
+	}
+
+	chain, err := findSplitChain(q, id)
+	if err != nil {
+		return mod.BatchOrigin{}, err
+	}
+	if len(chain) > 1 {
+		origin.SplitFrom = chain[1]
+	}
+
+	placeholders, args := common.InClause(chain)
+	rows, err := q.Query("SELECT io.`id`, io.`order_number`, io.`order_date`, em.`id`, em.`id_card_number`, em.`first_name`, em.`last_name`, "+
+		"w.`id`, w.`warehouse_code` FROM `inbound_orders` AS io "+
+		"INNER JOIN `employees` AS em ON em.`id` = io.`employee_id` INNER JOIN `warehouses` AS w ON w.`id` = io.`warehouse_id` "+
+		"WHERE io.`product_batch_id` IN ("+placeholders+") ORDER BY io.`id`", args...)
+	if err != nil {
+		return mod.BatchOrigin{}, e.ErrQueryError
+	}
+	defer rows.Close()
+	origin.InboundOrders = []mod.TraceInbound{}
+	for rows.Next() {
+		var in mod.TraceInbound
+		var date sql.NullTime
+		if err = rows.Scan(&in.ID, &in.OrderNumber, &date, &in.Employee.ID, &in.Employee.CardNumberID, &in.Employee.FirstName,
+			&in.Employee.LastName, &in.Warehouse.ID, &in.Warehouse.WarehouseCode); err != nil {
+			return mod.BatchOrigin{}, e.ErrParseError
+		}
+		if date.Valid {
+			in.OrderDate = date.Time.Format("2006-01-02")
+		}
+		origin.InboundOrders = append(origin.InboundOrders, in)
+	}
+	if err = rows.Err(); err != nil {
+		return mod.BatchOrigin{}, e.ErrQueryError
+	}
+	return origin, nil
+}
+
+// findDeliveries returns the allocations of the batches to the purchase orders with their buyers, oldest order first
+func findDeliveries(q traceQuerier, ids []int) ([]mod.TraceDelivery, error) {
+	deliveries := []mod.TraceDelivery{}
+	if len(ids) == 0 {
+		return deliveries, nil
+	}
+	placeholders, args := common.InClause(ids)
+	rows, err := q.Query("SELECT po.`id`, po.`order_number`, po.`order_date`, od.`id`, a.`product_batch_id`, a.`quantity`, "+
+		"COALESCE(b.`id`, 0), COALESCE(b.`id_card_number`, ''), COALESCE(b.`first_name`, ''), COALESCE(b.`last_name`, '') "+
+		"FROM `order_detail_allocations` AS a INNER JOIN `order_details` AS od ON od.`id` = a.`order_detail_id` "+
+		"INNER JOIN `purchase_orders` AS po ON po.`id` = od.`purchase_order_id` LEFT JOIN `buyers` AS b ON b.`id` = po.`buyer_id` "+
+		"WHERE a.`product_batch_id` IN ("+placeholders+") ORDER BY po.`id`, od.`id`, a.`id`", args...)
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var d mod.TraceDelivery
+		var date sql.NullTime
+		if err = rows.Scan(&d.PurchaseOrderID, &d.OrderNumber, &date, &d.OrderDetailID, &d.ProductBatchID, &d.Quantity,
+			&d.Buyer.ID, &d.Buyer.CardNumberID, &d.Buyer.FirstName, &d.Buyer.LastName); err != nil {
+			return nil, e.ErrParseError
+		}
+		d.OrderDate = mod.Date(date.Time)
+		deliveries = append(deliveries, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return deliveries, nil
+}
+
+// recallBuyers groups the deliveries by buyer in the order the buyers were first served
+func recallBuyers(deliveries []mod.TraceDelivery) []mod.RecallBuyer {
+	buyers := []mod.RecallBuyer{}
+	index := make(map[int]int)
+	for _, d := range deliveries {
+		i, ok := index[d.Buyer.ID]
+		if !ok {
+			i = len(buyers)
+			index[d.Buyer.ID] = i
+			buyers = append(buyers, mod.RecallBuyer{TraceBuyer: d.Buyer, PurchaseOrderIDs: []int{}})
+		}
+		if !slices.Contains(buyers[i].PurchaseOrderIDs, d.PurchaseOrderID) {
+			buyers[i].PurchaseOrderIDs = append(buyers[i].PurchaseOrderIDs, d.PurchaseOrderID)
+		}
+		buyers[i].Quantity += d.Quantity
+	}
+	return buyers
+}
+
+// FindBatchTrace returns the origin of the batch, its splits and the purchase orders served by any of them
+func (r *TraceDB) FindBatchTrace(id int) (mod.BatchTrace, error) {
+	origin, err := findOrigin(r.db, id)
+	if err != nil {
+		return mod.BatchTrace{}, err
+	}
+	splits, err := findSplits(r.db, id)
+	if err != nil {
+		return mod.BatchTrace{}, err
+	}
+	deliveries, err := findDeliveries(r.db, append([]int{id}, splits...))
+	if err != nil {
+		return mod.BatchTrace{}, err
+	}
+	return mod.BatchTrace{BatchOrigin: origin, Splits: splits, Deliveries: deliveries}, nil
+}
+
+// FindPurchaseOrderTrace returns the lines of the purchase order with the origin of every batch allocated to them
+func (r *TraceDB) FindPurchaseOrderTrace(id int) (mod.PurchaseOrderTrace, error) {
+	trace := mod.PurchaseOrderTrace{Lines: []mod.TraceLine{}}
+	var date sql.NullTime
+	err := r.db.QueryRow("SELECT po.`id`, po.`order_number`, po.`order_date`, COALESCE(b.`id`, 0), COALESCE(b.`id_card_number`, ''), "+
+		"COALESCE(b.`first_name`, ''), COALESCE(b.`last_name`, '') FROM `purchase_orders` AS po LEFT JOIN `buyers` AS b ON b.`id` = po.`buyer_id` "+
+		"WHERE po.`id` = ?", id).
+		Scan(&trace.PurchaseOrderID, &trace.OrderNumber, &date, &trace.Buyer.ID, &trace.Buyer.CardNumberID, &trace.Buyer.FirstName, &trace.Buyer.LastName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return mod.PurchaseOrderTrace{}, e.ErrPORepositoryNotFound
+	}
+	if err != nil {
+		return mod.PurchaseOrderTrace{}, e.ErrQueryError
+	}
+	trace.OrderDate = mod.Date(date.Time)
+
+	rows, err := r.db.Query("SELECT od.`id`, COALESCE(od.`product_record_id`, 0), COALESCE(od.`quantity`, 0), od.`backordered_quantity`, "+
+		"COALESCE(a.`product_batch_id`, 0), COALESCE(a.`quantity`, 0) FROM `order_details` AS od "+
+		"LEFT JOIN `order_detail_allocations` AS a ON a.`order_detail_id` = od.`id` WHERE od.`purchase_order_id` = ? ORDER BY od.`id`, a.`id`", id)
+	if err != nil {
+		return mod.PurchaseOrderTrace{}, e.ErrQueryError
+	}
+	// the origins are read once the rows are closed, the batches of the allocations are kept until then
+	var batches []int
+	for rows.Next() {
+		var line mod.TraceLine
+		var batchID, quantity int
+		if err = rows.Scan(&line.OrderDetailID, &line.ProductRecordID, &line.Quantity, &line.BackorderedQuantity, &batchID, &quantity); err != nil {
+			rows.Close()
+			return mod.PurchaseOrderTrace{}, e.ErrParseError
+		}
+		if n := len(trace.Lines); n == 0 || trace.Lines[n-1].OrderDetailID != line.OrderDetailID {
+			line.Allocations = []mod.TraceAllocation{}
+			trace.Lines = append(trace.Lines, line)
+		}
+		if batchID == 0 {
+			continue
+		}
+		last := &trace.Lines[len(trace.Lines)-1]
+		last.Allocations = append(last.Allocations, mod.TraceAllocation{Quantity: quantity, Origin: mod.BatchOrigin{Batch: mod.TraceBatch{ID: batchID}}})
+		batches = append(batches, batchID)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return mod.PurchaseOrderTrace{}, e.ErrQueryError
+	}
+
+	origins := make(map[int]mod.BatchOrigin)
+	for _, batchID := range batches {
+		if _, ok := origins[batchID]; ok {
+			continue
+		}
+		if origins[batchID], err = findOrigin(r.db, batchID); err != nil {
+			return mod.PurchaseOrderTrace{}, err
+		}
+	}
+	for i := range trace.Lines {
+		for j := range trace.Lines[i].Allocations {
+			allocation := &trace.Lines[i].Allocations[j]
+			allocation.Origin = origins[allocation.Origin.Batch.ID]
+		}
+	}
+	return trace, nil
+}
+
+// Recall rejects the batch of the recall and every batch of its split chain, the first batch of the chain and all
+// the batches split from it, recording a status change for every batch
+// that was not already rejected, and records the recall with its batches in the same transaction. The buyers are
+// the ones served by any of the batches
+func (r *TraceDB) Recall(recall *mod.Recall) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return e.ErrQueryError
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			err = e.ErrQueryError
+		}
+	}()
+
+	var exists, employee bool
+	if err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM `product_batches` WHERE `id` = ?), EXISTS(SELECT 1 FROM `employees` WHERE `id` = ?)",
+		recall.ProductBatchID, recall.EmployeeID).Scan(&exists, &employee); err != nil {
+		return e.ErrQueryError
+	}
+	if !exists {
+		return e.ErrProductBatchNotFound
+	}
+	if !employee {
+		return e.ErrEmployeeRepositoryNotFound
+	}
+
+	// the units of a split batch came from the same lot as every other batch of its chain, so the recall starts
+	// from the first batch of the chain
+	chain, err := findSplitChain(tx, recall.ProductBatchID)
+	if err != nil {
+		return err
+	}
+	root := chain[len(chain)-1]
+	splits, err := findSplits(tx, root)
+	if err != nil {
+		return err
+	}
+	recall.Batches = append([]int{root}, splits...)
+
+	placeholders, args := common.InClause(recall.Batches)
+	rows, err := tx.Query("SELECT `id`, `status` FROM `product_batches` WHERE `id` IN ("+placeholders+") ORDER BY `id` FOR UPDATE", args...)
+	if err != nil {
+		return e.ErrQueryError
+	}
+	var changes []mod.BatchStatusChange
+	for rows.Next() {
+		change := mod.BatchStatusChange{ToStatus: mod.BatchRejected, Reason: recall.Reason, EmployeeID: recall.EmployeeID, CreatedAt: recall.CreatedAt}
+		if err = rows.Scan(&change.ProductBatchID, &change.FromStatus); err != nil {
+			rows.Close()
+			return e.ErrParseError
+		}
+		if change.FromStatus != mod.BatchRejected {
+			changes = append(changes, change)
+		}
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return e.ErrQueryError
+	}
+	for i := range changes {
+		if _, err = tx.Exec("UPDATE `product_batches` SET `status` = ? WHERE `id` = ?", mod.BatchRejected, changes[i].ProductBatchID); err != nil {
+			return e.ErrQueryError
+		}
+		if err = insertStatusChange(tx, &changes[i]); err != nil {
+			return err
+		}
+	}
+
+	res, err := tx.Exec("INSERT INTO `recalls` (`product_batch_id`, `reason`, `employee_id`, `created_at`) VALUES (?, ?, ?, ?)",
+		recall.ProductBatchID, recall.Reason, recall.EmployeeID, recall.CreatedAt)
+	if err != nil {
+		return e.ErrInsertError
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return e.ErrInsertError
+	}
+	recall.ID = int(id)
+	for _, batchID := range recall.Batches {
+		if _, err = tx.Exec("INSERT INTO `recall_batches` (`recall_id`, `product_batch_id`) VALUES (?, ?)", recall.ID, batchID); err != nil {
+			return e.ErrInsertError
+		}
+	}
+
+	deliveries, err := findDeliveries(tx, recall.Batches)
+	if err != nil {
+		return err
+	}
+	recall.Buyers = recallBuyers(deliveries)
+	return nil
+}
+
+// FindRecall returns a recall with its batches and the buyers served by them
+func (r *TraceDB) FindRecall(id int) (mod.Recall, error) {
+	var recall mod.Recall
+	err := r.db.QueryRow("SELECT `id`, `product_batch_id`, `reason`, `employee_id`, `created_at` FROM `recalls` WHERE `id` = ?", id).
+		Scan(&recall.ID, &recall.ProductBatchID, &recall.Reason, &recall.EmployeeID, &recall.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return mod.Recall{}, e.ErrRecallNotFound
+	}
+	if err != nil {
+		return mod.Recall{}, e.ErrQueryError
+	}
+	if recall.Batches, err = queryIDs(r.db, "SELECT `product_batch_id` FROM `recall_batches` WHERE `recall_id` = ? ORDER BY `product_batch_id`", id); err != nil {
+		return mod.Recall{}, err
+	}
+	deliveries, err := findDeliveries(r.db, recall.Batches)
+	if err != nil {
+		return mod.Recall{}, err
+	}
+	recall.Buyers = recallBuyers(deliveries)
+	return recall, nil
+}
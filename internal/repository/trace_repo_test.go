@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	traceOriginQuery   = regexp.QuoteMeta("LEFT JOIN `sellers` AS s ON s.`id` = p.`seller_id` WHERE pb.`id` = ?")
+	traceOriginColumns = []string{"id", "batch_number", "status", "due_date", "section_id", "product_id", "product_code", "description",
+		"seller_id", "company_name", "telephone"}
+	traceSourceQuery   = regexp.QuoteMeta("SELECT `product_batch_id` FROM `transfers` WHERE `destination_batch_id` = ?")
+	traceInboundQuery  = regexp.QuoteMeta("FROM `inbound_orders` AS io")
+	traceInboundCols   = []string{"id", "order_number", "order_date", "employee_id", "id_card_number", "first_name", "last_name", "warehouse_id", "warehouse_code"}
+	traceSplitsQuery   = regexp.QuoteMeta("SELECT `destination_batch_id` FROM `transfers` WHERE `product_batch_id` IN (")
+	traceDeliveryQuery = regexp.QuoteMeta("FROM `order_detail_allocations` AS a")
+	traceDeliveryCols  = []string{"purchase_order_id", "order_number", "order_date", "order_detail_id", "product_batch_id", "quantity",
+		"buyer_id", "id_card_number", "first_name", "last_name"}
+)
+
+// expectOrigin expects the origin of the batch 7, split from the batch 1 that the inbound order 3 received
+func expectOrigin(mock sqlmock.Sqlmock, due time.Time) {
+	mock.ExpectQuery(traceOriginQuery).WithArgs(7).WillReturnRows(sqlmock.NewRows(traceOriginColumns).
+		AddRow(7, 12, mod.BatchAvailable, due, 2, 4, "PRD-4", "Frozen peas", 5, "Green Farms", "555-0100"))
+	mock.ExpectQuery(traceSourceQuery).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"product_batch_id"}).AddRow(1))
+	mock.ExpectQuery(traceSourceQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"product_batch_id"}))
+	mock.ExpectQuery(traceInboundQuery).WithArgs(7, 1).WillReturnRows(sqlmock.NewRows(traceInboundCols).
+		AddRow(3, "IN-3", time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC), 8, "E-8", "Ana", "Diaz", 2, "WH-2"))
+}
+
+func TestTraceDB_FindBatchTrace(t *testing.T) {
+	due := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	ordered := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("origin, splits and the buyers of both", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		expectOrigin(mock, due)
+		mock.ExpectQuery(traceSplitsQuery).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"destination_batch_id"}).AddRow(9))
+		mock.ExpectQuery(traceSplitsQuery).WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"destination_batch_id"}))
+		mock.ExpectQuery(traceDeliveryQuery).WithArgs(7, 9).WillReturnRows(sqlmock.NewRows(traceDeliveryCols).
+			AddRow(20, "PO-20", ordered, 30, 7, 4, 6, "B-6", "Luis", "Paz").
+			AddRow(21, "PO-21", ordered, 31, 9, 2, 6, "B-6", "Luis", "Paz"))
+
+		trace, err := NewTraceRepo(db).FindBatchTrace(7)
+
+		require.NoError(t, err)
+		require.Equal(t, &mod.TraceSeller{ID: 5, CompanyName: "Green Farms", Telephone: "555-0100"}, trace.Seller)
+		require.Equal(t, 1, trace.SplitFrom)
+		require.Equal(t, []mod.TraceInbound{{ID: 3, OrderNumber: "IN-3", OrderDate: "2024-06-02",
+			Employee:  mod.TraceEmployee{ID: 8, CardNumberID: "E-8", FirstName: "Ana", LastName: "Diaz"},
+			Warehouse: mod.TraceWarehouse{ID: 2, WarehouseCode: "WH-2"}}}, trace.InboundOrders)
+		require.Equal(t, []int{9}, trace.Splits)
+		require.Len(t, trace.Deliveries, 2)
+		require.Equal(t, 9, trace.Deliveries[1].ProductBatchID)
+		require.Equal(t, mod.TraceBuyer{ID: 6, CardNumberID: "B-6", FirstName: "Luis", LastName: "Paz"}, trace.Deliveries[0].Buyer)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("batch not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectQuery(traceOriginQuery).WithArgs(7).WillReturnRows(sqlmock.NewRows(traceOriginColumns))
+
+		_, err = NewTraceRepo(db).FindBatchTrace(7)
+
+		require.ErrorIs(t, err, e.ErrProductBatchNotFound)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTraceDB_FindPurchaseOrderTrace(t *testing.T) {
+	due := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	ordered := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	orderQuery := regexp.QuoteMeta("FROM `purchase_orders` AS po LEFT JOIN `buyers` AS b ON b.`id` = po.`buyer_id` WHERE po.`id` = ?")
+	orderCols := []string{"id", "order_number", "order_date", "buyer_id", "id_card_number", "first_name", "last_name"}
+
+	t.Run("lines with the origin of their batches", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectQuery(orderQuery).WithArgs(20).
+			WillReturnRows(sqlmock.NewRows(orderCols).AddRow(20, "PO-20", ordered, 6, "B-6", "Luis", "Paz"))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM `order_details` AS od LEFT JOIN `order_detail_allocations` AS a")).WithArgs(20).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_record_id", "quantity", "backordered_quantity", "product_batch_id", "quantity"}).
+				AddRow(30, 2, 6, 0, 7, 4).
+				AddRow(30, 2, 6, 0, 7, 2).
+				AddRow(31, 3, 5, 5, 0, 0))
+		expectOrigin(mock, due)
+
+		trace, err := NewTraceRepo(db).FindPurchaseOrderTrace(20)
+
+		require.NoError(t, err)
+		require.Equal(t, "Luis", trace.Buyer.FirstName)
+		require.Len(t, trace.Lines, 2)
+		require.Len(t, trace.Lines[0].Allocations, 2)
+		require.Equal(t, 2, trace.Lines[0].Allocations[1].Quantity)
+		require.Equal(t, "Green Farms", trace.Lines[0].Allocations[1].Origin.Seller.CompanyName)
+		require.Equal(t, "IN-3", trace.Lines[0].Allocations[0].Origin.InboundOrders[0].OrderNumber)
+		require.Equal(t, 5, trace.Lines[1].BackorderedQuantity)
+		require.Empty(t, trace.Lines[1].Allocations)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("purchase order not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectQuery(orderQuery).WithArgs(20).WillReturnRows(sqlmock.NewRows(orderCols))
+
+		_, err = NewTraceRepo(db).FindPurchaseOrderTrace(20)
+
+		require.ErrorIs(t, err, e.ErrPORepositoryNotFound)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTraceDB_Recall(t *testing.T) {
+	at := time.Date(2024, 7, 6, 10, 0, 0, 0, time.UTC)
+	ordered := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	existsQuery := regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM `product_batches` WHERE `id` = ?), EXISTS(SELECT 1 FROM `employees` WHERE `id` = ?)")
+	statusChange := regexp.QuoteMeta("INSERT INTO `product_batch_status_changes`")
+
+	t.Run("rejects the batch and its splits and lists the buyers", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery(existsQuery).WithArgs(7, 4).WillReturnRows(sqlmock.NewRows([]string{"batch", "employee"}).AddRow(true, true))
+		mock.ExpectQuery(traceSourceQuery).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"product_batch_id"}))
+		mock.ExpectQuery(traceSplitsQuery).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"destination_batch_id"}).AddRow(9))
+		mock.ExpectQuery(traceSplitsQuery).WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"destination_batch_id"}))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT `id`, `status` FROM `product_batches` WHERE `id` IN (?,?) ORDER BY `id` FOR UPDATE")).WithArgs(7, 9).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(7, mod.BatchQuarantined).AddRow(9, mod.BatchRejected))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_batches` SET `status` = ? WHERE `id` = ?")).WithArgs(mod.BatchRejected, 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(statusChange).WithArgs(7, mod.BatchQuarantined, mod.BatchRejected, "listeria", 4, at).
+			WillReturnResult(sqlmock.NewResult(11, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `recalls`")).WithArgs(7, "listeria", 4, at).WillReturnResult(sqlmock.NewResult(2, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `recall_batches`")).WithArgs(2, 7).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `recall_batches`")).WithArgs(2, 9).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(traceDeliveryQuery).WithArgs(7, 9).WillReturnRows(sqlmock.NewRows(traceDeliveryCols).
+			AddRow(20, "PO-20", ordered, 30, 7, 4, 6, "B-6", "Luis", "Paz").
+			AddRow(21, "PO-21", ordered, 31, 9, 2, 6, "B-6", "Luis", "Paz").
+			AddRow(22, "PO-22", ordered, 32, 9, 1, 8, "B-8", "Eva", "Sol"))
+		mock.ExpectCommit()
+
+		recall := mod.Recall{ProductBatchID: 7, Reason: "listeria", EmployeeID: 4, CreatedAt: at}
+		require.NoError(t, NewTraceRepo(db).Recall(&recall))
+
+		require.Equal(t, 2, recall.ID)
+		require.Equal(t, []int{7, 9}, recall.Batches)
+		require.Equal(t, []mod.RecallBuyer{
+			{TraceBuyer: mod.TraceBuyer{ID: 6, CardNumberID: "B-6", FirstName: "Luis", LastName: "Paz"}, PurchaseOrderIDs: []int{20, 21}, Quantity: 6},
+			{TraceBuyer: mod.TraceBuyer{ID: 8, CardNumberID: "B-8", FirstName: "Eva", LastName: "Sol"}, PurchaseOrderIDs: []int{22}, Quantity: 1},
+		}, recall.Buyers)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("a split batch rejects the first batch of its chain and every split of it", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectBegin()
+		mock.ExpectQuery(existsQuery).WithArgs(9, 4).WillReturnRows(sqlmock.NewRows([]string{"batch", "employee"}).AddRow(true, true))
+		mock.ExpectQuery(traceSourceQuery).WithArgs(9).WillReturnRows(sqlmock.NewRows([]string{"product_batch_id"}).AddRow(7))
+		mock.ExpectQuery(traceSourceQuery).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"product_batch_id"}))
+		mock.ExpectQuery(traceSplitsQuery).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"destination_batch_id"}).AddRow(8).AddRow(9))
+		mock.ExpectQuery(traceSplitsQuery).WithArgs(8, 9).WillReturnRows(sqlmock.NewRows([]string{"destination_batch_id"}))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT `id`, `status` FROM `product_batches` WHERE `id` IN (?,?,?) ORDER BY `id` FOR UPDATE")).WithArgs(7, 8, 9).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(7, mod.BatchAvailable).AddRow(8, mod.BatchAvailable).AddRow(9, mod.BatchRejected))
+		for _, id := range []int{7, 8} {
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_batches` SET `status` = ? WHERE `id` = ?")).WithArgs(mod.BatchRejected, id).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(statusChange).WithArgs(id, mod.BatchAvailable, mod.BatchRejected, "listeria", 4, at).
+				WillReturnResult(sqlmock.NewResult(int64(id), 1))
+		}
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `recalls`")).WithArgs(9, "listeria", 4, at).WillReturnResult(sqlmock.NewResult(3, 1))
+		for _, id := range []int{7, 8, 9} {
+			mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `recall_batches`")).WithArgs(3, id).WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		mock.ExpectQuery(traceDeliveryQuery).WithArgs(7, 8, 9).WillReturnRows(sqlmock.NewRows(traceDeliveryCols))
+		mock.ExpectCommit()
+
+		recall := mod.Recall{ProductBatchID: 9, Reason: "listeria", EmployeeID: 4, CreatedAt: at}
+		require.NoError(t, NewTraceRepo(db).Recall(&recall))
+
+		require.Equal(t, 9, recall.ProductBatchID)
+		require.Equal(t, []int{7, 8, 9}, recall.Batches)
+		require.Empty(t, recall.Buyers)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	for name, found := range map[string][]bool{"batch not found": {false, true}, "employee not found": {true, false}} {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			mock.ExpectBegin()
+			mock.ExpectQuery(existsQuery).WithArgs(7, 4).WillReturnRows(sqlmock.NewRows([]string{"batch", "employee"}).AddRow(found[0], found[1]))
+			mock.ExpectRollback()
+
+			err = NewTraceRepo(db).Recall(&mod.Recall{ProductBatchID: 7, Reason: "listeria", EmployeeID: 4, CreatedAt: at})
+
+			if !found[0] {
+				require.ErrorIs(t, err, e.ErrProductBatchNotFound)
+			} else {
+				require.ErrorIs(t, err, e.ErrEmployeeRepositoryNotFound)
+			}
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTraceDB_FindRecall(t *testing.T) {
+	at := time.Date(2024, 7, 6, 10, 0, 0, 0, time.UTC)
+	recallQuery := regexp.QuoteMeta("FROM `recalls` WHERE `id` = ?")
+
+	t.Run("recall with its batches and buyers", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectQuery(recallQuery).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id", "product_batch_id", "reason", "employee_id", "created_at"}).
+			AddRow(2, 7, "listeria", 4, at))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM `recall_batches` WHERE `recall_id` = ?")).WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"product_batch_id"}).AddRow(7).AddRow(9))
+		mock.ExpectQuery(traceDeliveryQuery).WithArgs(7, 9).WillReturnRows(sqlmock.NewRows(traceDeliveryCols))
+
+		recall, err := NewTraceRepo(db).FindRecall(2)
+
+		require.NoError(t, err)
+		require.Equal(t, []int{7, 9}, recall.Batches)
+		require.Empty(t, recall.Buyers)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("recall not found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		mock.ExpectQuery(recallQuery).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err = NewTraceRepo(db).FindRecall(2)
+
+		require.ErrorIs(t, err, e.ErrRecallNotFound)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
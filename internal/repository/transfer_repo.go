@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewTransferRepo creates a new instance of the transfer repository
+func NewTransferRepo(db *sql.DB) *TransferDB {
+	return &TransferDB{db: db}
+}
+
+// TransferDB is the implementation of the transfer database, see docs/SQL/migrations/0004_transfers.sql
+type TransferDB struct {
+	db *sql.DB
+}
+
+// transferBatch is the locked source batch of a transfer
+type transferBatch struct {
+	currentQuantity    int
+	minimumTemperature int
+	sectionID          int
+	productTypeID      int
+	status             string
+}
+
+// transferSection is a locked section of a transfer
+type transferSection struct {
+	currentTemperature float64
+	currentCapacity    int
+	maximumCapacity    int
+	productTypeID      int
+}
+
+// Save locks the batch and both sections, checks that the destination section stores the product type of the
+// batch, is not colder than the batch minimum temperature and has capacity for the units, then moves them. A batch
+// on hold cannot be transferred.
+// A partial transfer splits the batch, the moved units go to a new batch in the destination section
+func (r *TransferDB) Save(t *mod.Transfer) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return e.ErrQueryError
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			err = e.ErrQueryError
+		}
+	}()
+
+	var batch transferBatch
+	err = tx.QueryRow("SELECT pb.`current_quantity`, pb.`minimum_temperature`, pb.`section_id`, p.`product_type_id`, pb.`status` "+
+		"FROM `product_batches` AS pb JOIN `products` AS p ON p.`id` = pb.`product_id` WHERE pb.`id` = ? FOR UPDATE", t.ProductBatchID).
+		Scan(&batch.currentQuantity, &batch.minimumTemperature, &batch.sectionID, &batch.productTypeID, &batch.status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return e.ErrProductBatchNotFound
+	}
+	if err != nil {
+		return e.ErrQueryError
+	}
+	if mod.BatchOnHold(batch.status) {
+		return e.ErrProductBatchHeld
+	}
+	if batch.sectionID == t.ToSectionID {
+		return e.ErrTransferSameSection
+	}
+	if t.Quantity == 0 {
+		t.Quantity = batch.currentQuantity
+	}
+	if t.Quantity < 1 || t.Quantity > batch.currentQuantity {
+		return e.ErrTransferQuantityInvalid
+	}
+
+	var employee bool
+	if err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM `employees` WHERE `id` = ?)", t.EmployeeID).Scan(&employee); err != nil {
+		return e.ErrQueryError
+	}
+	if !employee {
+		return e.ErrEmployeeRepositoryNotFound
+	}
+
+	// both sections are locked in id order so concurrent transfers in opposite directions do not deadlock
+	sections, err := lockTransferSections(tx, batch.sectionID, t.ToSectionID)
+	if err != nil {
+		return err
+	}
+	to, ok := sections[t.ToSectionID]
+	switch {
+	case !ok:
+		return e.ErrSectionRepositoryNotFound
+	case to.productTypeID != batch.productTypeID:
+		return e.ErrTransferProductType
+	case to.currentTemperature < float64(batch.minimumTemperature):
+		return e.ErrTransferTemperature
+	case to.currentCapacity+t.Quantity > to.maximumCapacity:
+		return e.ErrTransferCapacity
+	}
+
+	t.FromSectionID = batch.sectionID
+	if t.DestinationBatchID, err = moveBatch(tx, t, batch.currentQuantity); err != nil {
+		return err
+	}
+	if _, err = tx.Exec("UPDATE `sections` SET `current_capacity` = `current_capacity` - ? WHERE `id` = ?", t.Quantity, t.FromSectionID); err != nil {
+		return e.ErrQueryError
+	}
+	if _, err = tx.Exec("UPDATE `sections` SET `current_capacity` = `current_capacity` + ? WHERE `id` = ?", t.Quantity, t.ToSectionID); err != nil {
+		return e.ErrQueryError
+	}
+	if err = snapshotSections(tx, t.CreatedAt, t.FromSectionID, t.ToSectionID); err != nil {
+		return err
+	}
+
+	res, err := tx.Exec("INSERT INTO `transfers` (`product_batch_id`, `destination_batch_id`, `from_section_id`, `to_section_id`, `quantity`, `employee_id`, `created_at`) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		t.ProductBatchID, t.DestinationBatchID, t.FromSectionID, t.ToSectionID, t.Quantity, t.EmployeeID, t.CreatedAt)
+	if err != nil {
+		return e.ErrInsertError
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return e.ErrInsertError
+	}
+	t.ID = int(id)
+
+	// the ledger takes the units out of the source batch and section and into the destination ones
+	out := mod.StockMovement{Type: mod.MovementTransfer, ProductBatchID: t.ProductBatchID, SectionID: t.FromSectionID,
+		Quantity: -t.Quantity, ReferenceType: mod.ReferenceTransfer, ReferenceID: t.ID, EmployeeID: t.EmployeeID}
+	if err = insertMovement(tx, &out); err != nil {
+		return err
+	}
+	in := mod.StockMovement{Type: mod.MovementTransfer, ProductBatchID: t.DestinationBatchID, SectionID: t.ToSectionID,
+		Quantity: t.Quantity, ReferenceType: mod.ReferenceTransfer, ReferenceID: t.ID, EmployeeID: t.EmployeeID}
+	return insertMovement(tx, &in)
+}
+
+// lockTransferSections locks the sections of a transfer and returns them by id
+func lockTransferSections(tx *sql.Tx, from, to int) (map[int]transferSection, error) {
+	rows, err := tx.Query("SELECT `id`, `current_temperature`, `current_capacity`, `maximum_capacity`, `product_type_id` FROM `sections` WHERE `id` IN (?, ?) ORDER BY `id` FOR UPDATE", from, to)
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	sections := make(map[int]transferSection, 2)
+	for rows.Next() {
+		var id int
+		var s transferSection
+		if err = rows.Scan(&id, &s.currentTemperature, &s.currentCapacity, &s.maximumCapacity, &s.productTypeID); err != nil {
+			return nil, e.ErrParseError
+		}
+		sections[id] = s
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return sections, nil
+}
+
+// moveBatch moves the whole batch to the destination section, or splits it when only part of its units move,
+// and returns the batch holding the moved units
+func moveBatch(tx *sql.Tx, t *mod.Transfer, current int) (int, error) {
+	if t.Quantity == current {
+		if _, err := tx.Exec("UPDATE `product_batches` SET `section_id` = ? WHERE `id` = ?", t.ToSectionID, t.ProductBatchID); err != nil {
+			return 0, e.ErrQueryError
+		}
+		return t.ProductBatchID, nil
+	}
+
+	if _, err := tx.Exec("UPDATE `product_batches` SET `current_quantity` = `current_quantity` - ? WHERE `id` = ?", t.Quantity, t.ProductBatchID); err != nil {
+		return 0, e.ErrQueryError
+	}
+	// the next batch number is read locking the last entry of the unique index, so two concurrent splits wait for
+	// each other instead of taking the same number
+	var number int
+	if err := tx.QueryRow("SELECT COALESCE(MAX(`batch_number`), 0) + 1 FROM `product_batches` FOR UPDATE").Scan(&number); err != nil {
+		return 0, e.ErrQueryError
+	}
+	// the split keeps the quality status and the expiry sweep state of the batch, otherwise the sweep would warn
+	// again about the moved units or miss that they already expired
+	res, err := tx.Exec("INSERT INTO `product_batches` (`batch_number`, `current_quantity`, `initial_quantity`, `current_temperature`, `minimum_temperature`, `due_date`, `manufacturing_date`, `manufacturing_hour`, `product_id`, `section_id`, `status`, `expiry_warned_at`, `expired_at`) "+
+		"SELECT ?, ?, ?, `current_temperature`, `minimum_temperature`, `due_date`, `manufacturing_date`, `manufacturing_hour`, `product_id`, ?, `status`, `expiry_warned_at`, `expired_at` "+
+		"FROM `product_batches` WHERE `id` = ?",
+		number, t.Quantity, t.Quantity, t.ToSectionID, t.ProductBatchID)
+	if err != nil {
+		return 0, e.ErrInsertError
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, e.ErrInsertError
+	}
+	return int(id), nil
+}
+
+// transferColumns are the columns read by scanTransfer
+const transferColumns = "SELECT `id`, `product_batch_id`, `destination_batch_id`, `from_section_id`, `to_section_id`, `quantity`, `employee_id`, `created_at` FROM `transfers`"
+
+// FindAll returns every transfer, newest first
+func (r *TransferDB) FindAll() ([]mod.Transfer, error) {
+	rows, err := r.db.Query(transferColumns + " ORDER BY `created_at` DESC, `id` DESC")
+	if err != nil {
+		return nil, e.ErrQueryError
+	}
+	defer rows.Close()
+	transfers := []mod.Transfer{}
+	for rows.Next() {
+		t, err := scanTransfer(rows)
+		if err != nil {
+			return nil, e.ErrParseError
+		}
+		transfers = append(transfers, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, e.ErrQueryError
+	}
+	return transfers, nil
+}
+
+// FindByID returns the transfer with the id
+func (r *TransferDB) FindByID(id int) (mod.Transfer, error) {
+	t, err := scanTransfer(r.db.QueryRow(transferColumns+" WHERE `id` = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return mod.Transfer{}, e.ErrTransferNotFound
+	}
+	if err != nil {
+		return mod.Transfer{}, e.ErrQueryError
+	}
+	return t, nil
+}
+
+// scanTransfer reads a transfer selected with transferColumns
+func scanTransfer(row interface{ Scan(...any) error }) (mod.Transfer, error) {
+	var t mod.Transfer
+	err := row.Scan(&t.ID, &t.ProductBatchID, &t.DestinationBatchID, &t.FromSectionID, &t.ToSectionID, &t.Quantity, &t.EmployeeID, &t.CreatedAt)
+	return t, err
+}
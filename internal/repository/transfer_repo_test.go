@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/cache"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	transferBatchQuery    = regexp.QuoteMeta("FROM `product_batches` AS pb JOIN `products` AS p ON p.`id` = pb.`product_id` WHERE pb.`id` = ? FOR UPDATE")
+	transferEmployeeQuery = regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM `employees` WHERE `id` = ?)")
+	transferSectionsQuery = regexp.QuoteMeta("FROM `sections` WHERE `id` IN (?, ?) ORDER BY `id` FOR UPDATE")
+	transferBatchColumns  = []string{"current_quantity", "minimum_temperature", "section_id", "product_type_id", "status"}
+	transferSectionCols   = []string{"id", "current_temperature", "current_capacity", "maximum_capacity", "product_type_id"}
+	stockMovementInsert   = regexp.QuoteMeta("INSERT INTO `stock_movements`")
+)
+
+// expectTransferLocks expects the batch 1 with 10 units in the section 1 and the destination section 2
+func expectTransferLocks(mock sqlmock.Sqlmock, toTemperature float64, toCapacity, toMaximum, toType int) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(transferBatchQuery).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(transferBatchColumns).AddRow(10, -10, 1, 3, mod.BatchAvailable))
+	mock.ExpectQuery(transferEmployeeQuery).WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(transferSectionsQuery).WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows(transferSectionCols).
+			AddRow(1, -15.0, 50, 100, 3).
+			AddRow(2, toTemperature, toCapacity, toMaximum, toType))
+}
+
+func TestTransferDB_Save(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("whole batch moves to the destination section", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		expectTransferLocks(mock, -8, 20, 100, 3)
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_batches` SET `section_id` = ? WHERE `id` = ?")).WithArgs(2, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("`current_capacity` = `current_capacity` - ? WHERE `id` = ?")).WithArgs(10, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("`current_capacity` = `current_capacity` + ? WHERE `id` = ?")).WithArgs(10, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(sectionSnapshotInsert).WithArgs(mod.SnapshotUpdate, now, 1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `transfers`")).WithArgs(1, 1, 1, 2, 10, 4, now).
+			WillReturnResult(sqlmock.NewResult(7, 1))
+		mock.ExpectExec(stockMovementInsert).WithArgs(mod.MovementTransfer, 1, 1, -10, mod.ReferenceTransfer, 7, 4, "").
+			WillReturnResult(sqlmock.NewResult(20, 1))
+		mock.ExpectExec(stockMovementInsert).WithArgs(mod.MovementTransfer, 1, 2, 10, mod.ReferenceTransfer, 7, 4, "").
+			WillReturnResult(sqlmock.NewResult(21, 1))
+		mock.ExpectCommit()
+
+		transfer := mod.Transfer{ProductBatchID: 1, ToSectionID: 2, EmployeeID: 4, CreatedAt: now}
+		require.NoError(t, NewTransferRepo(db).Save(&transfer))
+		require.Equal(t, mod.Transfer{ID: 7, ProductBatchID: 1, DestinationBatchID: 1, FromSectionID: 1, ToSectionID: 2, Quantity: 10, EmployeeID: 4, CreatedAt: now}, transfer)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("partial transfer splits the batch", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		expectTransferLocks(mock, -8, 20, 100, 3)
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_batches` SET `current_quantity` = `current_quantity` - ? WHERE `id` = ?")).WithArgs(4, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(`batch_number`), 0) + 1 FROM `product_batches` FOR UPDATE")).
+			WillReturnRows(sqlmock.NewRows([]string{"batch_number"}).AddRow(31))
+		mock.ExpectExec(regexp.QuoteMeta("`product_id`, ?, `status`, `expiry_warned_at`, `expired_at` FROM `product_batches` WHERE `id` = ?")).WithArgs(31, 4, 4, 2, 1).
+			WillReturnResult(sqlmock.NewResult(9, 1))
+		mock.ExpectExec(regexp.QuoteMeta("`current_capacity` = `current_capacity` - ? WHERE `id` = ?")).WithArgs(4, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("`current_capacity` = `current_capacity` + ? WHERE `id` = ?")).WithArgs(4, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(sectionSnapshotInsert).WithArgs(mod.SnapshotUpdate, now, 1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `transfers`")).WithArgs(1, 9, 1, 2, 4, 4, now).
+			WillReturnResult(sqlmock.NewResult(8, 1))
+		mock.ExpectExec(stockMovementInsert).WithArgs(mod.MovementTransfer, 1, 1, -4, mod.ReferenceTransfer, 8, 4, "").
+			WillReturnResult(sqlmock.NewResult(20, 1))
+		mock.ExpectExec(stockMovementInsert).WithArgs(mod.MovementTransfer, 9, 2, 4, mod.ReferenceTransfer, 8, 4, "").
+			WillReturnResult(sqlmock.NewResult(21, 1))
+		mock.ExpectCommit()
+
+		transfer := mod.Transfer{ProductBatchID: 1, ToSectionID: 2, Quantity: 4, EmployeeID: 4, CreatedAt: now}
+		require.NoError(t, NewTransferRepo(db).Save(&transfer))
+		require.Equal(t, 9, transfer.DestinationBatchID)
+		require.Equal(t, 8, transfer.ID)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	rejected := []struct {
+		name          string
+		quantity      int
+		toTemperature float64
+		toCapacity    int
+		toType        int
+		expected      error
+	}{
+		{name: "destination stores another product type", toTemperature: -8, toCapacity: 20, toType: 5, expected: e.ErrTransferProductType},
+		{name: "destination is colder than the batch minimum", toTemperature: -12, toCapacity: 20, toType: 3, expected: e.ErrTransferTemperature},
+		{name: "destination would exceed its maximum capacity", quantity: 6, toTemperature: -8, toCapacity: 95, toType: 3, expected: e.ErrTransferCapacity},
+	}
+	for _, tc := range rejected {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			expectTransferLocks(mock, tc.toTemperature, tc.toCapacity, 100, tc.toType)
+			mock.ExpectRollback()
+
+			transfer := mod.Transfer{ProductBatchID: 1, ToSectionID: 2, Quantity: tc.quantity, EmployeeID: 4, CreatedAt: now}
+			require.ErrorIs(t, NewTransferRepo(db).Save(&transfer), tc.expected)
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("quantity greater than the batch is rejected", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(transferBatchQuery).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(transferBatchColumns).AddRow(10, -10, 1, 3, mod.BatchAvailable))
+		mock.ExpectRollback()
+
+		transfer := mod.Transfer{ProductBatchID: 1, ToSectionID: 2, Quantity: 11, EmployeeID: 4}
+		require.ErrorIs(t, NewTransferRepo(db).Save(&transfer), e.ErrTransferQuantityInvalid)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("same section is rejected", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(transferBatchQuery).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(transferBatchColumns).AddRow(10, -10, 2, 3, mod.BatchReleased))
+		mock.ExpectRollback()
+
+		transfer := mod.Transfer{ProductBatchID: 1, ToSectionID: 2, EmployeeID: 4}
+		require.ErrorIs(t, NewTransferRepo(db).Save(&transfer), e.ErrTransferSameSection)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("batch on hold is rejected", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(transferBatchQuery).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(transferBatchColumns).AddRow(10, -10, 1, 3, mod.BatchQuarantined))
+		mock.ExpectRollback()
+
+		transfer := mod.Transfer{ProductBatchID: 1, ToSectionID: 2, EmployeeID: 4}
+		require.ErrorIs(t, NewTransferRepo(db).Save(&transfer), e.ErrProductBatchHeld)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("missing batch, employee or section", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		repo := NewTransferRepo(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(transferBatchQuery).WithArgs(1).WillReturnRows(sqlmock.NewRows(transferBatchColumns))
+		mock.ExpectRollback()
+		require.ErrorIs(t, repo.Save(&mod.Transfer{ProductBatchID: 1, ToSectionID: 2, EmployeeID: 4}), e.ErrProductBatchNotFound)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(transferBatchQuery).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(transferBatchColumns).AddRow(10, -10, 1, 3, mod.BatchAvailable))
+		mock.ExpectQuery(transferEmployeeQuery).WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectRollback()
+		require.ErrorIs(t, repo.Save(&mod.Transfer{ProductBatchID: 1, ToSectionID: 2, EmployeeID: 4}), e.ErrEmployeeRepositoryNotFound)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(transferBatchQuery).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(transferBatchColumns).AddRow(10, -10, 1, 3, mod.BatchAvailable))
+		mock.ExpectQuery(transferEmployeeQuery).WithArgs(4).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(transferSectionsQuery).WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows(transferSectionCols).AddRow(1, -15.0, 50, 100, 3))
+		mock.ExpectRollback()
+		require.ErrorIs(t, repo.Save(&mod.Transfer{ProductBatchID: 1, ToSectionID: 2, EmployeeID: 4}), e.ErrSectionRepositoryNotFound)
+
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTransferDB_Find(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewTransferRepo(db)
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	columns := []string{"id", "product_batch_id", "destination_batch_id", "from_section_id", "to_section_id", "quantity", "employee_id", "created_at"}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM `transfers` ORDER BY `created_at` DESC, `id` DESC")).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(7, 1, 9, 1, 2, 4, 4, now))
+	transfers, err := repo.FindAll()
+	require.NoError(t, err)
+	require.Equal(t, []mod.Transfer{{ID: 7, ProductBatchID: 1, DestinationBatchID: 9, FromSectionID: 1, ToSectionID: 2, Quantity: 4, EmployeeID: 4, CreatedAt: now}}, transfers)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM `transfers` WHERE `id` = ?")).WithArgs(8).
+		WillReturnRows(sqlmock.NewRows(columns))
+	_, err = repo.FindByID(8)
+	require.ErrorIs(t, err, e.ErrTransferNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCachedTransferRepo_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	c := cache.New[int, mod.Section](10, time.Minute)
+	c.Set(1, mod.Section{ID: 1})
+	c.Set(2, mod.Section{ID: 2})
+	c.Set(3, mod.Section{ID: 3})
+	repo := NewCachedTransferRepo(NewTransferRepo(db), c)
+
+	expectTransferLocks(mock, -8, 20, 100, 3)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `product_batches` SET `section_id` = ? WHERE `id` = ?")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("`current_capacity` = `current_capacity` - ?")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("`current_capacity` = `current_capacity` + ?")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(sectionSnapshotInsert).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `transfers`")).WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec(stockMovementInsert).WillReturnResult(sqlmock.NewResult(20, 1))
+	mock.ExpectExec(stockMovementInsert).WillReturnResult(sqlmock.NewResult(21, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Save(&mod.Transfer{ProductBatchID: 1, ToSectionID: 2, EmployeeID: 4}))
+	_, ok := c.Get(1)
+	require.False(t, ok)
+	_, ok = c.Get(2)
+	require.False(t, ok)
+	_, ok = c.Get(3)
+	require.True(t, ok)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
 	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
 )
 
@@ -19,7 +20,7 @@ func NewWarehouseRepository(db *sql.DB) *warehouseRepository {
 // GetAll devuelve un slice de warehouses
 func (r *warehouseRepository) GetAll() ([]models.Warehouse, error) {
 	query := `
-		SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature 
+		SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature, COALESCE(locality_id, 0), latitude, longitude
 		FROM warehouses
 	`
 
@@ -39,6 +40,9 @@ func (r *warehouseRepository) GetAll() ([]models.Warehouse, error) {
 			&wh.Telephone,
 			&wh.MinimumCapacity,
 			&wh.MinimumTemperature,
+			&wh.LocalityID,
+			&wh.Latitude,
+			&wh.Longitude,
 		); err != nil {
 			return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
 		}
@@ -55,7 +59,7 @@ func (r *warehouseRepository) GetAll() ([]models.Warehouse, error) {
 // GetByID sin contexto
 func (r *warehouseRepository) GetByID(id int) (models.Warehouse, error) {
 	query := `
-		SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature 
+		SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature, COALESCE(locality_id, 0), latitude, longitude
 		FROM warehouses 
 		WHERE id = ?
 	`
@@ -68,6 +72,9 @@ func (r *warehouseRepository) GetByID(id int) (models.Warehouse, error) {
 		&wh.Telephone,
 		&wh.MinimumCapacity,
 		&wh.MinimumTemperature,
+		&wh.LocalityID,
+		&wh.Latitude,
+		&wh.Longitude,
 	)
 
 	switch {
@@ -92,8 +99,8 @@ func (r *warehouseRepository) Save(wh *models.Warehouse) error {
 
 	query := `
 		INSERT INTO warehouses 
-			(warehouse_code, address, telephone, minimum_capacity, minimum_temperature) 
-		VALUES (?, ?, ?, ?, ?)
+			(warehouse_code, address, telephone, minimum_capacity, minimum_temperature, locality_id, latitude, longitude) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := r.db.Exec(query,
@@ -102,6 +109,9 @@ func (r *warehouseRepository) Save(wh *models.Warehouse) error {
 		wh.Telephone,
 		wh.MinimumCapacity,
 		wh.MinimumTemperature,
+		wh.LocalityID,
+		wh.Latitude,
+		wh.Longitude,
 	)
 	if err != nil {
 		return fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
@@ -125,7 +135,10 @@ func (r *warehouseRepository) Update(wh *models.Warehouse) error {
 			address = ?, 
 			telephone = ?, 
 			minimum_capacity = ?,
-			minimum_temperature = ?
+			minimum_temperature = ?,
+			locality_id = ?,
+			latitude = ?,
+			longitude = ?
 		WHERE id = ?
 	`
 
@@ -135,6 +148,9 @@ func (r *warehouseRepository) Update(wh *models.Warehouse) error {
 		wh.Telephone,
 		wh.MinimumCapacity,
 		wh.MinimumTemperature,
+		wh.LocalityID,
+		wh.Latitude,
+		wh.Longitude,
 		wh.ID,
 	)
 	if err != nil {
@@ -177,7 +193,7 @@ func (r *warehouseRepository) ExistsWarehouseCode(code string) (bool, error) {
 }
 func (r *warehouseRepository) GetByWarehouseCode(code string) (models.Warehouse, error) {
 	query := `
-		SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature 
+		SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature, COALESCE(locality_id, 0), latitude, longitude
 		FROM warehouses 
 		WHERE warehouse_code = ?
 	`
@@ -190,6 +206,9 @@ func (r *warehouseRepository) GetByWarehouseCode(code string) (models.Warehouse,
 		&wh.Telephone,
 		&wh.MinimumCapacity,
 		&wh.MinimumTemperature,
+		&wh.LocalityID,
+		&wh.Latitude,
+		&wh.Longitude,
 	)
 
 	switch {
@@ -201,3 +220,226 @@ func (r *warehouseRepository) GetByWarehouseCode(code string) (models.Warehouse,
 
 	return wh, nil
 }
+
+// GetCapacityReport suma la capacidad de las secciones de cada warehouse, o solo del warehouse id
+func (r *warehouseRepository) GetCapacityReport(id *int) ([]models.WarehouseCapacityReport, error) {
+	query := `
+		SELECT
+			w.id,
+			w.warehouse_code,
+			w.minimum_capacity,
+			COUNT(s.id) AS sections_count,
+			COALESCE(SUM(s.current_capacity), 0) AS current_capacity,
+			COALESCE(SUM(s.maximum_capacity), 0) AS maximum_capacity,
+			COALESCE(SUM(s.current_capacity < s.minimum_capacity), 0) AS sections_below_minimum
+		FROM warehouses w
+		LEFT JOIN sections s ON s.warehouse_id = w.id
+	`
+	var args []any
+	if id != nil {
+		query += "WHERE w.id = ?\n"
+		args = append(args, *id)
+	}
+	query += "GROUP BY w.id, w.warehouse_code, w.minimum_capacity\nORDER BY w.id"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+	}
+	defer rows.Close()
+
+	reports := []models.WarehouseCapacityReport{}
+	for rows.Next() {
+		var report models.WarehouseCapacityReport
+		if err := rows.Scan(
+			&report.WarehouseID,
+			&report.WarehouseCode,
+			&report.MinimumCapacity,
+			&report.SectionsCount,
+			&report.CurrentCapacity,
+			&report.MaximumCapacity,
+			&report.SectionsBelowMinimum,
+		); err != nil {
+			return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+		}
+		reports = append(reports, report)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+	}
+
+	if id != nil && len(reports) == 0 {
+		return nil, e.ErrWarehouseRepositoryNotFound
+	}
+
+	return reports, nil
+}
+
+// GetSections devuelve las secciones del warehouse con la cantidad de lotes que aún tienen stock y no están retenidos
+func (r *warehouseRepository) GetSections(id int, filter models.WarehouseSectionFilter) ([]models.WarehouseSection, error) {
+	query := `
+		SELECT
+			s.id, s.section_number, s.current_temperature, s.minimum_temperature, s.current_capacity,
+			s.minimum_capacity, s.maximum_capacity, s.warehouse_id, s.product_type_id,
+			COUNT(pb.id) AS active_batches
+		FROM sections s
+		LEFT JOIN product_batches pb ON pb.section_id = s.id AND pb.current_quantity > 0 AND pb.status IN ('available', 'released')
+		WHERE s.warehouse_id = ?
+	`
+	args := []any{id}
+	if filter.ProductTypeID != nil {
+		query += "AND s.product_type_id = ?\n"
+		args = append(args, *filter.ProductTypeID)
+	}
+	if filter.BelowMinimum {
+		query += "AND s.current_capacity < s.minimum_capacity\n"
+	}
+	query += "GROUP BY s.id\nORDER BY s.section_number"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+	}
+	defer rows.Close()
+
+	sections := []models.WarehouseSection{}
+	for rows.Next() {
+		var s models.WarehouseSection
+		if err := rows.Scan(
+			&s.ID,
+			&s.SectionNumber,
+			&s.CurrentTemperature,
+			&s.MinimumTemperature,
+			&s.CurrentCapacity,
+			&s.MinimumCapacity,
+			&s.MaximumCapacity,
+			&s.WarehouseID,
+			&s.ProductTypeID,
+			&s.ActiveBatches,
+		); err != nil {
+			return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+		}
+		sections = append(sections, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+	}
+
+	return sections, nil
+}
+
+// GetEmployees devuelve los empleados del warehouse, filtrando por nombre o apellido cuando name no está vacío
+func (r *warehouseRepository) GetEmployees(id int, name string) ([]models.Employee, error) {
+	query := `
+		SELECT id, id_card_number, first_name, last_name, wareHouse_id
+		FROM employees
+		WHERE wareHouse_id = ?
+	`
+	args := []any{id}
+	if name != "" {
+		query += "AND (first_name LIKE ? OR last_name LIKE ?)\n"
+		// los comodines de LIKE en el nombre se escapan para que % y _ se busquen como texto
+		like := common.LikePattern(name)
+		args = append(args, like, like)
+	}
+	query += "ORDER BY id"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+	}
+	defer rows.Close()
+
+	employees := []models.Employee{}
+	for rows.Next() {
+		var emp models.Employee
+		if err := rows.Scan(&emp.ID, &emp.CardNumberID, &emp.FirstName, &emp.LastName, &emp.WarehouseID); err != nil {
+			return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+		}
+		employees = append(employees, emp)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+	}
+
+	return employees, nil
+}
+
+// GetInboundOrders devuelve las últimas limit órdenes de entrada del warehouse, de la más nueva a la más vieja
+func (r *warehouseRepository) GetInboundOrders(id int, limit int) ([]models.InboundOrders, error) {
+	query := `
+		SELECT id, COALESCE(DATE_FORMAT(order_date, '%Y-%m-%d'), ''), order_number, employee_id, product_batch_id, wareHouse_id
+		FROM inbound_orders
+		WHERE wareHouse_id = ?
+		ORDER BY order_date DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+	}
+	defer rows.Close()
+
+	orders := []models.InboundOrders{}
+	for rows.Next() {
+		var io models.InboundOrders
+		if err := rows.Scan(&io.Id, &io.OrderDate, &io.OrderNumber, &io.EmployeeId, &io.ProductBatchId, &io.WarehouseId); err != nil {
+			return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+		}
+		orders = append(orders, io)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+	}
+
+	return orders, nil
+}
+
+// GetStockingProduct devuelve los warehouses con coordenadas que tienen lotes vigentes, liberados y con stock del producto
+func (r *warehouseRepository) GetStockingProduct(productID int) ([]models.WarehouseDistance, error) {
+	query := `
+		SELECT w.id, w.warehouse_code, w.address, COALESCE(w.locality_id, 0), w.latitude, w.longitude,
+			SUM(pb.current_quantity)
+		FROM warehouses AS w
+		JOIN sections AS s ON s.warehouse_id = w.id
+		JOIN product_batches AS pb ON pb.section_id = s.id
+		WHERE pb.product_id = ? AND pb.current_quantity > 0 AND pb.due_date > UTC_TIMESTAMP() AND pb.status IN ('available', 'released')
+			AND w.latitude IS NOT NULL AND w.longitude IS NOT NULL
+		GROUP BY w.id, w.warehouse_code, w.address, w.locality_id, w.latitude, w.longitude
+		ORDER BY w.id
+	`
+
+	rows, err := r.db.Query(query, productID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+	}
+	defer rows.Close()
+
+	warehouses := []models.WarehouseDistance{}
+	for rows.Next() {
+		var wd models.WarehouseDistance
+		if err := rows.Scan(
+			&wd.WarehouseID,
+			&wd.WarehouseCode,
+			&wd.Address,
+			&wd.LocalityID,
+			&wd.Latitude,
+			&wd.Longitude,
+			&wd.AvailableQuantity,
+		); err != nil {
+			return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+		}
+		warehouses = append(warehouses, wd)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", e.ErrRepositoryDatabase, err)
+	}
+
+	return warehouses, nil
+}
@@ -33,6 +33,7 @@ func TestGetAll(t *testing.T) {
 	defer close()
 
 	t.Run("success", func(t *testing.T) {
+		lat, lng := -34.6037, -58.3816
 		expect := []models.Warehouse{
 			{
 				ID:                 1,
@@ -41,6 +42,9 @@ func TestGetAll(t *testing.T) {
 				Telephone:          "123456789",
 				MinimumCapacity:    100,
 				MinimumTemperature: 25,
+				LocalityID:         1,
+				Latitude:           &lat,
+				Longitude:          &lng,
 			},
 			{
 				ID:                 2,
@@ -49,18 +53,19 @@ func TestGetAll(t *testing.T) {
 				Telephone:          "987654321",
 				MinimumCapacity:    200,
 				MinimumTemperature: 30,
+				LocalityID:         2,
 			},
 		}
 
 		// Configura el mock para retornar 2 filas
 		rows := sqlmock.NewRows([]string{
-			"id", "warehouse_code", "address", "telephone", "minimum_capacity", "minimum_temperature",
+			"id", "warehouse_code", "address", "telephone", "minimum_capacity", "minimum_temperature", "locality_id", "latitude", "longitude",
 		}).
-			AddRow(1, "WH001", "Calle Falsa 123", "123456789", 100, 25).
-			AddRow(2, "WH002", "Avenida Siempreviva 456", "987654321", 200, 30)
+			AddRow(1, "WH001", "Calle Falsa 123", "123456789", 100, 25, 1, lat, lng).
+			AddRow(2, "WH002", "Avenida Siempreviva 456", "987654321", 200, 30, 2, nil, nil)
 
 		mock.ExpectQuery(regexp.QuoteMeta(`
-            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature 
+            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature, COALESCE(locality_id, 0), latitude, longitude
             FROM warehouses
         `)).
 			WillReturnRows(rows)
@@ -74,11 +79,11 @@ func TestGetAll(t *testing.T) {
 	t.Run("empty_result", func(t *testing.T) {
 		// Configura el mock para retornar 0 filas
 		rows := sqlmock.NewRows([]string{
-			"id", "warehouse_code", "address", "telephone", "minimum_capacity", "minimum_temperature",
+			"id", "warehouse_code", "address", "telephone", "minimum_capacity", "minimum_temperature", "locality_id", "latitude", "longitude",
 		})
 
 		mock.ExpectQuery(regexp.QuoteMeta(`
-            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature 
+            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature, COALESCE(locality_id, 0), latitude, longitude
             FROM warehouses
         `)).
 			WillReturnRows(rows)
@@ -92,7 +97,7 @@ func TestGetAll(t *testing.T) {
 	t.Run("database_error", func(t *testing.T) {
 		// Simula un error en la consulta
 		mock.ExpectQuery(regexp.QuoteMeta(`
-            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature 
+            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature, COALESCE(locality_id, 0), latitude, longitude
             FROM warehouses
         `)).
 			WillReturnError(fmt.Errorf("database error"))
@@ -117,11 +122,12 @@ func TestGetByID(t *testing.T) {
 			Telephone:          "123456789",
 			MinimumCapacity:    100,
 			MinimumTemperature: 25,
+			LocalityID:         1,
 		}
 
 		// Configura el mock para retornar una fila
 		rows := sqlmock.NewRows([]string{
-			"id", "warehouse_code", "address", "telephone", "minimum_capacity", "minimum_temperature",
+			"id", "warehouse_code", "address", "telephone", "minimum_capacity", "minimum_temperature", "locality_id", "latitude", "longitude",
 		}).AddRow(
 			expected.ID,
 			expected.WarehouseCode,
@@ -129,10 +135,13 @@ func TestGetByID(t *testing.T) {
 			expected.Telephone,
 			expected.MinimumCapacity,
 			expected.MinimumTemperature,
+			expected.LocalityID,
+			expected.Latitude,
+			expected.Longitude,
 		)
 
 		mock.ExpectQuery(regexp.QuoteMeta(`
-            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature 
+            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature, COALESCE(locality_id, 0), latitude, longitude
             FROM warehouses 
             WHERE id = ?
         `)).
@@ -148,7 +157,7 @@ func TestGetByID(t *testing.T) {
 	t.Run("get_by_id_not_found", func(t *testing.T) {
 		// Configura el mock para retornar "no rows"
 		mock.ExpectQuery(regexp.QuoteMeta(`
-            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature 
+            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature, COALESCE(locality_id, 0), latitude, longitude
             FROM warehouses 
             WHERE id = ?
         `)).
@@ -165,7 +174,7 @@ func TestGetByID(t *testing.T) {
 	t.Run("get_by_id_database_error", func(t *testing.T) {
 		// Simula un error genérico de la base de datos
 		mock.ExpectQuery(regexp.QuoteMeta(`
-            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature 
+            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature, COALESCE(locality_id, 0), latitude, longitude
             FROM warehouses 
             WHERE id = ?
         `)).
@@ -190,6 +199,7 @@ func TestSave(t *testing.T) {
 			Telephone:          "123456789",
 			MinimumCapacity:    100,
 			MinimumTemperature: 25,
+			LocalityID:         1,
 		}
 
 		// Mock de SELECT EXISTS (no existe)
@@ -202,8 +212,8 @@ func TestSave(t *testing.T) {
 		// Mock de INSERT exitoso
 		mock.ExpectExec(regexp.QuoteMeta(`
             INSERT INTO warehouses 
-                (warehouse_code, address, telephone, minimum_capacity, minimum_temperature) 
-            VALUES (?, ?, ?, ?, ?)
+                (warehouse_code, address, telephone, minimum_capacity, minimum_temperature, locality_id, latitude, longitude) 
+            VALUES (?, ?, ?, ?, ?, ?, ?, ?)
         `)).
 			WithArgs(
 				res.WarehouseCode,
@@ -211,6 +221,9 @@ func TestSave(t *testing.T) {
 				res.Telephone,
 				res.MinimumCapacity,
 				res.MinimumTemperature,
+				res.LocalityID,
+				res.Latitude,
+				res.Longitude,
 			).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -227,6 +240,7 @@ func TestSave(t *testing.T) {
 			Telephone:          "123456789",
 			MinimumCapacity:    100,
 			MinimumTemperature: 25,
+			LocalityID:         1,
 		}
 
 		// Mock de SELECT EXISTS (ya existe)
@@ -249,6 +263,7 @@ func TestSave(t *testing.T) {
 			Telephone:          "123456789",
 			MinimumCapacity:    100,
 			MinimumTemperature: 25,
+			LocalityID:         1,
 		}
 
 		// Mock de SELECT EXISTS con error
@@ -271,6 +286,7 @@ func TestSave(t *testing.T) {
 			Telephone:          "123456789",
 			MinimumCapacity:    100,
 			MinimumTemperature: 25,
+			LocalityID:         1,
 		}
 
 		// Mock de SELECT EXISTS (no existe)
@@ -283,8 +299,8 @@ func TestSave(t *testing.T) {
 		// Mock de INSERT con error
 		mock.ExpectExec(regexp.QuoteMeta(`
             INSERT INTO warehouses 
-                (warehouse_code, address, telephone, minimum_capacity, minimum_temperature) 
-            VALUES (?, ?, ?, ?, ?)
+                (warehouse_code, address, telephone, minimum_capacity, minimum_temperature, locality_id, latitude, longitude) 
+            VALUES (?, ?, ?, ?, ?, ?, ?, ?)
         `)).
 			WithArgs(
 				req.WarehouseCode,
@@ -292,6 +308,9 @@ func TestSave(t *testing.T) {
 				req.Telephone,
 				req.MinimumCapacity,
 				req.MinimumTemperature,
+				req.LocalityID,
+				req.Latitude,
+				req.Longitude,
 			).
 			WillReturnError(fmt.Errorf("database error"))
 
@@ -308,6 +327,7 @@ func TestSave(t *testing.T) {
 			Telephone:          "123456789",
 			MinimumCapacity:    100,
 			MinimumTemperature: 25,
+			LocalityID:         1,
 		}
 
 		// Mock de SELECT EXISTS (no existe)
@@ -320,8 +340,8 @@ func TestSave(t *testing.T) {
 		// Mock de INSERT exitoso, pero error al obtener el ID
 		mock.ExpectExec(regexp.QuoteMeta(`
             INSERT INTO warehouses 
-                (warehouse_code, address, telephone, minimum_capacity, minimum_temperature) 
-            VALUES (?, ?, ?, ?, ?)
+                (warehouse_code, address, telephone, minimum_capacity, minimum_temperature, locality_id, latitude, longitude) 
+            VALUES (?, ?, ?, ?, ?, ?, ?, ?)
         `)).
 			WithArgs(
 				req.WarehouseCode,
@@ -329,6 +349,9 @@ func TestSave(t *testing.T) {
 				req.Telephone,
 				req.MinimumCapacity,
 				req.MinimumTemperature,
+				req.LocalityID,
+				req.Latitude,
+				req.Longitude,
 			).
 			WillReturnResult(sqlmock.NewErrorResult(fmt.Errorf("error getting last insert id")))
 
@@ -350,6 +373,7 @@ func TestUpdate(t *testing.T) {
 			Telephone:          "123456789",
 			MinimumCapacity:    100,
 			MinimumTemperature: 25,
+			LocalityID:         1,
 		}
 
 		mock.ExpectExec(regexp.QuoteMeta(`
@@ -359,7 +383,10 @@ func TestUpdate(t *testing.T) {
                 address = ?, 
                 telephone = ?, 
                 minimum_capacity = ?,
-                minimum_temperature = ?
+                minimum_temperature = ?,
+                locality_id = ?,
+                latitude = ?,
+                longitude = ?
             WHERE id = ?
         `)).
 			WithArgs(
@@ -368,6 +395,9 @@ func TestUpdate(t *testing.T) {
 				res.Telephone,
 				res.MinimumCapacity,
 				res.MinimumTemperature,
+				res.LocalityID,
+				res.Latitude,
+				res.Longitude,
 				res.ID,
 			).
 			WillReturnResult(sqlmock.NewResult(1, 1)) // 1 fila afectada
@@ -385,6 +415,7 @@ func TestUpdate(t *testing.T) {
 			Telephone:          "123456789",
 			MinimumCapacity:    100,
 			MinimumTemperature: 25,
+			LocalityID:         1,
 		}
 
 		mock.ExpectExec(regexp.QuoteMeta(`
@@ -394,7 +425,10 @@ func TestUpdate(t *testing.T) {
                 address = ?, 
                 telephone = ?, 
                 minimum_capacity = ?,
-                minimum_temperature = ?
+                minimum_temperature = ?,
+                locality_id = ?,
+                latitude = ?,
+                longitude = ?
             WHERE id = ?
         `)).
 			WithArgs(
@@ -403,6 +437,9 @@ func TestUpdate(t *testing.T) {
 				req.Telephone,
 				req.MinimumCapacity,
 				req.MinimumTemperature,
+				req.LocalityID,
+				req.Latitude,
+				req.Longitude,
 				req.ID,
 			).
 			WillReturnResult(sqlmock.NewResult(0, 0)) // 0 filas afectadas
@@ -421,6 +458,7 @@ func TestUpdate(t *testing.T) {
 			Telephone:          "123456789",
 			MinimumCapacity:    100,
 			MinimumTemperature: 25,
+			LocalityID:         1,
 		}
 
 		mock.ExpectExec(regexp.QuoteMeta(`
@@ -430,7 +468,10 @@ func TestUpdate(t *testing.T) {
                 address = ?, 
                 telephone = ?, 
                 minimum_capacity = ?,
-                minimum_temperature = ?
+                minimum_temperature = ?,
+                locality_id = ?,
+                latitude = ?,
+                longitude = ?
             WHERE id = ?
         `)).
 			WithArgs(
@@ -439,6 +480,9 @@ func TestUpdate(t *testing.T) {
 				req.Telephone,
 				req.MinimumCapacity,
 				req.MinimumTemperature,
+				req.LocalityID,
+				req.Latitude,
+				req.Longitude,
 				req.ID,
 			).
 			WillReturnError(&mysql.MySQLError{
@@ -460,6 +504,7 @@ func TestUpdate(t *testing.T) {
 			Telephone:          "123456789",
 			MinimumCapacity:    100,
 			MinimumTemperature: 25,
+			LocalityID:         1,
 		}
 
 		mock.ExpectExec(regexp.QuoteMeta(`
@@ -469,7 +514,10 @@ func TestUpdate(t *testing.T) {
                 address = ?, 
                 telephone = ?, 
                 minimum_capacity = ?,
-                minimum_temperature = ?
+                minimum_temperature = ?,
+                locality_id = ?,
+                latitude = ?,
+                longitude = ?
             WHERE id = ?
         `)).
 			WithArgs(
@@ -478,6 +526,9 @@ func TestUpdate(t *testing.T) {
 				req.Telephone,
 				req.MinimumCapacity,
 				req.MinimumTemperature,
+				req.LocalityID,
+				req.Latitude,
+				req.Longitude,
 				req.ID,
 			).
 			WillReturnError(fmt.Errorf("database error"))
@@ -588,11 +639,12 @@ func TestGetByWarehouseCode(t *testing.T) {
 			Telephone:          "123456789",
 			MinimumCapacity:    100,
 			MinimumTemperature: 25,
+			LocalityID:         1,
 		}
 
 		// Mock: Retorna una fila
 		rows := sqlmock.NewRows([]string{
-			"id", "warehouse_code", "address", "telephone", "minimum_capacity", "minimum_temperature",
+			"id", "warehouse_code", "address", "telephone", "minimum_capacity", "minimum_temperature", "locality_id", "latitude", "longitude",
 		}).
 			AddRow(
 				expected.ID,
@@ -601,10 +653,13 @@ func TestGetByWarehouseCode(t *testing.T) {
 				expected.Telephone,
 				expected.MinimumCapacity,
 				expected.MinimumTemperature,
+				expected.LocalityID,
+				expected.Latitude,
+				expected.Longitude,
 			)
 
 		mock.ExpectQuery(regexp.QuoteMeta(`
-            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature 
+            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature, COALESCE(locality_id, 0), latitude, longitude
             FROM warehouses 
             WHERE warehouse_code = ?
         `)).
@@ -621,7 +676,7 @@ func TestGetByWarehouseCode(t *testing.T) {
 
 		// Mock: Retorna error "no rows"
 		mock.ExpectQuery(regexp.QuoteMeta(`
-            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature 
+            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature, COALESCE(locality_id, 0), latitude, longitude
             FROM warehouses 
             WHERE warehouse_code = ?
         `)).
@@ -639,7 +694,7 @@ func TestGetByWarehouseCode(t *testing.T) {
 
 		// Mock: Retorna error genérico
 		mock.ExpectQuery(regexp.QuoteMeta(`
-            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature 
+            SELECT id, warehouse_code, address, telephone, minimum_capacity, minimum_temperature, COALESCE(locality_id, 0), latitude, longitude
             FROM warehouses 
             WHERE warehouse_code = ?
         `)).
@@ -652,3 +707,184 @@ func TestGetByWarehouseCode(t *testing.T) {
 		require.Equal(t, models.Warehouse{}, result)
 	})
 }
+
+func TestGetCapacityReport(t *testing.T) {
+	columns := []string{"id", "warehouse_code", "minimum_capacity", "sections_count", "current_capacity", "maximum_capacity", "sections_below_minimum"}
+
+	t.Run("all", func(t *testing.T) {
+		repo, mock, close := setupWareHouseRepo(t)
+		defer close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("LEFT JOIN sections s ON s.warehouse_id = w.id")).
+			WithArgs().
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, "WH1", 50, 2, 60, 80, 0).
+				AddRow(2, "WH2", 100, 0, 0, 0, 0))
+
+		result, err := repo.GetCapacityReport(nil)
+		require.NoError(t, err)
+		require.Equal(t, []models.WarehouseCapacityReport{
+			{WarehouseID: 1, WarehouseCode: "WH1", MinimumCapacity: 50, SectionsCount: 2, CurrentCapacity: 60, MaximumCapacity: 80},
+			{WarehouseID: 2, WarehouseCode: "WH2", MinimumCapacity: 100},
+		}, result)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("by_id", func(t *testing.T) {
+		repo, mock, close := setupWareHouseRepo(t)
+		defer close()
+		id := 1
+
+		mock.ExpectQuery(regexp.QuoteMeta("WHERE w.id = ?")).
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "WH1", 50, 2, 30, 80, 1))
+
+		result, err := repo.GetCapacityReport(&id)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, 1, result[0].SectionsBelowMinimum)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		repo, mock, close := setupWareHouseRepo(t)
+		defer close()
+		id := 9
+
+		mock.ExpectQuery(regexp.QuoteMeta("WHERE w.id = ?")).
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err := repo.GetCapacityReport(&id)
+		require.ErrorIs(t, err, e.ErrWarehouseRepositoryNotFound)
+	})
+
+	t.Run("database_error", func(t *testing.T) {
+		repo, mock, close := setupWareHouseRepo(t)
+		defer close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("FROM warehouses w")).
+			WillReturnError(fmt.Errorf("database error"))
+
+		_, err := repo.GetCapacityReport(nil)
+		require.ErrorIs(t, err, e.ErrRepositoryDatabase)
+	})
+}
+
+func TestGetSections(t *testing.T) {
+	columns := []string{"id", "section_number", "current_temperature", "minimum_temperature", "current_capacity", "minimum_capacity", "maximum_capacity", "warehouse_id", "product_type_id", "active_batches"}
+
+	t.Run("with_filters", func(t *testing.T) {
+		repo, mock, close := setupWareHouseRepo(t)
+		defer close()
+		productType := 2
+
+		mock.ExpectQuery(regexp.QuoteMeta("AND s.product_type_id = ?\nAND s.current_capacity < s.minimum_capacity")).
+			WithArgs(1, 2).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(3, 1, 4.5, 1.0, 5, 8, 10, 1, 2, 4))
+
+		result, err := repo.GetSections(1, models.WarehouseSectionFilter{ProductTypeID: &productType, BelowMinimum: true})
+		require.NoError(t, err)
+		require.Equal(t, []models.WarehouseSection{{
+			Section:       models.Section{ID: 3, SectionNumber: 1, CurrentTemperature: 4.5, MinimumTemperature: 1, CurrentCapacity: 5, MinimumCapacity: 8, MaximumCapacity: 10, WarehouseID: 1, ProductTypeID: 2},
+			ActiveBatches: 4,
+		}}, result)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database_error", func(t *testing.T) {
+		repo, mock, close := setupWareHouseRepo(t)
+		defer close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("FROM sections s")).
+			WithArgs(1).
+			WillReturnError(fmt.Errorf("database error"))
+
+		_, err := repo.GetSections(1, models.WarehouseSectionFilter{})
+		require.ErrorIs(t, err, e.ErrRepositoryDatabase)
+	})
+}
+
+func TestGetEmployees(t *testing.T) {
+	t.Run("by_name", func(t *testing.T) {
+		repo, mock, close := setupWareHouseRepo(t)
+		defer close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("AND (first_name LIKE ? OR last_name LIKE ?)")).
+			WithArgs(1, "%an%", "%an%").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "id_card_number", "first_name", "last_name", "wareHouse_id"}).
+				AddRow(7, "C7", "Ana", "Paz", 1))
+
+		result, err := repo.GetEmployees(1, "an")
+		require.NoError(t, err)
+		require.Equal(t, []models.Employee{{ID: 7, CardNumberID: "C7", FirstName: "Ana", LastName: "Paz", WarehouseID: 1}}, result)
+	})
+
+	t.Run("by_name_with_wildcards", func(t *testing.T) {
+		repo, mock, close := setupWareHouseRepo(t)
+		defer close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("AND (first_name LIKE ? OR last_name LIKE ?)")).
+			WithArgs(1, `%\%\_%`, `%\%\_%`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "id_card_number", "first_name", "last_name", "wareHouse_id"}))
+
+		result, err := repo.GetEmployees(1, "%_")
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+
+	t.Run("database_error", func(t *testing.T) {
+		repo, mock, close := setupWareHouseRepo(t)
+		defer close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("FROM employees")).
+			WithArgs(1).
+			WillReturnError(fmt.Errorf("database error"))
+
+		_, err := repo.GetEmployees(1, "")
+		require.ErrorIs(t, err, e.ErrRepositoryDatabase)
+	})
+}
+
+func TestGetInboundOrders(t *testing.T) {
+	repo, mock, close := setupWareHouseRepo(t)
+	defer close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY order_date DESC, id DESC")).
+		WithArgs(1, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_date", "order_number", "employee_id", "product_batch_id", "wareHouse_id"}).
+			AddRow(8, "2024-05-01", "IO-8", 7, 2, 1))
+
+	result, err := repo.GetInboundOrders(1, 10)
+	require.NoError(t, err)
+	require.Equal(t, []models.InboundOrders{{Id: 8, OrderDate: "2024-05-01", OrderNumber: "IO-8", EmployeeId: 7, ProductBatchId: 2, WarehouseId: 1}}, result)
+}
+
+func TestGetStockingProduct(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		repo, mock, close := setupWareHouseRepo(t)
+		defer close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("WHERE pb.product_id = ? AND pb.current_quantity > 0 AND pb.due_date > UTC_TIMESTAMP()")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "warehouse_code", "address", "locality_id", "latitude", "longitude", "available_quantity"}).
+				AddRow(1, "WH1", "Calle Falsa 123", 3, -34.6037, -58.3816, 40))
+
+		result, err := repo.GetStockingProduct(2)
+		require.NoError(t, err)
+		require.Equal(t, []models.WarehouseDistance{{WarehouseID: 1, WarehouseCode: "WH1", Address: "Calle Falsa 123", LocalityID: 3,
+			Latitude: -34.6037, Longitude: -58.3816, AvailableQuantity: 40}}, result)
+		require.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database_error", func(t *testing.T) {
+		repo, mock, close := setupWareHouseRepo(t)
+		defer close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("FROM warehouses AS w")).
+			WithArgs(2).
+			WillReturnError(fmt.Errorf("database error"))
+
+		_, err := repo.GetStockingProduct(2)
+		require.ErrorIs(t, err, e.ErrRepositoryDatabase)
+	})
+}
@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Next(t *testing.T) {
+	from := time.Date(2024, 5, 1, 10, 7, 30, 0, time.UTC) // a wednesday
+	tests := []struct {
+		name     string
+		spec     string
+		expected time.Time
+	}{
+		{name: "every minute", spec: "* * * * *", expected: time.Date(2024, 5, 1, 10, 8, 0, 0, time.UTC)},
+		{name: "step", spec: "*/15 * * * *", expected: time.Date(2024, 5, 1, 10, 15, 0, 0, time.UTC)},
+		{name: "list and range", spec: "0 2,9-11 * * *", expected: time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)},
+		{name: "daily shorthand", spec: "@daily", expected: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{name: "day of week", spec: "30 6 * * 1", expected: time.Date(2024, 5, 6, 6, 30, 0, 0, time.UTC)},
+		{name: "sunday as 7", spec: "0 0 * * 7", expected: time.Date(2024, 5, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "day of month or day of week", spec: "0 0 15 * 5", expected: time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC)},
+		{name: "next year", spec: "0 0 1 1 *", expected: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "interval", spec: "@every 90s", expected: from.Truncate(90 * time.Second).Add(90 * time.Second)},
+		{name: "interval aligned", spec: "@every 1h", expected: time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)},
+		{name: "impossible date", spec: "0 0 30 2 *", expected: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, schedule.Next(from))
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@every 1ms", "@sometimes"} {
+		t.Run(spec, func(t *testing.T) {
+			_, err := Parse(spec)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+)
+
+// RunsCleanup returns a job that deletes the runs older than retention from the history
+func RunsCleanup(rp internal.JobRepository, retention time.Duration) Func {
+	return func(ctx context.Context) error {
+		deleted, err := rp.DeleteRunsBefore(time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			log.Printf("scheduler: %d job runs deleted", deleted)
+		}
+		return nil
+	}
+}
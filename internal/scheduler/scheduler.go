@@ -0,0 +1,317 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// DefaultTimeout is how long a run can take before its context is cancelled, it is also the lease duration
+const DefaultTimeout = 5 * time.Minute
+
+// Func is the work of a job. ctx is done when the run times out or the scheduler stops, a job that can stop
+// midway should return then, one that cannot is waited for by Stop until its own deadline
+type Func func(ctx context.Context) error
+
+// job is a registered job and its state in this instance
+type job struct {
+	name     string
+	spec     string
+	schedule Schedule
+	timeout  time.Duration
+	fn       Func
+	running  bool
+	next     time.Time
+}
+
+// New creates a scheduler that records its leases and runs in rp. The owner identifies this instance in the
+// leases, when empty the host name and process id are used
+func New(rp internal.JobRepository, owner string) *Scheduler {
+	if owner == "" {
+		host, _ := os.Hostname()
+		owner = host + ":" + strconv.Itoa(os.Getpid())
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		rp:     rp,
+		owner:  owner,
+		jobs:   make(map[string]*job),
+		now:    time.Now,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Scheduler runs the registered jobs on their cron schedules. Every run takes a lease in the database first,
+// and the scheduled runs also record their activation on it, so when several instances share the database
+// only one of them runs each activation even if their timers fire after the lease was released
+type Scheduler struct {
+	rp    internal.JobRepository
+	owner string
+	now   func() time.Time
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	started bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Register adds a job that runs fn on the cron spec with DefaultTimeout. It must be called before Start
+func (s *Scheduler) Register(name, spec string, fn Func) error {
+	return s.RegisterWithTimeout(name, spec, DefaultTimeout, fn)
+}
+
+// RegisterWithTimeout adds a job whose runs are cancelled after timeout. It must be called before Start
+func (s *Scheduler) RegisterWithTimeout(name, spec string, timeout time.Duration, fn Func) error {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("%w: %v", e.ErrJobScheduleInvalid, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("%w: %s", e.ErrJobDuplicated, name)
+	}
+	s.jobs[name] = &job{
+		name:     name,
+		spec:     spec,
+		schedule: schedule,
+		timeout:  timeout,
+		fn:       fn,
+	}
+	return nil
+}
+
+// Start starts the timers of the registered jobs
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(j)
+	}
+}
+
+// Stop cancels the running jobs and waits for them to return or for ctx to be done
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.cancel()
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// loop waits for every activation of the job and runs it unless the job is paused
+func (s *Scheduler) loop(j *job) {
+	defer s.wg.Done()
+	for {
+		s.mu.Lock()
+		j.next = j.schedule.Next(s.now())
+		next := j.next
+		s.mu.Unlock()
+		if next.IsZero() {
+			return
+		}
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		paused, err := s.rp.FindPaused()
+		if err != nil {
+			log.Printf("scheduler: %s skipped: %v", j.name, err)
+			continue
+		}
+		if paused[j.name] {
+			continue
+		}
+		ok, err := s.claim(j, next)
+		if err != nil {
+			log.Printf("scheduler: %s lease failed: %v", j.name, err)
+		}
+		if !ok {
+			continue
+		}
+		s.execute(j, mod.JobTriggerSchedule)
+	}
+}
+
+// claim marks the job as running in this instance and takes its lease for the activation, it returns false
+// when the job is already running here or in another instance or the activation already ran. Manual runs
+// have no activation
+func (s *Scheduler) claim(j *job, activation time.Time) (bool, error) {
+	s.mu.Lock()
+	if j.running {
+		s.mu.Unlock()
+		return false, nil
+	}
+	j.running = true
+	s.mu.Unlock()
+
+	now := s.now()
+	ok, err := s.rp.AcquireLease(j.name, s.owner, activation, now, now.Add(j.timeout))
+	if err != nil || !ok {
+		s.mu.Lock()
+		j.running = false
+		s.mu.Unlock()
+		return false, err
+	}
+	return true, nil
+}
+
+// execute runs a claimed job, records the run and releases the lease
+func (s *Scheduler) execute(j *job, trigger string) {
+	ctx, cancel := context.WithTimeout(s.ctx, j.timeout)
+	defer cancel()
+
+	run := mod.JobRun{
+		Job:       j.name,
+		Owner:     s.owner,
+		Trigger:   trigger,
+		StartedAt: s.now(),
+		Status:    mod.JobRunSucceeded,
+	}
+	if err := safeRun(ctx, j.fn); err != nil {
+		run.Status = mod.JobRunFailed
+		run.Error = err.Error()
+	}
+	run.FinishedAt = s.now()
+	run.DurationMs = run.FinishedAt.Sub(run.StartedAt).Milliseconds()
+
+	if err := s.rp.SaveRun(&run); err != nil {
+		log.Printf("scheduler: %s run not recorded: %v", j.name, err)
+	}
+	if err := s.rp.ReleaseLease(j.name, s.owner, s.now()); err != nil {
+		log.Printf("scheduler: %s lease not released: %v", j.name, err)
+	}
+	s.mu.Lock()
+	j.running = false
+	s.mu.Unlock()
+}
+
+// safeRun runs fn turning a panic into an error so a broken job does not stop the scheduler
+func safeRun(ctx context.Context, fn Func) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return fn(ctx)
+}
+
+// job returns the registered job with the name
+func (s *Scheduler) job(name string) (*job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[name]
+	if !ok {
+		return nil, e.ErrJobNotFound
+	}
+	return j, nil
+}
+
+// FindAll returns every registered job sorted by name, with its pause state and latest run
+func (s *Scheduler) FindAll() ([]mod.Job, error) {
+	paused, err := s.rp.FindPaused()
+	if err != nil {
+		return nil, err
+	}
+	last, err := s.rp.FindLastRuns()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]mod.Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		item := mod.Job{
+			Name:     j.name,
+			Schedule: j.spec,
+			Paused:   paused[j.name],
+			Running:  j.running,
+			NextRun:  j.next,
+		}
+		if item.NextRun.IsZero() {
+			item.NextRun = j.schedule.Next(s.now())
+		}
+		if run, ok := last[j.name]; ok {
+			item.LastRun = &run
+		}
+		jobs = append(jobs, item)
+	}
+	sort.Slice(jobs, func(a, b int) bool { return jobs[a].Name < jobs[b].Name })
+	return jobs, nil
+}
+
+// FindRuns returns the latest runs of the job, newest first
+func (s *Scheduler) FindRuns(name string, limit int) ([]mod.JobRun, error) {
+	if _, err := s.job(name); err != nil {
+		return nil, err
+	}
+	return s.rp.FindRuns(name, limit)
+}
+
+// Trigger takes the lease of the job and runs it in the background, paused jobs can still be triggered
+func (s *Scheduler) Trigger(name string) error {
+	j, err := s.job(name)
+	if err != nil {
+		return err
+	}
+	if s.ctx.Err() != nil {
+		return e.ErrJobSchedulerStopped
+	}
+	ok, err := s.claim(j, time.Time{})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return e.ErrJobRunning
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(j, mod.JobTriggerManual)
+	}()
+	return nil
+}
+
+// Pause skips the scheduled runs of the job in every instance
+func (s *Scheduler) Pause(name string) error {
+	if _, err := s.job(name); err != nil {
+		return err
+	}
+	return s.rp.SetPaused(name, true)
+}
+
+// Resume restores the scheduled runs of the job in every instance
+func (s *Scheduler) Resume(name string) error {
+	if _, err := s.job(name); err != nil {
+		return err
+	}
+	return s.rp.SetPaused(name, false)
+}
@@ -0,0 +1,231 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryJobRepo keeps the leases and runs in memory, like several instances sharing a database
+type memoryJobRepo struct {
+	mu        sync.Mutex
+	owners    map[string]string
+	until     map[string]time.Time
+	activated map[string]time.Time
+	paused    map[string]bool
+	runs      []mod.JobRun
+}
+
+func newMemoryJobRepo() *memoryJobRepo {
+	return &memoryJobRepo{owners: map[string]string{}, until: map[string]time.Time{}, activated: map[string]time.Time{}, paused: map[string]bool{}}
+}
+
+func (r *memoryJobRepo) AcquireLease(job, owner string, activation, now, until time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.owners[job] != owner && now.Before(r.until[job]) {
+		return false, nil
+	}
+	if !activation.IsZero() {
+		if !r.activated[job].Before(activation) {
+			return false, nil
+		}
+		r.activated[job] = activation
+	}
+	r.owners[job], r.until[job] = owner, until
+	return true, nil
+}
+
+func (r *memoryJobRepo) ReleaseLease(job, owner string, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.owners[job] == owner {
+		r.until[job] = now
+	}
+	return nil
+}
+
+func (r *memoryJobRepo) SetPaused(job string, paused bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.paused[job] = paused
+	return nil
+}
+
+func (r *memoryJobRepo) FindPaused() (map[string]bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	paused := map[string]bool{}
+	for job, p := range r.paused {
+		paused[job] = p
+	}
+	return paused, nil
+}
+
+func (r *memoryJobRepo) SaveRun(run *mod.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run.ID = len(r.runs) + 1
+	r.runs = append(r.runs, *run)
+	return nil
+}
+
+func (r *memoryJobRepo) FindRuns(job string, limit int) ([]mod.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var runs []mod.JobRun
+	for i := len(r.runs) - 1; i >= 0 && len(runs) < limit; i-- {
+		if r.runs[i].Job == job {
+			runs = append(runs, r.runs[i])
+		}
+	}
+	return runs, nil
+}
+
+func (r *memoryJobRepo) FindLastRuns() (map[string]mod.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := map[string]mod.JobRun{}
+	for _, run := range r.runs {
+		last[run.Job] = run
+	}
+	return last, nil
+}
+
+func (r *memoryJobRepo) DeleteRunsBefore(t time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestScheduler_Trigger(t *testing.T) {
+	t.Run("records the run with its error", func(t *testing.T) {
+		rp := newMemoryJobRepo()
+		s := New(rp, "a")
+		require.NoError(t, s.Register("failing", "@daily", func(ctx context.Context) error { return errors.New("boom") }))
+
+		require.NoError(t, s.Trigger("failing"))
+		require.NoError(t, s.Stop(context.Background()))
+
+		runs, err := s.FindRuns("failing", 10)
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+		require.Equal(t, mod.JobRunFailed, runs[0].Status)
+		require.Equal(t, "boom", runs[0].Error)
+		require.Equal(t, mod.JobTriggerManual, runs[0].Trigger)
+	})
+
+	t.Run("a panic fails the run", func(t *testing.T) {
+		rp := newMemoryJobRepo()
+		s := New(rp, "a")
+		require.NoError(t, s.Register("panics", "@daily", func(ctx context.Context) error { panic("bad job") }))
+
+		require.NoError(t, s.Trigger("panics"))
+		require.NoError(t, s.Stop(context.Background()))
+		require.Equal(t, "panic: bad job", rp.runs[0].Error)
+	})
+
+	t.Run("only one instance runs the job", func(t *testing.T) {
+		rp := newMemoryJobRepo()
+		release := make(chan struct{})
+		fn := func(ctx context.Context) error {
+			<-release
+			return nil
+		}
+		a, b := New(rp, "a"), New(rp, "b")
+		require.NoError(t, a.Register("job", "@daily", fn))
+		require.NoError(t, b.Register("job", "@daily", fn))
+
+		require.NoError(t, a.Trigger("job"))
+		require.ErrorIs(t, a.Trigger("job"), e.ErrJobRunning)
+		require.ErrorIs(t, b.Trigger("job"), e.ErrJobRunning)
+		close(release)
+		require.NoError(t, a.Stop(context.Background()))
+
+		require.NoError(t, b.Trigger("job"))
+		require.NoError(t, b.Stop(context.Background()))
+		require.Len(t, rp.runs, 2)
+		require.Equal(t, "b", rp.runs[1].Owner)
+	})
+
+	t.Run("unknown and stopped", func(t *testing.T) {
+		s := New(newMemoryJobRepo(), "a")
+		require.NoError(t, s.Register("job", "@daily", func(ctx context.Context) error { return nil }))
+		require.ErrorIs(t, s.Trigger("other"), e.ErrJobNotFound)
+		require.NoError(t, s.Stop(context.Background()))
+		require.ErrorIs(t, s.Trigger("job"), e.ErrJobSchedulerStopped)
+	})
+}
+
+func TestScheduler_Claim(t *testing.T) {
+	rp := newMemoryJobRepo()
+	fn := func(ctx context.Context) error { return nil }
+	a, b := New(rp, "a"), New(rp, "b")
+	require.NoError(t, a.Register("job", "@hourly", fn))
+	require.NoError(t, b.Register("job", "@hourly", fn))
+	activation := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	ja, err := a.job("job")
+	require.NoError(t, err)
+	ok, err := a.claim(ja, activation)
+	require.NoError(t, err)
+	require.True(t, ok)
+	a.execute(ja, mod.JobTriggerSchedule)
+
+	// the lease was released but the activation already ran
+	jb, err := b.job("job")
+	require.NoError(t, err)
+	ok, err = b.claim(jb, activation)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = b.claim(jb, activation.Add(time.Hour))
+	require.NoError(t, err)
+	require.True(t, ok)
+	b.execute(jb, mod.JobTriggerSchedule)
+	require.Len(t, rp.runs, 2)
+}
+
+func TestScheduler_Schedule(t *testing.T) {
+	rp := newMemoryJobRepo()
+	s := New(rp, "a")
+	ran := make(chan struct{}, 10)
+	require.NoError(t, s.Register("tick", "@every 1s", func(ctx context.Context) error {
+		ran <- struct{}{}
+		return nil
+	}))
+	require.NoError(t, s.Register("paused", "@every 1s", func(ctx context.Context) error {
+		t.Error("paused job ran")
+		return nil
+	}))
+	require.NoError(t, s.Pause("paused"))
+	s.Start()
+
+	select {
+	case <-ran:
+	case <-time.After(3 * time.Second):
+		t.Fatal("the job did not run on schedule")
+	}
+	require.NoError(t, s.Stop(context.Background()))
+
+	jobs, err := s.FindAll()
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	require.Equal(t, "paused", jobs[0].Name)
+	require.True(t, jobs[0].Paused)
+	require.Nil(t, jobs[0].LastRun)
+	require.Equal(t, "tick", jobs[1].Name)
+	require.Equal(t, mod.JobTriggerSchedule, jobs[1].LastRun.Trigger)
+}
+
+func TestScheduler_Register(t *testing.T) {
+	s := New(newMemoryJobRepo(), "a")
+	fn := func(ctx context.Context) error { return nil }
+	require.ErrorIs(t, s.Register("job", "not a schedule", fn), e.ErrJobScheduleInvalid)
+	require.NoError(t, s.Register("job", "@hourly", fn))
+	require.ErrorIs(t, s.Register("job", "@hourly", fn), e.ErrJobDuplicated)
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// NewBatchExpiryService creates a new instance of the batch expiry service, leadTime is the warning lead time of
+// a product with an expiration rate of 1
+func NewBatchExpiryService(rp internal.BatchExpiryRepository, leadTime time.Duration) *BatchExpiryService {
+	return &BatchExpiryService{rp: rp, leadTime: leadTime, now: time.Now}
+}
+
+// BatchExpiryService is the default implementation of the batch expiry service
+type BatchExpiryService struct {
+	rp       internal.BatchExpiryRepository
+	leadTime time.Duration
+	now      func() time.Time
+}
+
+// FindExpiring returns the batches due in the next days, or within the lead time of their product when days is 0,
+// optionally of a single warehouse
+func (s *BatchExpiryService) FindExpiring(days, warehouseID int) ([]mod.ExpiringBatch, error) {
+	at := s.now().UTC()
+	batches, err := s.rp.FindExpiring(mod.ExpiringQuery{Days: days, WarehouseID: warehouseID, At: at, LeadTime: s.leadTime})
+	if err != nil {
+		return nil, err
+	}
+	for i := range batches {
+		batches[i].HoursLeft = math.Round(batches[i].DueDate.Sub(at).Hours()*100) / 100
+	}
+	return batches, nil
+}
+
+// Sweep expires the due batches and warns about the ones entering their lead time
+func (s *BatchExpiryService) Sweep() ([]mod.Alert, error) {
+	return s.rp.Sweep(s.now().UTC().Truncate(time.Millisecond), s.leadTime)
+}
+
+// FindAlerts returns the alerts of the filter
+func (s *BatchExpiryService) FindAlerts(f mod.AlertFilter) ([]mod.Alert, error) {
+	return s.rp.FindAlerts(f)
+}
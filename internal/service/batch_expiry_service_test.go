@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// mockBatchExpiryRepo answers the batch expiry reads and sweeps
+type mockBatchExpiryRepo struct {
+	mock.Mock
+}
+
+func (m *mockBatchExpiryRepo) FindExpiring(q mod.ExpiringQuery) ([]mod.ExpiringBatch, error) {
+	args := m.Called(q)
+	return args.Get(0).([]mod.ExpiringBatch), args.Error(1)
+}
+
+func (m *mockBatchExpiryRepo) Sweep(at time.Time, leadTime time.Duration) ([]mod.Alert, error) {
+	args := m.Called(at, leadTime)
+	return args.Get(0).([]mod.Alert), args.Error(1)
+}
+
+func (m *mockBatchExpiryRepo) FindAlerts(f mod.AlertFilter) ([]mod.Alert, error) {
+	args := m.Called(f)
+	return args.Get(0).([]mod.Alert), args.Error(1)
+}
+
+func TestBatchExpiryService(t *testing.T) {
+	now := time.Date(2024, 7, 1, 10, 0, 0, 123456789, time.UTC)
+	lead := 72 * time.Hour
+
+	t.Run("expiring batches get the hours left", func(t *testing.T) {
+		rp := new(mockBatchExpiryRepo)
+		rp.On("FindExpiring", mod.ExpiringQuery{WarehouseID: 2, At: now, LeadTime: lead}).
+			Return([]mod.ExpiringBatch{{ProductBatchID: 1, DueDate: now.Add(90 * time.Minute)}}, nil)
+		sv := NewBatchExpiryService(rp, lead)
+		sv.now = func() time.Time { return now }
+
+		batches, err := sv.FindExpiring(0, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1.5, batches[0].HoursLeft)
+		rp.AssertExpectations(t)
+	})
+
+	t.Run("sweep at the current millisecond with the lead time", func(t *testing.T) {
+		rp := new(mockBatchExpiryRepo)
+		rp.On("Sweep", now.Truncate(time.Millisecond), lead).Return([]mod.Alert{{ID: 1}}, nil)
+		sv := NewBatchExpiryService(rp, lead)
+		sv.now = func() time.Time { return now }
+
+		alerts, err := sv.Sweep()
+
+		assert.NoError(t, err)
+		assert.Len(t, alerts, 1)
+		rp.AssertExpectations(t)
+	})
+}
@@ -25,9 +25,12 @@ func (s *EmployeeService) FindAll() (employees []mod.Employee, err error) {
 }
 
 // FindByID returns a employee
-func (s *EmployeeService) FindByID(id int) (employee mod.Employee, err error) {
-	employee, err = s.rp.FindByID(id)
-	return
+func (s *EmployeeService) FindByID(id int) (employee *mod.Employee, err error) {
+	found, err := s.rp.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return &found, nil
 }
 
 // Save creates a new employee
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// maxIncludeDepth is the deepest include path accepted, e.g. seller.locality has depth 2
+const maxIncludeDepth = 3
+
+// relation describes how a related resource is embedded into its parent
+type relation struct {
+	// key is the response key the related resource is written to
+	key string
+	// parentField is the JSON field of the parent holding the ID used to load the related resource
+	parentField string
+	// childField is the JSON field of the related resource that matches parentField
+	childField string
+	// many embeds a list instead of a single object
+	many bool
+	// resource is the name of the related resource, used to resolve nested includes
+	resource string
+	// load returns the related resources of the given IDs in a single query
+	load func(ids []int) ([]map[string]any, error)
+}
+
+// NewIncludeService creates a new instance of the include service
+func NewIncludeService(include internal.IncludeRepository) *IncludeService {
+	s := &IncludeService{rp: include}
+	s.relations = map[string]map[string]relation{
+		"products": {
+			"seller":  {key: "seller", parentField: "seller_id", childField: "id", resource: "sellers", load: toMaps(s.rp.SellersByIDs)},
+			"records": {key: "records", parentField: "id", childField: "product_id", many: true, resource: "records", load: toMaps(s.rp.RecordsByProductIDs)},
+		},
+		"sellers": {
+			"locality": {key: "locality", parentField: "locality_id", childField: "id", resource: "localities", load: toMaps(s.rp.LocalitiesByIDs)},
+			"products": {key: "products", parentField: "id", childField: "seller_id", many: true, resource: "products", load: toMaps(s.rp.ProductsBySellerIDs)},
+		},
+		"sections": {
+			"warehouse": {key: "warehouse", parentField: "warehouse_id", childField: "ID", resource: "warehouses", load: toMaps(s.rp.WarehousesByIDs)},
+			"batches":   {key: "batches", parentField: "id", childField: "section_id", many: true, resource: "batches", load: toMaps(s.rp.BatchesBySectionIDs)},
+		},
+		"batches": {
+			"product": {key: "product", parentField: "product_id", childField: "id", resource: "products", load: toMaps(s.rp.ProductsByIDs)},
+			"section": {key: "section", parentField: "section_id", childField: "id", resource: "sections", load: toMaps(s.rp.SectionsByIDs)},
+		},
+		"employees": {
+			"warehouse": {key: "warehouse", parentField: "warehouse_id", childField: "ID", resource: "warehouses", load: toMaps(s.rp.WarehousesByIDs)},
+		},
+		"purchaseOrders": {
+			"buyer":   {key: "buyer", parentField: "buyer_id", childField: "id", resource: "buyers", load: toMaps(s.rp.BuyersByIDs)},
+			"details": {key: "products_details", parentField: "id", childField: "purchase_order_id", many: true, resource: "details", load: toMaps(s.rp.DetailsByPurchaseOrderIDs)},
+		},
+	}
+	return s
+}
+
+// IncludeService is the default implementation of the include service
+type IncludeService struct {
+	// rp is the repository used by the service
+	rp internal.IncludeRepository
+	// relations holds the relations of every resource by include name
+	relations map[string]map[string]relation
+}
+
+// toMaps adapts a repository loader to return the JSON representation of the models
+func toMaps[T any](load func(ids []int) ([]T, error)) func(ids []int) ([]map[string]any, error) {
+	return func(ids []int) ([]map[string]any, error) {
+		items, err := load(ids)
+		if err != nil {
+			return nil, err
+		}
+		return common.ToMaps(items)
+	}
+}
+
+// Validate checks that every include path is supported by the resource
+func (s *IncludeService) Validate(resource string, includes []string) error {
+	for _, path := range includes {
+		parts := strings.Split(path, ".")
+		if len(parts) > maxIncludeDepth {
+			return fmt.Errorf("%w: %s", e.ErrIncludeInvalid, path)
+		}
+		current := resource
+		for _, name := range parts {
+			rel, ok := s.relations[current][name]
+			if !ok {
+				return fmt.Errorf("%w: %s", e.ErrIncludeInvalid, path)
+			}
+			current = rel.resource
+		}
+	}
+	return nil
+}
+
+// Expand adds the included relations to the items, each relation is loaded once for all the items
+// and nested relations are loaded once for all the related items
+func (s *IncludeService) Expand(resource string, items []map[string]any, includes []string) error {
+	if err := s.Validate(resource, includes); err != nil {
+		return err
+	}
+	// group the paths by their first relation, e.g. seller and seller.locality -> seller: [locality]
+	nested := make(map[string][]string)
+	for _, path := range includes {
+		name, rest, _ := strings.Cut(path, ".")
+		if _, ok := nested[name]; !ok {
+			nested[name] = nil
+		}
+		if rest != "" {
+			nested[name] = append(nested[name], rest)
+		}
+	}
+	names := make([]string, 0, len(nested))
+	for name := range nested {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rel := s.relations[resource][name]
+		related, err := s.embed(rel, items)
+		if err != nil {
+			return err
+		}
+		if len(nested[name]) > 0 && len(related) > 0 {
+			if err = s.Expand(rel.resource, related, nested[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// embed loads the relation for all the items, writes it into each of them and returns the loaded items
+func (s *IncludeService) embed(rel relation, items []map[string]any) ([]map[string]any, error) {
+	seen := make(map[int]bool)
+	var ids []int
+	for _, item := range items {
+		if id, ok := common.MapInt(item, rel.parentField); ok && id > 0 && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	related, err := rel.load(ids)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[int][]map[string]any)
+	for _, child := range related {
+		if id, ok := common.MapInt(child, rel.childField); ok {
+			byKey[id] = append(byKey[id], child)
+		}
+	}
+
+	for _, item := range items {
+		id, _ := common.MapInt(item, rel.parentField)
+		children := byKey[id]
+		switch {
+		case rel.many && children == nil:
+			item[rel.key] = []map[string]any{}
+		case rel.many:
+			item[rel.key] = children
+		case len(children) > 0:
+			item[rel.key] = children[0]
+		default:
+			item[rel.key] = nil
+		}
+	}
+	return related, nil
+}
+
+// Keys returns the response keys written by the top level relations of the includes
+func (s *IncludeService) Keys(resource string, includes []string) (keys []string) {
+	for _, path := range includes {
+		name, _, _ := strings.Cut(path, ".")
+		if rel, ok := s.relations[resource][name]; ok {
+			keys = append(keys, rel.key)
+		}
+	}
+	return keys
+}
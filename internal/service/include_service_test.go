@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/internal/service"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// Mock del repositorio de includes, only the loaders used by the tests are mocked
+
+type MockIncludeRepo struct {
+	mock.Mock
+}
+
+func (m *MockIncludeRepo) SellersByIDs(ids []int) ([]mod.Seller, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]mod.Seller), args.Error(1)
+}
+
+func (m *MockIncludeRepo) LocalitiesByIDs(ids []int) ([]mod.Locality, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]mod.Locality), args.Error(1)
+}
+
+func (m *MockIncludeRepo) ProductsByIDs(ids []int) ([]mod.Product, error) { panic("not used") }
+
+func (m *MockIncludeRepo) ProductsBySellerIDs(ids []int) ([]mod.Product, error) { panic("not used") }
+
+func (m *MockIncludeRepo) RecordsByProductIDs(ids []int) ([]mod.ProductRecord, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]mod.ProductRecord), args.Error(1)
+}
+
+func (m *MockIncludeRepo) WarehousesByIDs(ids []int) ([]mod.Warehouse, error) { panic("not used") }
+
+func (m *MockIncludeRepo) SectionsByIDs(ids []int) ([]mod.Section, error) { panic("not used") }
+
+func (m *MockIncludeRepo) BatchesBySectionIDs(ids []int) ([]mod.ProductBatch, error) {
+	panic("not used")
+}
+
+func (m *MockIncludeRepo) BuyersByIDs(ids []int) ([]mod.Buyer, error) { panic("not used") }
+
+func (m *MockIncludeRepo) DetailsByPurchaseOrderIDs(ids []int) ([]mod.OrderDetails, error) {
+	panic("not used")
+}
+
+func TestIncludeService_Validate(t *testing.T) {
+	svc := service.NewIncludeService(new(MockIncludeRepo))
+
+	require.NoError(t, svc.Validate("products", []string{"seller", "seller.locality", "records"}))
+	require.NoError(t, svc.Validate("purchaseOrders", []string{"buyer", "details"}))
+	require.ErrorIs(t, svc.Validate("products", []string{"seller.warehouse"}), e.ErrIncludeInvalid)
+	require.ErrorIs(t, svc.Validate("employees", []string{"seller"}), e.ErrIncludeInvalid)
+	require.ErrorIs(t, svc.Validate("sellers", []string{"products.seller.products.seller"}), e.ErrIncludeInvalid)
+	require.Equal(t, []string{"products_details"}, svc.Keys("purchaseOrders", []string{"details"}))
+}
+
+func TestIncludeService_Expand(t *testing.T) {
+	repo := new(MockIncludeRepo)
+	svc := service.NewIncludeService(repo)
+	// the sellers and their localities are loaded with one query each, no matter how many products there are
+	repo.On("SellersByIDs", []int{3, 5}).Return([]mod.Seller{{ID: 3, CompanyName: "Acme", Locality: 1}, {ID: 5, CompanyName: "Globex", Locality: 1}}, nil).Once()
+	repo.On("LocalitiesByIDs", []int{1}).Return([]mod.Locality{{ID: 1, Name: "Brooklyn"}}, nil).Once()
+	repo.On("RecordsByProductIDs", []int{1, 2, 3}).Return([]mod.ProductRecord{{ID: 10, ProductID: 1}, {ID: 11, ProductID: 1}}, nil).Once()
+
+	items := []map[string]any{
+		{"id": json.Number("1"), "seller_id": json.Number("5")},
+		{"id": json.Number("2"), "seller_id": json.Number("3")},
+		{"id": json.Number("3"), "seller_id": json.Number("5")},
+	}
+	err := svc.Expand("products", items, []string{"seller.locality", "records"})
+	require.NoError(t, err)
+
+	out, err := json.Marshal(items)
+	require.NoError(t, err)
+	seller5 := `{"id":5,"cid":0,"company_name":"Globex","address":"","telephone":"","locality_id":1,"locality":{"id":1,"locality_name":"Brooklyn","province_name":"","country_name":""}}`
+	seller3 := `{"id":3,"cid":0,"company_name":"Acme","address":"","telephone":"","locality_id":1,"locality":{"id":1,"locality_name":"Brooklyn","province_name":"","country_name":""}}`
+	require.JSONEq(t, `[
+		{"id":1,"seller_id":5,"seller":`+seller5+`,"records":[
+			{"id":10,"last_update_date":"","purchase_price":0,"sale_price":0,"product_id":1},
+			{"id":11,"last_update_date":"","purchase_price":0,"sale_price":0,"product_id":1}]},
+		{"id":2,"seller_id":3,"seller":`+seller3+`,"records":[]},
+		{"id":3,"seller_id":5,"seller":`+seller5+`,"records":[]}
+	]`, string(out))
+	repo.AssertExpectations(t)
+}
@@ -27,6 +27,16 @@ func (s *LocalityService) FindSellersByLocID(id int) (result []mod.SelByLoc, err
 	return s.rp.FindSellersByLocID(id)
 }
 
+// FindWarehousesByLocID returns the warehouses count by locality
+func (s *LocalityService) FindWarehousesByLocID(id int) (result []mod.WhByLoc, err error) {
+	return s.rp.FindWarehousesByLocID(id)
+}
+
+// FindOverviewByLocID returns the sellers, carries and warehouses count by locality
+func (s *LocalityService) FindOverviewByLocID(id int) (result []mod.LocalityOverview, err error) {
+	return s.rp.FindOverviewByLocID(id)
+}
+
 // Save creates a new locality
 func (s *LocalityService) Save(locality *mod.Locality) (id int, err error) {
 	return s.rp.Save(locality)
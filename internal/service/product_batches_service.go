@@ -1,22 +1,90 @@
 package service
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
 	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
 )
 
 type ProductBatchService struct {
-	rp internal.ProductBatchRepository
+	rp  internal.ProductBatchRepository
+	now func() time.Time
 }
 
 func NewProductBatchRepository(batchesRepo internal.ProductBatchRepository) *ProductBatchService {
-	return &ProductBatchService{rp: batchesRepo}
+	return &ProductBatchService{rp: batchesRepo, now: time.Now}
+}
+
+func (s *ProductBatchService) FindAll(f mod.ProductBatchFilter) (batches []mod.ProductBatch, err error) {
+	return s.rp.FindAll(f)
 }
 
-func (s *ProductBatchService) FindAll() (batches []mod.ProductBatch, err error) {
-	return s.rp.FindAll()
+func (s *ProductBatchService) FindByID(id int) (mod.ProductBatch, error) {
+	return s.rp.FindByID(id)
 }
 
 func (s *ProductBatchService) Save(batch *mod.ProductBatch) error {
 	return s.rp.Save(batch)
 }
+
+// Update checks the patch against its own rules, the quantity cannot go below 0 and the temperature cannot go
+// below the minimum temperature of the batch, and applies it
+func (s *ProductBatchService) Update(id int, patch mod.ProductBatchPatch) (mod.ProductBatch, error) {
+	batch, err := s.rp.FindByID(id)
+	if err != nil {
+		return mod.ProductBatch{}, err
+	}
+	if batch.ArchivedAt != nil {
+		return mod.ProductBatch{}, e.ErrProductBatchArchived
+	}
+	msgs := []string{}
+	for _, msg := range e.ValidateStruct(patch) {
+		msgs = append(msgs, msg)
+	}
+	if patch.CurrentTemperature != nil && *patch.CurrentTemperature < batch.MinimumTemperature {
+		msgs = append(msgs, "CurrentTemperature must be greater than or equal to MinimumTemperature")
+	}
+	if len(msgs) > 0 {
+		sort.Strings(msgs)
+		return mod.ProductBatch{}, fmt.Errorf("%w: %s", e.ErrProductBatchInvalid, strings.Join(msgs, ", "))
+	}
+	return s.rp.Update(id, patch)
+}
+
+// Archive writes off the units of the batch and archives it now
+func (s *ProductBatchService) Archive(id int) (mod.ProductBatch, error) {
+	return s.rp.Archive(id, s.now().UTC().Truncate(time.Millisecond))
+}
+
+func (s *ProductBatchService) Delete(id int) error {
+	return s.rp.Delete(id)
+}
+
+// Hold quarantines an available or released batch, a batch on hold cannot be allocated, transferred or reported
+// as stock until it is released
+func (s *ProductBatchService) Hold(id int, req mod.BatchHoldRequest) (mod.ProductBatch, error) {
+	change := mod.BatchStatusChange{ProductBatchID: id, ToStatus: mod.BatchQuarantined, Reason: req.Reason, EmployeeID: req.EmployeeID,
+		CreatedAt: s.now().UTC().Truncate(time.Millisecond)}
+	return s.rp.ChangeStatus(&change, mod.BatchAvailable, mod.BatchReleased)
+}
+
+// Release ends the quarantine of a batch, it is released by default or rejected when the request says so. A
+// rejected batch stays on hold until it is archived
+func (s *ProductBatchService) Release(id int, req mod.BatchHoldRequest) (mod.ProductBatch, error) {
+	status := req.Status
+	if status == "" {
+		status = mod.BatchReleased
+	}
+	change := mod.BatchStatusChange{ProductBatchID: id, ToStatus: status, Reason: req.Reason, EmployeeID: req.EmployeeID,
+		CreatedAt: s.now().UTC().Truncate(time.Millisecond)}
+	return s.rp.ChangeStatus(&change, mod.BatchQuarantined)
+}
+
+func (s *ProductBatchService) FindStatusHistory(id int) ([]mod.BatchStatusChange, error) {
+	return s.rp.FindStatusHistory(id)
+}
@@ -0,0 +1,159 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/internal/service"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// MockProductBatchRepo only answers the reads and updates of a batch
+type MockProductBatchRepo struct {
+	internal.ProductBatchRepository
+	mock.Mock
+}
+
+func (m *MockProductBatchRepo) FindByID(id int) (mod.ProductBatch, error) {
+	args := m.Called(id)
+	return args.Get(0).(mod.ProductBatch), args.Error(1)
+}
+
+func (m *MockProductBatchRepo) Update(id int, patch mod.ProductBatchPatch) (mod.ProductBatch, error) {
+	args := m.Called(id, patch)
+	return args.Get(0).(mod.ProductBatch), args.Error(1)
+}
+
+func (m *MockProductBatchRepo) ChangeStatus(change *mod.BatchStatusChange, from ...string) (mod.ProductBatch, error) {
+	args := m.Called(change, from)
+	return args.Get(0).(mod.ProductBatch), args.Error(1)
+}
+
+func TestProductBatchService_Update(t *testing.T) {
+	stored := mod.ProductBatch{ID: 1, CurrentQuantity: 20, InitialQuantity: 10, CurrentTemperature: 2, MinimumTemperature: -5}
+	intPtr := func(n int) *int { return &n }
+
+	t.Run("applies a valid patch", func(t *testing.T) {
+		patch := mod.ProductBatchPatch{CurrentQuantity: intPtr(15)}
+		mockRepo := new(MockProductBatchRepo)
+		mockRepo.On("FindByID", 1).Return(stored, nil)
+		mockRepo.On("Update", 1, patch).Return(mod.ProductBatch{ID: 1, CurrentQuantity: 15}, nil)
+
+		batch, err := service.NewProductBatchRepository(mockRepo).Update(1, patch)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 15, batch.CurrentQuantity)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("lowers the quantity below the initial quantity", func(t *testing.T) {
+		for _, quantity := range []int{5, 0} {
+			patch := mod.ProductBatchPatch{CurrentQuantity: intPtr(quantity)}
+			mockRepo := new(MockProductBatchRepo)
+			mockRepo.On("FindByID", 1).Return(stored, nil)
+			mockRepo.On("Update", 1, patch).Return(mod.ProductBatch{ID: 1, CurrentQuantity: quantity}, nil)
+
+			batch, err := service.NewProductBatchRepository(mockRepo).Update(1, patch)
+
+			assert.NoError(t, err)
+			assert.Equal(t, quantity, batch.CurrentQuantity)
+			mockRepo.AssertExpectations(t)
+		}
+	})
+
+	t.Run("sets the temperature to 0", func(t *testing.T) {
+		patch := mod.ProductBatchPatch{CurrentTemperature: intPtr(0)}
+		mockRepo := new(MockProductBatchRepo)
+		mockRepo.On("FindByID", 1).Return(stored, nil)
+		mockRepo.On("Update", 1, patch).Return(mod.ProductBatch{ID: 1, CurrentTemperature: 0}, nil)
+
+		_, err := service.NewProductBatchRepository(mockRepo).Update(1, patch)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a negative quantity", func(t *testing.T) {
+		mockRepo := new(MockProductBatchRepo)
+		mockRepo.On("FindByID", 1).Return(stored, nil)
+
+		_, err := service.NewProductBatchRepository(mockRepo).Update(1, mod.ProductBatchPatch{CurrentQuantity: intPtr(-1)})
+
+		assert.ErrorIs(t, err, e.ErrProductBatchInvalid)
+		assert.ErrorContains(t, err, "CurrentQuantity must be greater than or equal to 0")
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a temperature below the minimum", func(t *testing.T) {
+		mockRepo := new(MockProductBatchRepo)
+		mockRepo.On("FindByID", 1).Return(stored, nil)
+
+		_, err := service.NewProductBatchRepository(mockRepo).Update(1, mod.ProductBatchPatch{CurrentTemperature: intPtr(-6)})
+
+		assert.ErrorIs(t, err, e.ErrProductBatchInvalid)
+		assert.ErrorContains(t, err, "CurrentTemperature must be greater than or equal to MinimumTemperature")
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects an archived batch", func(t *testing.T) {
+		archivedAt := time.Date(2024, 7, 6, 0, 0, 0, 0, time.UTC)
+		archived := stored
+		archived.ArchivedAt = &archivedAt
+		mockRepo := new(MockProductBatchRepo)
+		mockRepo.On("FindByID", 1).Return(archived, nil)
+
+		_, err := service.NewProductBatchRepository(mockRepo).Update(1, mod.ProductBatchPatch{CurrentQuantity: intPtr(15)})
+
+		assert.ErrorIs(t, err, e.ErrProductBatchArchived)
+	})
+}
+
+func TestProductBatchService_HoldRelease(t *testing.T) {
+	req := mod.BatchHoldRequest{Reason: "arrived at 9°C", EmployeeID: 4}
+	changeTo := func(status string) any {
+		return mock.MatchedBy(func(c *mod.BatchStatusChange) bool {
+			return c.ProductBatchID == 1 && c.ToStatus == status && c.Reason == req.Reason && c.EmployeeID == 4 && !c.CreatedAt.IsZero()
+		})
+	}
+
+	t.Run("hold quarantines an available or released batch", func(t *testing.T) {
+		mockRepo := new(MockProductBatchRepo)
+		mockRepo.On("ChangeStatus", changeTo(mod.BatchQuarantined), []string{mod.BatchAvailable, mod.BatchReleased}).
+			Return(mod.ProductBatch{ID: 1, Status: mod.BatchQuarantined}, nil)
+
+		batch, err := service.NewProductBatchRepository(mockRepo).Hold(1, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mod.BatchQuarantined, batch.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("release defaults to released", func(t *testing.T) {
+		mockRepo := new(MockProductBatchRepo)
+		mockRepo.On("ChangeStatus", changeTo(mod.BatchReleased), []string{mod.BatchQuarantined}).
+			Return(mod.ProductBatch{ID: 1, Status: mod.BatchReleased}, nil)
+
+		_, err := service.NewProductBatchRepository(mockRepo).Release(1, req)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("release can reject the batch", func(t *testing.T) {
+		rejected := req
+		rejected.Status = mod.BatchRejected
+		mockRepo := new(MockProductBatchRepo)
+		mockRepo.On("ChangeStatus", changeTo(mod.BatchRejected), []string{mod.BatchQuarantined}).
+			Return(mod.ProductBatch{}, e.ErrProductBatchStatus)
+
+		_, err := service.NewProductBatchRepository(mockRepo).Release(1, rejected)
+
+		assert.ErrorIs(t, err, e.ErrProductBatchStatus)
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewProductTypeService creates a new instance of the product type service
+func NewProductTypeService(rp internal.ProductTypeRepository) *ProductTypeService {
+	return &ProductTypeService{rp: rp}
+}
+
+// ProductTypeService is the default implementation of the product type service
+type ProductTypeService struct {
+	// rp is the repository used by the service
+	rp internal.ProductTypeRepository
+}
+
+// FindAll returns all the product types
+func (s *ProductTypeService) FindAll() ([]mod.ProductType, error) {
+	return s.rp.FindAll()
+}
+
+// FindByID returns a product type
+func (s *ProductTypeService) FindByID(id int) (mod.ProductType, error) {
+	return s.rp.FindByID(id)
+}
+
+// Save creates a product type whose range is not inverted
+func (s *ProductTypeService) Save(pt *mod.ProductType) error {
+	if *pt.MinimumTemperature > *pt.MaximumTemperature {
+		return e.ErrProductTypeRangeInvalid
+	}
+	return s.rp.Save(pt)
+}
+
+// Update applies the patch to the product type, the resulting range must not be inverted
+func (s *ProductTypeService) Update(id int, patch mod.ProductTypePatch) (mod.ProductType, error) {
+	current, err := s.rp.FindByID(id)
+	if err != nil {
+		return mod.ProductType{}, err
+	}
+	pt := common.PatchProductType(current, patch)
+	if *pt.MinimumTemperature > *pt.MaximumTemperature {
+		return mod.ProductType{}, e.ErrProductTypeRangeInvalid
+	}
+	if err = s.rp.Update(&pt); err != nil {
+		return mod.ProductType{}, err
+	}
+	return pt, nil
+}
+
+// Delete deletes a product type
+func (s *ProductTypeService) Delete(id int) error {
+	return s.rp.Delete(id)
+}
@@ -22,3 +22,13 @@ type PurchaseOrderService struct {
 func (s *PurchaseOrderService) Save(purchaseOrder *mod.PurchaseOrder) error {
 	return s.rp.Save(purchaseOrder)
 }
+
+// FindAll returns all purchase orders
+func (s *PurchaseOrderService) FindAll() ([]mod.PurchaseOrder, error) {
+	return s.rp.FindAll()
+}
+
+// FindByID returns a purchase order
+func (s *PurchaseOrderService) FindByID(id int) (mod.PurchaseOrder, error) {
+	return s.rp.FindByID(id)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+)
+
+// NewReportGenerators returns the asynchronous version of the synchronous report endpoints, by report type
+func NewReportGenerators(
+	buyers internal.BuyerService,
+	carries internal.CarryService,
+	localities internal.LocalityService,
+	warehouses internal.WarehouseService,
+	sections internal.SectionService,
+	inbounds internal.InboundService,
+	records internal.ProductRecordService,
+) map[string]internal.ReportGenerator {
+	return map[string]internal.ReportGenerator{
+		// purchaseOrders is /v1/buyers/reportPurchaseOrders, id is the buyer
+		"purchaseOrders": func(ctx context.Context, params mod.ReportParams) ([]map[string]any, error) {
+			return reportRows(buyers.GetPurchaseOrderReport(params.ID))
+		},
+		// carries is /v1/localities/reportCarries, id is the locality
+		"carries": func(ctx context.Context, params mod.ReportParams) ([]map[string]any, error) {
+			if params.ID == nil {
+				return reportRows(carries.ReportByLocalityAll())
+			}
+			return reportRows(carries.ReportByLocality(*params.ID))
+		},
+		// sellers is /v1/localities/reportSellers, id is the locality
+		"sellers": func(ctx context.Context, params mod.ReportParams) ([]map[string]any, error) {
+			id := -1
+			if params.ID != nil {
+				id = *params.ID
+			}
+			return reportRows(localities.FindSellersByLocID(id))
+		},
+		// warehouses is /v1/localities/reportWarehouses, id is the locality
+		"warehouses": func(ctx context.Context, params mod.ReportParams) ([]map[string]any, error) {
+			id := -1
+			if params.ID != nil {
+				id = *params.ID
+			}
+			return reportRows(localities.FindWarehousesByLocID(id))
+		},
+		// localities is /v1/localities/overview, id is the locality
+		"localities": func(ctx context.Context, params mod.ReportParams) ([]map[string]any, error) {
+			id := -1
+			if params.ID != nil {
+				id = *params.ID
+			}
+			return reportRows(localities.FindOverviewByLocID(id))
+		},
+		// capacity is /v1/warehouses/reportCapacity, id is the warehouse
+		"capacity": func(ctx context.Context, params mod.ReportParams) ([]map[string]any, error) {
+			return reportRows(warehouses.ReportCapacity(params.ID, params.Sort))
+		},
+		// products is /v1/sections/reportProducts, ids are the sections
+		"products": func(ctx context.Context, params mod.ReportParams) ([]map[string]any, error) {
+			return reportRows(sections.ReportProducts(mod.SectionReportFilter{
+				IDs:           params.IDs,
+				WarehouseID:   params.WarehouseID,
+				ProductTypeID: params.ProductTypeID,
+				Windows:       params.Windows,
+			}))
+		},
+		// inboundOrders is /v1/employees/reportInboundOrders, id is the employee
+		"inboundOrders": func(ctx context.Context, params mod.ReportParams) ([]map[string]any, error) {
+			id := 0
+			if params.ID != nil {
+				id = *params.ID
+			}
+			return reportRows(inbounds.FindOrdersByEmployee(id))
+		},
+		// records is /v1/products/reportRecords, id is the product
+		"records": func(ctx context.Context, params mod.ReportParams) ([]map[string]any, error) {
+			var found map[int]mod.ProductRecord
+			var err error
+			if params.ID == nil {
+				found, err = records.FindAllPR()
+			} else if _, err = records.FindProductByID(*params.ID); err == nil {
+				found, err = records.FindAllByProductIDPR(*params.ID)
+			}
+			if err != nil {
+				return nil, err
+			}
+			ids := make([]int, 0, len(found))
+			for id := range found {
+				ids = append(ids, id)
+			}
+			sort.Ints(ids)
+			list := make([]mod.ProductRecord, len(ids))
+			for i, id := range ids {
+				list[i] = found[id]
+			}
+			return common.ToMaps(list)
+		},
+	}
+}
+
+// reportRows converts the result of a report query to rows
+func reportRows[T any](items []T, err error) ([]map[string]any, error) {
+	if err != nil {
+		return nil, err
+	}
+	return common.ToMaps(items)
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// reportQueueSize is how many reports can wait for a worker
+const reportQueueSize = 100
+
+// Progress of a report through its stages
+const (
+	reportProgressStarted   = 10
+	reportProgressGenerated = 70
+	reportProgressDone      = 100
+)
+
+// NewReportService creates the report service, the jobs and files are kept in dir so the finished reports
+// survive a restart. Reports that were pending when the process stopped are marked as failed
+func NewReportService(dir string, workers int, generators map[string]internal.ReportGenerator) (*ReportService, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &ReportService{
+		dir:        dir,
+		workers:    workers,
+		generators: generators,
+		jobs:       make(map[string]*mod.ReportJob),
+		queue:      make(chan string, reportQueueSize),
+		now:        time.Now,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ReportService generates the reports in the background with a pool of workers
+type ReportService struct {
+	dir        string
+	workers    int
+	generators map[string]internal.ReportGenerator
+	now        func() time.Time
+
+	mu    sync.Mutex
+	jobs  map[string]*mod.ReportJob
+	queue chan string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// load reads the jobs stored in dir
+func (s *ReportService) load() error {
+	metas, err := filepath.Glob(filepath.Join(s.dir, "*.meta.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range metas {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var job mod.ReportJob
+		if err = json.Unmarshal(data, &job); err != nil {
+			log.Printf("reports: ignoring %s: %v", path, err)
+			continue
+		}
+		if job.Status == mod.ReportQueued || job.Status == mod.ReportRunning {
+			finished := s.now()
+			job.Status, job.Error, job.FinishedAt = mod.ReportFailed, "interrupted by a restart", &finished
+			if err = s.saveMeta(&job); err != nil {
+				return err
+			}
+		}
+		s.jobs[job.ID] = &job
+	}
+	return nil
+}
+
+// Start starts the workers
+func (s *ReportService) Start() {
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+}
+
+// Stop cancels the running reports and waits for the workers to return or for ctx to be done
+func (s *ReportService) Stop(ctx context.Context) error {
+	s.cancel()
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Enqueue queues the report of the given type, it fails when the type is unknown or the queue is full
+func (s *ReportService) Enqueue(reportType string, params mod.ReportParams) (mod.ReportJob, error) {
+	if _, ok := s.generators[reportType]; !ok {
+		return mod.ReportJob{}, e.ErrReportTypeInvalid
+	}
+	if params.Format == "" {
+		params.Format = mod.ReportJSON
+	}
+	id, err := newReportID()
+	if err != nil {
+		return mod.ReportJob{}, err
+	}
+	job := &mod.ReportJob{
+		ID:        id,
+		Type:      reportType,
+		Params:    params,
+		Status:    mod.ReportQueued,
+		CreatedAt: s.now(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err = s.saveMeta(job); err != nil {
+		return mod.ReportJob{}, err
+	}
+	select {
+	case s.queue <- id:
+	default:
+		os.Remove(s.metaPath(id))
+		return mod.ReportJob{}, e.ErrReportQueueFull
+	}
+	s.jobs[id] = job
+	return *job, nil
+}
+
+// FindByID returns the job with the given ID
+func (s *ReportService) FindByID(id string) (mod.ReportJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return mod.ReportJob{}, e.ErrReportNotFound
+	}
+	return *job, nil
+}
+
+// Open returns the file of a finished report
+func (s *ReportService) Open(id string) (mod.ReportJob, io.ReadSeekCloser, error) {
+	job, err := s.FindByID(id)
+	if err != nil {
+		return job, nil, err
+	}
+	if job.Status != mod.ReportDone {
+		return job, nil, e.ErrReportNotReady
+	}
+	f, err := os.Open(s.filePath(job))
+	if err != nil {
+		return job, nil, err
+	}
+	return job, f, nil
+}
+
+// Cleanup deletes the reports finished before the given time with their files
+func (s *ReportService) Cleanup(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := 0
+	for id, job := range s.jobs {
+		if job.FinishedAt == nil || !job.FinishedAt.Before(before) {
+			continue
+		}
+		for _, path := range []string{s.filePath(*job), s.metaPath(id)} {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return deleted, err
+			}
+		}
+		delete(s.jobs, id)
+		deleted++
+	}
+	return deleted, nil
+}
+
+// work generates the queued reports until the service stops
+func (s *ReportService) work() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case id := <-s.queue:
+			s.generate(id)
+		}
+	}
+}
+
+// generate runs the generator of the job and writes its file
+func (s *ReportService) generate(id string) {
+	job := s.update(id, func(job *mod.ReportJob) {
+		started := s.now()
+		job.Status, job.Progress, job.StartedAt = mod.ReportRunning, reportProgressStarted, &started
+	})
+
+	rows, err := s.generators[job.Type](s.ctx, job.Params)
+	if err == nil {
+		s.update(id, func(job *mod.ReportJob) { job.Progress = reportProgressGenerated })
+		err = s.writeFile(job, rows)
+	}
+
+	s.update(id, func(job *mod.ReportJob) {
+		finished := s.now()
+		job.FinishedAt = &finished
+		if err != nil {
+			job.Status, job.Error = mod.ReportFailed, err.Error()
+			return
+		}
+		job.Status, job.Progress, job.Rows = mod.ReportDone, reportProgressDone, len(rows)
+	})
+}
+
+// update changes the job and stores it, it returns the updated job
+func (s *ReportService) update(id string, change func(job *mod.ReportJob)) mod.ReportJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := s.jobs[id]
+	change(job)
+	if err := s.saveMeta(job); err != nil {
+		log.Printf("reports: %s not stored: %v", id, err)
+	}
+	return *job
+}
+
+// writeFile writes the rows in the format of the job, through a temporary file so a download never sees a
+// partial report
+func (s *ReportService) writeFile(job mod.ReportJob, rows []map[string]any) error {
+	var data []byte
+	var err error
+	if job.Params.Format == mod.ReportCSV {
+		data, err = common.ReportCSV(rows)
+	} else {
+		if rows == nil {
+			rows = []map[string]any{}
+		}
+		data, err = json.Marshal(rows)
+	}
+	if err != nil {
+		return err
+	}
+	return writeAtomic(s.filePath(job), data)
+}
+
+// saveMeta stores the job next to its file
+func (s *ReportService) saveMeta(job *mod.ReportJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(s.metaPath(job.ID), data)
+}
+
+// filePath is where the report of the job is written
+func (s *ReportService) filePath(job mod.ReportJob) string {
+	return filepath.Join(s.dir, job.ID+"."+job.Params.Format)
+}
+
+// metaPath is where the job is stored
+func (s *ReportService) metaPath(id string) string {
+	return filepath.Join(s.dir, id+".meta.json")
+}
+
+// writeAtomic writes data to a temporary file and renames it to path
+func writeAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// newReportID returns a random job ID
+func newReportID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("reports: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
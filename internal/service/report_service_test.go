@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/internal/service"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// waitReport polls the job until it finishes
+func waitReport(t *testing.T, sv *service.ReportService, id string) mod.ReportJob {
+	var job mod.ReportJob
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = sv.FindByID(id)
+		require.NoError(t, err)
+		return job.Status == mod.ReportDone || job.Status == mod.ReportFailed
+	}, 2*time.Second, 5*time.Millisecond)
+	return job
+}
+
+func TestReportService(t *testing.T) {
+	generators := map[string]internal.ReportGenerator{
+		"carries": func(ctx context.Context, params mod.ReportParams) ([]map[string]any, error) {
+			return []map[string]any{{"locality_id": *params.ID, "carries_count": 2}}, nil
+		},
+		"broken": func(ctx context.Context, params mod.ReportParams) ([]map[string]any, error) {
+			return nil, errors.New("query failed")
+		},
+	}
+	dir := t.TempDir()
+	sv, err := service.NewReportService(dir, 2, generators)
+	require.NoError(t, err)
+	sv.Start()
+	defer sv.Stop(context.Background())
+	id := 7
+
+	t.Run("generates the report in the requested format", func(t *testing.T) {
+		job, err := sv.Enqueue("carries", mod.ReportParams{ID: &id, Format: mod.ReportCSV})
+		require.NoError(t, err)
+		require.Equal(t, mod.ReportQueued, job.Status)
+
+		job = waitReport(t, sv, job.ID)
+		require.Equal(t, mod.ReportDone, job.Status)
+		require.Equal(t, 100, job.Progress)
+		require.Equal(t, 1, job.Rows)
+
+		_, file, err := sv.Open(job.ID)
+		require.NoError(t, err)
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		require.NoError(t, err)
+		require.Equal(t, "carries_count,locality_id\n2,7\n", string(data))
+	})
+
+	t.Run("json is the default format", func(t *testing.T) {
+		job, err := sv.Enqueue("carries", mod.ReportParams{ID: &id})
+		require.NoError(t, err)
+		job = waitReport(t, sv, job.ID)
+
+		data, err := os.ReadFile(filepath.Join(dir, job.ID+".json"))
+		require.NoError(t, err)
+		require.JSONEq(t, `[{"carries_count":2,"locality_id":7}]`, string(data))
+	})
+
+	t.Run("failures are recorded", func(t *testing.T) {
+		job, err := sv.Enqueue("broken", mod.ReportParams{})
+		require.NoError(t, err)
+		job = waitReport(t, sv, job.ID)
+		require.Equal(t, mod.ReportFailed, job.Status)
+		require.Equal(t, "query failed", job.Error)
+
+		_, _, err = sv.Open(job.ID)
+		require.ErrorIs(t, err, e.ErrReportNotReady)
+	})
+
+	t.Run("unknown type and job", func(t *testing.T) {
+		_, err := sv.Enqueue("nope", mod.ReportParams{})
+		require.ErrorIs(t, err, e.ErrReportTypeInvalid)
+		_, err = sv.FindByID("nope")
+		require.ErrorIs(t, err, e.ErrReportNotFound)
+	})
+
+	t.Run("finished reports survive a restart until they expire", func(t *testing.T) {
+		restarted, err := service.NewReportService(dir, 1, generators)
+		require.NoError(t, err)
+
+		deleted, err := restarted.Cleanup(time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.Zero(t, deleted)
+		deleted, err = restarted.Cleanup(time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, 3, deleted)
+
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Empty(t, files)
+	})
+}
+
+func TestReportService_Restart(t *testing.T) {
+	dir := t.TempDir()
+	sv, err := service.NewReportService(dir, 1, map[string]internal.ReportGenerator{
+		"carries": func(ctx context.Context, params mod.ReportParams) ([]map[string]any, error) { return nil, nil },
+	})
+	require.NoError(t, err)
+	// the workers never start, like a process stopped with the job in the queue
+	job, err := sv.Enqueue("carries", mod.ReportParams{})
+	require.NoError(t, err)
+
+	restarted, err := service.NewReportService(dir, 1, nil)
+	require.NoError(t, err)
+	job, err = restarted.FindByID(job.ID)
+	require.NoError(t, err)
+	require.Equal(t, mod.ReportFailed, job.Status)
+	require.Equal(t, "interrupted by a restart", job.Error)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewSearchService creates a new instance of the search service
+func NewSearchService(search internal.SearchRepository) *SearchService {
+	return &SearchService{
+		rp: search,
+	}
+}
+
+// SearchService is the default implementation of the search service
+type SearchService struct {
+	// rp is the repository used by the service
+	rp internal.SearchRepository
+}
+
+// Search searches every type and returns the best limit results of all of them
+func (s *SearchService) Search(q string, types []string, limit int) ([]mod.SearchResult, error) {
+	terms := common.SearchTerms(q)
+	if len(terms) == 0 {
+		return nil, e.ErrSearchQueryRequired
+	}
+	var results []mod.SearchResult
+	for _, t := range types {
+		found, err := s.rp.Search(t, terms, limit)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, found...)
+	}
+	return common.RankSearchResults(terms, results, limit), nil
+}
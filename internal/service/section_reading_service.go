@@ -0,0 +1,75 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// readingsDefaultWindow is the history returned when the range has no start
+const readingsDefaultWindow = 24 * time.Hour
+
+// NewSectionReadingService creates a new instance of the section reading service
+func NewSectionReadingService(rp internal.SectionReadingRepository, sections internal.SectionRepository) *SectionReadingService {
+	return &SectionReadingService{rp: rp, sections: sections, now: time.Now}
+}
+
+// SectionReadingService is the default implementation of the section reading service
+type SectionReadingService struct {
+	rp       internal.SectionReadingRepository
+	sections internal.SectionRepository
+	now      func() time.Time
+}
+
+// Ingest stores the readings in chronological order, the sensors may send them out of order
+func (s *SectionReadingService) Ingest(sectionID int, batch mod.SectionReadingBatch) (mod.SectionReadingsResult, error) {
+	readings := make([]mod.SectionReading, 0, len(batch))
+	for _, rd := range batch {
+		readings = append(readings, mod.SectionReading{
+			SectionID:   sectionID,
+			Temperature: *rd.Temperature,
+			RecordedAt:  rd.RecordedAt.UTC(),
+		})
+	}
+	sort.SliceStable(readings, func(i, j int) bool {
+		return readings[i].RecordedAt.Before(readings[j].RecordedAt)
+	})
+	return s.rp.Save(sectionID, readings)
+}
+
+// FindReadings returns the readings of an existing section, the last day when the range is empty
+func (s *SectionReadingService) FindReadings(sectionID int, rg mod.SectionReadingRange) ([]mod.SectionReading, error) {
+	if _, err := s.sections.FindByID(sectionID); err != nil {
+		return nil, err
+	}
+	return s.rp.FindReadings(sectionID, s.window(rg))
+}
+
+// FindRollups returns the hourly rollups of an existing section, the last day when the range is empty
+func (s *SectionReadingService) FindRollups(sectionID int, rg mod.SectionReadingRange) ([]mod.SectionReadingRollup, error) {
+	if _, err := s.sections.FindByID(sectionID); err != nil {
+		return nil, err
+	}
+	return s.rp.FindRollups(sectionID, s.window(rg))
+}
+
+// FindAlerts returns the alerts of an existing section
+func (s *SectionReadingService) FindAlerts(sectionID int, open *bool) ([]mod.SectionAlert, error) {
+	if _, err := s.sections.FindByID(sectionID); err != nil {
+		return nil, err
+	}
+	return s.rp.FindAlerts(sectionID, open)
+}
+
+// window fills the missing bounds of the range, to defaults to now and from to a day before to
+func (s *SectionReadingService) window(rg mod.SectionReadingRange) mod.SectionReadingRange {
+	if rg.To.IsZero() {
+		rg.To = s.now()
+	}
+	if rg.From.IsZero() {
+		rg.From = rg.To.Add(-readingsDefaultWindow)
+	}
+	return mod.SectionReadingRange{From: rg.From.UTC(), To: rg.To.UTC()}
+}
@@ -1,6 +1,11 @@
 package service
 
 import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
 	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
 	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 )
@@ -8,7 +13,8 @@ import (
 // NewSectionService creates a new instance of the section service
 func NewSectionService(sections internal.SectionRepository) *SectionService {
 	return &SectionService{
-		rp: sections,
+		rp:  sections,
+		now: time.Now,
 	}
 }
 
@@ -16,6 +22,8 @@ func NewSectionService(sections internal.SectionRepository) *SectionService {
 type SectionService struct {
 	// rp is the repository used by the service
 	rp internal.SectionRepository
+	// now returns the time the due dates of the products report are compared with
+	now func() time.Time
 }
 
 // FindAll returns all sections
@@ -44,6 +52,86 @@ func (s *SectionService) Delete(id int) (err error) {
 	return s.rp.Delete(id)
 }
 
-func (s *SectionService) ReportProducts(ids []int) ([]mod.ReportProductsResponse, error) {
-	return s.rp.ReportProducts(ids)
+// sectionReportWindows are the expiry windows of the products report when the request has none, in days
+var sectionReportWindows = []int{7, 30}
+
+// ReportProducts returns the stock report of the sections of the filter as of now
+func (s *SectionService) ReportProducts(f mod.SectionReportFilter) ([]mod.ReportProductsResponse, error) {
+	if len(f.Windows) == 0 {
+		f.Windows = sectionReportWindows
+	}
+	if f.At.IsZero() {
+		f.At = s.now().UTC()
+	}
+	return s.rp.ReportProducts(f)
+}
+
+// Weights of the slotting score, a section that scores all of them gets 100
+const (
+	slotTemperatureWeight = 40
+	slotColocationWeight  = 30
+	slotCapacityWeight    = 30
+)
+
+// Recommend ranks the sections that store the product type of the batch and have room for it. A section scores the
+// full temperature weight when it is already at or below the recommended freezing temperature of the product and half
+// when it can reach it, the co-location weight when it holds batches of the same product and the capacity weight in
+// proportion to the room it has left once the batch is placed
+func (s *SectionService) Recommend(req mod.SlottingRequest) ([]mod.SectionRecommendation, error) {
+	product, candidates, err := s.rp.FindSlotCandidates(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := make([]mod.SectionRecommendation, 0, len(candidates))
+	for _, c := range candidates {
+		rec := mod.SectionRecommendation{
+			SectionID:         c.SectionID,
+			SectionNumber:     c.SectionNumber,
+			WarehouseID:       c.WarehouseID,
+			RemainingCapacity: c.MaximumCapacity - c.CurrentCapacity - req.Quantity,
+			ProductBatches:    c.ProductBatches,
+			Reasons:           []string{fmt.Sprintf("stores product type %d like the product", product.ProductTypeID)},
+		}
+
+		var score float64
+		switch {
+		case c.CurrentTemperature <= product.RecomFreezTemp:
+			score += slotTemperatureWeight
+			rec.Reasons = append(rec.Reasons, fmt.Sprintf("is at %.1f°C, at or below the recommended %.1f°C",
+				c.CurrentTemperature, product.RecomFreezTemp))
+		case c.MinimumTemperature <= product.RecomFreezTemp:
+			score += slotTemperatureWeight / 2
+			rec.Reasons = append(rec.Reasons, fmt.Sprintf("is at %.1f°C but can reach the recommended %.1f°C",
+				c.CurrentTemperature, product.RecomFreezTemp))
+		default:
+			rec.Reasons = append(rec.Reasons, fmt.Sprintf("cannot go below %.1f°C, above the recommended %.1f°C",
+				c.MinimumTemperature, product.RecomFreezTemp))
+		}
+
+		if c.ProductBatches > 0 {
+			score += slotColocationWeight
+			rec.Reasons = append(rec.Reasons, fmt.Sprintf("already holds %d batches of the product", c.ProductBatches))
+		} else {
+			rec.Reasons = append(rec.Reasons, "holds no batches of the product")
+		}
+
+		free := float64(rec.RemainingCapacity) / float64(c.MaximumCapacity)
+		score += slotCapacityWeight * free
+		rec.Reasons = append(rec.Reasons, fmt.Sprintf("keeps %d of %d units free after the batch", rec.RemainingCapacity, c.MaximumCapacity))
+
+		rec.Score = math.Round(score*100) / 100
+		ranked = append(ranked, rec)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].RemainingCapacity > ranked[j].RemainingCapacity
+	})
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+	}
+	return ranked, nil
 }
@@ -0,0 +1,82 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/internal/service"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// MockSlottingRepo only answers the slotting candidates
+type MockSlottingRepo struct {
+	internal.SectionRepository
+	mock.Mock
+}
+
+func (m *MockSlottingRepo) FindSlotCandidates(req mod.SlottingRequest) (mod.SlottingProduct, []mod.SlottingSection, error) {
+	args := m.Called(req)
+	return args.Get(0).(mod.SlottingProduct), args.Get(1).([]mod.SlottingSection), args.Error(2)
+}
+
+func TestSectionService_Recommend(t *testing.T) {
+	req := mod.SlottingRequest{ProductID: 4, Quantity: 10}
+	product := mod.SlottingProduct{ID: 4, ProductTypeID: 2, RecomFreezTemp: -18}
+
+	t.Run("ranks by temperature, co-location and capacity", func(t *testing.T) {
+		mockRepo := new(MockSlottingRepo)
+		mockRepo.On("FindSlotCandidates", req).Return(product, []mod.SlottingSection{
+			// too warm, empty and with a lot of room
+			{SectionID: 1, SectionNumber: 11, WarehouseID: 1, CurrentTemperature: 4, MinimumTemperature: 0, CurrentCapacity: 0, MaximumCapacity: 100},
+			// cold enough and holding the product
+			{SectionID: 2, SectionNumber: 12, WarehouseID: 1, CurrentTemperature: -20, MinimumTemperature: -25, CurrentCapacity: 40, MaximumCapacity: 100, ProductBatches: 2},
+			// can cool down, empty
+			{SectionID: 3, SectionNumber: 13, WarehouseID: 2, CurrentTemperature: -10, MinimumTemperature: -25, CurrentCapacity: 50, MaximumCapacity: 100},
+		}, nil)
+
+		svc := service.NewSectionService(mockRepo)
+		result, err := svc.Recommend(req)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 3)
+		assert.Equal(t, []int{2, 3, 1}, []int{result[0].SectionID, result[1].SectionID, result[2].SectionID})
+		assert.Equal(t, []int{1, 2, 3}, []int{result[0].Rank, result[1].Rank, result[2].Rank})
+		assert.Equal(t, 85.0, result[0].Score)
+		assert.Equal(t, 50, result[0].RemainingCapacity)
+		assert.Equal(t, []string{
+			"stores product type 2 like the product",
+			"is at -20.0°C, at or below the recommended -18.0°C",
+			"already holds 2 batches of the product",
+			"keeps 50 of 100 units free after the batch",
+		}, result[0].Reasons)
+		assert.Equal(t, 32.0, result[1].Score)
+		assert.Equal(t, "is at -10.0°C but can reach the recommended -18.0°C", result[1].Reasons[1])
+		assert.Equal(t, 27.0, result[2].Score)
+		assert.Equal(t, "cannot go below 0.0°C, above the recommended -18.0°C", result[2].Reasons[1])
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("no candidates", func(t *testing.T) {
+		mockRepo := new(MockSlottingRepo)
+		mockRepo.On("FindSlotCandidates", req).Return(product, []mod.SlottingSection{}, nil)
+
+		result, err := service.NewSectionService(mockRepo).Recommend(req)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("product not found", func(t *testing.T) {
+		mockRepo := new(MockSlottingRepo)
+		mockRepo.On("FindSlotCandidates", req).Return(mod.SlottingProduct{}, []mod.SlottingSection(nil), e.ErrProductRepositoryNotFound)
+
+		result, err := service.NewSectionService(mockRepo).Recommend(req)
+
+		assert.ErrorIs(t, err, e.ErrProductRepositoryNotFound)
+		assert.Nil(t, result)
+	})
+}
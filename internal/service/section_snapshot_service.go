@@ -0,0 +1,56 @@
+package service
+
+import (
+	"time"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+const (
+	// historyDefaultWindow is the history returned when the query has no start
+	historyDefaultWindow = 7 * 24 * time.Hour
+	// historyDefaultInterval is the bucket size when the query has none
+	historyDefaultInterval = time.Hour
+	// historyMaxBuckets keeps a small interval over a long range from returning an unbounded history
+	historyMaxBuckets = 5000
+)
+
+// NewSectionSnapshotService creates a new instance of the section snapshot service
+func NewSectionSnapshotService(rp internal.SectionSnapshotRepository, sections internal.SectionRepository) *SectionSnapshotService {
+	return &SectionSnapshotService{rp: rp, sections: sections, now: time.Now}
+}
+
+// SectionSnapshotService is the default implementation of the section snapshot service
+type SectionSnapshotService struct {
+	rp       internal.SectionSnapshotRepository
+	sections internal.SectionRepository
+	now      func() time.Time
+}
+
+// Snapshot takes a periodic snapshot of every section
+func (s *SectionSnapshotService) Snapshot() (int, error) {
+	return s.rp.SnapshotAll(s.now().UTC())
+}
+
+// FindHistory returns the history of an existing section, the last week by the hour when the query is empty
+func (s *SectionSnapshotService) FindHistory(sectionID int, q mod.SectionHistoryQuery) ([]mod.SectionHistoryBucket, error) {
+	if q.To.IsZero() {
+		q.To = s.now()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-historyDefaultWindow)
+	}
+	if q.Interval == 0 {
+		q.Interval = historyDefaultInterval
+	}
+	q.From, q.To = q.From.UTC(), q.To.UTC()
+	if !q.From.Before(q.To) || q.To.Sub(q.From)/q.Interval > historyMaxBuckets {
+		return nil, e.ErrSectionHistoryInvalid
+	}
+	if _, err := s.sections.FindByID(sectionID); err != nil {
+		return nil, err
+	}
+	return s.rp.FindHistory(sectionID, q)
+}
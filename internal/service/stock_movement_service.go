@@ -0,0 +1,43 @@
+package service
+
+import (
+	"time"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// NewStockMovementService creates a new instance of the stock movement service
+func NewStockMovementService(rp internal.StockMovementRepository) *StockMovementService {
+	return &StockMovementService{rp: rp, now: time.Now}
+}
+
+// StockMovementService is the default implementation of the stock movement service
+type StockMovementService struct {
+	rp  internal.StockMovementRepository
+	now func() time.Time
+}
+
+// Adjust applies a manual adjustment or write-off to a batch, a write-off can only remove units
+func (s *StockMovementService) Adjust(m *mod.StockMovement) error {
+	if m.Quantity == 0 || (m.Type == mod.MovementWriteOff && m.Quantity > 0) {
+		return e.ErrStockMovementInvalid
+	}
+	return s.rp.Adjust(m)
+}
+
+// FindLedgerByBatchID returns the movements of a batch with its balances
+func (s *StockMovementService) FindLedgerByBatchID(batchID int) (mod.StockLedger, error) {
+	return s.rp.FindLedgerByBatchID(batchID)
+}
+
+// Reconcile flags the batches and sections whose counters disagree with the ledger
+func (s *StockMovementService) Reconcile() ([]mod.StockDiscrepancy, error) {
+	return s.rp.Reconcile(s.now().UTC())
+}
+
+// FindDiscrepancies returns the discrepancies found by the last reconciliation
+func (s *StockMovementService) FindDiscrepancies() ([]mod.StockDiscrepancy, error) {
+	return s.rp.FindDiscrepancies()
+}
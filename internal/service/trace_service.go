@@ -0,0 +1,44 @@
+package service
+
+import (
+	"time"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// NewTraceService creates a new instance of the trace service
+func NewTraceService(rp internal.TraceRepository) *TraceService {
+	return &TraceService{rp: rp, now: time.Now}
+}
+
+// TraceService is the default implementation of the trace service
+type TraceService struct {
+	rp  internal.TraceRepository
+	now func() time.Time
+}
+
+// FindBatchTrace returns where the batch came from and the buyers it reached
+func (s *TraceService) FindBatchTrace(id int) (mod.BatchTrace, error) {
+	return s.rp.FindBatchTrace(id)
+}
+
+// FindPurchaseOrderTrace returns where the batches of the purchase order came from
+func (s *TraceService) FindPurchaseOrderTrace(id int) (mod.PurchaseOrderTrace, error) {
+	return s.rp.FindPurchaseOrderTrace(id)
+}
+
+// Recall rejects the batch of the request and its splits now and returns the buyers to contact
+func (s *TraceService) Recall(req mod.RecallRequest) (mod.Recall, error) {
+	recall := mod.Recall{ProductBatchID: req.ProductBatchID, Reason: req.Reason, EmployeeID: req.EmployeeID,
+		CreatedAt: s.now().UTC().Truncate(time.Millisecond)}
+	if err := s.rp.Recall(&recall); err != nil {
+		return mod.Recall{}, err
+	}
+	return recall, nil
+}
+
+// FindRecall returns a recall with the buyers to contact
+func (s *TraceService) FindRecall(id int) (mod.Recall, error) {
+	return s.rp.FindRecall(id)
+}
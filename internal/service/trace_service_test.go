@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// mockTraceRepo answers the traces and records the recalls
+type mockTraceRepo struct {
+	mock.Mock
+}
+
+func (m *mockTraceRepo) FindBatchTrace(id int) (mod.BatchTrace, error) {
+	args := m.Called(id)
+	return args.Get(0).(mod.BatchTrace), args.Error(1)
+}
+
+func (m *mockTraceRepo) FindPurchaseOrderTrace(id int) (mod.PurchaseOrderTrace, error) {
+	args := m.Called(id)
+	return args.Get(0).(mod.PurchaseOrderTrace), args.Error(1)
+}
+
+func (m *mockTraceRepo) Recall(recall *mod.Recall) error {
+	args := m.Called(recall)
+	return args.Error(0)
+}
+
+func (m *mockTraceRepo) FindRecall(id int) (mod.Recall, error) {
+	args := m.Called(id)
+	return args.Get(0).(mod.Recall), args.Error(1)
+}
+
+func TestTraceService_Recall(t *testing.T) {
+	now := time.Date(2024, 7, 6, 7, 0, 0, 123456789, time.FixedZone("UTC-3", -3*60*60))
+	req := mod.RecallRequest{ProductBatchID: 7, Reason: "listeria", EmployeeID: 4}
+	expected := &mod.Recall{ProductBatchID: 7, Reason: "listeria", EmployeeID: 4,
+		CreatedAt: time.Date(2024, 7, 6, 10, 0, 0, 123000000, time.UTC)}
+
+	t.Run("recall at the current millisecond in UTC", func(t *testing.T) {
+		rp := new(mockTraceRepo)
+		rp.On("Recall", expected).Run(func(args mock.Arguments) {
+			recall := args.Get(0).(*mod.Recall)
+			recall.ID, recall.Batches = 2, []int{7, 9}
+		}).Return(nil)
+		sv := NewTraceService(rp)
+		sv.now = func() time.Time { return now }
+
+		recall, err := sv.Recall(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, recall.ID)
+		assert.Equal(t, []int{7, 9}, recall.Batches)
+		rp.AssertExpectations(t)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		rp := new(mockTraceRepo)
+		rp.On("Recall", expected).Return(e.ErrProductBatchNotFound)
+		sv := NewTraceService(rp)
+		sv.now = func() time.Time { return now }
+
+		recall, err := sv.Recall(req)
+
+		assert.ErrorIs(t, err, e.ErrProductBatchNotFound)
+		assert.Equal(t, mod.Recall{}, recall)
+	})
+}
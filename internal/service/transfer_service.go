@@ -0,0 +1,35 @@
+package service
+
+import (
+	"time"
+
+	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// NewTransferService creates a new instance of the transfer service
+func NewTransferService(rp internal.TransferRepository) *TransferService {
+	return &TransferService{rp: rp, now: time.Now}
+}
+
+// TransferService is the default implementation of the transfer service
+type TransferService struct {
+	rp  internal.TransferRepository
+	now func() time.Time
+}
+
+// Save moves the stock of the transfer and records it with the current time
+func (s *TransferService) Save(t *mod.Transfer) error {
+	t.CreatedAt = s.now().UTC()
+	return s.rp.Save(t)
+}
+
+// FindAll returns every transfer, newest first
+func (s *TransferService) FindAll() ([]mod.Transfer, error) {
+	return s.rp.FindAll()
+}
+
+// FindByID returns the transfer with the id
+func (s *TransferService) FindByID(id int) (mod.Transfer, error) {
+	return s.rp.FindByID(id)
+}
@@ -1,17 +1,21 @@
 package service
 
 import (
+	"math"
+	"sort"
+
 	internal "github.com/smartineztri_meli/W17-G2-Bootcamp/internal/interfaces"
 	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
 )
 
 type warehouseService struct {
-	repo internal.WarehouseRepository
+	repo       internal.WarehouseRepository
+	localities internal.LocalityRepository
 }
 
-func NewWarehouseService(repo internal.WarehouseRepository) *warehouseService {
-	return &warehouseService{repo: repo}
+func NewWarehouseService(repo internal.WarehouseRepository, localities internal.LocalityRepository) *warehouseService {
+	return &warehouseService{repo: repo, localities: localities}
 }
 
 // FindAll devuelve un slice de warehouses (no un mapa)
@@ -24,6 +28,10 @@ func (s *warehouseService) FindByID(id int) (mod.Warehouse, error) {
 }
 
 func (s *warehouseService) Save(w *mod.Warehouse) error {
+	if err := s.checkLocality(w.LocalityID); err != nil {
+		return err
+	}
+
 	// Usamos el método ExistsWarehouseCode del repositorio
 	exists, err := s.repo.ExistsWarehouseCode(w.WarehouseCode)
 	if err != nil {
@@ -43,6 +51,10 @@ func (s *warehouseService) Update(w *mod.Warehouse) error {
 		return err
 	}
 
+	if err := s.checkLocality(w.LocalityID); err != nil {
+		return err
+	}
+
 	// Verificamos si el nuevo código ya existe en otro registro
 	existingWarehouse, err := s.repo.GetByWarehouseCode(w.WarehouseCode)
 	if err != nil && err != e.ErrWarehouseRepositoryNotFound {
@@ -59,3 +71,146 @@ func (s *warehouseService) Update(w *mod.Warehouse) error {
 func (s *warehouseService) Delete(id int) error {
 	return s.repo.Delete(id)
 }
+
+// checkLocality verifica que la localidad del warehouse exista
+func (s *warehouseService) checkLocality(id int) error {
+	exists, err := s.localities.ExistsByID(id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return e.ErrWarehouseLocalityNotFound
+	}
+	return nil
+}
+
+// Valores aceptados por el parámetro sort del reporte de capacidad
+const (
+	CapacitySortUtilization     = "utilization"
+	CapacitySortUtilizationDesc = "-utilization"
+)
+
+// ReportCapacity calcula la utilización de cada warehouse y si alcanza su capacidad mínima
+func (s *warehouseService) ReportCapacity(id *int, order string) ([]mod.WarehouseCapacityReport, error) {
+	if order != "" && order != CapacitySortUtilization && order != CapacitySortUtilizationDesc {
+		return nil, e.ErrWarehouseReportSortInvalid
+	}
+
+	reports, err := s.repo.GetCapacityReport(id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range reports {
+		rp := &reports[i]
+		if rp.MaximumCapacity > 0 {
+			rp.UtilizationPercentage = math.Round(float64(rp.CurrentCapacity)/float64(rp.MaximumCapacity)*10000) / 100
+		}
+		rp.MeetsMinimumCapacity = rp.CurrentCapacity >= rp.MinimumCapacity
+	}
+
+	if order != "" {
+		desc := order == CapacitySortUtilizationDesc
+		sort.SliceStable(reports, func(i, j int) bool {
+			if desc {
+				return reports[i].UtilizationPercentage > reports[j].UtilizationPercentage
+			}
+			return reports[i].UtilizationPercentage < reports[j].UtilizationPercentage
+		})
+	}
+
+	return reports, nil
+}
+
+// RecentInboundOrders es la cantidad de órdenes de entrada que muestra el overview del warehouse
+const RecentInboundOrders = 10
+
+// FindOverview devuelve el warehouse con sus secciones, empleados y últimas órdenes de entrada
+func (s *warehouseService) FindOverview(id int) (mod.WarehouseOverview, error) {
+	wh, err := s.repo.GetByID(id)
+	if err != nil {
+		return mod.WarehouseOverview{}, err
+	}
+
+	sections, err := s.repo.GetSections(id, mod.WarehouseSectionFilter{})
+	if err != nil {
+		return mod.WarehouseOverview{}, err
+	}
+
+	employees, err := s.repo.GetEmployees(id, "")
+	if err != nil {
+		return mod.WarehouseOverview{}, err
+	}
+
+	orders, err := s.repo.GetInboundOrders(id, RecentInboundOrders)
+	if err != nil {
+		return mod.WarehouseOverview{}, err
+	}
+
+	return mod.WarehouseOverview{
+		Warehouse:           wh,
+		Sections:            sections,
+		Employees:           employees,
+		RecentInboundOrders: orders,
+	}, nil
+}
+
+// FindSections devuelve las secciones del warehouse, que debe existir
+func (s *warehouseService) FindSections(id int, filter mod.WarehouseSectionFilter) ([]mod.WarehouseSection, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetSections(id, filter)
+}
+
+// FindEmployees devuelve los empleados del warehouse, que debe existir
+func (s *warehouseService) FindEmployees(id int, name string) ([]mod.Employee, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetEmployees(id, name)
+}
+
+// earthRadiusKm es el radio medio de la Tierra usado por la fórmula de haversine
+const earthRadiusKm = 6371.0
+
+// FindNearest ordena por distancia a la localidad los warehouses con lotes vigentes del producto y devuelve los
+// limit más cercanos
+func (s *warehouseService) FindNearest(localityID, productID, limit int) ([]mod.WarehouseDistance, error) {
+	loc, err := s.localities.FindByID(localityID)
+	if err != nil {
+		return nil, err
+	}
+	if loc.Latitude == nil || loc.Longitude == nil {
+		return nil, e.ErrLocalityCoordinatesMissing
+	}
+
+	warehouses, err := s.repo.GetStockingProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range warehouses {
+		wd := &warehouses[i]
+		km := haversineKm(*loc.Latitude, *loc.Longitude, wd.Latitude, wd.Longitude)
+		wd.DistanceKm = math.Round(km*100) / 100
+	}
+	sort.SliceStable(warehouses, func(i, j int) bool {
+		return warehouses[i].DistanceKm < warehouses[j].DistanceKm
+	})
+
+	if len(warehouses) > limit {
+		warehouses = warehouses[:limit]
+	}
+	return warehouses, nil
+}
+
+// haversineKm devuelve la distancia en kilómetros entre dos coordenadas
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
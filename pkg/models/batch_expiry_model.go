@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Alert types raised by the expiry sweep
+const (
+	AlertBatchesExpiring = "batches_expiring"
+	AlertBatchesExpired  = "batches_expired"
+)
+
+// AlertTypes lists the valid alert types
+var AlertTypes = []string{AlertBatchesExpiring, AlertBatchesExpired}
+
+// ExpiringQuery selects the batches with units that are due after At. With Days the window is the same for every
+// batch, without it a batch is expiring once it enters the lead time of its product: LeadTime scaled by the
+// expiration rate of the product, so a product that spoils twice as fast is warned twice as early
+type ExpiringQuery struct {
+	Days        int
+	WarehouseID int
+	At          time.Time
+	LeadTime    time.Duration
+}
+
+// ExpiringBatch is a batch close to its due date with the seller and warehouse to warn
+type ExpiringBatch struct {
+	ProductBatchID  int       `json:"product_batch_id"`
+	BatchNumber     int       `json:"batch_number"`
+	ProductID       int       `json:"product_id"`
+	SellerID        int       `json:"seller_id"`
+	SectionID       int       `json:"section_id"`
+	WarehouseID     int       `json:"warehouse_id"`
+	CurrentQuantity int       `json:"current_quantity"`
+	DueDate         time.Time `json:"due_date"`
+	// HoursLeft is the time left until the due date
+	HoursLeft float64 `json:"hours_left"`
+}
+
+// Alert groups the batches of a warehouse and seller found by one sweep
+type Alert struct {
+	ID          int    `json:"id"`
+	Type        string `json:"type"`
+	WarehouseID int    `json:"warehouse_id"`
+	SellerID    int    `json:"seller_id"`
+	// Quantity is the sum of the units of the batches
+	Quantity        int       `json:"quantity"`
+	ProductBatchIDs []int     `json:"product_batch_ids"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// AlertFilter selects the alerts to list, the zero values do not filter
+type AlertFilter struct {
+	Type        string
+	WarehouseID int
+	SellerID    int
+	From        time.Time
+	To          time.Time
+}
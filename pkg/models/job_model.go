@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Job run statuses
+const (
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed"
+)
+
+// Job run triggers
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+)
+
+// Job is a recurring task of the scheduler
+type Job struct {
+	// Name is the unique name of the job
+	Name string `json:"name"`
+	// Schedule is the cron expression of the job, e.g. "*/5 * * * *" or "@daily"
+	Schedule string `json:"schedule"`
+	// Paused tells if the scheduled runs are skipped, manual runs are still allowed
+	Paused bool `json:"paused"`
+	// Running tells if this instance is running the job right now
+	Running bool `json:"running"`
+	// NextRun is when the job runs next
+	NextRun time.Time `json:"next_run"`
+	// LastRun is the latest run of the job recorded by any instance
+	LastRun *JobRun `json:"last_run"`
+}
+
+// JobRun is a run of a job recorded in the history
+type JobRun struct {
+	// ID is the unique identifier of the run
+	ID int `json:"id"`
+	// Job is the name of the job that ran
+	Job string `json:"job"`
+	// Owner identifies the instance that ran the job
+	Owner string `json:"owner"`
+	// Trigger is what started the run, JobTriggerSchedule or JobTriggerManual
+	Trigger string `json:"trigger"`
+	// StartedAt is when the run started
+	StartedAt time.Time `json:"started_at"`
+	// FinishedAt is when the run finished
+	FinishedAt time.Time `json:"finished_at"`
+	// DurationMs is how long the run took in milliseconds
+	DurationMs int64 `json:"duration_ms"`
+	// Status is JobRunSucceeded or JobRunFailed
+	Status string `json:"status"`
+	// Error is the error of a failed run
+	Error string `json:"error,omitempty"`
+}
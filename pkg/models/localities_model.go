@@ -10,6 +10,9 @@ type Locality struct {
 	Province string `json:"province_name" validate:"required"`
 	// Country is the country's name
 	Country string `json:"country_name" validate:"required"`
+	// Latitude and Longitude are the coordinates of the locality, nil when they are unknown
+	Latitude  *float64 `json:"latitude,omitempty" validate:"omitempty,latitude"`
+	Longitude *float64 `json:"longitude,omitempty" validate:"omitempty,longitude"`
 }
 
 type SelByLoc struct {
@@ -17,3 +20,19 @@ type SelByLoc struct {
 	Name  string `json:"locality_name"`
 	Count int    `json:"sellers_count"`
 }
+
+// WhByLoc is the number of warehouses of a locality
+type WhByLoc struct {
+	ID    int    `json:"locality_id"`
+	Name  string `json:"locality_name"`
+	Count int    `json:"warehouses_count"`
+}
+
+// LocalityOverview is the number of sellers, carries and warehouses of a locality
+type LocalityOverview struct {
+	ID              int    `json:"locality_id"`
+	Name            string `json:"locality_name"`
+	SellersCount    int    `json:"sellers_count"`
+	CarriesCount    int    `json:"carries_count"`
+	WarehousesCount int    `json:"warehouses_count"`
+}
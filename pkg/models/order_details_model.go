@@ -7,4 +7,16 @@ type OrderDetails struct {
 	Temperature       float64 `json:"temperature" validate:"required"`
 	ProductRecordId   int     `json:"product_record_id" validate:"required"`
 	PurchaseOrderId   int     `json:"purchase_order_id"`
+	// Allocations are the batches that served the detail, filled when the order is saved
+	Allocations []OrderAllocation `json:"allocations,omitempty"`
+	// BackorderedQuantity is the part of the quantity that had no stock, only when the order allows backorders
+	BackorderedQuantity int `json:"backordered_quantity,omitempty"`
+}
+
+// OrderAllocation is the quantity of an order detail taken out of a batch
+type OrderAllocation struct {
+	ProductBatchID int `json:"product_batch_id"`
+	// SectionID is the section of the batch, the units picked released room in it
+	SectionID int `json:"section_id"`
+	Quantity  int `json:"quantity"`
 }
@@ -16,4 +16,66 @@ type ProductBatch struct {
 	ManufacturingHour  string    `json:"manufacturing_hour" validate:"required,hhmmss"`
 	ProductId          int       `json:"product_id" validate:"required,gt=0"`
 	SectionId          int       `json:"section_id" validate:"required,gt=0"`
+	// ArchivedAt is set when the batch is archived, an archived batch has no units and cannot be updated
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	// ExpiredAt is set by the expiry sweep once the batch is past its due date
+	ExpiredAt *time.Time `json:"expired_at,omitempty"`
+	// Status is the quality status of the batch, new batches are available
+	Status string `json:"status,omitempty"`
+}
+
+// Quality statuses of a batch, a batch on hold keeps its units but cannot be allocated, transferred or reported as stock
+const (
+	BatchAvailable   = "available"
+	BatchQuarantined = "quarantined"
+	BatchReleased    = "released"
+	BatchRejected    = "rejected"
+)
+
+// BatchOnHold reports whether a batch with the status is on hold
+func BatchOnHold(status string) bool {
+	return status == BatchQuarantined || status == BatchRejected
+}
+
+// BatchHoldRequest is the body of POST /v1/productBatches/{id}/hold and /release, Status is the outcome of a
+// release and defaults to released
+type BatchHoldRequest struct {
+	Reason     string `json:"reason" validate:"required,max=255"`
+	EmployeeID int    `json:"employee_id" validate:"required,gt=0"`
+	Status     string `json:"status" validate:"omitempty,oneof=released rejected"`
+}
+
+// BatchStatusChange is a recorded change of the status of a batch
+type BatchStatusChange struct {
+	ID             int       `json:"id"`
+	ProductBatchID int       `json:"product_batch_id"`
+	FromStatus     string    `json:"from_status"`
+	ToStatus       string    `json:"to_status"`
+	Reason         string    `json:"reason"`
+	EmployeeID     int       `json:"employee_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ProductBatchPatch is the body of PATCH /v1/productBatches/{id}. The quantity can go down to 0, releasing the
+// units from the section, and the temperature keeps the minimum temperature of the batch as its floor
+type ProductBatchPatch struct {
+	CurrentQuantity    *int `json:"current_quantity" validate:"omitempty,gte=0"`
+	CurrentTemperature *int `json:"current_temperature"`
+}
+
+// ProductBatchFilter narrows the product batches, the zero values do not filter
+type ProductBatchFilter struct {
+	ProductID   int
+	SectionID   int
+	WarehouseID int
+	// DueFrom and DueTo keep the batches due in [DueFrom, DueTo)
+	DueFrom time.Time
+	DueTo   time.Time
+	// Archived keeps only the archived batches when true and only the active ones when false
+	Archived *bool
+}
+
+// IsZero reports whether the filter keeps every batch
+func (f ProductBatchFilter) IsZero() bool {
+	return f.ProductID == 0 && f.SectionID == 0 && f.WarehouseID == 0 && f.DueFrom.IsZero() && f.DueTo.IsZero() && f.Archived == nil
 }
@@ -0,0 +1,23 @@
+package models
+
+// ProductType is a registered product type with the temperature range it must be stored in
+type ProductType struct {
+	// ID is the unique identifier of the product type
+	ID int `json:"id"`
+	// Name is the unique name of the product type
+	Name string `json:"name" validate:"required,max=60"`
+	// MinimumTemperature is the lowest storage temperature of the product type
+	MinimumTemperature *float64 `json:"minimum_temperature" validate:"required"`
+	// MaximumTemperature is the highest storage temperature of the product type
+	MaximumTemperature *float64 `json:"maximum_temperature" validate:"required"`
+	// HazardClass is the hazard class of the product type, none when it is not hazardous
+	HazardClass string `json:"hazard_class" validate:"required,oneof=none flammable corrosive toxic oxidizing explosive compressed_gas"`
+}
+
+// ProductTypePatch is the body of PATCH /v1/productTypes/{id}, the missing fields keep their value
+type ProductTypePatch struct {
+	Name               *string  `json:"name" validate:"omitempty,min=1,max=60"`
+	MinimumTemperature *float64 `json:"minimum_temperature"`
+	MaximumTemperature *float64 `json:"maximum_temperature"`
+	HazardClass        *string  `json:"hazard_class" validate:"omitempty,oneof=none flammable corrosive toxic oxidizing explosive compressed_gas"`
+}
@@ -12,7 +12,24 @@ type PurchaseOrder struct {
 	OrderDate       Date           `json:"order_date" validate:"required"`
 	TrackingCode    string         `json:"tracking_code" validate:"required"`
 	BuyerId         int            `json:"buyer_id" validate:"required"`
-	ProductsDetails []OrderDetails `json:"products_details" validate:"min=1"`
+	ProductsDetails []OrderDetails `json:"products_details,omitempty" validate:"min=1"`
+	// AllowBackorder saves the order when the stock does not cover a detail, the missing units are backordered
+	AllowBackorder bool `json:"allow_backorder,omitempty"`
+}
+
+// SectionIDs returns the distinct sections the allocations of the order took units from, in order of appearance
+func (po PurchaseOrder) SectionIDs() []int {
+	var ids []int
+	seen := make(map[int]bool)
+	for _, od := range po.ProductsDetails {
+		for _, a := range od.Allocations {
+			if !seen[a.SectionID] {
+				seen[a.SectionID] = true
+				ids = append(ids, a.SectionID)
+			}
+		}
+	}
+	return ids
 }
 
 type Date time.Time
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Report job statuses
+const (
+	ReportQueued  = "queued"
+	ReportRunning = "running"
+	ReportDone    = "done"
+	ReportFailed  = "failed"
+)
+
+// Report formats
+const (
+	ReportJSON = "json"
+	ReportCSV  = "csv"
+)
+
+// ReportParams are the parameters of an asynchronous report
+type ReportParams struct {
+	// ID filters the report by the entity of the report type, e.g. the buyer of the purchase orders report
+	ID *int `json:"id,omitempty" validate:"omitempty,gte=0"`
+	// IDs filters the reports that accept several entities, e.g. the sections of the products report
+	IDs []int `json:"ids,omitempty" validate:"omitempty,dive,gt=0"`
+	// WarehouseID and ProductTypeID filter the sections of the products report
+	WarehouseID   int `json:"warehouse_id,omitempty" validate:"omitempty,gt=0"`
+	ProductTypeID int `json:"product_type_id,omitempty" validate:"omitempty,gt=0"`
+	// Windows are the expiry windows in days of the products report, 7 and 30 by default
+	Windows []int `json:"windows,omitempty" validate:"omitempty,max=5,dive,gte=1,lte=365"`
+	// Sort orders the capacity report by utilization, utilization or -utilization
+	Sort string `json:"sort,omitempty" validate:"omitempty,oneof=utilization -utilization"`
+	// Format is the format of the generated file, ReportJSON by default
+	Format string `json:"format,omitempty" validate:"omitempty,oneof=json csv"`
+}
+
+// ReportJob is a report generated in the background
+type ReportJob struct {
+	// ID is the unique identifier of the job
+	ID string `json:"id"`
+	// Type is the report type, e.g. purchaseOrders
+	Type string `json:"type"`
+	// Params are the parameters the report was requested with
+	Params ReportParams `json:"params"`
+	// Status is ReportQueued, ReportRunning, ReportDone or ReportFailed
+	Status string `json:"status"`
+	// Progress is the completed percentage, from 0 to 100
+	Progress int `json:"progress"`
+	// Rows is the number of rows of a finished report
+	Rows int `json:"rows"`
+	// Error is the error of a failed report
+	Error string `json:"error,omitempty"`
+	// CreatedAt is when the report was requested
+	CreatedAt time.Time `json:"created_at"`
+	// StartedAt is when the generation started
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	// FinishedAt is when the generation finished
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,20 @@
+package models
+
+// SearchTypes are the entities supported by the search, in the order they are searched
+var SearchTypes = []string{"products", "sellers", "buyers", "warehouses", "carries"}
+
+// SearchResult is an entity that matched a search
+type SearchResult struct {
+	// Type is the entity type, one of SearchTypes
+	Type string `json:"type"`
+	// ID is the identifier of the entity
+	ID int `json:"id"`
+	// Title is the main text of the entity, e.g. the product code or the buyer's full name
+	Title string `json:"title"`
+	// Score is the relevance of the result, higher is better
+	Score float64 `json:"score"`
+	// Fields contains the searched columns of the entity
+	Fields map[string]string `json:"fields"`
+	// Highlights contains the fields that matched with the matches wrapped in <em> tags
+	Highlights map[string]string `json:"highlights"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Section is a struct that contains the section's information
 type Section struct {
 	// ID is the unique identifier of the section
@@ -40,8 +42,45 @@ type SectionPatch struct {
 	ProductTypeID *int `json:"product_type_id,omitempty" validate:"omitempty,gte=1"`
 }
 
+// ReportProductsResponse is the stock of a section in the products report
 type ReportProductsResponse struct {
-	SectionId     int
-	SectionNumber int
-	ProductsCount int
+	SectionId     int `json:"section_id"`
+	SectionNumber int `json:"section_number"`
+	WarehouseID   int `json:"warehouse_id"`
+	ProductTypeID int `json:"product_type_id"`
+	// ProductsCount is the number of distinct products with stock available in the section
+	ProductsCount int `json:"products_count"`
+	// BatchesCount is the number of batches with stock available in the section
+	BatchesCount int `json:"batches_count"`
+	// CurrentQuantity are the units of every batch in the section, the ones on hold included
+	CurrentQuantity int `json:"current_quantity"`
+	// HeldBatches and HeldQuantity are the quarantined or rejected batches that still occupy the section
+	HeldBatches     int `json:"held_batches"`
+	HeldQuantity    int `json:"held_quantity"`
+	MaximumCapacity int `json:"maximum_capacity"`
+	// FillPercentage is CurrentQuantity over MaximumCapacity, rounded to two decimals
+	FillPercentage float64 `json:"fill_percentage"`
+	// ExpiredBatches and ExpiredQuantity are the available batches with stock past their due date
+	ExpiredBatches  int `json:"expired_batches"`
+	ExpiredQuantity int `json:"expired_quantity"`
+	// Expiring are the available batches not expired yet that expire within each window of the filter
+	Expiring []ExpiryWindow `json:"expiring"`
+}
+
+// ExpiryWindow counts the batches of a section that expire in the next Days days
+type ExpiryWindow struct {
+	Days     int `json:"days"`
+	Batches  int `json:"batches"`
+	Quantity int `json:"quantity"`
+}
+
+// SectionReportFilter narrows the products report, the zero values do not filter
+type SectionReportFilter struct {
+	IDs           []int
+	WarehouseID   int
+	ProductTypeID int
+	// Windows are the expiry windows in days, 7 and 30 by default
+	Windows []int
+	// At is the time the due dates are compared with, now by default
+	At time.Time
 }
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// SectionReadingRequest is a temperature reading sent by a sensor
+type SectionReadingRequest struct {
+	Temperature *float64  `json:"temperature" validate:"required"`
+	RecordedAt  time.Time `json:"recorded_at" validate:"required"`
+}
+
+// SectionReadingBatch is the body of POST /v1/sections/{id}/readings, a single reading or an array of them
+type SectionReadingBatch []SectionReadingRequest
+
+// UnmarshalJSON accepts a reading object or an array of readings, the unknown fields are rejected
+func (b *SectionReadingBatch) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var many []SectionReadingRequest
+		if err := dec.Decode(&many); err != nil {
+			return err
+		}
+		*b = many
+		return nil
+	}
+	var one SectionReadingRequest
+	if err := dec.Decode(&one); err != nil {
+		return err
+	}
+	*b = SectionReadingBatch{one}
+	return nil
+}
+
+// SectionReading is a stored temperature reading of a section
+type SectionReading struct {
+	ID          int       `json:"id"`
+	SectionID   int       `json:"section_id"`
+	Temperature float64   `json:"temperature"`
+	RecordedAt  time.Time `json:"recorded_at"`
+}
+
+// SectionReadingRollup summarizes the readings of a section in an hour
+type SectionReadingRollup struct {
+	SectionID      int       `json:"section_id"`
+	BucketStart    time.Time `json:"bucket_start"`
+	MinTemperature float64   `json:"min_temperature"`
+	MaxTemperature float64   `json:"max_temperature"`
+	AvgTemperature float64   `json:"avg_temperature"`
+	ReadingsCount  int       `json:"readings_count"`
+}
+
+// Types of the section alerts
+const (
+	AlertBelowMinimum       = "below_minimum"
+	AlertOutsideProductType = "outside_product_type"
+)
+
+// SectionAlert is opened by the first reading out of range and closed by the first reading back in range
+type SectionAlert struct {
+	ID        int    `json:"id"`
+	SectionID int    `json:"section_id"`
+	Type      string `json:"type"`
+	// Temperature is the reading that opened the alert and Threshold the limit it crossed
+	Temperature float64    `json:"temperature"`
+	Threshold   float64    `json:"threshold"`
+	OpenedAt    time.Time  `json:"opened_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+}
+
+// SectionReadingsResult is the outcome of ingesting a batch of readings
+type SectionReadingsResult struct {
+	Accepted           int            `json:"accepted"`
+	CurrentTemperature float64        `json:"current_temperature"`
+	AlertsOpened       []SectionAlert `json:"alerts_opened"`
+	AlertsClosed       []int          `json:"alerts_closed"`
+}
+
+// SectionReadingRange narrows the readings history of a section
+type SectionReadingRange struct {
+	From time.Time
+	To   time.Time
+}
@@ -0,0 +1,42 @@
+package models
+
+// SlottingRequest is the batch a section is recommended for, WarehouseID 0 looks in every warehouse
+type SlottingRequest struct {
+	ProductID   int
+	Quantity    int
+	WarehouseID int
+}
+
+// SlottingProduct is the product of the batch to place
+type SlottingProduct struct {
+	ID             int
+	ProductTypeID  int
+	RecomFreezTemp float64
+}
+
+// SlottingSection is a section that stores the product type of the batch and has room for it
+type SlottingSection struct {
+	SectionID          int
+	SectionNumber      int
+	WarehouseID        int
+	CurrentTemperature float64
+	MinimumTemperature float64
+	CurrentCapacity    int
+	MaximumCapacity    int
+	// ProductBatches is the number of batches of the same product with units in the section
+	ProductBatches int
+}
+
+// SectionRecommendation is a ranked candidate section for a batch, Reasons explain its Score
+type SectionRecommendation struct {
+	Rank          int `json:"rank"`
+	SectionID     int `json:"section_id"`
+	SectionNumber int `json:"section_number"`
+	WarehouseID   int `json:"warehouse_id"`
+	// Score goes from 0 to 100, higher is better
+	Score float64 `json:"score"`
+	// RemainingCapacity is the room left in the section once the batch is placed
+	RemainingCapacity int      `json:"remaining_capacity"`
+	ProductBatches    int      `json:"product_batches"`
+	Reasons           []string `json:"reasons"`
+}
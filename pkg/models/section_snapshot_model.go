@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Sources of the section snapshots
+const (
+	SnapshotPeriodic = "periodic"
+	SnapshotUpdate   = "update"
+)
+
+// SectionHistoryQuery narrows the snapshot history of a section, the snapshots are grouped in buckets of Interval
+type SectionHistoryQuery struct {
+	From     time.Time
+	To       time.Time
+	Interval time.Duration
+}
+
+// SectionHistoryBucket aggregates the snapshots of a section taken in [BucketStart, BucketStart + interval)
+type SectionHistoryBucket struct {
+	SectionID   int       `json:"section_id"`
+	BucketStart time.Time `json:"bucket_start"`
+	Snapshots   int       `json:"snapshots"`
+	// MaximumCapacity is the largest maximum capacity the section had in the bucket
+	MaximumCapacity int     `json:"maximum_capacity"`
+	MinCapacity     int     `json:"min_capacity"`
+	AvgCapacity     float64 `json:"avg_capacity"`
+	MaxCapacity     int     `json:"max_capacity"`
+	MinTemperature  float64 `json:"min_temperature"`
+	AvgTemperature  float64 `json:"avg_temperature"`
+	MaxTemperature  float64 `json:"max_temperature"`
+}
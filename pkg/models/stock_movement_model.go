@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Types of the stock movements
+const (
+	MovementReceipt    = "receipt"
+	MovementPick       = "pick"
+	MovementTransfer   = "transfer"
+	MovementAdjustment = "adjustment"
+	MovementWriteOff   = "write_off"
+)
+
+// Documents referenced by the stock movements
+const (
+	ReferenceInboundOrder  = "inbound_order"
+	ReferencePurchaseOrder = "purchase_order"
+	ReferenceTransfer      = "transfer"
+)
+
+// StockMovement is an entry of the stock ledger, Quantity is positive when units enter the batch and the
+// section and negative when they leave
+type StockMovement struct {
+	ID             int       `json:"id"`
+	Type           string    `json:"type"`
+	ProductBatchID int       `json:"product_batch_id"`
+	SectionID      int       `json:"section_id"`
+	Quantity       int       `json:"quantity"`
+	ReferenceType  string    `json:"reference_type,omitempty"`
+	ReferenceID    int       `json:"reference_id,omitempty"`
+	EmployeeID     int       `json:"employee_id,omitempty"`
+	Reason         string    `json:"reason,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// StockAdjustmentRequest is the body of a manual adjustment, Quantity is the signed change of the batch
+type StockAdjustmentRequest struct {
+	Type       string `json:"type" validate:"required,oneof=adjustment write_off"`
+	Quantity   int    `json:"quantity" validate:"required"`
+	Reason     string `json:"reason" validate:"required,max=255"`
+	EmployeeID int    `json:"employee_id" validate:"required,gt=0"`
+}
+
+// StockLedger is the movement history of a product batch with its stored and derived balances
+type StockLedger struct {
+	ProductBatchID  int             `json:"product_batch_id"`
+	CurrentQuantity int             `json:"current_quantity"`
+	Balance         int             `json:"balance"`
+	Movements       []StockMovement `json:"movements"`
+}
+
+// Entities checked by the stock reconciliation
+const (
+	DiscrepancyBatch   = "batch"
+	DiscrepancySection = "section"
+)
+
+// StockDiscrepancy is a batch or section whose stored counter disagrees with the ledger
+type StockDiscrepancy struct {
+	Entity     string    `json:"entity"`
+	EntityID   int       `json:"entity_id"`
+	Stored     int       `json:"stored"`
+	Ledger     int       `json:"ledger"`
+	DetectedAt time.Time `json:"detected_at"`
+}
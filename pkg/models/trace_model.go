@@ -0,0 +1,138 @@
+package models
+
+import "time"
+
+// TraceSeller is the seller of a traced product, the contact details are the ones a recall needs
+type TraceSeller struct {
+	ID          int    `json:"id"`
+	CompanyName string `json:"company_name"`
+	Telephone   string `json:"telephone"`
+}
+
+// TraceProduct is the product of a traced batch
+type TraceProduct struct {
+	ID          int    `json:"id"`
+	ProductCode string `json:"product_code"`
+	Description string `json:"description"`
+}
+
+// TraceBatch is a traced batch
+type TraceBatch struct {
+	ID          int       `json:"id"`
+	BatchNumber int       `json:"batch_number"`
+	Status      string    `json:"status"`
+	DueDate     time.Time `json:"due_date"`
+	SectionID   int       `json:"section_id"`
+}
+
+// TraceEmployee is the employee that received an inbound order
+type TraceEmployee struct {
+	ID           int    `json:"id"`
+	CardNumberID string `json:"card_number_id"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+}
+
+// TraceWarehouse is the warehouse an inbound order was received in
+type TraceWarehouse struct {
+	ID            int    `json:"id"`
+	WarehouseCode string `json:"warehouse_code"`
+}
+
+// TraceInbound is an inbound order that received a traced batch
+type TraceInbound struct {
+	ID          int            `json:"id"`
+	OrderNumber string         `json:"order_number"`
+	OrderDate   string         `json:"order_date"`
+	Employee    TraceEmployee  `json:"employee"`
+	Warehouse   TraceWarehouse `json:"warehouse"`
+}
+
+// TraceBuyer is a buyer that received units of a traced batch
+type TraceBuyer struct {
+	ID           int    `json:"id"`
+	CardNumberID string `json:"card_number_id"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+}
+
+// TraceDelivery is the quantity of a traced batch allocated to a purchase order line
+type TraceDelivery struct {
+	PurchaseOrderID int        `json:"purchase_order_id"`
+	OrderNumber     string     `json:"order_number"`
+	OrderDate       Date       `json:"order_date"`
+	OrderDetailID   int        `json:"order_detail_id"`
+	ProductBatchID  int        `json:"product_batch_id"`
+	Quantity        int        `json:"quantity"`
+	Buyer           TraceBuyer `json:"buyer"`
+}
+
+// BatchOrigin is where a batch came from: its seller and product and the inbound orders that received it. A batch
+// split by a transfer has no inbound order of its own, SplitFrom is its source and the inbound orders are the
+// ones of the first batch of the chain
+type BatchOrigin struct {
+	Seller        *TraceSeller   `json:"seller"`
+	Product       TraceProduct   `json:"product"`
+	Batch         TraceBatch     `json:"batch"`
+	SplitFrom     int            `json:"split_from,omitempty"`
+	InboundOrders []TraceInbound `json:"inbound_orders"`
+}
+
+// BatchTrace is the trace of GET /v1/trace/batch/{id}, the origin of the batch and the purchase orders served by
+// it or by the batches split from it
+type BatchTrace struct {
+	BatchOrigin
+	// Splits are the batches split from the batch by transfers, directly or through other splits
+	Splits     []int           `json:"splits"`
+	Deliveries []TraceDelivery `json:"purchase_orders"`
+}
+
+// TraceAllocation is the quantity of a purchase order line served by a batch with its origin
+type TraceAllocation struct {
+	Quantity int         `json:"quantity"`
+	Origin   BatchOrigin `json:"origin"`
+}
+
+// TraceLine is a purchase order line with the batches that served it
+type TraceLine struct {
+	OrderDetailID       int               `json:"order_detail_id"`
+	ProductRecordID     int               `json:"product_record_id"`
+	Quantity            int               `json:"quantity"`
+	BackorderedQuantity int               `json:"backordered_quantity"`
+	Allocations         []TraceAllocation `json:"allocations"`
+}
+
+// PurchaseOrderTrace is the trace of GET /v1/trace/purchaseOrder/{id}, the origin of every batch that served the order
+type PurchaseOrderTrace struct {
+	PurchaseOrderID int         `json:"purchase_order_id"`
+	OrderNumber     string      `json:"order_number"`
+	OrderDate       Date        `json:"order_date"`
+	Buyer           TraceBuyer  `json:"buyer"`
+	Lines           []TraceLine `json:"lines"`
+}
+
+// RecallRequest is the body of POST /v1/recalls
+type RecallRequest struct {
+	ProductBatchID int    `json:"product_batch_id" validate:"required,gt=0"`
+	Reason         string `json:"reason" validate:"required,max=255"`
+	EmployeeID     int    `json:"employee_id" validate:"required,gt=0"`
+}
+
+// RecallBuyer is a buyer to contact in a recall with the purchase orders and units of the recalled batches it received
+type RecallBuyer struct {
+	TraceBuyer
+	PurchaseOrderIDs []int `json:"purchase_order_ids"`
+	Quantity         int   `json:"quantity"`
+}
+
+// Recall rejects a batch and every batch of its split chain, Batches are the affected batches and Buyers the buyers
+// that received units of them
+type Recall struct {
+	ID             int           `json:"id"`
+	ProductBatchID int           `json:"product_batch_id"`
+	Reason         string        `json:"reason"`
+	EmployeeID     int           `json:"employee_id"`
+	CreatedAt      time.Time     `json:"created_at"`
+	Batches        []int         `json:"product_batch_ids"`
+	Buyers         []RecallBuyer `json:"buyers"`
+}
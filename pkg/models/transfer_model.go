@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// TransferRequest is the body of a transfer, Quantity 0 moves the whole batch
+type TransferRequest struct {
+	ProductBatchID int `json:"product_batch_id" validate:"required,gt=0"`
+	ToSectionID    int `json:"to_section_id" validate:"required,gt=0"`
+	Quantity       int `json:"quantity" validate:"gte=0"`
+	EmployeeID     int `json:"employee_id" validate:"required,gt=0"`
+}
+
+// Transfer is a movement of stock from the section of a product batch to another section
+type Transfer struct {
+	ID             int `json:"id"`
+	ProductBatchID int `json:"product_batch_id"`
+	// DestinationBatchID is the batch holding the moved units, it is ProductBatchID when the whole batch moved
+	// and a new batch when the batch was split
+	DestinationBatchID int       `json:"destination_batch_id"`
+	FromSectionID      int       `json:"from_section_id"`
+	ToSectionID        int       `json:"to_section_id"`
+	Quantity           int       `json:"quantity"`
+	EmployeeID         int       `json:"employee_id"`
+	CreatedAt          time.Time `json:"created_at"`
+}
@@ -8,4 +8,61 @@ type Warehouse struct {
 	Telephone          string `json:"Telephone" validate:"required"`
 	MinimumCapacity    int    `json:"Minimum_Capacity" validate:"required,min=1"`
 	MinimumTemperature int    `json:"Minimum_Temperature" validate:"required,min=0"`
+	LocalityID         int    `json:"Locality_ID" validate:"required,min=1"`
+	// Latitude and Longitude are the coordinates of the warehouse, nil when they are unknown
+	Latitude  *float64 `json:"Latitude,omitempty" validate:"omitempty,latitude"`
+	Longitude *float64 `json:"Longitude,omitempty" validate:"omitempty,longitude"`
+}
+
+// WarehouseCapacityReport aggregates the capacity of the sections of a warehouse
+type WarehouseCapacityReport struct {
+	WarehouseID   int    `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+	// MinimumCapacity is the minimum capacity required by the warehouse
+	MinimumCapacity int `json:"minimum_capacity"`
+	SectionsCount   int `json:"sections_count"`
+	// CurrentCapacity and MaximumCapacity are the sums of the capacities of the sections
+	CurrentCapacity int `json:"current_capacity"`
+	MaximumCapacity int `json:"maximum_capacity"`
+	// UtilizationPercentage is CurrentCapacity over MaximumCapacity, 0 when the warehouse has no sections
+	UtilizationPercentage float64 `json:"utilization_percentage"`
+	// SectionsBelowMinimum counts the sections whose current capacity is under their minimum capacity
+	SectionsBelowMinimum int `json:"sections_below_minimum"`
+	// MeetsMinimumCapacity tells if CurrentCapacity reaches the MinimumCapacity of the warehouse
+	MeetsMinimumCapacity bool `json:"meets_minimum_capacity"`
+}
+
+// WarehouseSection is a section of a warehouse with the number of its product batches that still have stock
+type WarehouseSection struct {
+	Section
+	ActiveBatches int `json:"active_batches"`
+}
+
+// WarehouseSectionFilter narrows the sections of a warehouse
+type WarehouseSectionFilter struct {
+	// ProductTypeID keeps the sections of the product type when it is not nil
+	ProductTypeID *int
+	// BelowMinimum keeps the sections whose current capacity is under their minimum capacity
+	BelowMinimum bool
+}
+
+// WarehouseOverview is a warehouse with its sections, employees and latest inbound orders
+type WarehouseOverview struct {
+	Warehouse           Warehouse          `json:"warehouse"`
+	Sections            []WarehouseSection `json:"sections"`
+	Employees           []Employee         `json:"employees"`
+	RecentInboundOrders []InboundOrders    `json:"recent_inbound_orders"`
+}
+
+// WarehouseDistance is a warehouse that stocks a product with its distance to a locality
+type WarehouseDistance struct {
+	WarehouseID   int     `json:"warehouse_id"`
+	WarehouseCode string  `json:"warehouse_code"`
+	Address       string  `json:"address"`
+	LocalityID    int     `json:"locality_id"`
+	Latitude      float64 `json:"latitude"`
+	Longitude     float64 `json:"longitude"`
+	// AvailableQuantity is the current quantity of the non-expired batches of the product in the warehouse
+	AvailableQuantity int     `json:"available_quantity"`
+	DistanceKm        float64 `json:"distance_km"`
 }
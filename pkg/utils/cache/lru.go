@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config holds the settings of a single entity cache
+type Config struct {
+	// Enabled turns the cache on for the entity
+	Enabled bool
+	// Size is the maximum number of entries kept in memory
+	Size int
+	// TTL is how long an entry is served before it is loaded again
+	TTL time.Duration
+}
+
+// Stats is a snapshot of the cache counters
+type Stats struct {
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	Size      int     `json:"size"`
+	HitRatio  float64 `json:"hit_ratio"`
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// LRU is a size bounded least recently used cache whose entries expire after a TTL
+type LRU[K comparable, V any] struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	ll    *list.List
+	items map[K]*list.Element
+	// now is replaced in tests to control expiration
+	now func() time.Time
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// New creates a new LRU cache, a size lower than 1 keeps a single entry and a zero ttl never expires entries
+func New[K comparable, V any](size int, ttl time.Duration) *LRU[K, V] {
+	if size < 1 {
+		size = 1
+	}
+	return &LRU[K, V]{
+		size:  size,
+		ttl:   ttl,
+		ll:    list.New(),
+		items: make(map[K]*list.Element),
+		now:   time.Now,
+	}
+}
+
+// Get returns the value stored for the key if present and not expired
+func (c *LRU[K, V]) Get(key K) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, found := c.items[key]
+	if !found {
+		c.misses.Add(1)
+		return
+	}
+	ent := el.Value.(*entry[K, V])
+	if c.ttl > 0 && c.now().After(ent.expiresAt) {
+		c.removeElement(el)
+		c.misses.Add(1)
+		return
+	}
+	c.ll.MoveToFront(el)
+	c.hits.Add(1)
+	return ent.value, true
+}
+
+// Set stores the value for the key, evicting the least recently used entry when full
+func (c *LRU[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiresAt := c.now().Add(c.ttl)
+	if el, found := c.items[key]; found {
+		ent := el.Value.(*entry[K, V])
+		ent.value = value
+		ent.expiresAt = expiresAt
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+	if c.ll.Len() > c.size {
+		c.removeElement(c.ll.Back())
+		c.evictions.Add(1)
+	}
+}
+
+// Delete removes the key from the cache
+func (c *LRU[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, found := c.items[key]; found {
+		c.removeElement(el)
+	}
+}
+
+// Purge removes every entry from the cache
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	c.items = make(map[K]*list.Element)
+}
+
+// Len returns the number of entries currently stored
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Stats returns the hit, miss and eviction counters of the cache
+func (c *LRU[K, V]) Stats() Stats {
+	st := Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Size:      c.Len(),
+	}
+	if total := st.Hits + st.Misses; total > 0 {
+		st.HitRatio = float64(st.Hits) / float64(total)
+	}
+	return st
+}
+
+func (c *LRU[K, V]) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*entry[K, V]).key)
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU_GetSet(t *testing.T) {
+	c := New[int, string](2, time.Minute)
+
+	_, ok := c.Get(1)
+	require.False(t, ok)
+
+	c.Set(1, "one")
+	c.Set(2, "two")
+	v, ok := c.Get(1)
+	require.True(t, ok)
+	require.Equal(t, "one", v)
+
+	// 2 is the least recently used entry, so it is evicted
+	c.Set(3, "three")
+	_, ok = c.Get(2)
+	require.False(t, ok)
+	_, ok = c.Get(3)
+	require.True(t, ok)
+
+	st := c.Stats()
+	require.Equal(t, uint64(2), st.Hits)
+	require.Equal(t, uint64(2), st.Misses)
+	require.Equal(t, uint64(1), st.Evictions)
+	require.Equal(t, 2, st.Size)
+	require.Equal(t, 0.5, st.HitRatio)
+}
+
+func TestLRU_Expiration(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New[int, string](10, time.Second)
+	c.now = func() time.Time { return now }
+
+	c.Set(1, "one")
+	_, ok := c.Get(1)
+	require.True(t, ok)
+
+	now = now.Add(2 * time.Second)
+	_, ok = c.Get(1)
+	require.False(t, ok)
+	require.Equal(t, 0, c.Len())
+}
+
+func TestLRU_DeleteAndPurge(t *testing.T) {
+	c := New[int, string](10, 0)
+	c.Set(1, "one")
+	c.Set(2, "two")
+
+	c.Delete(1)
+	_, ok := c.Get(1)
+	require.False(t, ok)
+
+	c.Purge()
+	require.Equal(t, 0, c.Len())
+}
+
+func TestRegistry_Stats(t *testing.T) {
+	reg := NewRegistry()
+	c := New[int, string](10, 0)
+	c.Set(1, "one")
+	c.Get(1)
+	reg.Register("products", c)
+
+	stats := reg.Stats()
+	require.Len(t, stats, 1)
+	require.Equal(t, uint64(1), stats["products"].Hits)
+}
@@ -0,0 +1,37 @@
+package cache
+
+import "sync"
+
+// StatsProvider is implemented by every cache that exposes metrics
+type StatsProvider interface {
+	Stats() Stats
+}
+
+// Registry keeps the caches of the application by entity name so their metrics can be exposed
+type Registry struct {
+	mu     sync.RWMutex
+	caches map[string]StatsProvider
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{caches: make(map[string]StatsProvider)}
+}
+
+// Register adds a cache under the given name
+func (r *Registry) Register(name string, c StatsProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.caches[name] = c
+}
+
+// Stats returns the metrics of every registered cache
+func (r *Registry) Stats() map[string]Stats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make(map[string]Stats, len(r.caches))
+	for name, c := range r.caches {
+		result[name] = c.Stats()
+	}
+	return result
+}
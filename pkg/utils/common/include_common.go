@@ -0,0 +1,70 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// InClause returns the placeholders and args of an IN (...) condition over the ids
+func InClause(ids []int) (string, []any) {
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return strings.TrimSuffix(strings.Repeat("?,", len(ids)), ","), args
+}
+
+// SplitQueryList splits a comma separated query parameter, dropping blanks and duplicates
+func SplitQueryList(raw string) (values []string) {
+	seen := make(map[string]bool)
+	for _, v := range strings.Split(raw, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		values = append(values, v)
+	}
+	return values
+}
+
+// ToMaps converts a slice of models to their JSON representation, numbers are kept as json.Number
+func ToMaps[T any](items []T) ([]map[string]any, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var result []map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// MapInt reads an integer field of a JSON object, it returns false when the field is missing or not an integer
+func MapInt(item map[string]any, field string) (int, bool) {
+	switch v := item[field].(type) {
+	case json.Number:
+		n, err := v.Int64()
+		return int(n), err == nil
+	case float64:
+		return int(v), v == float64(int(v))
+	case int:
+		return v, true
+	}
+	return 0, false
+}
+
+// SelectFields keeps only the given fields of a JSON object plus the keep ones, e.g. the included relations
+func SelectFields(item map[string]any, fields []string, keep []string) map[string]any {
+	result := make(map[string]any, len(fields)+len(keep))
+	for _, f := range append(fields, keep...) {
+		if v, ok := item[f]; ok {
+			result[f] = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,22 @@
+package common
+
+import (
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+)
+
+// PatchProductType returns the product type with the fields of the patch that are set
+func PatchProductType(pt mod.ProductType, patch mod.ProductTypePatch) mod.ProductType {
+	if patch.Name != nil {
+		pt.Name = *patch.Name
+	}
+	if patch.MinimumTemperature != nil {
+		pt.MinimumTemperature = patch.MinimumTemperature
+	}
+	if patch.MaximumTemperature != nil {
+		pt.MaximumTemperature = patch.MaximumTemperature
+	}
+	if patch.HazardClass != nil {
+		pt.HazardClass = *patch.HazardClass
+	}
+	return pt
+}
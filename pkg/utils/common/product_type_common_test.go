@@ -0,0 +1,37 @@
+package common_test
+
+import (
+	"testing"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchProductType(t *testing.T) {
+	minimum, maximum, warmer := -18.0, -12.0, 4.0
+	name, hazard := "Chilled", "none"
+	base := mod.ProductType{ID: 1, Name: "Frozen", MinimumTemperature: &minimum, MaximumTemperature: &maximum, HazardClass: "none"}
+
+	tests := []struct {
+		name  string
+		patch mod.ProductTypePatch
+		want  mod.ProductType
+	}{
+		{
+			name:  "#1 Nothing to Patch - empty patch",
+			patch: mod.ProductTypePatch{},
+			want:  base,
+		},
+		{
+			name:  "#2 Patch - name, maximum temperature and hazard class",
+			patch: mod.ProductTypePatch{Name: &name, MaximumTemperature: &warmer, HazardClass: &hazard},
+			want:  mod.ProductType{ID: 1, Name: "Chilled", MinimumTemperature: &minimum, MaximumTemperature: &warmer, HazardClass: "none"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, common.PatchProductType(base, tt.patch))
+		})
+	}
+}
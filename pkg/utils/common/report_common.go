@@ -0,0 +1,62 @@
+package common
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ReportCSV writes the rows as CSV, the header is the sorted union of the row keys and nested values are
+// written as JSON
+func ReportCSV(rows []map[string]any) ([]byte, error) {
+	seen := make(map[string]bool)
+	var header []string
+	for _, row := range rows {
+		for k := range row {
+			if !seen[k] {
+				seen[k] = true
+				header = append(header, k)
+			}
+		}
+	}
+	sort.Strings(header)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(header))
+	for _, row := range rows {
+		for i, k := range header {
+			cell, err := csvCell(row[k])
+			if err != nil {
+				return nil, err
+			}
+			record[i] = cell
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// csvCell formats a JSON value as a CSV cell
+func csvCell(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool, float64, int:
+		return fmt.Sprint(v), nil
+	}
+	data, err := json.Marshal(v)
+	return string(data), err
+}
@@ -0,0 +1,23 @@
+package common_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportCSV(t *testing.T) {
+	rows := []map[string]any{
+		{"id": json.Number("1"), "name": "Fresh, Inc", "active": true},
+		{"id": json.Number("2"), "tags": []any{"a", "b"}, "address": nil},
+	}
+
+	data, err := common.ReportCSV(rows)
+
+	require.NoError(t, err)
+	require.Equal(t, "active,address,id,name,tags\n"+
+		"true,,1,\"Fresh, Inc\",\n"+
+		",,2,,\"[\"\"a\"\",\"\"b\"\"]\"\n", string(data))
+}
@@ -0,0 +1,174 @@
+package common
+
+import (
+	"sort"
+	"strings"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// ParseSearchTypes parses the comma separated types of a search, an empty value means every type
+func ParseSearchTypes(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return mod.SearchTypes, nil
+	}
+	var types []string
+	seen := make(map[string]bool)
+	for _, t := range strings.Split(raw, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		valid := false
+		for _, st := range mod.SearchTypes {
+			if t == st {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, e.ErrSearchTypeInvalid
+		}
+		seen[t] = true
+		types = append(types, t)
+	}
+	if len(types) == 0 {
+		return nil, e.ErrSearchTypeInvalid
+	}
+	return types, nil
+}
+
+// SearchTerms returns the distinct lower case words of a search
+func SearchTerms(q string) (terms []string) {
+	seen := make(map[string]bool)
+	for _, t := range strings.Fields(strings.ToLower(q)) {
+		t = strings.Trim(t, `+-<>()~*"@`)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		terms = append(terms, t)
+	}
+	return terms
+}
+
+// FulltextQuery builds a MySQL boolean mode query where every term also matches as a prefix
+func FulltextQuery(terms []string) string {
+	words := make([]string, 0, len(terms))
+	for _, t := range terms {
+		t = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`+-<>()~*"@`, r) {
+				return -1
+			}
+			return r
+		}, t)
+		if t != "" {
+			words = append(words, t+"*")
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// likeEscaper escapes the LIKE wildcards of a term
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// LikePattern returns a LIKE pattern that finds the term anywhere in a column
+func LikePattern(term string) string {
+	return "%" + likeEscaper.Replace(term) + "%"
+}
+
+// PrefixPattern returns a LIKE pattern that finds the columns starting with the term
+func PrefixPattern(term string) string {
+	return likeEscaper.Replace(term) + "%"
+}
+
+// scoreField scores how well a value matches a term: 3 for the whole value, 2 for the start of a word, 1 for anywhere else
+func scoreField(value, term string) float64 {
+	value = strings.ToLower(value)
+	switch {
+	case value == term:
+		return 3
+	case strings.HasPrefix(value, term):
+		return 2
+	}
+	for _, word := range strings.Fields(value) {
+		if strings.HasPrefix(word, term) {
+			return 2
+		}
+	}
+	if strings.Contains(value, term) {
+		return 1
+	}
+	return 0
+}
+
+// Highlight wraps every occurrence of the terms in the value with <em> tags, ignoring case
+func Highlight(value string, terms []string) string {
+	lower := strings.ToLower(value)
+	marked := make([]bool, len(lower))
+	for _, t := range terms {
+		for start := 0; t != ""; {
+			i := strings.Index(lower[start:], t)
+			if i < 0 {
+				break
+			}
+			for j := start + i; j < start+i+len(t); j++ {
+				marked[j] = true
+			}
+			start += i + len(t)
+		}
+	}
+	// lower casing can change the length of some runes, in that case the value is returned as is
+	if len(lower) != len(value) {
+		return value
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if marked[i] && (i == 0 || !marked[i-1]) {
+			b.WriteString("<em>")
+		}
+		b.WriteByte(value[i])
+		if marked[i] && (i == len(value)-1 || !marked[i+1]) {
+			b.WriteString("</em>")
+		}
+	}
+	return b.String()
+}
+
+// RankSearchResults scores and highlights the results, drops the ones that do not match any term and
+// returns the best ones first, up to limit. Scores are computed here so every entity type and both the
+// FULLTEXT and LIKE searches are ranked the same way
+func RankSearchResults(terms []string, results []mod.SearchResult, limit int) []mod.SearchResult {
+	ranked := make([]mod.SearchResult, 0, len(results))
+	for _, res := range results {
+		res.Score = 0
+		res.Highlights = make(map[string]string)
+		for field, value := range res.Fields {
+			var score float64
+			for _, t := range terms {
+				score += scoreField(value, t)
+			}
+			if score > 0 {
+				res.Score += score
+				res.Highlights[field] = Highlight(value, terms)
+			}
+		}
+		if res.Score > 0 {
+			ranked = append(ranked, res)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		if ranked[i].Type != ranked[j].Type {
+			return ranked[i].Type < ranked[j].Type
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
@@ -0,0 +1,65 @@
+package common_test
+
+import (
+	"testing"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/common"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearchTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []string
+		wantErr error
+	}{
+		{name: "empty means every type", raw: "", want: mod.SearchTypes},
+		{name: "trims and removes duplicates", raw: " Products,sellers,products", want: []string{"products", "sellers"}},
+		{name: "unknown type", raw: "products,orders", wantErr: e.ErrSearchTypeInvalid},
+		{name: "only commas", raw: ",,", wantErr: e.ErrSearchTypeInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := common.ParseSearchTypes(tt.raw)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSearchQueries(t *testing.T) {
+	terms := common.SearchTerms(`  Frozen +PEAS "frozen" `)
+	require.Equal(t, []string{"frozen", "peas"}, terms)
+	require.Equal(t, "frozen* peas*", common.FulltextQuery(terms))
+	require.Equal(t, `%50\%\_off%`, common.LikePattern("50%_off"))
+	require.Equal(t, `50\%\_off%`, common.PrefixPattern("50%_off"))
+}
+
+func TestHighlight(t *testing.T) {
+	require.Equal(t, "<em>Froz</em>en <em>Peas</em> and <em>peas</em>", common.Highlight("Frozen Peas and peas", []string{"peas", "froz"}))
+	require.Equal(t, "<em>abcd</em>", common.Highlight("abcd", []string{"abc", "bcd"}))
+	require.Equal(t, "nothing", common.Highlight("nothing", []string{"peas"}))
+}
+
+func TestRankSearchResults(t *testing.T) {
+	results := []mod.SearchResult{
+		{Type: "products", ID: 1, Title: "P001", Fields: map[string]string{"product_code": "P001", "description": "Green peas"}},
+		{Type: "sellers", ID: 7, Title: "Peas Co", Fields: map[string]string{"company_name": "Peas", "cid": "77"}},
+		{Type: "carries", ID: 3, Title: "Chickpeas SA", Fields: map[string]string{"company_name": "Chickpeas SA"}},
+		{Type: "buyers", ID: 2, Title: "John Doe", Fields: map[string]string{"first_name": "John"}},
+	}
+
+	ranked := common.RankSearchResults([]string{"peas"}, results, 10)
+	require.Len(t, ranked, 3)
+	require.Equal(t, "sellers", ranked[0].Type)
+	require.Equal(t, float64(3), ranked[0].Score)
+	require.Equal(t, "products", ranked[1].Type)
+	require.Equal(t, map[string]string{"description": "Green <em>peas</em>"}, ranked[1].Highlights)
+	require.Equal(t, "carries", ranked[2].Type)
+	require.Equal(t, float64(1), ranked[2].Score)
+
+	require.Len(t, common.RankSearchResults([]string{"peas"}, results, 1), 1)
+}
@@ -54,37 +54,65 @@ func PatchSection(request models.SectionPatch) map[string]interface{} {
 	return fields
 }
 
-func GetQueryReport(ids []int) (string, []interface{}) {
-	// Common SQL
-	base := `
-        SELECT 
-            s.id, 
-            s.section_number, 
-            COUNT(p.id) as product_count
-        FROM sections s
-        LEFT JOIN products p ON p.id = s.product_type_id
-    `
-	whereClause := ""
-	var args []interface{}
+// reportAvailable is the predicate of the batches that are not on hold in the products report
+const reportAvailable = "pb.status IN ('available', 'released')"
+
+// GetQueryReport builds the products report query of the filter. The batches without units are left out, a batch
+// is expired when its due date is before f.At and expiring in a window when it is due in [f.At, f.At + days).
+// The columns are the section, its warehouse, product type and maximum capacity, the distinct products and batches
+// available, the units of every batch, the batches and units on hold, the expired batches and units and then the
+// batches and units of each window. The held batches still occupy the section, so they only count in the units
+func GetQueryReport(f models.SectionReportFilter) (string, []interface{}) {
+	columns := []string{
+		"s.id", "s.section_number", "s.warehouse_id", "s.product_type_id", "s.maximum_capacity",
+		"COUNT(DISTINCT CASE WHEN " + reportAvailable + " THEN pb.product_id END)",
+		"COALESCE(SUM(CASE WHEN " + reportAvailable + " THEN 1 ELSE 0 END), 0)",
+		"COALESCE(SUM(pb.current_quantity), 0)",
+		"COALESCE(SUM(CASE WHEN pb.id IS NOT NULL AND NOT " + reportAvailable + " THEN 1 ELSE 0 END), 0)",
+		"COALESCE(SUM(CASE WHEN pb.id IS NOT NULL AND NOT " + reportAvailable + " THEN pb.current_quantity ELSE 0 END), 0)",
+		"COALESCE(SUM(CASE WHEN " + reportAvailable + " AND pb.due_date < ? THEN 1 ELSE 0 END), 0)",
+		"COALESCE(SUM(CASE WHEN " + reportAvailable + " AND pb.due_date < ? THEN pb.current_quantity ELSE 0 END), 0)",
+	}
+	args := []interface{}{f.At, f.At}
+	for _, days := range f.Windows {
+		until := f.At.AddDate(0, 0, days)
+		columns = append(columns,
+			"COALESCE(SUM(CASE WHEN "+reportAvailable+" AND pb.due_date >= ? AND pb.due_date < ? THEN 1 ELSE 0 END), 0)",
+			"COALESCE(SUM(CASE WHEN "+reportAvailable+" AND pb.due_date >= ? AND pb.due_date < ? THEN pb.current_quantity ELSE 0 END), 0)")
+		args = append(args, f.At, until, f.At, until)
+	}
 
+	var where []string
 	// If ID list is provided, filter, else show all
-	if len(ids) > 0 {
-		placeholders := make([]string, len(ids))
-		for i := range ids {
+	if len(f.IDs) > 0 {
+		placeholders := make([]string, len(f.IDs))
+		for i, id := range f.IDs {
 			placeholders[i] = "?"
-		}
-		whereClause = fmt.Sprintf("WHERE s.id IN (%s)", strings.Join(placeholders, ","))
-		for _, id := range ids {
 			args = append(args, id)
 		}
+		where = append(where, fmt.Sprintf("s.id IN (%s)", strings.Join(placeholders, ",")))
+	}
+	if f.WarehouseID > 0 {
+		where = append(where, "s.warehouse_id = ?")
+		args = append(args, f.WarehouseID)
+	}
+	if f.ProductTypeID > 0 {
+		where = append(where, "s.product_type_id = ?")
+		args = append(args, f.ProductTypeID)
+	}
+	whereClause := ""
+	if len(where) > 0 {
+		whereClause = "WHERE " + strings.Join(where, " AND ")
 	}
 
 	sql := fmt.Sprintf(`
+        SELECT %s
+        FROM sections s
+        LEFT JOIN product_batches pb ON pb.section_id = s.id AND pb.current_quantity > 0
         %s
-        %s
-        GROUP BY s.id, s.section_number
+        GROUP BY s.id, s.section_number, s.warehouse_id, s.product_type_id, s.maximum_capacity
         ORDER BY s.id
-    `, base, whereClause)
+    `, strings.Join(columns, ", "), whereClause)
 	return sql, args
 }
 
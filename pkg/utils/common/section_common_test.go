@@ -4,7 +4,9 @@ import (
 	"context"
 	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
@@ -86,28 +88,35 @@ func TestPatchSection(t *testing.T) {
 }
 
 func TestGetQueryReport(t *testing.T) {
+	at := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	// boundaries are the args of the expired and the 7 days columns
+	boundaries := []interface{}{at, at, at, at.AddDate(0, 0, 7), at, at.AddDate(0, 0, 7)}
 	tests := []struct {
 		name      string
-		ids       []int
-		wantWhere string // part of SQL to match for IDs filter
+		filter    mod.SectionReportFilter
+		wantWhere string // part of SQL to match for the filters
 		wantArgs  []interface{}
 	}{
-		{"empty (all)", nil, "WHERE", nil},
-		{"one id", []int{3}, "WHERE s.id IN (?)", []interface{}{3}},
-		{"many ids", []int{2, 4, 8}, "WHERE s.id IN (?,?,?)\n", []interface{}{2, 4, 8}},
-		{"no filter", []int{}, "WHERE", nil}, // this maybe generates no WHERE clause!
+		{"one id", mod.SectionReportFilter{IDs: []int{3}}, "WHERE s.id IN (?)\n", []interface{}{3}},
+		{"many ids", mod.SectionReportFilter{IDs: []int{2, 4, 8}}, "WHERE s.id IN (?,?,?)\n", []interface{}{2, 4, 8}},
+		{"warehouse", mod.SectionReportFilter{WarehouseID: 5}, "WHERE s.warehouse_id = ?\n", []interface{}{5}},
+		{"all filters", mod.SectionReportFilter{IDs: []int{1}, WarehouseID: 5, ProductTypeID: 6},
+			"WHERE s.id IN (?) AND s.warehouse_id = ? AND s.product_type_id = ?\n", []interface{}{1, 5, 6}},
+		{"no filter", mod.SectionReportFilter{}, "", nil},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			sql, args := GetQueryReport(tc.ids)
-			if len(tc.ids) == 0 {
-				require.NotContains(t, sql, "WHERE s.id IN")
-				require.Equal(t, 0, len(args))
+			tc.filter.At = at
+			tc.filter.Windows = []int{7}
+			sql, args := GetQueryReport(tc.filter)
+			if tc.wantWhere == "" {
+				require.NotContains(t, sql, "WHERE")
 			} else {
 				require.Contains(t, sql, tc.wantWhere)
-				require.Equal(t, tc.wantArgs, args)
 			}
-			require.Contains(t, sql, "LEFT JOIN products p ON")
+			require.Equal(t, append(append([]interface{}{}, boundaries...), tc.wantArgs...), args)
+			require.Contains(t, sql, "LEFT JOIN product_batches pb ON pb.section_id = s.id")
+			require.Equal(t, 7, strings.Count(sql, "SUM(CASE WHEN"))
 		})
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/go-sql-driver/mysql"
+	"sync"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -20,6 +21,10 @@ var (
 	ErrRequestFailedBody     = errors.New("handler: failed to read body")
 	ErrRequestInternalServer = errors.New("handler: internal server error")
   ErrNothingToUpdate     = errors.New("handler: nothing to update")
+	ErrRequestContentType    = errors.New("handler: Content-Type must be application/json")
+	ErrRequestBodyTooLarge   = errors.New("handler: request body is too large")
+	ErrRequestUnknownField   = errors.New("handler: body has an unknown field")
+	ErrRequestFieldType      = errors.New("handler: body has a field with a wrong type")
 	//Query
 	ErrQueryError   = errors.New("repository: unable to execute query")
 	ErrParseError   = errors.New("repository: unable to parse row")
@@ -35,6 +40,21 @@ var (
 	SectionDeleted       = "handler: section deleted successfully"
 	SectionCreated       = "handler: section successfully created"
 	SectionUpdated       = "handler: section successfully updated"
+	JobTriggered         = "handler: job run started"
+	JobPaused            = "handler: job paused"
+	JobResumed           = "handler: job resumed"
+	ReportQueued         = "handler: report queued"
+	TransferCompleted    = "handler: transfer completed"
+	StockAdjusted        = "handler: stock adjusted"
+	ReadingsAccepted     = "handler: readings accepted"
+	ProductBatchUpdated  = "handler: product batch updated"
+	ProductBatchArchived = "handler: product batch archived"
+	ProductBatchHeld     = "handler: product batch held"
+	ProductBatchReleased = "handler: product batch released"
+	RecallCreated        = "handler: recall created"
+	ProductTypeCreated   = "handler: product type created"
+	ProductTypeUpdated   = "handler: product type updated"
+	ProductTypeDeleted   = "handler: product type deleted"
 
 	// Errores de Buyer
 	ErrBuyerRepositoryNotFound       = errors.New("repository: buyer not found")
@@ -43,6 +63,7 @@ var (
 
 	//PurchaseOrder
 	ErrPORepositoryOrderNumberDuplicated = errors.New("repository: Order number duplicated")
+	ErrPORepositoryNotFound              = errors.New("repository: purchase order not found")
 
 	//Employee
 	// ErrEmployeeRepositoryNotFound is returned when the employee is not found
@@ -73,9 +94,20 @@ var (
 	ErrEmptyDB                     = errors.New("repository: empty DB")
 	ErrSectionRepositoryNotFound   = errors.New("repository: section not found")
 	ErrSectionRepositoryDuplicated = errors.New("repository: section already exists")
+	ErrSectionReportFilterInvalid  = errors.New("handler: warehouse_id and product_type_id must be integers greater than 0")
+	ErrSectionReportWindowsInvalid = errors.New("handler: windows must be 1 to 5 comma separated days between 1 and 365")
+	ErrSectionRecommendInvalid     = errors.New("handler: product_id and quantity must be integers greater than 0, and warehouse_id when given")
 
-	ErrProductBatchNotFound   = errors.New("repository: Product Batch not found")
-	ErrProductBatchDuplicated = errors.New("repository: Product Batch already exists")
+	ErrProductBatchNotFound    = errors.New("repository: Product Batch not found")
+	ErrProductBatchDuplicated  = errors.New("repository: Product Batch already exists")
+	ErrProductBatchInUse       = errors.New("repository: Product Batch is referenced by orders, transfers or status changes, archive it first")
+	ErrProductBatchArchived    = errors.New("repository: Product Batch is archived")
+	ErrProductBatchInvalid     = errors.New("service: Product Batch update breaks its rules")
+	ErrProductBatchFilter      = errors.New("handler: product_id, section_id and warehouse_id must be integers greater than 0, due_from and due_to dates or RFC3339 times and archived true or false")
+	ErrProductBatchPatchEmpty  = errors.New("handler: current_quantity or current_temperature is required")
+	ErrProductBatchHeld        = errors.New("repository: Product Batch is on hold")
+	ErrProductBatchStatus      = errors.New("repository: Product Batch cannot change from its current status to the requested one")
+	ErrSectionCapacityExceeded = errors.New("repository: section does not have capacity for the batch")
 
 	//Seller
 	// ErrSellerRepositoryNotFound is returned when the seller is not found
@@ -92,11 +124,78 @@ var (
 	// Errores de Warehouse
 	ErrWarehouseRepositoryNotFound   = errors.New("repository: warehouse not found")
 	ErrWarehouseRepositoryDuplicated = errors.New("repository: warehouse already exists")
+	ErrWarehouseReportSortInvalid    = errors.New("handler: sort must be utilization or -utilization")
+	ErrWarehouseLocalityNotFound     = errors.New("repository: locality not found for warehouse")
+	ErrWarehouseFilterInvalid        = errors.New("handler: product_type_id must be an integer and below_minimum a boolean")
+	ErrWarehouseNearestInvalid       = errors.New("handler: locality_id and product_id must be positive integers and limit an integer between 1 and 50")
+	ErrLocalityCoordinatesMissing    = errors.New("service: the locality has no coordinates")
 
 	// Errores de Carry (Nuevos)
 	ErrCarryRepositoryNotFound         = errors.New("repository: carry not found")
 	ErrCarryRepositoryDuplicated       = errors.New("repository: carry already exists")
 	ErrCarryRepositoryLocalityNotFound = errors.New("repository: locality not found for carry")
+
+	// Errores de Transfers
+	ErrTransferNotFound        = errors.New("repository: transfer not found")
+	ErrTransferSameSection     = errors.New("repository: the batch is already in the destination section")
+	ErrTransferQuantityInvalid = errors.New("repository: quantity must be between 1 and the batch current quantity")
+	ErrTransferProductType     = errors.New("repository: destination section stores another product type")
+	ErrTransferTemperature     = errors.New("repository: destination section is colder than the batch minimum temperature")
+	ErrTransferCapacity        = errors.New("repository: destination section does not have capacity for the quantity")
+
+	// Errores de Stock
+	ErrStockInsufficient    = errors.New("repository: not enough stock for the quantity")
+	ErrStockMovementInvalid = errors.New("service: a write_off must remove units and an adjustment must change them")
+
+	// Errores de Product types
+	ErrProductTypeNotFound     = errors.New("repository: product type not found")
+	ErrProductTypeDuplicated   = errors.New("repository: product type already exists")
+	ErrProductTypeInUse        = errors.New("repository: product type is used by products or sections")
+	ErrProductTypeRangeInvalid = errors.New("service: minimum_temperature must not be above maximum_temperature")
+	ErrProductBatchSectionType = errors.New("repository: section stores another product type than the product of the batch")
+
+	// Errores de Section readings
+	ErrSectionReadingsInvalid     = errors.New("handler: readings must be 1 to 500 readings with a temperature and a recorded_at")
+	ErrSectionReadingRangeInvalid = errors.New("handler: from and to must be RFC3339 times and from must be before to")
+	ErrSectionAlertStatusInvalid  = errors.New("handler: status must be open or closed")
+
+	// Errores de Section history
+	ErrSectionHistoryInvalid       = errors.New("handler: from must be before to, interval must be between 1m and 30d and the range at most 5000 intervals")
+	ErrSectionHistoryFormatInvalid = errors.New("handler: format must be json or csv")
+
+	// Errores de Expiry alerts
+	ErrExpiringQueryInvalid = errors.New("handler: days must be an integer between 1 and 365 and warehouse_id an integer greater than 0")
+	ErrAlertFilterInvalid   = errors.New("handler: type must be batches_expiring or batches_expired, warehouse_id and seller_id integers greater than 0 and from and to RFC3339 times with from before to")
+
+	// Errores de Trace
+	ErrRecallNotFound = errors.New("repository: recall not found")
+
+	// Errores de Search
+	ErrSearchQueryRequired = errors.New("handler: q is required")
+	ErrSearchTypeInvalid   = errors.New("handler: types must be a list of products, sellers, buyers, warehouses or carries")
+	ErrSearchLimitInvalid  = errors.New("handler: limit must be an integer between 1 and 100")
+
+	// Errores de Include
+	ErrIncludeInvalid = errors.New("handler: include is not supported")
+
+	// Errores de Events
+	ErrEventTopicInvalid      = errors.New("handler: topics must be a list of sections, productBatches, inboundOrders, purchaseOrders or alerts")
+	ErrEventLastIDInvalid     = errors.New("handler: Last-Event-ID must be a positive integer")
+	ErrEventStreamUnsupported = errors.New("handler: streaming is not supported")
+
+	// Errores de Jobs
+	ErrJobNotFound         = errors.New("scheduler: job not found")
+	ErrJobDuplicated       = errors.New("scheduler: job already registered")
+	ErrJobScheduleInvalid  = errors.New("scheduler: invalid schedule")
+	ErrJobRunning          = errors.New("scheduler: job is already running")
+	ErrJobSchedulerStopped = errors.New("scheduler: scheduler is stopped")
+	ErrJobLimitInvalid     = errors.New("handler: limit must be an integer between 1 and 100")
+
+	// Errores de Reports
+	ErrReportTypeInvalid = errors.New("service: report type must be purchaseOrders, carries, sellers, products, inboundOrders or records")
+	ErrReportNotFound    = errors.New("service: report not found")
+	ErrReportNotReady    = errors.New("service: report is not finished")
+	ErrReportQueueFull   = errors.New("service: too many reports queued, try again later")
 )
 
 func validTime(fl validator.FieldLevel) bool {
@@ -120,13 +219,43 @@ type FakeResult struct{}
 func (r FakeResult) LastInsertId() (int64, error) { return 0, errors.New("fail on last insert id") }
 func (r FakeResult) RowsAffected() (int64, error) { return 1, nil }
 
+// customValidations are the tags registered in the shared validator
+var customValidations = map[string]validator.Func{
+	"hhmmss": validTime,
+}
+
+var (
+	validate     *validator.Validate
+	validateOnce sync.Once
+)
+
+// Validator returns the validator shared by every handler, with all the custom tags registered
+func Validator() *validator.Validate {
+	validateOnce.Do(func() {
+		validate = validator.New(validator.WithRequiredStructEnabled())
+		for tag, fn := range customValidations {
+			if err := validate.RegisterValidation(tag, fn); err != nil {
+				panic(err)
+			}
+		}
+	})
+	return validate
+}
+
 // ValidateStruct returns a string map of formatted errors
 func ValidateStruct(s interface{}) map[string]string {
-	v := validator.New()
-	v.RegisterValidation("hhmmss", validTime)
-	errorsList := make(map[string]string)
+	return validationErrors(Validator().Struct(s))
+}
 
-	err := v.Struct(s)
+// ValidateStructPartial returns a string map of formatted errors of the given fields only, the rules that compare
+// with other fields still see the whole struct
+func ValidateStructPartial(s interface{}, fields ...string) map[string]string {
+	return validationErrors(Validator().StructPartial(s, fields...))
+}
+
+// validationErrors formats the errors of the validator by field
+func validationErrors(err error) map[string]string {
+	errorsList := make(map[string]string)
 	if err == nil {
 		return nil
 	}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+)
+
+// MaxBodyBytes is the largest request body accepted by DecodeJSON
+const MaxBodyBytes = 1 << 20
+
+// DecodeJSON decodes the JSON body of the request into dst. The request must be application/json, the body
+// is limited to MaxBodyBytes and must contain a single object without unknown fields. The returned errors
+// wrap the e.ErrRequest* errors and name the offending field when there is one
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return e.ErrRequestContentType
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, MaxBodyBytes)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err = dec.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+	if err = dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return e.ErrRequestBodyTooLarge
+		}
+		return fmt.Errorf("%w: body must contain a single JSON object", e.ErrRequestWrongBody)
+	}
+	return nil
+}
+
+// decodeError maps the errors of the JSON decoder to the request errors
+func decodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, io.EOF):
+		return e.ErrRequestNoBody
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return fmt.Errorf("%w: malformed JSON", e.ErrRequestWrongBody)
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("%w: malformed JSON at position %d", e.ErrRequestWrongBody, syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		if typeErr.Field == "" {
+			return fmt.Errorf("%w: body must be %s", e.ErrRequestWrongBody, jsonKind(typeErr.Type))
+		}
+		return fmt.Errorf("%w: %s must be %s, got %s", e.ErrRequestFieldType, typeErr.Field, jsonKind(typeErr.Type), typeErr.Value)
+	case errors.As(err, &maxBytesErr):
+		return e.ErrRequestBodyTooLarge
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return fmt.Errorf("%w: %s", e.ErrRequestUnknownField, strings.TrimPrefix(err.Error(), "json: unknown field "))
+	}
+	return fmt.Errorf("%w: %s", e.ErrRequestWrongBody, err.Error())
+}
+
+// jsonKind describes the JSON value expected for a Go type
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	}
+	return "a " + t.String()
+}
+
+// DecodeStatus returns the status code of a DecodeJSON error
+func DecodeStatus(err error) int {
+	switch {
+	case errors.Is(err, e.ErrRequestContentType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, e.ErrRequestBodyTooLarge):
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
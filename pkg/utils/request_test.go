@@ -0,0 +1,111 @@
+package utils_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils"
+	e "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/utils/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type decodeBatch struct {
+	NetWeight float64 `json:"net_weight"`
+	Dimension struct {
+		Width int `json:"width"`
+	} `json:"dimension"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantErr     error
+		wantMessage string
+		wantStatus  int
+	}{
+		{
+			name:        "#1 Success",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"net_weight":1.5,"dimension":{"width":3}}`,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "#2 Error - Wrong Content-Type",
+			contentType: "text/plain",
+			body:        `{"net_weight":1.5}`,
+			wantErr:     e.ErrRequestContentType,
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "#3 Error - Empty Body",
+			contentType: "application/json",
+			body:        ``,
+			wantErr:     e.ErrRequestNoBody,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "#4 Error - Unknown Field",
+			contentType: "application/json",
+			body:        `{"net_wieght":1.5}`,
+			wantErr:     e.ErrRequestUnknownField,
+			wantMessage: `"net_wieght"`,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "#5 Error - Nested Field Wrong Type",
+			contentType: "application/json",
+			body:        `{"dimension":{"width":"3"}}`,
+			wantErr:     e.ErrRequestFieldType,
+			wantMessage: "dimension.width must be an integer, got string",
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "#6 Error - Malformed JSON",
+			contentType: "application/json",
+			body:        `{"net_weight":}`,
+			wantErr:     e.ErrRequestWrongBody,
+			wantMessage: "malformed JSON at position 15",
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "#7 Error - Trailing Data",
+			contentType: "application/json",
+			body:        `{"net_weight":1}{"net_weight":2}`,
+			wantErr:     e.ErrRequestWrongBody,
+			wantMessage: "single JSON object",
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "#8 Error - Body Too Large",
+			contentType: "application/json",
+			body:        `{"net_weight":` + strings.Repeat("1", utils.MaxBodyBytes) + `}`,
+			wantErr:     e.ErrRequestBodyTooLarge,
+			wantStatus:  http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+
+			var dst decodeBatch
+			err := utils.DecodeJSON(w, req, &dst)
+
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				require.Equal(t, 1.5, dst.NetWeight)
+				require.Equal(t, 3, dst.Dimension.Width)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Contains(t, err.Error(), tt.wantMessage)
+			require.Equal(t, tt.wantStatus, utils.DecodeStatus(err))
+		})
+	}
+}
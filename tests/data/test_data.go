@@ -85,11 +85,11 @@ func (suite *TestSuite) buildSellers() ([]string, *sqlmock.Rows) {
 }
 
 func (suite *TestSuite) buildLocalities() ([]string, *sqlmock.Rows) {
-	column := []string{"id", "locality_name", "province_name", "country_name"}
+	column := []string{"id", "locality_name", "province_name", "country_name", "latitude", "longitude"}
 	return column, sqlmock.NewRows(column).
-		AddRow(1, "Manhattan", "New York", "USA").
-		AddRow(2, "Downtown", "California", "USA").
-		AddRow(3, "Lakeview", "Illinois", "USA")
+		AddRow(1, "Manhattan", "New York", "USA", 40.7831, -73.9712).
+		AddRow(2, "Downtown", "California", "USA", nil, nil).
+		AddRow(3, "Lakeview", "Illinois", "USA", nil, nil)
 }
 
 func (suite *TestSuite) buildSelByLoc() ([]string, *sqlmock.Rows) {
@@ -0,0 +1,25 @@
+package mock
+
+import (
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBatchExpiryService struct {
+	mock.Mock
+}
+
+func (m *MockBatchExpiryService) FindExpiring(days, warehouseID int) ([]mod.ExpiringBatch, error) {
+	args := m.Called(days, warehouseID)
+	return args.Get(0).([]mod.ExpiringBatch), args.Error(1)
+}
+
+func (m *MockBatchExpiryService) Sweep() ([]mod.Alert, error) {
+	args := m.Called()
+	return args.Get(0).([]mod.Alert), args.Error(1)
+}
+
+func (m *MockBatchExpiryService) FindAlerts(f mod.AlertFilter) ([]mod.Alert, error) {
+	args := m.Called(f)
+	return args.Get(0).([]mod.Alert), args.Error(1)
+}
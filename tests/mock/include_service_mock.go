@@ -0,0 +1,24 @@
+package mock
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type MockIncludeService struct {
+	mock.Mock
+}
+
+func (m *MockIncludeService) Validate(resource string, includes []string) error {
+	args := m.Called(resource, includes)
+	return args.Error(0)
+}
+
+func (m *MockIncludeService) Expand(resource string, items []map[string]any, includes []string) error {
+	args := m.Called(resource, items, includes)
+	return args.Error(0)
+}
+
+func (m *MockIncludeService) Keys(resource string, includes []string) []string {
+	args := m.Called(resource, includes)
+	return args.Get(0).([]string)
+}
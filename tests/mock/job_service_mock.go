@@ -0,0 +1,35 @@
+package mock
+
+import (
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockJobService struct {
+	mock.Mock
+}
+
+func (m *MockJobService) FindAll() ([]mod.Job, error) {
+	args := m.Called()
+	return args.Get(0).([]mod.Job), args.Error(1)
+}
+
+func (m *MockJobService) FindRuns(name string, limit int) ([]mod.JobRun, error) {
+	args := m.Called(name, limit)
+	return args.Get(0).([]mod.JobRun), args.Error(1)
+}
+
+func (m *MockJobService) Trigger(name string) error {
+	args := m.Called(name)
+	return args.Error(0)
+}
+
+func (m *MockJobService) Pause(name string) error {
+	args := m.Called(name)
+	return args.Error(0)
+}
+
+func (m *MockJobService) Resume(name string) error {
+	args := m.Called(name)
+	return args.Error(0)
+}
@@ -0,0 +1,45 @@
+package mock
+
+import (
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLocalityRepository struct {
+	mock.Mock
+}
+
+func (m *MockLocalityRepository) FindAllLocalities() ([]mod.Locality, error) {
+	args := m.Called()
+	return args.Get(0).([]mod.Locality), args.Error(1)
+}
+
+func (m *MockLocalityRepository) FindByID(id int) (mod.Locality, error) {
+	args := m.Called(id)
+	return args.Get(0).(mod.Locality), args.Error(1)
+}
+
+func (m *MockLocalityRepository) ExistsByID(id int) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLocalityRepository) FindSellersByLocID(id int) ([]mod.SelByLoc, error) {
+	args := m.Called(id)
+	return args.Get(0).([]mod.SelByLoc), args.Error(1)
+}
+
+func (m *MockLocalityRepository) FindWarehousesByLocID(id int) ([]mod.WhByLoc, error) {
+	args := m.Called(id)
+	return args.Get(0).([]mod.WhByLoc), args.Error(1)
+}
+
+func (m *MockLocalityRepository) FindOverviewByLocID(id int) ([]mod.LocalityOverview, error) {
+	args := m.Called(id)
+	return args.Get(0).([]mod.LocalityOverview), args.Error(1)
+}
+
+func (m *MockLocalityRepository) Save(locality *mod.Locality) (int, error) {
+	args := m.Called(locality)
+	return args.Get(0).(int), args.Error(1)
+}
@@ -19,6 +19,16 @@ func (m *MockLocalityService) FindSellersByLocID(id int) (result []mod.SelByLoc,
 	return args.Get(0).([]mod.SelByLoc), args.Error(1)
 }
 
+func (m *MockLocalityService) FindWarehousesByLocID(id int) ([]mod.WhByLoc, error) {
+	args := m.Called(id)
+	return args.Get(0).([]mod.WhByLoc), args.Error(1)
+}
+
+func (m *MockLocalityService) FindOverviewByLocID(id int) ([]mod.LocalityOverview, error) {
+	args := m.Called(id)
+	return args.Get(0).([]mod.LocalityOverview), args.Error(1)
+}
+
 func (m *MockLocalityService) Save(locality *mod.Locality) (int, error) {
 	args := m.Called(locality)
 	return args.Get(0).(int), args.Error(1)
@@ -3,30 +3,60 @@ package mock
 import (
 	"database/sql/driver"
 	"github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"regexp"
 	"time"
 )
 
-var ProductBatchTableStruct = []string{"id", "batch_number", "current_quantity", "initial_quantity", "current_temperature", "minimum_temperature", "due_date", "manufacturing_date", "manufacturing_hour", "product_id", "section_id"}
+var ProductBatchTableStruct = []string{"id", "batch_number", "current_quantity", "initial_quantity", "current_temperature", "minimum_temperature", "due_date", "manufacturing_date", "manufacturing_hour", "product_id", "section_id", "archived_at", "expired_at", "status"}
 
 var ProductBatchDataValuesSelect = [][]driver.Value{
 	{
-		1, 1, 200, 200, 2, -5, time.Date(2024, 07, 05, 17, 00, 00, 0, time.UTC), time.Date(2024, 06, 1, 0, 00, 00, 0, time.UTC), "08:00:00", 1, 1,
+		1, 1, 200, 200, 2, -5, time.Date(2024, 07, 05, 17, 00, 00, 0, time.UTC), time.Date(2024, 06, 1, 0, 00, 00, 0, time.UTC), "08:00:00", 1, 1, nil, nil, "available",
 	},
 	{
-		2, 2, 310, 310, -2, -6, time.Date(2024, 8, 01, 12, 00, 00, 0, time.UTC), time.Date(2024, 7, 1, 0, 00, 00, 0, time.UTC), "09:30:00", 2, 2,
+		2, 2, 310, 310, -2, -6, time.Date(2024, 8, 01, 12, 00, 00, 0, time.UTC), time.Date(2024, 7, 1, 0, 00, 00, 0, time.UTC), "09:30:00", 2, 2, nil, nil, "available",
 	},
 }
 
-var ProductBatchSelectExpectedQuery = "SELECT `id`,`batch_number`, `current_quantity`, `initial_quantity`, `current_temperature`, `minimum_temperature`, `due_date`, `manufacturing_date`, `manufacturing_hour`, `product_id`, `section_id` FROM `product_batches` "
+var ProductBatchSelectExpectedQuery = regexp.QuoteMeta("SELECT pb.`id`, pb.`batch_number`, pb.`current_quantity`, pb.`initial_quantity`, pb.`current_temperature`, pb.`minimum_temperature`, " +
+	"pb.`due_date`, pb.`manufacturing_date`, pb.`manufacturing_hour`, pb.`product_id`, pb.`section_id`, pb.`archived_at`, pb.`expired_at`, pb.`status` FROM `product_batches` AS pb")
 
 type MockProductBatchService struct {
-	MockFindAll func() ([]models.ProductBatch, error)
-	MockSave    func(*models.ProductBatch) error
+	MockFindAll  func(models.ProductBatchFilter) ([]models.ProductBatch, error)
+	MockFindByID func(int) (models.ProductBatch, error)
+	MockSave     func(*models.ProductBatch) error
+	MockUpdate   func(int, models.ProductBatchPatch) (models.ProductBatch, error)
+	MockArchive  func(int) (models.ProductBatch, error)
+	MockDelete   func(int) error
+	MockHold     func(int, models.BatchHoldRequest) (models.ProductBatch, error)
+	MockRelease  func(int, models.BatchHoldRequest) (models.ProductBatch, error)
+	MockHistory  func(int) ([]models.BatchStatusChange, error)
 }
 
-func (m *MockProductBatchService) FindAll() ([]models.ProductBatch, error) {
-	return m.MockFindAll()
+func (m *MockProductBatchService) FindAll(f models.ProductBatchFilter) ([]models.ProductBatch, error) {
+	return m.MockFindAll(f)
+}
+func (m *MockProductBatchService) FindByID(id int) (models.ProductBatch, error) {
+	return m.MockFindByID(id)
 }
 func (m *MockProductBatchService) Save(pb *models.ProductBatch) error {
 	return m.MockSave(pb)
 }
+func (m *MockProductBatchService) Update(id int, patch models.ProductBatchPatch) (models.ProductBatch, error) {
+	return m.MockUpdate(id, patch)
+}
+func (m *MockProductBatchService) Archive(id int) (models.ProductBatch, error) {
+	return m.MockArchive(id)
+}
+func (m *MockProductBatchService) Delete(id int) error {
+	return m.MockDelete(id)
+}
+func (m *MockProductBatchService) Hold(id int, req models.BatchHoldRequest) (models.ProductBatch, error) {
+	return m.MockHold(id, req)
+}
+func (m *MockProductBatchService) Release(id int, req models.BatchHoldRequest) (models.ProductBatch, error) {
+	return m.MockRelease(id, req)
+}
+func (m *MockProductBatchService) FindStatusHistory(id int) ([]models.BatchStatusChange, error) {
+	return m.MockHistory(id)
+}
@@ -0,0 +1,35 @@
+package mock
+
+import (
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProductTypeService struct {
+	mock.Mock
+}
+
+func (m *MockProductTypeService) FindAll() ([]mod.ProductType, error) {
+	args := m.Called()
+	return args.Get(0).([]mod.ProductType), args.Error(1)
+}
+
+func (m *MockProductTypeService) FindByID(id int) (mod.ProductType, error) {
+	args := m.Called(id)
+	return args.Get(0).(mod.ProductType), args.Error(1)
+}
+
+func (m *MockProductTypeService) Save(pt *mod.ProductType) error {
+	args := m.Called(pt)
+	return args.Error(0)
+}
+
+func (m *MockProductTypeService) Update(id int, patch mod.ProductTypePatch) (mod.ProductType, error) {
+	args := m.Called(id, patch)
+	return args.Get(0).(mod.ProductType), args.Error(1)
+}
+
+func (m *MockProductTypeService) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
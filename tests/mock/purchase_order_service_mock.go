@@ -13,3 +13,13 @@ func (m *MockPurchaseOrderService) Save(buyer *mod.PurchaseOrder) (err error) {
 	args := m.Called(buyer)
 	return args.Error(0)
 }
+
+func (m *MockPurchaseOrderService) FindAll() ([]mod.PurchaseOrder, error) {
+	args := m.Called()
+	return args.Get(0).([]mod.PurchaseOrder), args.Error(1)
+}
+
+func (m *MockPurchaseOrderService) FindByID(id int) (mod.PurchaseOrder, error) {
+	args := m.Called(id)
+	return args.Get(0).(mod.PurchaseOrder), args.Error(1)
+}
@@ -0,0 +1,34 @@
+package mock
+
+import (
+	"io"
+	"time"
+
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReportService struct {
+	mock.Mock
+}
+
+func (m *MockReportService) Enqueue(reportType string, params mod.ReportParams) (mod.ReportJob, error) {
+	args := m.Called(reportType, params)
+	return args.Get(0).(mod.ReportJob), args.Error(1)
+}
+
+func (m *MockReportService) FindByID(id string) (mod.ReportJob, error) {
+	args := m.Called(id)
+	return args.Get(0).(mod.ReportJob), args.Error(1)
+}
+
+func (m *MockReportService) Open(id string) (mod.ReportJob, io.ReadSeekCloser, error) {
+	args := m.Called(id)
+	file, _ := args.Get(1).(io.ReadSeekCloser)
+	return args.Get(0).(mod.ReportJob), file, args.Error(2)
+}
+
+func (m *MockReportService) Cleanup(before time.Time) (int, error) {
+	args := m.Called(before)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,15 @@
+package mock
+
+import (
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSearchService struct {
+	mock.Mock
+}
+
+func (m *MockSearchService) Search(q string, types []string, limit int) ([]mod.SearchResult, error) {
+	args := m.Called(q, types, limit)
+	return args.Get(0).([]mod.SearchResult), args.Error(1)
+}
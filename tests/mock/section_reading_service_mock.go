@@ -0,0 +1,30 @@
+package mock
+
+import (
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSectionReadingService struct {
+	mock.Mock
+}
+
+func (m *MockSectionReadingService) Ingest(sectionID int, batch mod.SectionReadingBatch) (mod.SectionReadingsResult, error) {
+	args := m.Called(sectionID, batch)
+	return args.Get(0).(mod.SectionReadingsResult), args.Error(1)
+}
+
+func (m *MockSectionReadingService) FindReadings(sectionID int, rg mod.SectionReadingRange) ([]mod.SectionReading, error) {
+	args := m.Called(sectionID, rg)
+	return args.Get(0).([]mod.SectionReading), args.Error(1)
+}
+
+func (m *MockSectionReadingService) FindRollups(sectionID int, rg mod.SectionReadingRange) ([]mod.SectionReadingRollup, error) {
+	args := m.Called(sectionID, rg)
+	return args.Get(0).([]mod.SectionReadingRollup), args.Error(1)
+}
+
+func (m *MockSectionReadingService) FindAlerts(sectionID int, open *bool) ([]mod.SectionAlert, error) {
+	args := m.Called(sectionID, open)
+	return args.Get(0).([]mod.SectionAlert), args.Error(1)
+}
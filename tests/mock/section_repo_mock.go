@@ -30,7 +30,8 @@ type MockSectionService struct {
 	MockSave           func(section *mod.Section) error
 	MockDelete         func(id int) error
 	MockUpdate         func(id int, fields map[string]interface{}) (*mod.Section, error)
-	MockReportProducts func(ids []int) ([]mod.ReportProductsResponse, error)
+	MockReportProducts func(f mod.SectionReportFilter) ([]mod.ReportProductsResponse, error)
+	MockRecommend      func(req mod.SlottingRequest) ([]mod.SectionRecommendation, error)
 }
 
 func (m *MockSectionService) FindAll() ([]mod.Section, error) {
@@ -46,8 +47,12 @@ func (m *MockSectionService) Delete(id int) error {
 func (m *MockSectionService) Update(id int, fields map[string]interface{}) (*mod.Section, error) {
 	return m.MockUpdate(id, fields)
 }
-func (m *MockSectionService) ReportProducts(ids []int) ([]mod.ReportProductsResponse, error) {
-	return m.MockReportProducts(ids)
+func (m *MockSectionService) ReportProducts(f mod.SectionReportFilter) ([]mod.ReportProductsResponse, error) {
+	return m.MockReportProducts(f)
+}
+
+func (m *MockSectionService) Recommend(req mod.SlottingRequest) ([]mod.SectionRecommendation, error) {
+	return m.MockRecommend(req)
 }
 
 func (m *MockSectionService) Save(section *mod.Section) error {
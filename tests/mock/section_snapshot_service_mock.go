@@ -0,0 +1,20 @@
+package mock
+
+import (
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSectionSnapshotService struct {
+	mock.Mock
+}
+
+func (m *MockSectionSnapshotService) Snapshot() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSectionSnapshotService) FindHistory(sectionID int, q mod.SectionHistoryQuery) ([]mod.SectionHistoryBucket, error) {
+	args := m.Called(sectionID, q)
+	return args.Get(0).([]mod.SectionHistoryBucket), args.Error(1)
+}
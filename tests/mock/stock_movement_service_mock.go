@@ -0,0 +1,30 @@
+package mock
+
+import (
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStockMovementService struct {
+	mock.Mock
+}
+
+func (m *MockStockMovementService) Adjust(mv *mod.StockMovement) error {
+	args := m.Called(mv)
+	return args.Error(0)
+}
+
+func (m *MockStockMovementService) FindLedgerByBatchID(batchID int) (mod.StockLedger, error) {
+	args := m.Called(batchID)
+	return args.Get(0).(mod.StockLedger), args.Error(1)
+}
+
+func (m *MockStockMovementService) Reconcile() ([]mod.StockDiscrepancy, error) {
+	args := m.Called()
+	return args.Get(0).([]mod.StockDiscrepancy), args.Error(1)
+}
+
+func (m *MockStockMovementService) FindDiscrepancies() ([]mod.StockDiscrepancy, error) {
+	args := m.Called()
+	return args.Get(0).([]mod.StockDiscrepancy), args.Error(1)
+}
@@ -0,0 +1,30 @@
+package mock
+
+import (
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTraceService struct {
+	mock.Mock
+}
+
+func (m *MockTraceService) FindBatchTrace(id int) (mod.BatchTrace, error) {
+	args := m.Called(id)
+	return args.Get(0).(mod.BatchTrace), args.Error(1)
+}
+
+func (m *MockTraceService) FindPurchaseOrderTrace(id int) (mod.PurchaseOrderTrace, error) {
+	args := m.Called(id)
+	return args.Get(0).(mod.PurchaseOrderTrace), args.Error(1)
+}
+
+func (m *MockTraceService) Recall(req mod.RecallRequest) (mod.Recall, error) {
+	args := m.Called(req)
+	return args.Get(0).(mod.Recall), args.Error(1)
+}
+
+func (m *MockTraceService) FindRecall(id int) (mod.Recall, error) {
+	args := m.Called(id)
+	return args.Get(0).(mod.Recall), args.Error(1)
+}
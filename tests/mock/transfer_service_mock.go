@@ -0,0 +1,25 @@
+package mock
+
+import (
+	mod "github.com/smartineztri_meli/W17-G2-Bootcamp/pkg/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransferService struct {
+	mock.Mock
+}
+
+func (m *MockTransferService) Save(t *mod.Transfer) error {
+	args := m.Called(t)
+	return args.Error(0)
+}
+
+func (m *MockTransferService) FindAll() ([]mod.Transfer, error) {
+	args := m.Called()
+	return args.Get(0).([]mod.Transfer), args.Error(1)
+}
+
+func (m *MockTransferService) FindByID(id int) (mod.Transfer, error) {
+	args := m.Called(id)
+	return args.Get(0).(mod.Transfer), args.Error(1)
+}
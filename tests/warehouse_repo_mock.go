@@ -47,3 +47,28 @@ func (m *WarehouseMock) ExistsWarehouseCode(code string) (bool, error) {
 	args := m.Called(code)
 	return args.Bool(0), args.Error(1)
 }
+
+func (m *WarehouseMock) GetCapacityReport(id *int) ([]models.WarehouseCapacityReport, error) {
+	args := m.Called(id)
+	return args.Get(0).([]models.WarehouseCapacityReport), args.Error(1)
+}
+
+func (m *WarehouseMock) GetSections(id int, filter models.WarehouseSectionFilter) ([]models.WarehouseSection, error) {
+	args := m.Called(id, filter)
+	return args.Get(0).([]models.WarehouseSection), args.Error(1)
+}
+
+func (m *WarehouseMock) GetEmployees(id int, name string) ([]models.Employee, error) {
+	args := m.Called(id, name)
+	return args.Get(0).([]models.Employee), args.Error(1)
+}
+
+func (m *WarehouseMock) GetInboundOrders(id int, limit int) ([]models.InboundOrders, error) {
+	args := m.Called(id, limit)
+	return args.Get(0).([]models.InboundOrders), args.Error(1)
+}
+
+func (m *WarehouseMock) GetStockingProduct(productID int) ([]models.WarehouseDistance, error) {
+	args := m.Called(productID)
+	return args.Get(0).([]models.WarehouseDistance), args.Error(1)
+}